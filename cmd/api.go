@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+// mastodonAPI is the part of the Mastodon client that posting, editing,
+// deleting, syncing, reboosting, and following use, so those commands can be
+// tested against a fake instead of a server
+type mastodonAPI interface {
+	GetInstance() (*mastodon.Instance, error)
+	VerifyCredentials() (*mastodon.Account, error)
+	GetNotification(id string) (*mastodon.Notification, error)
+
+	ResolveStatusID(ref string) (string, error)
+	GetStatus(id string) (*mastodon.Status, error)
+	GetStatusContext(id string) (*mastodon.StatusContext, error)
+	GetAccountStatuses(limit int) ([]*mastodon.Status, error)
+	ResolveAccount(ref string) (*mastodon.Account, error)
+
+	PostStatus(params mastodon.StatusParams) (*mastodon.Status, error)
+	ScheduleStatus(params mastodon.StatusParams, at time.Time) (*mastodon.ScheduledStatus, error)
+	EditStatus(id string, params mastodon.StatusParams) (*mastodon.Status, error)
+	DeleteStatus(id string) (*mastodon.Status, error)
+	ReblogStatus(id string) (*mastodon.Status, error)
+	UnreblogStatus(id string) (*mastodon.Status, error)
+	FollowAccount(id string, params mastodon.FollowParams) (*mastodon.Relationship, error)
+	UploadMedia(fileData []byte, filename, mimeType, description string) (*mastodon.MediaAttachment, error)
+}
+
+var _ mastodonAPI = (*mastodon.Client)(nil)
+
+// newAPI creates the client the commands above talk to, as newClient does.
+// Tests replace it to hand them a fake.
+var newAPI = func(store *config.Store, domain, accessToken string) (mastodonAPI, error) {
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		// Not a nil *mastodon.Client wrapped in the interface
+		return nil, err
+	}
+	return client, nil
+}
+
+// apiWithContext makes api's requests stop when ctx is done, as
+// mastodon.Client.WithContext does. Fakes are returned as they are.
+func apiWithContext(api mastodonAPI, ctx context.Context) mastodonAPI {
+	if client, ok := api.(*mastodon.Client); ok {
+		return client.WithContext(ctx)
+	}
+	return api
+}
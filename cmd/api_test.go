@@ -0,0 +1,598 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/fatih/color"
+)
+
+// fakeAPI is an instance that keeps its statuses in memory
+type fakeAPI struct {
+	statuses map[string]*mastodon.Status
+	mine     []*mastodon.Status
+	posted   []mastodon.StatusParams
+	edited   []mastodon.StatusParams
+	deleted  []string
+	// reblogs are the boosts and unboosts made, as "reblog ID" and
+	// "unreblog ID"
+	reblogs []string
+	// contexts are the replies below statuses, by ID
+	contexts map[string]*mastodon.StatusContext
+	// accounts are the accounts that can be found, by handle without the @
+	accounts map[string]*mastodon.Account
+	// follows are the follows made, by account ID
+	follows map[string]mastodon.FollowParams
+	// rateLimits is how many follows and deletions to turn away as rate
+	// limited before letting any through
+	rateLimits int
+}
+
+func newFakeAPI(mine ...*mastodon.Status) *fakeAPI {
+	api := &fakeAPI{statuses: make(map[string]*mastodon.Status), mine: mine}
+	for _, status := range mine {
+		api.statuses[status.ID] = status
+	}
+	return api
+}
+
+func (f *fakeAPI) GetInstance() (*mastodon.Instance, error) {
+	return &mastodon.Instance{}, nil
+}
+
+func (f *fakeAPI) VerifyCredentials() (*mastodon.Account, error) {
+	return &mastodon.Account{ID: "1", Acct: "me"}, nil
+}
+
+func (f *fakeAPI) GetNotification(id string) (*mastodon.Notification, error) {
+	return nil, fmt.Errorf("no notification %s", id)
+}
+
+func (f *fakeAPI) ResolveStatusID(ref string) (string, error) {
+	return ref, nil
+}
+
+func (f *fakeAPI) GetStatus(id string) (*mastodon.Status, error) {
+	if status, ok := f.statuses[id]; ok {
+		return status, nil
+	}
+	return nil, &mastodon.APIError{Op: "get status", StatusCode: 404}
+}
+
+func (f *fakeAPI) GetStatusContext(id string) (*mastodon.StatusContext, error) {
+	if context, ok := f.contexts[id]; ok {
+		return context, nil
+	}
+	return &mastodon.StatusContext{}, nil
+}
+
+func (f *fakeAPI) GetAccountStatuses(limit int) ([]*mastodon.Status, error) {
+	return f.mine[:min(limit, len(f.mine))], nil
+}
+
+func (f *fakeAPI) ResolveAccount(ref string) (*mastodon.Account, error) {
+	if account, ok := f.accounts[strings.TrimPrefix(ref, "@")]; ok {
+		return account, nil
+	}
+	return nil, &mastodon.APIError{Op: "look up account", StatusCode: 404}
+}
+
+func (f *fakeAPI) PostStatus(params mastodon.StatusParams) (*mastodon.Status, error) {
+	f.posted = append(f.posted, params)
+	status := &mastodon.Status{
+		ID:        fmt.Sprintf("%d", 100+len(f.posted)),
+		Content:   params.Status,
+		Account:   &mastodon.Account{ID: "1", Acct: "me"},
+		CreatedAt: time.Now(),
+	}
+	f.statuses[status.ID] = status
+	return status, nil
+}
+
+func (f *fakeAPI) ScheduleStatus(params mastodon.StatusParams, at time.Time) (*mastodon.ScheduledStatus, error) {
+	return &mastodon.ScheduledStatus{ID: "s1", ScheduledAt: at}, nil
+}
+
+func (f *fakeAPI) EditStatus(id string, params mastodon.StatusParams) (*mastodon.Status, error) {
+	status, err := f.GetStatus(id)
+	if err != nil {
+		return nil, err
+	}
+	f.edited = append(f.edited, params)
+	status.Content = params.Status
+	return status, nil
+}
+
+func (f *fakeAPI) DeleteStatus(id string) (*mastodon.Status, error) {
+	if f.rateLimits > 0 {
+		f.rateLimits--
+		return nil, &mastodon.APIError{Op: "delete status", StatusCode: 429, RetryAfter: time.Millisecond}
+	}
+	status, err := f.GetStatus(id)
+	if err != nil {
+		return nil, err
+	}
+	delete(f.statuses, id)
+	f.deleted = append(f.deleted, id)
+	return status, nil
+}
+
+func (f *fakeAPI) ReblogStatus(id string) (*mastodon.Status, error) {
+	status, err := f.GetStatus(id)
+	if err != nil {
+		return nil, err
+	}
+	f.reblogs = append(f.reblogs, "reblog "+id)
+	return status, nil
+}
+
+func (f *fakeAPI) UnreblogStatus(id string) (*mastodon.Status, error) {
+	status, err := f.GetStatus(id)
+	if err != nil {
+		return nil, err
+	}
+	f.reblogs = append(f.reblogs, "unreblog "+id)
+	return status, nil
+}
+
+func (f *fakeAPI) FollowAccount(id string, params mastodon.FollowParams) (*mastodon.Relationship, error) {
+	if f.rateLimits > 0 {
+		f.rateLimits--
+		return nil, &mastodon.APIError{Op: "follow account", StatusCode: 429, RetryAfter: time.Millisecond}
+	}
+	if f.follows == nil {
+		f.follows = make(map[string]mastodon.FollowParams)
+	}
+	f.follows[id] = params
+	return &mastodon.Relationship{ID: id, Following: true, ShowingReblogs: params.Reblogs}, nil
+}
+
+func (f *fakeAPI) UploadMedia(fileData []byte, filename, mimeType, description string) (*mastodon.MediaAttachment, error) {
+	return &mastodon.MediaAttachment{ID: "m1", Description: description}, nil
+}
+
+// useFakeAPI logs in to a fresh store and points the commands at api
+func useFakeAPI(t *testing.T, api *fakeAPI) *config.Store {
+	t.Helper()
+	t.Setenv(config.DatabaseEnv, filepath.Join(t.TempDir(), "tusk.db"))
+
+	store, err := config.NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	store.Set("domain", "https://example.social")
+	store.Set("access_token", "token")
+
+	saved := newAPI
+	newAPI = func(*config.Store, string, string) (mastodonAPI, error) { return api, nil }
+	t.Cleanup(func() { newAPI = saved })
+	return store
+}
+
+func TestSyncAddsPostsOldestFirst(t *testing.T) {
+	api := newFakeAPI(&mastodon.Status{ID: "3"}, &mastodon.Status{ID: "2"}, &mastodon.Status{ID: "1"})
+	store := useFakeAPI(t, api)
+
+	if err := runSync(syncCmd, nil); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	last, _ := store.GetLastPostID()
+	if last != "3" {
+		t.Errorf("Expected the newest post last in history, got %q", last)
+	}
+}
+
+func TestDeleteLatestRemovesFromHistory(t *testing.T) {
+	api := newFakeAPI(&mastodon.Status{ID: "7"})
+	store := useFakeAPI(t, api)
+	store.AddPostToHistory("7")
+	store.CacheStatus("7", "me", "<p>bye</p>")
+
+	deleteLatest, deleteForce = true, true
+	defer func() { deleteLatest, deleteForce = false, false }()
+
+	if err := runDelete(deleteCmd, nil); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	if len(api.deleted) != 1 || api.deleted[0] != "7" {
+		t.Errorf("Expected status 7 to be deleted, got %q", api.deleted)
+	}
+	if last, _ := store.GetLastPostID(); last != "" {
+		t.Errorf("Expected an empty history, got %q", last)
+	}
+	if cached, _ := store.GetCachedStatus("7"); cached != nil {
+		t.Errorf("Expected the cached copy to be removed, got %+v", cached)
+	}
+}
+
+func TestDeleteThreadRepliesFirst(t *testing.T) {
+	me := &mastodon.Account{ID: "1", Acct: "me"}
+	alice := &mastodon.Account{ID: "2", Acct: "alice"}
+	api := newFakeAPI(
+		&mastodon.Status{ID: "10", Account: me},
+		&mastodon.Status{ID: "11", InReplyTo: "10", Account: me},
+		&mastodon.Status{ID: "12", InReplyTo: "11", Account: alice},
+		&mastodon.Status{ID: "13", InReplyTo: "11", Account: me},
+	)
+	api.contexts = map[string]*mastodon.StatusContext{
+		"10": {Descendants: api.mine[1:]},
+	}
+	useFakeAPI(t, api)
+
+	// The instance turns one deletion away, and tusk waits it out
+	api.rateLimits = 1
+	deleteThread, deleteForce, deleteThreadDelay = true, true, 0
+	defer func() { deleteThread, deleteForce, deleteThreadDelay = false, false, time.Second }()
+
+	if err := runDelete(deleteCmd, []string{"10"}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	if want := []string{"13", "11", "10"}; !slices.Equal(api.deleted, want) {
+		t.Errorf("Expected %q deleted in order, got %q", want, api.deleted)
+	}
+}
+
+// useStdin feeds text to the command as if it were piped in
+func useStdin(t *testing.T, text string) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	w.WriteString(text)
+	w.Close()
+
+	saved := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = saved
+		r.Close()
+	})
+}
+
+func TestEditLatest(t *testing.T) {
+	api := newFakeAPI(&mastodon.Status{
+		ID:               "5",
+		Content:          "<p>Old text</p>",
+		Visibility:       mastodon.VisibilityPublic,
+		MediaAttachments: []*mastodon.MediaAttachment{{ID: "m1"}},
+	})
+	store := useFakeAPI(t, api)
+	store.AddPostToHistory("5")
+
+	editLatest, editVisibility, editContentWarn = true, "u", "spoilers"
+	defer func() { editLatest, editVisibility, editContentWarn, editDryRun = false, "", "", false }()
+
+	// A dry run changes nothing
+	editDryRun = true
+	useStdin(t, "New text\n")
+	if err := runEdit(editCmd, nil); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(api.edited) != 0 {
+		t.Fatalf("Expected a dry run not to edit, got %+v", api.edited)
+	}
+
+	editDryRun = false
+	useStdin(t, "New text\n")
+	if err := runEdit(editCmd, nil); err != nil {
+		t.Fatalf("edit failed: %v", err)
+	}
+
+	if len(api.edited) != 1 {
+		t.Fatalf("Expected one edit, got %+v", api.edited)
+	}
+	params := api.edited[0]
+	if params.Status != "New text" || params.Visibility != mastodon.VisibilityUnlisted || params.SpoilerText != "spoilers" {
+		t.Errorf("Unexpected edit %+v", params)
+	}
+	if !slices.Equal(params.MediaIDs, []string{"m1"}) {
+		t.Errorf("Expected the existing media to be kept, got %q", params.MediaIDs)
+	}
+}
+
+func TestEditMissingStatus(t *testing.T) {
+	api := newFakeAPI()
+	useFakeAPI(t, api)
+
+	useStdin(t, "New text\n")
+	err := runEdit(editCmd, []string{"404"})
+	if apiErr, ok := mastodon.AsAPIError(err); !ok || !apiErr.NotFound() {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if len(api.edited) != 0 {
+		t.Errorf("Expected nothing edited, got %+v", api.edited)
+	}
+}
+
+func TestVerifyDeleted(t *testing.T) {
+	saved := deleteCheckDelays
+	deleteCheckDelays = []time.Duration{0, 0}
+	defer func() { deleteCheckDelays = saved }()
+
+	api := newFakeAPI(&mastodon.Status{ID: "7"})
+	if err := verifyDeleted(api, "8"); err != nil {
+		t.Errorf("Expected a missing status to be verified gone, got %v", err)
+	}
+	if err := verifyDeleted(api, "7"); err == nil {
+		t.Error("Expected a status the instance still has to be reported, got nil")
+	}
+}
+
+func TestVerifyDeletedStopsWhenInterrupted(t *testing.T) {
+	saved := deleteCheckDelays
+	deleteCheckDelays = []time.Duration{time.Hour}
+	defer func() { deleteCheckDelays = saved }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	savedContext := runContext
+	runContext = ctx
+	defer func() { runContext = savedContext }()
+
+	api := newFakeAPI(&mastodon.Status{ID: "7"})
+	if err := verifyDeleted(api, "7"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the check to stop when interrupted, got %v", err)
+	}
+}
+
+func TestReplyInheritsVisibility(t *testing.T) {
+	api := newFakeAPI()
+	api.statuses["5"] = &mastodon.Status{ID: "5", Visibility: mastodon.VisibilityPrivate, Account: &mastodon.Account{ID: "2", Acct: "alice"}}
+	useFakeAPI(t, api)
+
+	// Tests have no terminal, so the text comes from a file
+	path := filepath.Join(t.TempDir(), "reply.md")
+	if err := os.WriteFile(path, []byte("Agreed"), 0600); err != nil {
+		t.Fatalf("Failed to write post: %v", err)
+	}
+
+	replyTo, postFile = "5", path
+	defer func() { replyTo, postFile = "", "" }()
+
+	if err := sendPost(postCmd, nil); err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+
+	if len(api.posted) != 1 || api.posted[0].Visibility != mastodon.VisibilityPrivate {
+		t.Errorf("Expected a private reply, got %+v", api.posted)
+	}
+}
+
+func TestReplyVisibilitySetting(t *testing.T) {
+	for _, tc := range []struct {
+		parent  mastodon.Visibility
+		replyTo string
+		want    mastodon.Visibility
+	}{
+		{replyTo: "", want: mastodon.VisibilityPrivate},
+		{parent: mastodon.VisibilityPublic, replyTo: "5", want: mastodon.VisibilityUnlisted},
+		// Still no wider than the status replied to
+		{parent: mastodon.VisibilityDirect, replyTo: "5", want: mastodon.VisibilityDirect},
+	} {
+		api := newFakeAPI()
+		api.statuses["5"] = &mastodon.Status{ID: "5", Visibility: tc.parent, Account: &mastodon.Account{ID: "2", Acct: "alice"}}
+		store := useFakeAPI(t, api)
+		store.Set("default_visibility", "private")
+		store.Set("default_reply_visibility", "unlisted")
+
+		path := filepath.Join(t.TempDir(), "post.md")
+		if err := os.WriteFile(path, []byte("Agreed"), 0600); err != nil {
+			t.Fatalf("Failed to write post: %v", err)
+		}
+
+		replyTo, postFile = tc.replyTo, path
+		err := sendPost(postCmd, nil)
+		replyTo, postFile = "", ""
+		if err != nil {
+			t.Fatalf("post failed: %v", err)
+		}
+
+		if len(api.posted) != 1 || api.posted[0].Visibility != tc.want {
+			t.Errorf("Replying to %q (%s): expected %s, got %+v", tc.replyTo, tc.parent, tc.want, api.posted)
+		}
+	}
+}
+
+func TestReminderUsesDefaultVisibility(t *testing.T) {
+	api := newFakeAPI()
+	store := useFakeAPI(t, api)
+	store.Set("default_visibility", "private")
+
+	id, err := store.AddReminder("Post the release notes", time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to add reminder: %v", err)
+	}
+	postReminder(store, &config.Reminder{ID: id}, "The release notes are out")
+
+	if len(api.posted) != 1 || api.posted[0].Visibility != mastodon.VisibilityPrivate {
+		t.Errorf("Expected a private post, got %+v", api.posted)
+	}
+	if reminders, _ := store.ListReminders(); len(reminders) != 0 {
+		t.Errorf("Expected the reminder to be removed once posted, got %+v", reminders)
+	}
+}
+
+func TestReplyCarriesContentWarning(t *testing.T) {
+	for _, tc := range []struct {
+		parent, want string
+		noCW         bool
+	}{
+		{parent: "film spoilers", want: "re: film spoilers"},
+		{parent: "re: film spoilers", want: "re: film spoilers"},
+		{parent: "film spoilers", noCW: true, want: ""},
+	} {
+		api := newFakeAPI()
+		api.statuses["5"] = &mastodon.Status{ID: "5", SpoilerText: tc.parent, Account: &mastodon.Account{ID: "2", Acct: "alice"}}
+		useFakeAPI(t, api)
+
+		path := filepath.Join(t.TempDir(), "reply.md")
+		if err := os.WriteFile(path, []byte("Agreed"), 0600); err != nil {
+			t.Fatalf("Failed to write post: %v", err)
+		}
+
+		replyTo, postFile, noReplyCW = "5", path, tc.noCW
+		err := sendPost(postCmd, nil)
+		replyTo, postFile, noReplyCW = "", "", false
+		if err != nil {
+			t.Fatalf("post failed: %v", err)
+		}
+
+		if len(api.posted) != 1 || api.posted[0].SpoilerText != tc.want {
+			t.Errorf("Replying to %q with noCW=%v: expected CW %q, got %+v", tc.parent, tc.noCW, tc.want, api.posted)
+		}
+	}
+}
+
+func TestDuplicatePostAborted(t *testing.T) {
+	api := newFakeAPI()
+	store := useFakeAPI(t, api)
+	store.Set("duplicate_check", duplicateAbort)
+
+	path := filepath.Join(t.TempDir(), "post.md")
+	if err := os.WriteFile(path, []byte("Deploying  now\n"), 0600); err != nil {
+		t.Fatalf("Failed to write post: %v", err)
+	}
+	postFile = path
+	defer func() { postFile = "" }()
+
+	if err := sendPost(postCmd, nil); err != nil {
+		t.Fatalf("First post failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("Deploying now"), 0600); err != nil {
+		t.Fatalf("Failed to write post: %v", err)
+	}
+	if err := sendPost(postCmd, nil); err == nil {
+		t.Error("Expected the duplicate post to be refused, got nil")
+	}
+	if len(api.posted) != 1 {
+		t.Errorf("Expected one post, got %d", len(api.posted))
+	}
+
+	allowDuplicate = true
+	defer func() { allowDuplicate = false }()
+	if err := sendPost(postCmd, nil); err != nil {
+		t.Fatalf("Post with --allow-duplicate failed: %v", err)
+	}
+}
+
+func TestShareUsesPageTitle(t *testing.T) {
+	api := newFakeAPI()
+	useFakeAPI(t, api)
+
+	saved := fetchCard
+	fetchCard = func(_ *config.Store, pageURL string) (*mastodon.Card, error) {
+		return &mastodon.Card{URL: pageURL, Title: "Tusk 2.0 released"}, nil
+	}
+	assumeYes = true
+	defer func() { fetchCard, assumeYes = saved, false }()
+
+	if err := runShare(shareCmd, []string{"blog.example/tusk-2"}); err != nil {
+		t.Fatalf("share failed: %v", err)
+	}
+	if err := runShare(shareCmd, []string{"https://blog.example/tusk-3", "Even", "better"}); err != nil {
+		t.Fatalf("share with a comment failed: %v", err)
+	}
+
+	want := []string{
+		"Tusk 2.0 released\n\nhttps://blog.example/tusk-2",
+		"Even better\n\nhttps://blog.example/tusk-3",
+	}
+	if len(api.posted) != len(want) {
+		t.Fatalf("Expected %d posts, got %+v", len(want), api.posted)
+	}
+	for i, params := range api.posted {
+		if params.Status != want[i] {
+			t.Errorf("Expected post %q, got %q", want[i], params.Status)
+		}
+	}
+
+	if err := runShare(shareCmd, []string{"ftp://files.example/a"}); err == nil {
+		t.Error("Expected error for a non-web link, got nil")
+	}
+}
+
+func TestPostPrintsOnlyID(t *testing.T) {
+	api := newFakeAPI()
+	useFakeAPI(t, api)
+
+	path := filepath.Join(t.TempDir(), "post.md")
+	if err := os.WriteFile(path, []byte("Hello"), 0600); err != nil {
+		t.Fatalf("Failed to write post: %v", err)
+	}
+	postFile = path
+
+	var stdout bytes.Buffer
+	oldOutput, oldError := color.Output, color.Error
+	color.Output, color.Error = &stdout, io.Discard
+	output.SetFormat(output.FormatID)
+	defer func() {
+		postFile = ""
+		color.Output, color.Error = oldOutput, oldError
+		output.SetFormat(output.FormatPlain)
+	}()
+
+	if err := sendPost(postCmd, nil); err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+	if got := stdout.String(); got != "101\n" {
+		t.Errorf("Expected only the new status's ID on stdout, got %q", got)
+	}
+}
+
+func TestAsyncPostChecksImagesFirst(t *testing.T) {
+	api := newFakeAPI()
+	store := useFakeAPI(t, api)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "post.md")
+	if err := os.WriteFile(path, []byte("Hello"), 0600); err != nil {
+		t.Fatalf("Failed to write post: %v", err)
+	}
+	notImage := filepath.Join(dir, "photo.jpg")
+	if err := os.WriteFile(notImage, []byte("not an image"), 0600); err != nil {
+		t.Fatalf("Failed to write image: %v", err)
+	}
+	postFile, postAsync, imagePath, altText = path, true, notImage, "A photo"
+	defer func() { postFile, postAsync, imagePath, altText = "", false, "", "" }()
+
+	if err := sendPost(postCmd, nil); err == nil || !strings.Contains(err.Error(), "failed to process image") {
+		t.Fatalf("Expected the image to be refused before queueing, got %v", err)
+	}
+	if jobs, _ := store.ListJobs(10); len(jobs) != 0 {
+		t.Errorf("Expected nothing queued, got %d jobs", len(jobs))
+	}
+}
+
+func TestLoadStatusesFetchesReplyParents(t *testing.T) {
+	api := newFakeAPI(&mastodon.Status{ID: "2", InReplyTo: "1", Content: "<p>Agreed</p>"})
+	api.statuses["1"] = &mastodon.Status{ID: "1", Account: &mastodon.Account{Acct: "alice"}, Content: "<p>Tea is best</p>"}
+	store := useFakeAPI(t, api)
+
+	msg := loadStatuses(store, api)().(statusesLoadedMsg)
+	if msg.err != nil {
+		t.Fatalf("Failed to load statuses: %v", msg.err)
+	}
+	if len(msg.items) != 1 || msg.items[0].replyLine != "↳ reply to @alice: Tea is best" {
+		t.Errorf("Expected the uncached parent to be fetched, got %+v", msg.items)
+	}
+	if cached, _ := store.GetCachedStatus("1"); cached == nil {
+		t.Error("Expected the parent to be cached")
+	}
+}
@@ -2,26 +2,71 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"html/template"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"biesnecker.com/tusk/internal/config"
-	"biesnecker.com/tusk/internal/mastodon"
 	"biesnecker.com/tusk/internal/oauth"
 	"biesnecker.com/tusk/internal/output"
 	"github.com/spf13/cobra"
 )
 
+var (
+	authToken        string
+	authDomain       string
+	authScopes       string
+	authCallbackPort int
+)
+
 var authCmd = &cobra.Command{
 	Use:   "auth",
 	Short: "Authenticate with a Mastodon instance",
-	Long:  `Authenticate with a Mastodon instance to obtain an access token.`,
-	RunE:  runAuth,
+	Long: `Authenticate with a Mastodon instance to obtain an access token.
+
+To skip the browser, for a bot or on a server, create an access token in your
+instance's settings (Preferences > Development) and log in with it. Pass - as
+the token to read it from stdin, which keeps it out of your shell history.
+
+Examples:
+  tusk auth
+  tusk auth --domain mastodon.social
+  tusk auth --scopes "read write push"
+  tusk auth --callback-port 8790
+  tusk auth --domain mastodon.social --token "$MASTODON_TOKEN"
+  pass show mastodon | tusk auth --domain mastodon.social --token -`,
+	RunE: runAuth,
+}
+
+func init() {
+	// Stored, unlike the global --token, which is for a single run
+	authCmd.Flags().StringVar(&authToken, "token", "", "Log in with an access token created in your instance's settings, or - to read it from stdin")
+	authCmd.Flags().StringVar(&authDomain, "domain", "", "Instance domain to log in to, instead of being asked for it")
+	authCmd.Flags().StringVar(&authScopes, "scopes", defaultScopes, "OAuth scopes to ask for, such as \"read write push\"")
+	authCmd.Flags().IntVar(&authCallbackPort, "callback-port", 0, "Port the browser returns to, instead of the auth_callback_port setting or a random one")
 }
 
 func runAuth(cmd *cobra.Command, args []string) error {
+	scopes, err := parseScopesFlag(authScopes)
+	if err != nil {
+		return err
+	}
+	if authToken != "" && cmd.Flags().Changed("scopes") {
+		return invalidf("--scopes can't be used with --token; the token's scopes were chosen when it was made")
+	}
+	if cmd.Flags().Changed("callback-port") {
+		if err := validatePort(strconv.Itoa(authCallbackPort)); err != nil {
+			return invalidf("--callback-port %v", err)
+		}
+		if authToken != "" {
+			return invalidf("--callback-port can't be used with --token, which doesn't open a browser")
+		}
+	}
+
 	store, err := config.NewStore()
 	if err != nil {
 		return fmt.Errorf("failed to open config store: %w", err)
@@ -31,46 +76,136 @@ func runAuth(cmd *cobra.Command, args []string) error {
 	existingToken, _ := store.Get("access_token")
 	if existingToken != "" {
 		output.Info("You are already authenticated.")
-		output.Prompt("Do you want to re-authenticate? (y/N): ")
+		ok, err := confirm("", "Do you want to re-authenticate?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Info("Authentication cancelled.")
+			return errCancelled
+		}
+	}
 
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
+	if authToken != "" {
+		if authDomain == "" {
+			return invalidf("--token needs --domain to say which instance it's for")
+		}
+		token := authToken
+		if token == "-" {
+			if token, err = getTextFromStdin(); err != nil {
+				return err
+			}
+		}
+		return loginWithToken(store, instanceBaseURL(authDomain), strings.TrimSpace(token))
+	}
 
-		if response != "y" && response != "yes" {
-			output.Info("Authentication cancelled.")
-			return nil
+	if nonInteractive {
+		return invalidf("logging in needs a browser and can't be done in non-interactive mode; use --token, or --instance and --token for a single run")
+	}
+
+	domain := strings.TrimSpace(authDomain)
+	if domain == "" {
+		output.Prompt("Enter your Mastodon instance domain (e.g., mastodon.social): ")
+		reader := bufio.NewReader(os.Stdin)
+		domain, err = reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read domain: %w", err)
 		}
+		domain = strings.TrimSpace(domain)
+	}
+
+	if domain == "" {
+		return invalidf("domain cannot be empty")
+	}
+
+	return authenticate(store, instanceBaseURL(domain), scopes)
+}
+
+// loginWithToken saves an access token made outside tusk, such as in the
+// instance's settings, once the instance has accepted it
+func loginWithToken(store *config.Store, domain, accessToken string) error {
+	if accessToken == "" {
+		return invalidf("access token cannot be empty")
 	}
 
-	output.Prompt("Enter your Mastodon instance domain (e.g., mastodon.social): ")
-	reader := bufio.NewReader(os.Stdin)
-	domain, err := reader.ReadString('\n')
+	// As in authenticate, the client starts without a login so none of the
+	// stored one's bookkeeping is touched before the token checks out
+	client, err := newClient(store, domain, "")
 	if err != nil {
-		return fmt.Errorf("failed to read domain: %w", err)
+		return err
 	}
-	domain = strings.TrimSpace(domain)
+	client.AccessToken = accessToken
 
-	if domain == "" {
-		return fmt.Errorf("domain cannot be empty")
+	output.Info("Checking access token...")
+	me, err := client.VerifyCredentials()
+	if err != nil {
+		return fmt.Errorf("failed to log in with the access token: %w", err)
+	}
+
+	if err := store.Set("domain", domain); err != nil {
+		return fmt.Errorf("failed to save domain: %w", err)
+	}
+	if err := store.Set("access_token", accessToken); err != nil {
+		return fmt.Errorf("failed to save access token: %w", err)
+	}
+	// The token wasn't issued to an app tusk registered, so there's nothing
+	// to revoke it with on logout
+	store.Delete("client_id")
+	store.Delete("client_secret")
+	// Nor does the instance say what it's allowed to do
+	store.Delete(scopesKey)
+	store.SetAccount(client.BaseURL, me.ID)
+
+	output.Success("Logged in as @%s", me.Acct)
+	return nil
+}
+
+// callbackPort is the port for the browser to return to: --callback-port,
+// then the auth_callback_port setting, else 0 for a random one
+func callbackPort(store *config.Store) int {
+	if authCallbackPort != 0 {
+		return authCallbackPort
 	}
+	port, _ := strconv.Atoi(getSetting(store, "auth_callback_port"))
+	return port
+}
 
-	if !strings.HasPrefix(domain, "http://") && !strings.HasPrefix(domain, "https://") {
-		domain = "https://" + domain
+// loadAuthPage reads the auth_page template
+func loadAuthPage(path string) (*template.Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
+	return oauth.ParsePageTemplate(string(text))
+}
 
+// authenticate runs the OAuth flow against the instance at domain, asking
+// for scopes, and saves the new access token and the scopes it was granted
+func authenticate(store *config.Store, domain, scopes string) error {
 	output.Info("Starting OAuth flow...")
 
-	callbackServer, err := oauth.NewCallbackServer()
+	callbackServer, err := oauth.NewCallbackServer(callbackPort(store))
 	if err != nil {
 		return fmt.Errorf("failed to create callback server: %w", err)
 	}
+	defer callbackServer.Close()
+	if path := getSetting(store, "auth_page"); path != "" {
+		page, err := loadAuthPage(path)
+		if err != nil {
+			output.Warning("Using the built-in page after logging in: %v", err)
+		} else {
+			callbackServer.SetPageTemplate(page)
+		}
+	}
 
-	client := mastodon.NewClient(domain, "")
+	client, err := newClient(store, domain, "")
+	if err != nil {
+		return err
+	}
 	redirectURI := callbackServer.RedirectURI()
 
 	output.Info("Registering application...")
-	app, err := client.RegisterApp("Tusk CLI", redirectURI, "read write follow")
+	app, err := client.RegisterApp("Tusk CLI", redirectURI, scopes)
 	if err != nil {
 		return fmt.Errorf("failed to register app: %w", err)
 	}
@@ -85,7 +220,7 @@ func runAuth(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save client_secret: %w", err)
 	}
 
-	authURL := client.GetAuthorizationURL(app.ClientID, redirectURI, "read write follow")
+	authURL := client.GetAuthorizationURL(app.ClientID, redirectURI, scopes)
 
 	if err := callbackServer.Start(); err != nil {
 		return fmt.Errorf("failed to start callback server: %w", err)
@@ -101,20 +236,49 @@ func runAuth(cmd *cobra.Command, args []string) error {
 
 	output.Info("Waiting for authorization (timeout: 5 minutes)...")
 
-	code, err := callbackServer.WaitForCode(5 * time.Minute)
+	code, err := callbackServer.WaitForCode(runContext, 5*time.Minute)
+	var authErr *oauth.AuthorizationError
+	if errors.As(err, &authErr) {
+		switch {
+		case authErr.Denied():
+			return cancelledf("%s Run 'tusk auth' to try again.", authErr.Message())
+		case authErr.Code == "invalid_scope":
+			return invalidf("%s Asked for %q; try fewer with 'tusk auth --scopes'.", authErr.Message(), scopes)
+		}
+		return fmt.Errorf("failed to log in: %w", err)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get authorization code: %w", err)
 	}
 
 	output.Info("Exchanging code for access token...")
-	accessToken, err := client.GetAccessToken(app.ClientID, app.ClientSecret, redirectURI, code)
+	token, err := client.ExchangeCode(app.ClientID, app.ClientSecret, redirectURI, code)
 	if err != nil {
 		return fmt.Errorf("failed to get access token: %w", err)
 	}
+	accessToken := token.AccessToken
 
 	if err := store.Set("access_token", accessToken); err != nil {
 		return fmt.Errorf("failed to save access token: %w", err)
 	}
+	// Instances that leave the scopes out of the response grant what was
+	// asked for
+	granted := token.Scope
+	if granted == "" {
+		granted = scopes
+	}
+	if err := store.Set(scopesKey, granted); err != nil {
+		return fmt.Errorf("failed to save scopes: %w", err)
+	}
+
+	// Switch to the new account's post history. If it can't be looked up now,
+	// the next command that uses the login will do it.
+	client.AccessToken = accessToken
+	if me, err := client.VerifyCredentials(); err == nil {
+		store.SetAccount(client.BaseURL, me.ID)
+	} else {
+		store.Delete("account")
+	}
 
 	output.Success("Authentication successful!")
 	return nil
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// The banner's last result is cached in the config table under these keys
+const (
+	bannerMessageKey   = "banner_message"
+	bannerCheckedAtKey = "banner_checked_at"
+)
+
+// bannerTimeout bounds how long a refresh can hold up the command
+const bannerTimeout = 3 * time.Second
+
+// Commands the banner would be noise for, or would get in the way of
+var bannerSkipped = []string{"auth", "logout", "clear", "config", "daemon", "help", "completion", "docs"}
+
+// showBanner prints a one-line notice before a command when the account needs
+// attention. Checks are cached for the banner_interval setting, so most
+// commands only read the cache.
+func showBanner(cmd *cobra.Command) {
+	if replayPath != "" || recordPath != "" || instanceDomain != "" || cmd.Hidden {
+		return
+	}
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if containsString(bannerSkipped, c.Name()) {
+			return
+		}
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return
+	}
+	defer store.Close()
+
+	if getSetting(store, "banner") == "off" {
+		return
+	}
+	if token, _ := store.Get("access_token"); token == "" {
+		return
+	}
+
+	interval, _ := strconv.Atoi(getSetting(store, "banner_interval"))
+	checkedAt, _ := store.Get(bannerCheckedAtKey)
+	last, err := time.Parse(time.RFC3339, checkedAt)
+
+	message, _ := store.Get(bannerMessageKey)
+	if err != nil || time.Since(last) > time.Duration(interval)*time.Minute {
+		message = refreshBanner(store)
+	}
+
+	if message != "" {
+		output.Warning("%s", message)
+	}
+}
+
+// refreshBanner checks the account and caches the resulting message, which is
+// empty when nothing needs attention. Failed checks are skipped rather than
+// reported, since the banner is only advisory.
+func refreshBanner(store *config.Store) string {
+	domain, _ := store.Get("domain")
+	accessToken, _ := store.Get("access_token")
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return ""
+	}
+	client.HTTPClient.Timeout = bannerTimeout
+
+	var notices []string
+	if valid, err := client.TokenValid(); err == nil && !valid {
+		notices = append(notices, "your login has expired or was revoked; run 'tusk auth'")
+	} else if err == nil {
+		if announcements, err := client.GetAnnouncements(); err == nil {
+			unread := 0
+			for _, announcement := range announcements {
+				if !announcement.Read {
+					unread++
+				}
+			}
+			if unread > 0 {
+				notices = append(notices, pluralize(unread, "unread instance announcement"))
+			}
+		}
+
+		if requests, err := client.GetFollowRequests(40); err == nil && len(requests) > 0 {
+			notices = append(notices, pluralize(len(requests), "pending follow request"))
+		}
+	}
+
+	message := strings.Join(notices, "; ")
+	store.Set(bannerMessageKey, message)
+	store.Set(bannerCheckedAtKey, time.Now().Format(time.RFC3339))
+	return message
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
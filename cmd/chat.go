@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	chatEditor bool
+	chatDryRun bool
+)
+
+var chatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Pleroma/Akkoma chat messages",
+	Long: `List and send chat messages on Pleroma and Akkoma instances. Chats are separate
+from direct statuses there; on Mastodon use 'tusk dm' instead.
+
+Examples:
+  tusk chat list
+  tusk chat send @alice@example.com "Hey, got a minute?"
+  tusk chat send @alice@example.com -e`,
+}
+
+var chatListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your chats",
+	Args:  cobra.NoArgs,
+	RunE:  runChatList,
+}
+
+var chatSendCmd = &cobra.Command{
+	Use:         "send @user@instance [TEXT]",
+	Short:       "Send a chat message",
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: needsScopes("write"),
+	RunE:        runChatSend,
+}
+
+func init() {
+	chatSendCmd.Flags().BoolVarP(&chatEditor, "editor", "e", false, "Compose message in $EDITOR")
+	chatSendCmd.Flags().BoolVar(&chatDryRun, "dry-run", false, "Show what would be sent without actually sending")
+
+	chatCmd.AddCommand(chatListCmd)
+	chatCmd.AddCommand(chatSendCmd)
+}
+
+// newChatClient creates a client after checking the instance has the chats
+// API, so users get a clear error rather than a 404
+func newChatClient(store *config.Store) (*mastodon.Client, error) {
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return nil, errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Flavor != mastodon.FlavorUnknown {
+		if !client.Flavor.SupportsChats() {
+			return nil, fmt.Errorf("%s runs %s, which doesn't support chats. Use 'tusk dm' for direct messages", domain, client.Flavor)
+		}
+		return client, nil
+	}
+
+	instance, err := client.GetInstance()
+	if err != nil {
+		return nil, err
+	}
+	if !instance.SupportsChats() {
+		return nil, fmt.Errorf("%s doesn't support chats (version %s). Use 'tusk dm' for direct messages", domain, instance.Version)
+	}
+
+	return client, nil
+}
+
+func runChatList(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	client, err := newChatClient(store)
+	if err != nil {
+		return err
+	}
+
+	chats, err := client.GetChats()
+	if err != nil {
+		return err
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID", Detail: true},
+			{Key: "acct", Header: "ACCOUNT", Prefix: "@"},
+			{Key: "updated_at", Header: "UPDATED"},
+			{Key: "unread", Header: "UNREAD"},
+			{Key: "last_message", Header: "LAST MESSAGE", Width: 70},
+		},
+		Empty: "No chats.",
+	}
+
+	for _, chat := range chats {
+		acct := "unknown"
+		if chat.Account != nil {
+			acct = chat.Account.Acct
+		}
+		last := ""
+		if chat.LastMessage != nil {
+			last = stripHTML(chat.LastMessage.Content)
+		}
+		listing.Rows = append(listing.Rows, []any{chat.ID, acct, chat.UpdatedAt, chat.Unread, last})
+	}
+
+	listing.Plain = func(i int) {
+		row := listing.Rows[i]
+		unread := ""
+		if n := row[3].(int); n > 0 {
+			unread = fmt.Sprintf("  (%d unread)", n)
+		}
+		output.Plain("@%s  %s%s", row[1], chats[i].UpdatedAt.Local().Format("2006-01-02 15:04"), unread)
+
+		if chats[i].LastMessage != nil {
+			output.Plain("    %s", truncate(row[4].(string), 70))
+		}
+	}
+
+	return output.Render(listing)
+}
+
+func runChatSend(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	client, err := newChatClient(store)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimPrefix(args[0], "@") == "" {
+		return invalidf("recipient cannot be empty")
+	}
+
+	account, err := client.ResolveAccount(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find recipient %s: %w", args[0], err)
+	}
+
+	messageText, err := getStatusText(args[1:], chatEditor)
+	if err != nil {
+		return err
+	}
+
+	if messageText == "" {
+		return invalidf("message text cannot be empty")
+	}
+
+	if chatDryRun {
+		output.Info("Dry run mode - would send:")
+		output.Plain("To: @%s (%s)", account.Acct, account.URL)
+		output.Plain("Message: %s", messageText)
+		return nil
+	}
+
+	chat, err := client.GetOrCreateChat(account.ID)
+	if err != nil {
+		return err
+	}
+
+	output.Info("Sending message...")
+	if _, err := client.SendChatMessage(chat.ID, messageText); err != nil {
+		return err
+	}
+
+	output.Success("Message sent to @%s!", account.Acct)
+	return nil
+}
@@ -1,10 +1,7 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"strings"
 
 	"biesnecker.com/tusk/internal/config"
 	"biesnecker.com/tusk/internal/output"
@@ -32,15 +29,13 @@ func runClear(cmd *cobra.Command, args []string) error {
 	defer store.Close()
 
 	if !clearForce {
-		output.Prompt("Are you sure you want to clear the post history? (y/N): ")
-
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
-
-		if response != "y" && response != "yes" {
+		ok, err := confirm("--force", "Are you sure you want to clear the post history?")
+		if err != nil {
+			return err
+		}
+		if !ok {
 			output.Info("Clear cancelled.")
-			return nil
+			return errCancelled
 		}
 	}
 
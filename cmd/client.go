@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+var (
+	recordPath  string
+	replayPath  string
+	insecureTLS bool
+
+	// instanceDomain and instanceToken are set by --instance and --token, or
+	// TUSK_INSTANCE and TUSK_TOKEN, to use another login than the stored one
+	// for a single run. Without a token, only public data can be read.
+	instanceDomain string
+	instanceToken  string
+)
+
+// Environment variables for --instance and --token
+const (
+	instanceEnv = "TUSK_INSTANCE"
+	tokenEnv    = "TUSK_TOKEN"
+)
+
+// applyInstanceFlags fills in --instance and --token from the environment
+func applyInstanceFlags() error {
+	if instanceDomain == "" {
+		instanceDomain = os.Getenv(instanceEnv)
+	}
+	if instanceToken == "" {
+		instanceToken = os.Getenv(tokenEnv)
+	}
+	if instanceToken != "" && instanceDomain == "" {
+		return invalidf("--token needs --instance (or %s) to say which instance it's for", instanceEnv)
+	}
+	return nil
+}
+
+// overridingLogin reports whether --instance replaces the stored login
+func overridingLogin() bool {
+	return instanceDomain != ""
+}
+
+// credentials returns the instance and access token to use: those from
+// --instance and --token when given, and the stored login otherwise. The
+// stored token is never sent to another instance.
+func credentials(store *config.Store) (domain, accessToken string) {
+	if overridingLogin() {
+		return instanceBaseURL(instanceDomain), instanceToken
+	}
+	domain, _ = store.Get("domain")
+	accessToken, _ = store.Get("access_token")
+	return domain, accessToken
+}
+
+// newClient creates a Mastodon client, wired up to record or replay API
+// interactions when --record or --replay is set. Request timings are saved to
+// the store for 'tusk metrics', and the client adapts to the instance's
+// server software, unless store is nil.
+func newClient(store *config.Store, domain, accessToken string) (*mastodon.Client, error) {
+	if store == nil {
+		return setUpClient(nil, domain, accessToken, mastodon.TransportOptions{Insecure: insecureTLS})
+	}
+
+	client, err := setUpClient(store, domain, accessToken, mastodon.TransportOptions{
+		Insecure:   insecureTLS,
+		CAFile:     getSetting(store, "ca_bundle"),
+		SOCKSProxy: getSetting(store, "socks_proxy"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A cassette has to have the lookup in it when it's replayed without
+	// the cache
+	if accessToken != "" && replayPath == "" && recordPath == "" {
+		client.CacheAccountID(store)
+	}
+	if replayPath == "" && recordPath == "" && getSetting(store, "response_cache") == "on" {
+		client.CacheResponses(store)
+	}
+	client.Flavor = serverFlavor(store, client)
+	client.ContentType = getSetting(store, "content_type")
+	if overridingLogin() {
+		useOverrideAccount(store, client)
+	} else if accessToken != "" {
+		rememberAccount(store, client)
+	}
+	return client, nil
+}
+
+// useOverrideAccount points the post history at the account --token logs in
+// as, for this run only, so its posts aren't mixed into the stored login's
+func useOverrideAccount(store *config.Store, client *mastodon.Client) {
+	if client.AccessToken == "" || replayPath != "" {
+		return
+	}
+
+	accountID, err := client.AccountID()
+	if err != nil {
+		output.Debug("Failed to look up the --token account: %v", err)
+	}
+	// Without the ID, its posts are still kept out of the stored login's
+	// history
+	store.UseAccount(client.BaseURL, accountID)
+}
+
+// rememberAccount records whose post history the store holds, the first time
+// a login is used without it being known. Logins from older versions of tusk
+// didn't record it.
+func rememberAccount(store *config.Store, client *mastodon.Client) {
+	// A cassette won't have the request in it
+	if account, _ := store.Account(); account != "" || replayPath != "" {
+		return
+	}
+
+	accountID, err := client.AccountID()
+	if err != nil {
+		output.Debug("Failed to look up your account: %v", err)
+		return
+	}
+	if err := store.SetAccount(client.BaseURL, accountID); err != nil {
+		output.Debug("Failed to record your account: %v", err)
+	}
+}
+
+// setUpClient creates a client that connects with opts; see newClient
+func setUpClient(store *config.Store, domain, accessToken string, opts mastodon.TransportOptions) (*mastodon.Client, error) {
+	client := mastodon.NewClient(domain, accessToken)
+	if err := client.UseTransport(opts); err != nil {
+		return nil, err
+	}
+	client.UseContext(runContext)
+
+	if replayPath != "" {
+		if err := client.ReplayFrom(replayPath); err != nil {
+			return nil, fmt.Errorf("failed to load replay cassette: %w", err)
+		}
+	} else if recordPath != "" {
+		client.RecordTo(recordPath)
+	}
+
+	// Replayed requests don't say anything about the instance
+	if replayPath == "" && store != nil {
+		client.ObserveRequests(func(m mastodon.RequestMetric) {
+			store.RecordRequestMetric(m.Method, m.Endpoint, m.Status, m.Duration, m.Retry)
+		})
+	}
+
+	// The trace covers everything --verbose would say about requests
+	if debugLogger != nil {
+		client.TraceTo(debugLogger)
+	} else if output.Verbose() {
+		client.ObserveRequests(func(m mastodon.RequestMetric) {
+			output.Debug("%s %s -> %d (%s)", m.Method, m.Endpoint, m.Status, m.Duration.Round(time.Millisecond))
+		})
+	}
+
+	return client, nil
+}
+
+// instanceBaseURL turns a domain like mastodon.social into the instance's
+// base URL, leaving full URLs alone. Onion services default to plain HTTP,
+// since Tor already encrypts and authenticates the connection.
+func instanceBaseURL(domain string) string {
+	if strings.HasPrefix(domain, "http://") || strings.HasPrefix(domain, "https://") {
+		return domain
+	}
+	if mastodon.IsOnion(domain) {
+		return "http://" + domain
+	}
+	return "https://" + domain
+}
+
+// newReadClient creates a client for a command that only reads public data.
+// With --instance and no --token it talks to that instance without a login,
+// and its requests aren't counted in your instance's metrics; otherwise it
+// uses the login, as newClient does.
+func newReadClient(store *config.Store) (*mastodon.Client, error) {
+	if overridingLogin() && instanceToken == "" {
+		// The CA bundle is for your own instance, but the SOCKS proxy is for
+		// everything
+		opts := mastodon.TransportOptions{
+			Insecure:   insecureTLS,
+			SOCKSProxy: getSetting(store, "socks_proxy"),
+		}
+		return setUpClient(nil, instanceBaseURL(instanceDomain), "", opts)
+	}
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return nil, &codedError{code: exitAuth, err: errors.New("not authenticated. Run 'tusk auth' first, or pass --instance to browse an instance's public posts")}
+	}
+
+	return newClient(store, domain, accessToken)
+}
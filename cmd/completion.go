@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+// completionHistoryLimit is how many of your latest posts are offered when
+// completing a status ID
+const completionHistoryLimit = 30
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate a shell completion script",
+	Long: `Print a completion script for your shell. Besides commands and flags, it
+completes status IDs from your post history, accounts from statuses you've
+seen, visibilities, language codes, series names, and settings.
+
+Bash:
+  source <(tusk completion bash)
+  # or, to load it in every session:
+  tusk completion bash > /etc/bash_completion.d/tusk
+
+Zsh:
+  tusk completion zsh > "${fpath[1]}/_tusk"
+
+Fish:
+  tusk completion fish > ~/.config/fish/completions/tusk.fish
+
+PowerShell:
+  tusk completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
+
+// registerCompletions attaches the dynamic completions to commands and
+// flags. It runs from Execute, once every command's flags are defined.
+func registerCompletions() {
+	for _, cmd := range []*cobra.Command{editCmd, deleteCmd, redraftCmd, diffCmd, engagementCmd, pinCmd, unpinCmd, reactCmd, pollCmd, voteCmd} {
+		cmd.ValidArgsFunction = completeFirstArg(completeStatusIDs)
+	}
+	for _, cmd := range []*cobra.Command{dmCmd, chatSendCmd, whoisCmd, userCmd, relationshipCmd, noteCmd} {
+		cmd.ValidArgsFunction = completeFirstArg(completeAccounts)
+	}
+	followCmd.ValidArgsFunction = completeAccounts
+	seriesRemoveCmd.ValidArgsFunction = completeFirstArg(completeSeries)
+	configGetCmd.ValidArgsFunction = completeFirstArg(completeSettings)
+	configSetCmd.ValidArgsFunction = completeFirstArg(completeSettings)
+
+	for _, cmd := range []*cobra.Command{notifyCmd, pushSubscribeCmd} {
+		cmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions(mastodon.NotificationTypes, cobra.ShellCompDirectiveNoFileComp))
+	}
+	pushSubscribeCmd.RegisterFlagCompletionFunc("policy", cobra.FixedCompletions([]string{
+		mastodon.PushPolicyAll, mastodon.PushPolicyFollowed, mastodon.PushPolicyFollower, mastodon.PushPolicyNone,
+	}, cobra.ShellCompDirectiveNoFileComp))
+
+	for _, cmd := range []*cobra.Command{rootCmd, postCmd} {
+		cmd.RegisterFlagCompletionFunc("reply", completeStatusIDs)
+		cmd.RegisterFlagCompletionFunc("series", completeSeries)
+	}
+	for _, cmd := range []*cobra.Command{rootCmd, postCmd, editCmd, redraftCmd, profilesSetCmd} {
+		cmd.RegisterFlagCompletionFunc("visibility", completeVisibility)
+	}
+	for _, cmd := range []*cobra.Command{rootCmd, postCmd, editCmd, redraftCmd, dmCmd, profilesSetCmd} {
+		cmd.RegisterFlagCompletionFunc("lang", completeLanguages)
+	}
+}
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completeFirstArg completes only a command's first argument, leaving the
+// rest, such as status text, alone
+func completeFirstArg(complete completionFunc) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// completeStatusIDs offers your latest posts, described by their cached
+// text where there is one
+func completeStatusIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := config.NewStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer store.Close()
+
+	entries, err := store.ListPostHistory(completionHistoryLimit)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		if entry.Content == "" {
+			ids = append(ids, entry.StatusID)
+			continue
+		}
+		content := strings.Join(strings.Fields(stripHTML(entry.Content)), " ")
+		ids = append(ids, fmt.Sprintf("%s\t%s", entry.StatusID, truncate(content, 50)))
+	}
+	// The shell would sort them, so keep them newest first
+	return ids, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeAccounts offers the accounts of statuses in the local cache
+func completeAccounts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := config.NewStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer store.Close()
+
+	accounts, err := store.CachedAccounts()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, 0, len(accounts))
+	for _, acct := range accounts {
+		completions = append(completions, "@"+acct)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+func completeSeries(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := config.NewStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer store.Close()
+
+	list, err := store.ListSeries()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, series := range list {
+		names = append(names, series.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeSettings(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	for name, s := range settings {
+		names = append(names, name+"\t"+s.Description)
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeVisibility(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		"public\tVisible to everyone, in public timelines",
+		"unlisted\tVisible to everyone, but not in public timelines",
+		"private\tFollowers only",
+		"direct\tMentioned accounts only",
+	}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+func completeLanguages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return languageCodes, cobra.ShellCompDirectiveNoFileComp
+}
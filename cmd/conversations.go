@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	conversationsLimit  int
+	conversationsUnread bool
+	convReplyEditor     bool
+	convReplyDryRun     bool
+)
+
+var conversationsCmd = &cobra.Command{
+	Use:     "conversations",
+	Aliases: []string{"convos"},
+	Short:   "List your direct message conversations",
+	Long: `List your direct message conversations, most recently active first, with unread
+ones marked. Read a conversation to see the whole thread, or reply to its most
+recent message without looking up the status ID.
+
+Examples:
+  tusk conversations
+  tusk conversations --unread
+  tusk conversations read 12345
+  tusk conversations reply 12345 "Sounds good!"`,
+	Args: cobra.NoArgs,
+	RunE: runConversations,
+}
+
+var conversationsReadCmd = &cobra.Command{
+	Use:         "read ID",
+	Short:       "Show a conversation and mark it read",
+	Args:        cobra.ExactArgs(1),
+	Annotations: needsScopes("write:conversations"),
+	RunE:        runConversationsRead,
+}
+
+var conversationsReplyCmd = &cobra.Command{
+	Use:         "reply ID [TEXT]",
+	Short:       "Reply to the most recent message in a conversation",
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runConversationsReply,
+}
+
+func init() {
+	conversationsCmd.Flags().IntVarP(&conversationsLimit, "limit", "n", 20, "Number of conversations to list")
+	conversationsCmd.Flags().BoolVar(&conversationsUnread, "unread", false, "Only list unread conversations")
+
+	conversationsReplyCmd.Flags().BoolVarP(&convReplyEditor, "editor", "e", false, "Compose reply in $EDITOR")
+	conversationsReplyCmd.Flags().BoolVar(&convReplyDryRun, "dry-run", false, "Show what would be sent without actually sending")
+
+	conversationsCmd.AddCommand(conversationsReadCmd)
+	conversationsCmd.AddCommand(conversationsReplyCmd)
+}
+
+// conversationsLookback is how many conversations are searched for one given
+// by ID, since there's no endpoint to fetch a single conversation
+const conversationsLookback = 40
+
+func runConversations(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	conversations, err := client.GetConversations(conversationsLimit)
+	if err != nil {
+		return err
+	}
+
+	if conversationsUnread {
+		var unread []*mastodon.Conversation
+		for _, conversation := range conversations {
+			if conversation.Unread {
+				unread = append(unread, conversation)
+			}
+		}
+		conversations = unread
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID"},
+			{Key: "unread", Header: "UNREAD"},
+			{Key: "with", Header: "WITH"},
+			{Key: "updated_at", Header: "UPDATED"},
+			{Key: "last_status_id", Header: "LAST STATUS", Detail: true},
+			{Key: "last_message", Header: "LAST MESSAGE", Width: 70},
+		},
+		Empty: "No conversations.",
+	}
+
+	for _, conversation := range conversations {
+		var lastID, last string
+		var updated any
+		if status := conversation.LastStatus; status != nil {
+			lastID, last, updated = status.ID, stripHTML(status.Content), status.CreatedAt
+		}
+		listing.Rows = append(listing.Rows, []any{
+			conversation.ID, conversation.Unread, conversationAccounts(conversation), updated, lastID, last,
+		})
+	}
+
+	listing.Plain = func(i int) {
+		conversation := conversations[i]
+		marker := " "
+		if conversation.Unread {
+			marker = "*"
+		}
+
+		if status := conversation.LastStatus; status != nil {
+			output.Plain("%s %s  %s  %s", marker, conversation.ID, conversationAccounts(conversation),
+				status.CreatedAt.Local().Format("2006-01-02 15:04"))
+			output.Plain("    @%s: %s", statusAuthor(status),
+				output.Fold(status.SpoilerText, truncate(stripHTML(status.Content), 70), false))
+		} else {
+			output.Plain("%s %s  %s", marker, conversation.ID, conversationAccounts(conversation))
+		}
+	}
+
+	return output.Render(listing)
+}
+
+func runConversationsRead(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	conversation, err := findConversation(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	context, err := client.GetStatusContext(conversation.LastStatus.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation thread: %w", err)
+	}
+
+	// Everything up to the latest message, oldest first
+	thread := append(context.Ancestors, conversation.LastStatus)
+
+	output.Plain("Conversation with %s", conversationAccounts(conversation))
+	for _, status := range thread {
+		output.Plain("")
+		output.Plain("@%s  %s", statusAuthor(status), status.CreatedAt.Local().Format("2006-01-02 15:04"))
+		output.Plain("%s", output.Highlight(output.Fold(status.SpoilerText, htmlToText(status.Content), true)))
+	}
+
+	if conversation.Unread {
+		if _, err := client.MarkConversationRead(conversation.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runConversationsReply(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	conversation, err := findConversation(client, args[0])
+	if err != nil {
+		return err
+	}
+	target := conversation.LastStatus
+
+	replyText, err := getStatusText(args[1:], convReplyEditor)
+	if err != nil {
+		return err
+	}
+
+	if replyText == "" {
+		return invalidf("reply text cannot be empty")
+	}
+
+	me, err := client.VerifyCredentials()
+	if err != nil {
+		return err
+	}
+
+	// Everyone in the conversation has to be mentioned to keep them in it
+	statusText, notified := addReplyMentions(replyText, me, target, true)
+
+	params := mastodon.StatusParams{
+		Status:      statusText,
+		InReplyToID: target.ID,
+		Visibility:  mastodon.VisibilityDirect,
+		SpoilerText: replySpoilerText(target.SpoilerText),
+		Language:    target.Language,
+
+		IdempotencyKey: mastodon.NewIdempotencyKey(),
+	}
+
+	if convReplyDryRun {
+		output.Info("Dry run mode - would send:")
+		output.Plain("In reply to: @%s: %s", statusAuthor(target), truncate(stripHTML(target.Content), 70))
+		output.Plain("Status: %s", statusText)
+		output.Plain("Visibility: direct")
+		if params.SpoilerText != "" {
+			output.Plain("Content warning: %s", params.SpoilerText)
+		}
+		return nil
+	}
+
+	output.Info("Sending reply...")
+	status, err := client.PostStatus(params)
+	if err != nil {
+		return fmt.Errorf("failed to send reply: %w", err)
+	}
+
+	if err := store.AddPostToHistory(status.ID); err != nil {
+		output.Error("Failed to save post to history: %v", err)
+	}
+
+	// Replying means it's been read
+	if conversation.Unread {
+		if _, err := client.MarkConversationRead(conversation.ID); err != nil {
+			output.Warning("Failed to mark conversation read: %v", err)
+		}
+	}
+
+	if len(notified) > 0 {
+		output.Success("Reply sent to %s!", formatMentions(notified))
+	} else {
+		output.Success("Reply sent!")
+	}
+	output.Result(status.ID, status.URL)
+	runHook(store, hookPost, status)
+
+	return nil
+}
+
+// findConversation looks up one of your recent conversations by ID
+func findConversation(client *mastodon.Client, id string) (*mastodon.Conversation, error) {
+	conversations, err := client.GetConversations(conversationsLookback)
+	if err != nil {
+		return nil, err
+	}
+
+	conversation := mastodon.FindConversation(conversations, id)
+	if conversation == nil {
+		return nil, fmt.Errorf("no conversation %s among your %d most recent. Run 'tusk conversations' to list them", id, conversationsLookback)
+	}
+	if conversation.LastStatus == nil {
+		return nil, fmt.Errorf("conversation %s has no messages", id)
+	}
+	return conversation, nil
+}
+
+// conversationAccounts lists the other people in a conversation
+func conversationAccounts(conversation *mastodon.Conversation) string {
+	accts := make([]string, 0, len(conversation.Accounts))
+	for _, account := range conversation.Accounts {
+		accts = append(accts, "@"+account.Acct)
+	}
+	if len(accts) == 0 {
+		return "yourself"
+	}
+	return strings.Join(accts, ", ")
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+)
+
+// Listing commands save the position after the last page they showed under a
+// cursor named for the command, so --continue can pick up from there. What a
+// cursor holds (a status ID, a page URL) is up to the command.
+
+// loadCursor returns the saved position for a listing command when resuming,
+// or "" to start from the beginning
+func loadCursor(store *config.Store, name string, resume bool) (string, error) {
+	if !resume {
+		return "", nil
+	}
+
+	value, err := store.GetCursor(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to load saved position: %w", err)
+	}
+	if value == "" {
+		output.Info("No saved position, starting from the beginning.")
+	}
+	return value, nil
+}
+
+// saveCursor records where a listing stopped. An empty value means the end
+// was reached, so the saved position is cleared and the next --continue
+// starts over.
+func saveCursor(store *config.Store, name, value string) {
+	var err error
+	if value == "" {
+		err = store.ClearCursor(name)
+	} else {
+		err = store.SetCursor(name, value)
+	}
+	if err != nil {
+		output.Error("Failed to save position: %v", err)
+	}
+}
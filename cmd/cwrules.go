@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var cwRulesCmd = &cobra.Command{
+	Use:   "cw-rules",
+	Short: "Manage automatic content warning rules",
+	Long: `Put a content warning on posts whose text matches a regular expression.
+Patterns ignore case. When several rules match, their warnings are joined.
+
+Rules only apply when a post has no content warning of its own, from -w,
+frontmatter, or a hashtag profile. Pass --no-auto-cw to post without them.
+
+Examples:
+  tusk cw-rules add 'politic|election' politics
+  tusk cw-rules add '\b(recipe|dinner|lunch)\b' food
+  tusk cw-rules
+  tusk cw-rules remove 'politic|election'`,
+	Args: cobra.NoArgs,
+	RunE: runCWRulesList,
+}
+
+var cwRulesAddCmd = &cobra.Command{
+	Use:   "add PATTERN CW",
+	Short: "Add a rule, or change the warning of an existing one",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runCWRulesAdd,
+}
+
+var cwRulesRemoveCmd = &cobra.Command{
+	Use:   "remove PATTERN",
+	Short: "Remove a rule",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCWRulesRemove,
+}
+
+func init() {
+	cwRulesCmd.AddCommand(cwRulesAddCmd)
+	cwRulesCmd.AddCommand(cwRulesRemoveCmd)
+}
+
+func runCWRulesList(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	rules, err := store.ListCWRules()
+	if err != nil {
+		return fmt.Errorf("failed to read CW rules: %w", err)
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "pattern", Header: "PATTERN"},
+			{Key: "spoiler_text", Header: "CW"},
+		},
+		Empty:   "No CW rules. Add one with 'tusk cw-rules add PATTERN CW'.",
+		Tabular: true,
+	}
+
+	for _, rule := range rules {
+		listing.Rows = append(listing.Rows, []any{rule.Pattern, rule.SpoilerText})
+	}
+
+	return output.Render(listing)
+}
+
+func runCWRulesAdd(cmd *cobra.Command, args []string) error {
+	rule := config.CWRule{Pattern: args[0], SpoilerText: strings.TrimSpace(args[1])}
+	if _, err := regexp.Compile(rule.Pattern); err != nil {
+		return invalidf("invalid pattern: %v", err)
+	}
+	if rule.SpoilerText == "" {
+		return invalidf("content warning cannot be empty")
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.SetCWRule(rule); err != nil {
+		return fmt.Errorf("failed to save CW rule: %w", err)
+	}
+
+	output.Success("Posts matching /%s/ will get CW %q", rule.Pattern, rule.SpoilerText)
+	return nil
+}
+
+func runCWRulesRemove(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	removed, err := store.RemoveCWRule(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to remove CW rule: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("no CW rule with pattern %q. See 'tusk cw-rules'", args[0])
+	}
+
+	output.Success("CW rule removed for /%s/", args[0])
+	return nil
+}
+
+func compileCWRule(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// applyCWRules fills in the content warning from the rules whose patterns
+// match text, if the post doesn't have one already. It returns the
+// resulting settings and a description of each rule that took effect.
+func applyCWRules(store *config.Store, text string, settings postSettings) (postSettings, []string, error) {
+	if settings.SpoilerText != "" {
+		return settings, nil, nil
+	}
+
+	rules, err := store.ListCWRules()
+	if err != nil {
+		return settings, nil, fmt.Errorf("failed to load CW rules: %w", err)
+	}
+
+	var applied []string
+	var warnings []string
+	for _, rule := range rules {
+		re, err := compileCWRule(rule.Pattern)
+		if err != nil {
+			output.Error("Skipping CW rule /%s/: %v", rule.Pattern, err)
+			continue
+		}
+		if !re.MatchString(text) || containsString(warnings, rule.SpoilerText) {
+			continue
+		}
+		warnings = append(warnings, rule.SpoilerText)
+		applied = append(applied, fmt.Sprintf("/%s/ → CW %q", rule.Pattern, rule.SpoilerText))
+	}
+
+	settings.SpoilerText = strings.Join(warnings, ", ")
+	return settings, applied, nil
+}
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/daemon"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/internal/webpush"
+	"github.com/spf13/cobra"
+)
+
+// daemonLockFile sits next to the database, so there's one daemon per account
+const daemonLockFile = "daemon.lock"
+
+// daemonPollInterval is how often a worker that stays running checks the job
+// queue, scheduled reboosts, and whether it's still subscribed to pushes
+const daemonPollInterval = 5 * time.Second
+
+// daemonStopTimeout is how long 'tusk daemon stop' waits, which should cover
+// finishing an upload in progress
+const daemonStopTimeout = 30 * time.Second
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Manage the background worker",
+	Long: `Manage the background worker that sends posts queued with --async, receives
+push notifications (see 'tusk push'), and boosts posts again on schedule (see
+'tusk reboost').
+
+Only one worker runs at a time, so a post is never sent twice. It starts when
+a post is queued and exits once the queue is empty, unless it's subscribed to
+push notifications or has reboosts scheduled, in which case it keeps running
+until stopped or there's nothing left for it to do.`,
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the background worker is running",
+	Args:  cobra.NoArgs,
+	RunE:  runDaemonStatus,
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the background worker after its current job",
+	Args:  cobra.NoArgs,
+	RunE:  runDaemonStop,
+}
+
+var daemonRunCmd = &cobra.Command{
+	Use:    "run",
+	Short:  "Run the background worker in the foreground",
+	Args:   cobra.NoArgs,
+	Hidden: true,
+	RunE:   runDaemonRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonStatusCmd)
+	daemonCmd.AddCommand(daemonStopCmd)
+	daemonCmd.AddCommand(daemonRunCmd)
+}
+
+// startDaemon launches "tusk daemon run" without waiting for it. If a worker
+// is already running, the new one exits straight away and the running one
+// picks the job up.
+func startDaemon() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	worker := exec.Command(exe, "daemon", "run")
+	daemon.Detach(worker)
+	if err := worker.Start(); err != nil {
+		return err
+	}
+	return worker.Process.Release()
+}
+
+func runDaemonStatus(cmd *cobra.Command, args []string) error {
+	lockPath, err := config.DataPath(daemonLockFile)
+	if err != nil {
+		return err
+	}
+
+	info, err := daemon.Status(lockPath)
+	if err != nil {
+		return err
+	}
+
+	if info == nil {
+		output.Info("The background worker is not running.")
+		return nil
+	}
+
+	output.Success("The background worker is running (PID %d, since %s)", info.PID, info.StartedAt.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+func runDaemonStop(cmd *cobra.Command, args []string) error {
+	lockPath, err := config.DataPath(daemonLockFile)
+	if err != nil {
+		return err
+	}
+
+	info, err := daemon.Stop(lockPath, daemonStopTimeout)
+	if err != nil {
+		return err
+	}
+
+	if info == nil {
+		output.Info("The background worker is not running.")
+		return nil
+	}
+
+	output.Success("Stopped the background worker (PID %d)", info.PID)
+	return nil
+}
+
+func runDaemonRun(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	lockPath, err := config.DataPath(daemonLockFile)
+	if err != nil {
+		return err
+	}
+	domain, _ := store.Get("domain")
+
+	// Finish the current job before exiting when asked to stop
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	for {
+		lock, err := daemon.Acquire(lockPath, domain)
+		var running *daemon.RunningError
+		if errors.As(err, &running) {
+			// The running worker takes care of the queue
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var stopped bool
+		if resident(store) {
+			stopped = runResident(store, stop)
+		} else {
+			stopped = drainJobs(store, stop)
+		}
+		lock.Release()
+		if stopped {
+			return nil
+		}
+
+		// A job queued while the lock was being released would otherwise be
+		// missed: its own worker found the lock still held and exited. A new
+		// push subscription needs listening for with its keys.
+		next, err := store.NextPendingJob()
+		if err != nil {
+			return err
+		}
+		if next == nil && !resident(store) {
+			return nil
+		}
+	}
+}
+
+// drainJobs runs pending jobs, oldest first, until there are none left or a
+// stop signal arrives. It reports whether it was stopped.
+func drainJobs(store *config.Store, stop <-chan os.Signal) bool {
+	for {
+		select {
+		case <-stop:
+			return true
+		default:
+		}
+
+		job, err := store.NextPendingJob()
+		if err != nil {
+			output.Error("Failed to read the job queue: %v", err)
+			return false
+		}
+		if job == nil {
+			return false
+		}
+
+		if err := runJob(store, job); err != nil {
+			output.Error("Job #%d: %v", job.ID, err)
+		}
+	}
+}
+
+// resident reports whether the worker has more to do than empty the queue,
+// and so keeps running: receiving pushes, or boosting posts again on schedule
+func resident(store *config.Store) bool {
+	return loadPushKeys(store) != nil || hasReboosts(store)
+}
+
+// runResident runs queued jobs as they arrive, raises pushes received on the
+// push_listen setting while subscribed, and boosts posts again as they come
+// due. It returns once the push subscription is replaced, or there's nothing
+// left for it to do, and the queue is empty, or when a stop signal arrives,
+// and reports whether it was stopped.
+func runResident(store *config.Store, stop <-chan os.Signal) bool {
+	keys := loadPushKeys(store)
+
+	// pushes stays nil, and so never ready, until the receiver is listening.
+	// If it can't listen yet, it tries again on each tick.
+	var pushes <-chan []byte
+	var stopReceiver func()
+	var listenErr error
+	listen := func() {
+		received, closeReceiver, err := receivePushes(store, keys)
+		if err != nil {
+			if listenErr == nil || err.Error() != listenErr.Error() {
+				output.Error("%v", err)
+			}
+			listenErr = err
+			return
+		}
+		pushes = received
+		stopReceiver = closeReceiver
+	}
+	defer func() {
+		if stopReceiver != nil {
+			stopReceiver()
+		}
+	}()
+	if keys != nil {
+		listen()
+	}
+
+	ticker := time.NewTicker(daemonPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if drainJobs(store, stop) {
+			return true
+		}
+		runDueReboosts(store, time.Now())
+
+		select {
+		case <-stop:
+			return true
+		case message := <-pushes:
+			deliverPush(store, message)
+		case <-ticker.C:
+			current := loadPushKeys(store)
+			if !samePushKeys(keys, current) || (current == nil && !hasReboosts(store)) {
+				return drainJobs(store, stop)
+			}
+			if keys != nil && pushes == nil {
+				listen()
+			}
+		}
+	}
+}
+
+// receivePushes starts listening for pushes encrypted to keys on the
+// push_listen setting. Pushes are handed over on the channel rather than
+// raised in the server's goroutines, so they and the jobs take turns with
+// the store. Call the returned func to stop listening.
+func receivePushes(store *config.Store, keys *webpush.Keys) (<-chan []byte, func(), error) {
+	address := getSetting(store, "push_listen")
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen for push notifications on %s: %w", address, err)
+	}
+
+	pushes := make(chan []byte, 16)
+	server := &http.Server{
+		Handler: webpush.Handler(keys, func(message []byte) {
+			select {
+			case pushes <- message:
+			default:
+				output.Error("Dropped a push notification; too many are waiting")
+			}
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(listener)
+
+	output.Info("Receiving push notifications on %s", listener.Addr())
+	return pushes, func() { server.Close() }, nil
+}
+
+// samePushKeys reports whether two push subscriptions' keys, either of which
+// may be nil, are the same
+func samePushKeys(a, b *webpush.Keys) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Private() == b.Private()
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/daemon"
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var dbRestoreForce bool
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Back up or restore tusk's database",
+	Long: `Back up or restore tusk's database of post history, queued posts, and caches,
+together with your login and settings, for example to move them to another
+machine.
+
+Backups include your access token, so keep them somewhere private.
+
+Examples:
+  tusk db backup ~/tusk-backup.db
+  tusk db restore ~/tusk-backup.db`,
+}
+
+var dbBackupCmd = &cobra.Command{
+	Use:   "backup FILE",
+	Short: "Write a copy of the database to FILE",
+	Long: `Write a copy of the database to FILE, which must not exist yet. The copy is
+consistent even if the background worker is busy.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDBBackup,
+}
+
+var dbRestoreCmd = &cobra.Command{
+	Use:   "restore FILE",
+	Short: "Replace the database with a backup",
+	Long: `Replace the database with a backup made by 'tusk db backup'. The current
+database is kept beside it, so a restore can be undone by hand. Backups from
+older versions of tusk are brought up to date.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDBRestore,
+}
+
+func init() {
+	dbRestoreCmd.Flags().BoolVarP(&dbRestoreForce, "force", "f", false, "Skip confirmation")
+
+	dbCmd.AddCommand(dbBackupCmd)
+	dbCmd.AddCommand(dbRestoreCmd)
+}
+
+func runDBBackup(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.Backup(args[0]); err != nil {
+		return err
+	}
+
+	output.Success("Backed up the database to %s", args[0])
+	return nil
+}
+
+func runDBRestore(cmd *cobra.Command, args []string) error {
+	// The worker holds the database open and would keep writing to the old one
+	lockPath, err := config.DataPath(daemonLockFile)
+	if err != nil {
+		return err
+	}
+	info, err := daemon.Status(lockPath)
+	if err != nil {
+		return err
+	}
+	if info != nil {
+		return fmt.Errorf("the background worker is running (PID %d). Run 'tusk daemon stop' first", info.PID)
+	}
+
+	if !dbRestoreForce {
+		if !assumeYes && !isTerminal() {
+			return cancelledf("restoring replaces your login and history; use --force to confirm")
+		}
+
+		ok, err := confirm("--force", "Replace your login, history, and settings with %s?", args[0])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Info("Restore cancelled.")
+			return errCancelled
+		}
+	}
+
+	previousPath, err := config.Restore(args[0])
+	if err != nil {
+		return err
+	}
+
+	output.Success("Restored the database from %s", args[0])
+	if previousPath != "" {
+		output.Info("The previous database was saved as %s", previousPath)
+	}
+	return nil
+}
@@ -1,14 +1,16 @@
 package cmd
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
 	"strings"
+	"time"
 
 	"biesnecker.com/tusk/internal/config"
-	"biesnecker.com/tusk/internal/mastodon"
+	"biesnecker.com/tusk/internal/oauth"
 	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -18,20 +20,36 @@ var (
 	deleteLatest bool
 	deleteForce  bool
 	deleteTUI    bool
+	deleteDryRun bool
+	deleteThread bool
 )
 
 var deleteCmd = &cobra.Command{
 	Use:   "delete [ID]",
 	Short: "Delete a status",
-	Long:  `Delete a status by ID or delete your most recent post.`,
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runDelete,
+	Long: `Delete a status by ID or delete your most recent post.
+
+With --thread, the status starts a thread of your own, and it's deleted along
+with all your replies to it and to each other, newest first. Replies from
+others, and your answers to them, are left alone. Deletions are spaced a
+second apart; when the instance rate limits them, tusk waits until it allows
+more, or stops if that's more than half an hour away.
+
+Examples:
+  tusk delete 109876543210
+  tusk delete --latest
+  tusk delete --thread 109876543210`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runDelete,
 }
 
 func init() {
 	deleteCmd.Flags().BoolVarP(&deleteLatest, "latest", "l", false, "Delete the most recent post")
 	deleteCmd.Flags().BoolVarP(&deleteForce, "force", "f", false, "Skip confirmation")
 	deleteCmd.Flags().BoolVar(&deleteTUI, "tui", false, "Interactive TUI selection mode")
+	deleteCmd.Flags().BoolVar(&deleteDryRun, "dry-run", false, "Show what would be deleted without actually deleting")
+	deleteCmd.Flags().BoolVar(&deleteThread, "thread", false, "Delete the status and your whole thread of replies below it")
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
@@ -41,17 +59,22 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	}
 	defer store.Close()
 
-	domain, _ := store.Get("domain")
-	accessToken, _ := store.Get("access_token")
+	domain, accessToken := credentials(store)
 
 	if accessToken == "" {
-		return fmt.Errorf("not authenticated. Run 'tusk auth' first")
+		return errNotAuthenticated
 	}
 
-	client := mastodon.NewClient(domain, accessToken)
+	client, err := newAPI(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
 
 	// TUI mode
 	if deleteTUI {
+		if deleteThread {
+			return invalidf("--thread can't be used with --tui")
+		}
 		return runDeleteTUI(store, client)
 	}
 
@@ -67,125 +90,261 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		}
 		statusID = lastPostID
 	} else if len(args) == 1 {
-		statusID = args[0]
+		statusID, err = client.ResolveStatusID(args[0])
+		if err != nil {
+			return err
+		}
 	} else {
-		return fmt.Errorf("must provide status ID or use --latest flag")
+		return invalidf("must provide status ID or use --latest flag")
 	}
 
-	if !deleteForce {
-		output.Prompt("Are you sure you want to delete status %s? This cannot be undone. (y/N): ", statusID)
+	if deleteThread {
+		return deleteSelfThread(store, client, statusID)
+	}
 
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
+	if deleteDryRun {
+		status, err := client.GetStatus(statusID)
+		if err != nil {
+			return fmt.Errorf("failed to get status: %w", err)
+		}
+		output.Info("Dry run mode - would delete:")
+		printStatusSummary(status)
+		return nil
+	}
 
-		if response != "y" && response != "yes" {
+	if !deleteForce {
+		ok, err := confirm("--force", "Are you sure you want to delete status %s? This cannot be undone.", statusID)
+		if err != nil {
+			return err
+		}
+		if !ok {
 			output.Info("Deletion cancelled.")
-			return nil
+			return errCancelled
 		}
 	}
 
 	output.Info("Deleting status...")
-	if err := client.DeleteStatus(statusID); err != nil {
+	deleted, err := deleteStatus(store, client, statusID)
+	if err != nil {
 		return fmt.Errorf("failed to delete status: %w", err)
 	}
 
-	// Remove from post history
-	if err := store.RemovePostFromHistory(statusID); err != nil {
-		output.Error("Failed to remove post from history: %v", err)
+	if err := verifyDeleted(client, statusID); err != nil {
+		output.Warning("Status %s was deleted, but %v", statusID, err)
+	} else {
+		output.Success("Status deleted!")
 	}
-
-	output.Success("Status deleted!")
+	runHook(store, hookDelete, deleted)
 	return nil
 }
 
-// TUI model and methods
+// deleteSelfThread deletes the thread of the user's own statuses that starts
+// at rootID, replies before what they reply to, so no reply is left behind
+// pointing at a deleted status
+func deleteSelfThread(store *config.Store, client mastodonAPI, rootID string) error {
+	root, err := client.GetStatus(rootID)
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+	me, err := client.VerifyCredentials()
+	if err != nil {
+		return err
+	}
+	statusContext, err := client.GetStatusContext(rootID)
+	if err != nil {
+		return fmt.Errorf("failed to get thread: %w", err)
+	}
 
-type statusItem struct {
-	id       string
-	content  string
-	url      string
-	selected bool
-}
+	thread := mastodon.SelfThread(me.ID, root, statusContext.Descendants)
+	if thread == nil {
+		return invalidf("status %s isn't yours, so there's no thread of yours to delete", rootID)
+	}
 
-type deleteModel struct {
-	store    *config.Store
-	client   *mastodon.Client
-	statuses []statusItem
-	cursor   int
-	syncing  bool
-	err      error
-	quitting bool
+	if deleteDryRun {
+		output.Info("Dry run mode - would delete %d post(s), newest first:", len(thread))
+		for _, status := range thread {
+			output.Plain("%s  %s", status.ID, truncate(stripHTML(status.Content), 60))
+		}
+		return nil
+	}
+
+	if !deleteForce {
+		ok, err := confirm("--force", "Delete status %s and %d repl(ies) of yours below it? This cannot be undone.", rootID, len(thread)-1)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Info("Deletion cancelled.")
+			return errCancelled
+		}
+	}
+
+	var failed, unverified []string
+	for i, status := range thread {
+		if i > 0 && !pause(deleteThreadDelay) {
+			reportUnverified(unverified)
+			return deleteStopped(i, len(thread), runContext.Err())
+		}
+
+		output.Info("Deleting %d/%d: %s...", i+1, len(thread), status.ID)
+		var deleted *mastodon.Status
+		err := waitOutRateLimit(func() error {
+			var err error
+			deleted, err = deleteStatus(store, client, status.ID)
+			return err
+		})
+		if interrupted() || endsRun(err) {
+			// The rest would fail the same way; what's left is still a
+			// whole thread, since replies go first
+			reportUnverified(unverified)
+			return deleteStopped(i, len(thread), err)
+		}
+		if err != nil {
+			output.Error("Failed to delete status %s: %v", status.ID, err)
+			failed = append(failed, status.ID)
+			continue
+		}
+		if err := verifyDeleted(client, status.ID); err != nil {
+			unverified = append(unverified, status.ID)
+		}
+		runHook(store, hookDelete, deleted)
+	}
+
+	reportUnverified(unverified)
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to delete %d of %d post(s): %s", len(failed), len(thread), strings.Join(failed, ", "))
+	}
+	output.Success("Deleted the thread (%d post(s))!", len(thread))
+	return nil
 }
 
-type syncCompleteMsg struct {
-	err error
+// deleteStopped explains where deleting a thread stopped
+func deleteStopped(done, total int, err error) error {
+	output.Info("Stopped after %d of %d post(s). Run the same command again to delete the rest.", done, total)
+	return err
 }
 
-func loadStatuses(store *config.Store, client *mastodon.Client) ([]statusItem, error) {
-	statuses, err := client.GetAccountStatuses(50)
+// deleteThreadDelay spaces out the deletions of a thread, which instances
+// rate limit more tightly than most requests
+var deleteThreadDelay = time.Second
+
+// deleteCheckDelays are the waits before each check that a deleted status is
+// gone. An instance can go on serving it for a moment, so it's only reported
+// once every check has found it.
+var deleteCheckDelays = []time.Duration{0, time.Second, 3 * time.Second}
+
+// deleteStatus deletes a status and forgets it locally: its place in the post
+// history and its cached copy
+func deleteStatus(store *config.Store, client mastodonAPI, id string) (*mastodon.Status, error) {
+	deleted, err := client.DeleteStatus(id)
 	if err != nil {
 		return nil, err
 	}
 
-	items := make([]statusItem, 0, len(statuses))
-	for _, status := range statuses {
-		content := stripHTML(status.Content)
-		items = append(items, statusItem{
-			id:       status.ID,
-			content:  content,
-			url:      status.URL,
-			selected: false,
-		})
+	if err := store.RemovePostFromHistory(id); err != nil {
+		output.Error("Failed to remove post %s from history: %v", id, err)
 	}
+	if err := store.UncacheStatus(id); err != nil {
+		output.Error("Failed to remove post %s from the cache: %v", id, err)
+	}
+	return deleted, nil
+}
 
-	return items, nil
+// verifyDeleted checks that the instance no longer has a deleted status. The
+// error says why it couldn't be confirmed.
+func verifyDeleted(client mastodonAPI, id string) error {
+	var problem error
+	for _, delay := range deleteCheckDelays {
+		if !pause(delay) {
+			return runContext.Err()
+		}
+		_, err := client.GetStatus(id)
+		if apiErr, ok := mastodon.AsAPIError(err); ok && apiErr.NotFound() {
+			return nil
+		}
+		if err != nil {
+			problem = fmt.Errorf("checking that it's gone failed: %w", err)
+		} else {
+			problem = errors.New("the instance still shows it")
+		}
+	}
+	return problem
 }
 
-func initialModel(store *config.Store, client *mastodon.Client) deleteModel {
-	statuses, err := loadStatuses(store, client)
-	return deleteModel{
-		store:    store,
-		client:   client,
-		statuses: statuses,
-		cursor:   0,
-		err:      err,
+// printStatusSummary shows the parts of a status that deleting it would remove
+func printStatusSummary(status *mastodon.Status) {
+	output.Plain("ID: %s", status.ID)
+	output.URL(status.URL)
+	output.Plain("Content: %s", stripHTML(status.Content))
+	if status.SpoilerText != "" {
+		output.Plain("Content warning: %s", status.SpoilerText)
+	}
+	if len(status.MediaAttachments) > 0 {
+		output.Plain("Media: %d attachment(s)", len(status.MediaAttachments))
 	}
 }
 
-func (m deleteModel) Init() tea.Cmd {
-	return nil
+// TUI model and methods
+
+type deleteModel struct {
+	statusPicker
+
+	store    *config.Store
+	client   mastodonAPI
+	loading  bool
+	spinner  spinner.Model
+	err      error
+	quitting bool
+	message  string
 }
 
-func doSync(store *config.Store, client *mastodon.Client) tea.Cmd {
-	return func() tea.Msg {
-		_, err := loadStatuses(store, client)
-		return syncCompleteMsg{err: err}
+func initialModel(store *config.Store, client mastodonAPI) deleteModel {
+	return deleteModel{
+		store:   store,
+		client:  client,
+		loading: true,
+		spinner: newSpinner(),
 	}
 }
 
+func (m deleteModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, loadStatuses(m.store, m.client))
+}
+
 func (m deleteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case syncCompleteMsg:
-		m.syncing = false
+	case statusesLoadedMsg:
+		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
-			// Reload statuses
-			statuses, err := loadStatuses(m.store, m.client)
-			if err != nil {
-				m.err = err
-			} else {
-				m.statuses = statuses
-				m.cursor = 0
-			}
+			m.statuses = msg.items
+			m.cursor = 0
+			m.offset = 0
 		}
 		return m, nil
 
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.width = msg.Width
+
 	case tea.KeyMsg:
-		if m.syncing {
+		if m.loading {
+			if msg.String() == "ctrl+c" || msg.String() == "q" {
+				m.quitting = true
+				return m, tea.Quit
+			}
 			return m, nil
 		}
+		m.message = ""
 
 		switch msg.String() {
 		case "ctrl+c", "q":
@@ -207,10 +366,20 @@ func (m deleteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.statuses[m.cursor].selected = !m.statuses[m.cursor].selected
 			}
 
+		case "p":
+			m.preview = !m.preview
+
+		case "o":
+			if len(m.statuses) > 0 {
+				if err := oauth.OpenBrowser(m.statuses[m.cursor].url); err != nil {
+					m.message = fmt.Sprintf("Failed to open browser: %v", err)
+				}
+			}
+
 		case "s":
 			// Sync posts
-			m.syncing = true
-			return m, doSync(m.store, m.client)
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, loadStatuses(m.store, m.client))
 
 		case "d":
 			// Delete selected posts
@@ -236,6 +405,9 @@ func (m deleteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if len(m.statuses) > 0 {
+		m.offset = scrollOffset(m.offset, m.cursor, m.listHeight(), m.itemLines)
+	}
 	return m, nil
 }
 
@@ -248,8 +420,8 @@ func (m deleteModel) View() string {
 		return fmt.Sprintf("Error: %v\n\nPress q to quit.\n", m.err)
 	}
 
-	if m.syncing {
-		return "Syncing posts...\n"
+	if m.loading {
+		return fmt.Sprintf("%s Loading posts...\n", m.spinner.View())
 	}
 
 	var b strings.Builder
@@ -261,7 +433,7 @@ func (m deleteModel) View() string {
 
 	// Instructions
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	b.WriteString(helpStyle.Render("↑/k: up  ↓/j: down  space: toggle  s: sync  d: delete  q: quit"))
+	b.WriteString(helpStyle.Render("↑/k: up  ↓/j: down  space: toggle  o: open  p: preview  s: sync  d: delete  q: quit"))
 	b.WriteString("\n\n")
 
 	if len(m.statuses) == 0 {
@@ -274,7 +446,9 @@ func (m deleteModel) View() string {
 	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
 	normalStyle := lipgloss.NewStyle()
 
-	for i, status := range m.statuses {
+	end := visibleItems(m.offset, len(m.statuses), m.listHeight(), m.itemLines)
+	for i := m.offset; i < end; i++ {
+		status := m.statuses[i]
 		cursor := " "
 		if m.cursor == i {
 			cursor = ">"
@@ -299,13 +473,38 @@ func (m deleteModel) View() string {
 
 		b.WriteString(line)
 		b.WriteString("\n")
+
+		if status.replyLine != "" {
+			b.WriteString(helpStyle.Render("    " + status.replyLine))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.offset > 0 || end < len(m.statuses) {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  %d–%d of %d", m.offset+1, end, len(m.statuses))))
+		b.WriteString("\n")
+	}
+
+	if m.preview {
+		b.WriteString("\n")
+		b.WriteString(renderPreview(m.highlighted(), m.width))
+	}
+
+	if m.message != "" {
+		b.WriteString("\n")
+		b.WriteString(m.message)
+		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
-func runDeleteTUI(store *config.Store, client *mastodon.Client) error {
-	p := tea.NewProgram(initialModel(store, client))
+func runDeleteTUI(store *config.Store, client mastodonAPI) error {
+	if err := requireInteractive("--tui"); err != nil {
+		return err
+	}
+
+	p := tea.NewProgram(initialModel(store, client), tea.WithContext(runContext))
 	finalModel, err := p.Run()
 	if err != nil {
 		return fmt.Errorf("error running TUI: %w", err)
@@ -329,35 +528,57 @@ func runDeleteTUI(store *config.Store, client *mastodon.Client) error {
 		return nil
 	}
 
-	// Final confirmation
-	output.Prompt("Delete %d post(s)? This cannot be undone. (y/N): ", len(selectedIDs))
-
-	reader := bufio.NewReader(os.Stdin)
-	response, _ := reader.ReadString('\n')
-	response = strings.TrimSpace(strings.ToLower(response))
+	if deleteDryRun {
+		output.Info("Dry run mode - would delete %d post(s):", len(selectedIDs))
+		for _, status := range m.statuses {
+			if status.selected {
+				output.Plain("%s  %s", status.id, truncate(status.content, 60))
+			}
+		}
+		return nil
+	}
 
-	if response != "y" && response != "yes" {
+	// Final confirmation
+	ok, err := confirm("", "Delete %d post(s)? This cannot be undone.", len(selectedIDs))
+	if err != nil {
+		return err
+	}
+	if !ok {
 		output.Info("Deletion cancelled.")
-		return nil
+		return errCancelled
 	}
 
 	// Delete posts
 	deletedCount := 0
-	for _, id := range selectedIDs {
+	var unverified []string
+	for i, id := range selectedIDs {
+		if interrupted() {
+			reportUnverified(unverified)
+			return deleteStopped(i, len(selectedIDs), runContext.Err())
+		}
+
 		output.Info("Deleting status %s...", id)
-		if err := client.DeleteStatus(id); err != nil {
+		deleted, err := deleteStatus(store, client, id)
+		if err != nil {
 			output.Error("Failed to delete status %s: %v", id, err)
 			continue
 		}
-
-		// Remove from post history
-		if err := store.RemovePostFromHistory(id); err != nil {
-			output.Error("Failed to remove post %s from history: %v", id, err)
+		if err := verifyDeleted(client, id); err != nil {
+			unverified = append(unverified, id)
 		}
 
 		deletedCount++
+		runHook(store, hookDelete, deleted)
 	}
 
 	output.Success("Deleted %d post(s)!", deletedCount)
+	reportUnverified(unverified)
 	return nil
 }
+
+// reportUnverified lists deleted statuses the instance still seemed to have
+func reportUnverified(ids []string) {
+	if len(ids) > 0 {
+		output.Warning("Couldn't confirm %d post(s) are gone; check them again shortly: %s", len(ids), strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/diff"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffArchive string
+	diffUpdate  bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [ID]",
+	Short: "Compare a local copy of a status with the server",
+	Long: `Compare the locally cached (or archived) version of a status with what's on the
+server now, highlighting edits made from other clients. Defaults to your latest post.
+
+Examples:
+  tusk diff
+  tusk diff STATUS_ID
+  tusk diff STATUS_ID --archive archive/
+  tusk diff --update`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffArchive, "archive", "", "Compare against an export archive directory instead of the cache")
+	diffCmd.Flags().BoolVar(&diffUpdate, "update", false, "Refresh the cached copy with the server version afterwards")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	var statusID string
+	if len(args) == 1 {
+		statusID, err = client.ResolveStatusID(args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		statusID, err = store.GetLastPostID()
+		if err != nil {
+			return fmt.Errorf("failed to get last post ID: %w", err)
+		}
+		if statusID == "" {
+			return fmt.Errorf("no posts in history")
+		}
+	}
+
+	var local *mastodon.Status
+	var source string
+	if diffArchive != "" {
+		statuses, err := readExportRaw(filepath.Join(diffArchive, exportRawFile))
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if status.ID == statusID {
+				local = status
+				break
+			}
+		}
+		source = "archive"
+	} else {
+		cached, err := store.GetCachedStatus(statusID)
+		if err != nil {
+			return fmt.Errorf("failed to read cache: %w", err)
+		}
+		if cached != nil {
+			local = &mastodon.Status{ID: cached.StatusID, Content: cached.Content}
+		}
+		source = "cache"
+	}
+	if local == nil {
+		return fmt.Errorf("status %s is not in the local %s", statusID, source)
+	}
+
+	remote, err := client.GetStatus(statusID)
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+
+	localText := htmlToText(local.Content)
+	remoteText := htmlToText(remote.Content)
+
+	changed := false
+	output.Info("Comparing %s copy of %s with the server:", source, statusID)
+	if diff.Changed(diff.Lines(localText, remoteText)) {
+		changed = true
+		output.Plain("Content:")
+		printDiff(source, "server", localText, remoteText)
+	}
+
+	// Only archives keep these fields
+	if diffArchive != "" {
+		if local.SpoilerText != remote.SpoilerText {
+			changed = true
+			printChange("Content warning", local.SpoilerText, remote.SpoilerText)
+		}
+		if local.Visibility != remote.Visibility {
+			changed = true
+			printChange("Visibility", local.Visibility.String(), remote.Visibility.String())
+		}
+		if len(local.MediaAttachments) != len(remote.MediaAttachments) {
+			changed = true
+			printChange("Media attachments", fmt.Sprint(len(local.MediaAttachments)), fmt.Sprint(len(remote.MediaAttachments)))
+		}
+	}
+
+	if !changed {
+		output.Success("No differences.")
+	}
+
+	if diffUpdate {
+		cacheStatuses(store, []*mastodon.Status{remote})
+		output.Info("Cache updated.")
+	}
+
+	return nil
+}
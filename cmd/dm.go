@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dmEditor      bool
+	dmContentWarn string
+	dmLanguage    string
+	dmDryRun      bool
+)
+
+var dmCmd = &cobra.Command{
+	Use:   "dm @user@instance [TEXT]",
+	Short: "Send a direct message",
+	Long: `Send a direct message to a single account, given as a handle or a link to their
+profile. The recipient is looked up before sending, the mention is prepended
+automatically, and visibility is always direct.
+
+Examples:
+  tusk dm @alice@example.com "Hey, got a minute?"
+  tusk dm alice@example.com -e
+  tusk dm https://example.com/@alice "Hi!"
+  echo "Hello" | tusk dm @alice@example.com`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runDM,
+}
+
+func init() {
+	dmCmd.Flags().BoolVarP(&dmEditor, "editor", "e", false, "Compose message in $EDITOR")
+	dmCmd.Flags().StringVarP(&dmContentWarn, "cw", "w", "", "Content warning / spoiler text")
+	dmCmd.Flags().StringVarP(&dmLanguage, "lang", "l", "", "ISO 639 language code (e.g., en, es, fr, de, ja)")
+	dmCmd.Flags().BoolVar(&dmDryRun, "dry-run", false, "Show what would be sent without actually sending")
+}
+
+func runDM(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	recipient := strings.TrimPrefix(strings.TrimPrefix(args[0], "acct:"), "@")
+	if recipient == "" {
+		return invalidf("recipient cannot be empty")
+	}
+
+	// Make sure the recipient exists before composing anything
+	account, err := client.ResolveAccount(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find recipient %s: %w", args[0], err)
+	}
+	if strings.Contains(recipient, "://") {
+		recipient = account.Acct
+	}
+
+	messageText, err := getStatusText(args[1:], dmEditor)
+	if err != nil {
+		return err
+	}
+
+	if messageText == "" {
+		return invalidf("message text cannot be empty")
+	}
+
+	// Any other mention in the body would also receive the message
+	mentions := extractMentions(messageText)
+	var extra []string
+	for _, mention := range mentions {
+		if !sameAccount(mention, account.Acct, recipient) {
+			extra = append(extra, "@"+mention)
+		}
+	}
+	if len(extra) > 0 {
+		ok, err := confirm("", "Warning: %s will also receive this message. Continue?", strings.Join(extra, ", "))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Info("Message cancelled.")
+			return errCancelled
+		}
+	}
+
+	// Don't double up the mention if the message already starts with it
+	statusText := "@" + recipient + " " + messageText
+	if strings.HasPrefix(messageText, "@") && len(mentions) > 0 && sameAccount(mentions[0], account.Acct, recipient) {
+		statusText = messageText
+	}
+
+	params := mastodon.StatusParams{
+		Status:      statusText,
+		Visibility:  mastodon.VisibilityDirect,
+		SpoilerText: dmContentWarn,
+		Language:    dmLanguage,
+
+		IdempotencyKey: mastodon.NewIdempotencyKey(),
+	}
+
+	if dmDryRun {
+		output.Info("Dry run mode - would send:")
+		output.Plain("To: @%s (%s)", account.Acct, account.URL)
+		output.Plain("Status: %s", statusText)
+		output.Plain("Visibility: direct")
+		if dmContentWarn != "" {
+			output.Plain("Content warning: %s", dmContentWarn)
+		}
+		if dmLanguage != "" {
+			output.Plain("Language: %s", dmLanguage)
+		}
+		return nil
+	}
+
+	output.Info("Sending direct message...")
+	status, err := client.PostStatus(params)
+	if err != nil {
+		return fmt.Errorf("failed to send direct message: %w", err)
+	}
+
+	if err := store.AddPostToHistory(status.ID); err != nil {
+		output.Error("Failed to save post to history: %v", err)
+	}
+
+	output.Success("Direct message sent to @%s!", account.Acct)
+	output.Result(status.ID, status.URL)
+	runHook(store, hookPost, status)
+
+	return nil
+}
+
+// sameAccount reports whether a mention refers to the looked-up recipient.
+// Local accounts come back from the server without a domain, so a bare
+// username matches too.
+func sameAccount(mention, acct, recipient string) bool {
+	mention = strings.ToLower(mention)
+	return mention == strings.ToLower(acct) || mention == strings.ToLower(recipient)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Reference documentation for scripting tusk",
+}
+
+var docsExitCodesCmd = &cobra.Command{
+	Use:   "exit-codes",
+	Short: "List the codes tusk exits with",
+	Long: `List the codes tusk exits with, so scripts can tell failures apart. The
+codes won't change meaning between releases.
+
+Examples:
+  tusk docs exit-codes
+  tusk docs exit-codes --output json`,
+	Args: cobra.NoArgs,
+	RunE: runDocsExitCodes,
+}
+
+func init() {
+	docsCmd.AddCommand(docsExitCodesCmd)
+}
+
+func runDocsExitCodes(cmd *cobra.Command, args []string) error {
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "code", Header: "CODE"},
+			{Key: "name", Header: "NAME"},
+			{Key: "meaning", Header: "MEANING", Width: 80},
+		},
+		Tabular: true,
+	}
+	for _, c := range exitCodes {
+		listing.Rows = append(listing.Rows, []any{c.Code, c.Name, c.Meaning})
+	}
+	return output.Render(listing)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+)
+
+// How the duplicate_check setting handles a post with the same text as a
+// recent one
+const (
+	duplicateAsk   = "ask"
+	duplicateAbort = "abort"
+	duplicateOff   = "off"
+)
+
+// remoteMention matches the domain of a mention, which the instance leaves
+// out of the status HTML
+var remoteMention = regexp.MustCompile(`(@\w+)@[\w.-]+\w`)
+
+// checkDuplicate guards against posting text that's the same as one of your
+// recent posts, as a retried script or a repeated shell command would. It
+// asks or refuses according to the duplicate_check setting, and returns
+// errCancelled if the post shouldn't go ahead.
+func checkDuplicate(store *config.Store, text string) error {
+	mode := getSetting(store, "duplicate_check")
+	if mode == duplicateOff || allowDuplicate {
+		return nil
+	}
+	limit, _ := strconv.Atoi(getSetting(store, "duplicate_check_posts"))
+
+	duplicate, err := findDuplicate(store, text, limit)
+	if err != nil {
+		output.Error("Failed to check for a duplicate post: %v", err)
+		return nil
+	}
+	if duplicate == nil {
+		return nil
+	}
+
+	posted := duplicate.PostedAt.Local().Format("2006-01-02 15:04")
+	if dryRun {
+		output.Warning("You posted the same text at %s, as status %s", posted, duplicate.StatusID)
+		return nil
+	}
+	if mode == duplicateAbort || watchingPosts {
+		return cancelledf("you posted the same text at %s, as status %s; pass --allow-duplicate to post it again", posted, duplicate.StatusID)
+	}
+
+	ok, err := confirm("--allow-duplicate", "Warning: You posted the same text at %s, as status %s. Post it again?", posted, duplicate.StatusID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		output.Info("Post cancelled.")
+		return errCancelled
+	}
+	return nil
+}
+
+// findDuplicate returns the latest of your last limit posts whose cached text
+// is the same as text, ignoring spacing, or nil if there isn't one
+func findDuplicate(store *config.Store, text string, limit int) (*config.HistoryEntry, error) {
+	entries, err := store.ListPostHistory(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	want := comparableText(text)
+	for _, entry := range entries {
+		if entry.Content != "" && comparableText(stripHTML(entry.Content)) == want {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// comparableText reduces a post's text to what survives the trip through the
+// instance's HTML, so the text as written matches the text as cached
+func comparableText(text string) string {
+	text = remoteMention.ReplaceAllString(text, "$1")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func validateDuplicateCheck(value string) error {
+	switch value {
+	case duplicateAsk, duplicateAbort, duplicateOff:
+		return nil
+	}
+	return fmt.Errorf("must be ask, abort, or off")
+}
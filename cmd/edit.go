@@ -1,65 +1,82 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"strings"
 
 	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/diff"
 	"biesnecker.com/tusk/internal/image"
-	"biesnecker.com/tusk/internal/mastodon"
+	"biesnecker.com/tusk/internal/oauth"
 	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
 var (
-	editLatest    bool
-	editTUI       bool
-	editEditor    bool
-	editVisibility string
+	editLatest      bool
+	editTUI         bool
+	editEditor      bool
+	editVisibility  string
 	editContentWarn string
 	editLanguage    string
 	editImagePath   string
 	editAltText     string
+	editDryRun      bool
 )
 
 var editCmd = &cobra.Command{
-	Use:   "edit [ID] [TEXT]",
-	Short: "Edit a status",
-	Long:  `Edit a status by ID, your most recent post, or select interactively via TUI.`,
-	Args:  cobra.MinimumNArgs(0),
-	RunE:  runEdit,
+	Use:         "edit [ID] [TEXT]",
+	Short:       "Edit a status",
+	Long:        `Edit a status by ID, your most recent post, or select interactively via TUI.`,
+	Args:        cobra.MinimumNArgs(0),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runEdit,
 }
 
 func init() {
 	editCmd.Flags().BoolVarP(&editLatest, "latest", "l", false, "Edit the most recent post")
 	editCmd.Flags().BoolVar(&editTUI, "tui", false, "Interactive TUI selection mode")
 	editCmd.Flags().BoolVarP(&editEditor, "editor", "e", false, "Compose edit in $EDITOR")
-	editCmd.Flags().StringVarP(&editVisibility, "visibility", "v", "", "Post visibility (public, unlisted, private, direct)")
+	editCmd.Flags().StringVarP(&editVisibility, "visibility", "v", "", "Post visibility: public (p), unlisted (u), private (f), direct (d)")
 	editCmd.Flags().StringVarP(&editContentWarn, "cw", "w", "", "Content warning / spoiler text")
 	editCmd.Flags().StringVar(&editLanguage, "lang", "", "ISO 639 language code (e.g., en, es, fr, de, ja)")
 	editCmd.Flags().StringVarP(&editImagePath, "image", "i", "", "Path to image file to attach")
 	editCmd.Flags().StringVar(&editAltText, "alt", "", "Alt text for the image")
+	editCmd.Flags().BoolVar(&editDryRun, "dry-run", false, "Show what would change without actually editing")
 }
 
 func runEdit(cmd *cobra.Command, args []string) error {
+	// An empty visibility leaves the current one unchanged
+	var newVisibility mastodon.Visibility
+	if editVisibility != "" {
+		v, err := mastodon.ParseVisibility(editVisibility)
+		if err != nil {
+			return err
+		}
+		newVisibility = v
+	}
+
 	store, err := config.NewStore()
 	if err != nil {
 		return fmt.Errorf("failed to open config store: %w", err)
 	}
 	defer store.Close()
 
-	domain, _ := store.Get("domain")
-	accessToken, _ := store.Get("access_token")
+	domain, accessToken := credentials(store)
 
 	if accessToken == "" {
-		return fmt.Errorf("not authenticated. Run 'tusk auth' first")
+		return errNotAuthenticated
 	}
 
-	client := mastodon.NewClient(domain, accessToken)
+	client, err := newAPI(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
 
 	// Determine which status to edit
 	var statusID string
@@ -83,10 +100,13 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		}
 		statusID = lastPostID
 	} else if len(args) > 0 {
-		statusID = args[0]
+		statusID, err = client.ResolveStatusID(args[0])
+		if err != nil {
+			return err
+		}
 		args = args[1:] // Remove the ID from args for status text extraction
 	} else {
-		return fmt.Errorf("must provide status ID, use --latest, or use --tui")
+		return invalidf("must provide status ID, use --latest, or use --tui")
 	}
 
 	// Get the current status to check it exists and get its content
@@ -96,7 +116,7 @@ func runEdit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get current status text (stripped of HTML)
-	currentText := stripHTML(currentStatus.Content)
+	currentText := htmlToText(currentStatus.Content)
 
 	// Get status text
 	var statusText string
@@ -106,6 +126,23 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+
+		// Show what changed before submitting, unless the dry run will
+		if statusText != "" && !editDryRun {
+			if !diff.Changed(diff.Lines(currentText, statusText)) {
+				output.Info("Content unchanged.")
+			} else {
+				printDiff("original", "edited", currentText, statusText)
+				ok, err := confirm("", "Submit this edit?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					output.Info("Edit cancelled.")
+					return errCancelled
+				}
+			}
+		}
 	} else if len(args) > 0 || !isTerminal() {
 		statusText, err = getStatusText(args, false)
 		if err != nil {
@@ -118,20 +155,47 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		statusText = currentText
 	}
 
+	if editDryRun {
+		output.Info("Dry run mode - would edit status %s:", statusID)
+		if diff.Changed(diff.Lines(currentText, statusText)) {
+			output.Plain("Content:")
+			printDiff("original", "edited", currentText, statusText)
+		} else {
+			output.Plain("Content: unchanged")
+		}
+		if newVisibility != "" {
+			printChange("Visibility", currentStatus.Visibility.String(), newVisibility.String())
+		}
+		if editContentWarn != "" {
+			printChange("Content warning", currentStatus.SpoilerText, editContentWarn)
+		}
+		if editLanguage != "" {
+			printChange("Language", currentStatus.Language, editLanguage)
+		}
+		if editImagePath != "" {
+			output.Plain("Media: replace %d attachment(s) with %s", len(currentStatus.MediaAttachments), editImagePath)
+			if editAltText != "" {
+				output.Plain("Alt text: %s", editAltText)
+			}
+		} else if len(currentStatus.MediaAttachments) > 0 {
+			output.Plain("Media: keep %d existing attachment(s)", len(currentStatus.MediaAttachments))
+		}
+		return nil
+	}
+
 	// Handle image upload
 	var mediaIDs []string
 	if editImagePath != "" {
 		// User is providing a new image - upload it
 		// Check for alt text
 		if editAltText == "" {
-			output.Prompt("Warning: No alt text provided for image. Continue without alt text? (y/N): ")
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
-			response = strings.TrimSpace(strings.ToLower(response))
-
-			if response != "y" && response != "yes" {
+			ok, err := confirm("--alt", "Warning: No alt text provided for image. Continue without alt text?")
+			if err != nil {
+				return err
+			}
+			if !ok {
 				output.Info("Edit cancelled. Please add --alt \"your alt text\" and try again.")
-				return nil
+				return errCancelled
 			}
 		}
 
@@ -167,7 +231,7 @@ func runEdit(cmd *cobra.Command, args []string) error {
 
 	params := mastodon.StatusParams{
 		Status:      statusText,
-		Visibility:  editVisibility,
+		Visibility:  newVisibility,
 		SpoilerText: editContentWarn,
 		MediaIDs:    mediaIDs,
 		Language:    editLanguage,
@@ -179,8 +243,10 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to edit status: %w", err)
 	}
 
+	cacheStatuses(store, []*mastodon.Status{status})
+
 	output.Success("Status edited!")
-	output.URL(status.URL)
+	output.Result(status.ID, status.URL)
 
 	return nil
 }
@@ -193,92 +259,64 @@ func isTerminal() bool {
 
 // TUI for selecting a post to edit
 
-type editStatusItem struct {
-	id       string
-	content  string
-	url      string
-	selected bool
-}
-
 type editSelectModel struct {
+	statusPicker
+
 	store    *config.Store
-	client   *mastodon.Client
-	statuses []editStatusItem
-	cursor   int
-	syncing  bool
+	client   mastodonAPI
+	loading  bool
+	spinner  spinner.Model
 	err      error
 	selected bool
+	message  string
 }
 
-type editSyncCompleteMsg struct {
-	err error
-}
-
-func loadEditStatuses(store *config.Store, client *mastodon.Client) ([]editStatusItem, error) {
-	statuses, err := client.GetAccountStatuses(50)
-	if err != nil {
-		return nil, err
-	}
-
-	items := make([]editStatusItem, 0, len(statuses))
-	for _, status := range statuses {
-		content := stripHTML(status.Content)
-		items = append(items, editStatusItem{
-			id:       status.ID,
-			content:  content,
-			url:      status.URL,
-			selected: false,
-		})
-	}
-
-	return items, nil
-}
-
-func initialEditModel(store *config.Store, client *mastodon.Client) editSelectModel {
-	statuses, err := loadEditStatuses(store, client)
+func initialEditModel(store *config.Store, client mastodonAPI) editSelectModel {
 	return editSelectModel{
-		store:    store,
-		client:   client,
-		statuses: statuses,
-		cursor:   0,
-		err:      err,
-		selected: false,
+		store:   store,
+		client:  client,
+		loading: true,
+		spinner: newSpinner(),
 	}
 }
 
 func (m editSelectModel) Init() tea.Cmd {
-	return nil
-}
-
-func doEditSync(store *config.Store, client *mastodon.Client) tea.Cmd {
-	return func() tea.Msg {
-		_, err := loadEditStatuses(store, client)
-		return editSyncCompleteMsg{err: err}
-	}
+	return tea.Batch(m.spinner.Tick, loadStatuses(m.store, m.client))
 }
 
 func (m editSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case editSyncCompleteMsg:
-		m.syncing = false
+	case statusesLoadedMsg:
+		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
-			// Reload statuses
-			statuses, err := loadEditStatuses(m.store, m.client)
-			if err != nil {
-				m.err = err
-			} else {
-				m.statuses = statuses
-				m.cursor = 0
-			}
+			m.statuses = msg.items
+			m.cursor = 0
+			m.offset = 0
 		}
 		return m, nil
 
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.width = msg.Width
+
 	case tea.KeyMsg:
-		if m.syncing {
+		if m.loading {
+			if msg.String() == "ctrl+c" || msg.String() == "q" {
+				return m, tea.Quit
+			}
 			return m, nil
 		}
+		m.message = ""
 
 		switch msg.String() {
 		case "ctrl+c", "q":
@@ -294,10 +332,20 @@ func (m editSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor++
 			}
 
+		case "p":
+			m.preview = !m.preview
+
+		case "o":
+			if len(m.statuses) > 0 {
+				if err := oauth.OpenBrowser(m.statuses[m.cursor].url); err != nil {
+					m.message = fmt.Sprintf("Failed to open browser: %v", err)
+				}
+			}
+
 		case "s":
 			// Sync posts
-			m.syncing = true
-			return m, doEditSync(m.store, m.client)
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, loadStatuses(m.store, m.client))
 
 		case "enter", " ":
 			// Select current post
@@ -306,6 +354,9 @@ func (m editSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if len(m.statuses) > 0 {
+		m.offset = scrollOffset(m.offset, m.cursor, m.listHeight(), m.itemLines)
+	}
 	return m, nil
 }
 
@@ -314,8 +365,8 @@ func (m editSelectModel) View() string {
 		return fmt.Sprintf("Error: %v\n\nPress q to quit.\n", m.err)
 	}
 
-	if m.syncing {
-		return "Syncing posts...\n"
+	if m.loading {
+		return fmt.Sprintf("%s Loading posts...\n", m.spinner.View())
 	}
 
 	var b strings.Builder
@@ -327,7 +378,7 @@ func (m editSelectModel) View() string {
 
 	// Instructions
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	b.WriteString(helpStyle.Render("↑/k: up  ↓/j: down  enter/space: select  s: sync  q: quit"))
+	b.WriteString(helpStyle.Render("↑/k: up  ↓/j: down  enter/space: select  o: open  p: preview  s: sync  q: quit"))
 	b.WriteString("\n\n")
 
 	if len(m.statuses) == 0 {
@@ -339,7 +390,9 @@ func (m editSelectModel) View() string {
 	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
 	normalStyle := lipgloss.NewStyle()
 
-	for i, status := range m.statuses {
+	end := visibleItems(m.offset, len(m.statuses), m.listHeight(), m.itemLines)
+	for i := m.offset; i < end; i++ {
+		status := m.statuses[i]
 		cursor := " "
 		if m.cursor == i {
 			cursor = ">"
@@ -356,13 +409,38 @@ func (m editSelectModel) View() string {
 
 		b.WriteString(line)
 		b.WriteString("\n")
+
+		if status.replyLine != "" {
+			b.WriteString(helpStyle.Render("    " + status.replyLine))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.offset > 0 || end < len(m.statuses) {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  %d–%d of %d", m.offset+1, end, len(m.statuses))))
+		b.WriteString("\n")
+	}
+
+	if m.preview {
+		b.WriteString("\n")
+		b.WriteString(renderPreview(m.highlighted(), m.width))
+	}
+
+	if m.message != "" {
+		b.WriteString("\n")
+		b.WriteString(m.message)
+		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
-func runEditTUI(store *config.Store, client *mastodon.Client) (string, error) {
-	p := tea.NewProgram(initialEditModel(store, client))
+func runEditTUI(store *config.Store, client mastodonAPI) (string, error) {
+	if err := requireInteractive("--tui"); err != nil {
+		return "", err
+	}
+
+	p := tea.NewProgram(initialEditModel(store, client), tea.WithContext(runContext))
 	finalModel, err := p.Run()
 	if err != nil {
 		return "", fmt.Errorf("error running TUI: %w", err)
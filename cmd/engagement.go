@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	engagementLatest bool
+	engagementLimit  int
+	engagementPages  int
+)
+
+var engagementCmd = &cobra.Command{
+	Use:   "engagement [ID]",
+	Short: "Show who favourited and boosted a status",
+	Long: `Show how many favourites, boosts, and replies a status has, and who favourited
+and boosted it.
+
+Examples:
+  tusk engagement 109876543210
+  tusk engagement --latest
+  tusk engagement --latest --pages 5`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEngagement,
+}
+
+func init() {
+	engagementCmd.Flags().BoolVarP(&engagementLatest, "latest", "l", false, "Show engagement with your most recent post")
+	engagementCmd.Flags().IntVarP(&engagementLimit, "limit", "n", 40, "Number of accounts per page")
+	engagementCmd.Flags().IntVar(&engagementPages, "pages", 1, "Number of pages of each to list")
+}
+
+// engagementPage fetches a page of the accounts that engaged with a status
+type engagementPage func(statusID, pageURL string, limit int) ([]*mastodon.Account, string, error)
+
+func runEngagement(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	var statusID string
+
+	if engagementLatest {
+		lastPostID, err := store.GetLastPostID()
+		if err != nil {
+			return fmt.Errorf("failed to get last post ID: %w", err)
+		}
+		if lastPostID == "" {
+			return fmt.Errorf("no posts in history")
+		}
+		statusID = lastPostID
+	} else if len(args) == 1 {
+		statusID, err = client.ResolveStatusID(args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		return invalidf("must provide status ID or use --latest flag")
+	}
+
+	status, err := client.GetStatus(statusID)
+	if err != nil {
+		return err
+	}
+
+	favouritedBy, moreFavourites, err := fetchEngagement(client.GetFavouritedBy, statusID)
+	if err != nil {
+		return err
+	}
+	rebloggedBy, moreReblogs, err := fetchEngagement(client.GetRebloggedBy, statusID)
+	if err != nil {
+		return err
+	}
+
+	output.Info("%s", truncate(stripHTML(status.Content), 70))
+	replies := fmt.Sprintf("%d replies", status.RepliesCount)
+	if status.RepliesCount == 1 {
+		replies = "1 reply"
+	}
+	output.Info("%s, %s, %s", pluralize(status.FavouritesCount, "favourite"), pluralize(status.ReblogsCount, "boost"), replies)
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "type", Header: "TYPE"},
+			{Key: "acct", Header: "ACCOUNT", Prefix: "@"},
+			{Key: "display_name", Header: "NAME"},
+			{Key: "url", Header: "URL", Detail: true},
+		},
+		Empty: "No favourites or boosts yet.",
+	}
+
+	for _, account := range favouritedBy {
+		listing.Rows = append(listing.Rows, []any{"favourite", account.Acct, account.DisplayName, output.Href(account.URL)})
+	}
+	for _, account := range rebloggedBy {
+		listing.Rows = append(listing.Rows, []any{"boost", account.Acct, account.DisplayName, output.Href(account.URL)})
+	}
+
+	listing.Plain = func(i int) {
+		row := listing.Rows[i]
+		if i == 0 || listing.Rows[i-1][0] != row[0] {
+			if i > 0 {
+				output.Plain("")
+			}
+			if row[0] == "favourite" {
+				output.Plain("Favourited by:")
+			} else {
+				output.Plain("Boosted by:")
+			}
+		}
+		output.Plain("  @%-30s %s", row[1], row[2])
+	}
+
+	if err := output.Render(listing); err != nil {
+		return err
+	}
+
+	if moreFavourites || moreReblogs {
+		output.Info("There are more; use --pages to list them")
+	}
+	return nil
+}
+
+// fetchEngagement lists up to --pages pages of accounts, and reports whether
+// there are more
+func fetchEngagement(fetch engagementPage, statusID string) ([]*mastodon.Account, bool, error) {
+	var accounts []*mastodon.Account
+	next := ""
+	for i := 0; i < engagementPages; i++ {
+		page, nextURL, err := fetch(statusID, next, engagementLimit)
+		if err != nil {
+			return nil, false, err
+		}
+		accounts = append(accounts, page...)
+
+		next = nextURL
+		if next == "" {
+			break
+		}
+	}
+	return accounts, next != "", nil
+}
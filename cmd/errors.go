@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+// explainError adds advice to errors from the instance that the user can do
+// something about
+func explainError(err error) error {
+	apiErr, ok := mastodon.AsAPIError(err)
+	if !ok {
+		return err
+	}
+
+	var hint string
+	switch {
+	case apiErr.Unauthorized():
+		hint = "Your login has expired or was revoked. Run 'tusk auth' to log in again."
+	case apiErr.NotFound():
+		hint = "It may have been deleted or be hidden from your account, or your instance may not support this."
+	case apiErr.RateLimited():
+		hint = "Your instance is rate limiting requests."
+		if apiErr.RetryAfter > 0 {
+			hint += fmt.Sprintf(" Try again in %s.", apiErr.RetryAfter.Round(time.Second))
+		} else {
+			hint += " Try again in a few minutes."
+		}
+	default:
+		return err
+	}
+
+	return fmt.Errorf("%w\n%s", err, hint)
+}
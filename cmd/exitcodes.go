@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+// Exit codes. Scripts rely on these, so a code's meaning must never change;
+// add a new one instead.
+const (
+	exitOK          = 0
+	exitFailure     = 1
+	exitInvalid     = 2
+	exitAuth        = 3
+	exitNetwork     = 4
+	exitRateLimited = 5
+	exitCancelled   = 6
+
+	// exitInterrupted follows the shell's convention of 128 plus the
+	// signal, SIGINT
+	exitInterrupted = 130
+)
+
+// exitCodes documents the codes for 'tusk docs exit-codes'
+var exitCodes = []struct {
+	Code    int
+	Name    string
+	Meaning string
+}{
+	{exitOK, "ok", "The command succeeded"},
+	{exitFailure, "failure", "Any other error, such as a status that doesn't exist"},
+	{exitInvalid, "invalid", "A bad command, flag, or argument, or content the instance rejected as invalid"},
+	{exitAuth, "auth", "Not logged in, or the instance rejected the login"},
+	{exitNetwork, "network", "The instance couldn't be reached, or failed with a server error"},
+	{exitRateLimited, "rate-limited", "The instance is rate limiting requests"},
+	{exitCancelled, "cancelled", "You declined a confirmation, or one was needed but couldn't be asked"},
+	{exitInterrupted, "interrupted", "Stopped with Ctrl+C or SIGTERM"},
+}
+
+// codedError gives an error the code tusk exits with
+type codedError struct {
+	code int
+	err  error
+	// reported errors have already been explained to the user, so they
+	// aren't printed again
+	reported bool
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+var errNotAuthenticated = &codedError{code: exitAuth, err: errors.New("not authenticated. Run 'tusk auth' first")}
+
+// errCancelled is returned when the user declines to go ahead, after saying
+// what was cancelled
+var errCancelled = &codedError{code: exitCancelled, err: errors.New("cancelled"), reported: true}
+
+// invalidf is for a bad combination of flags or arguments
+func invalidf(format string, args ...any) error {
+	return &codedError{code: exitInvalid, err: fmt.Errorf(format, args...)}
+}
+
+// cancelledf is for a cancellation that needs explaining, such as a
+// confirmation that couldn't be asked for
+func cancelledf(format string, args ...any) error {
+	return &codedError{code: exitCancelled, err: fmt.Errorf(format, args...)}
+}
+
+// exitCode is the code tusk exits with after err
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, context.Canceled) {
+		return exitInterrupted
+	}
+
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+
+	if apiErr, ok := mastodon.AsAPIError(err); ok {
+		switch {
+		case apiErr.Unauthorized():
+			return exitAuth
+		case apiErr.RateLimited():
+			return exitRateLimited
+		case apiErr.Invalid():
+			return exitInvalid
+		case apiErr.StatusCode >= 500:
+			return exitNetwork
+		}
+		return exitFailure
+	}
+
+	// The HTTP client reports connection failures and timeouts this way
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return exitNetwork
+	}
+	return exitFailure
+}
+
+// reported reports whether err has already been explained to the user
+func reported(err error) bool {
+	var coded *codedError
+	return errors.As(err, &coded) && coded.reported
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+func TestExitCode(t *testing.T) {
+	apiError := func(code int) error {
+		return fmt.Errorf("failed to post: %w", &mastodon.APIError{Op: "post status", StatusCode: code})
+	}
+
+	for _, tt := range []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, exitOK},
+		{"plain error", errors.New("something broke"), exitFailure},
+		{"invalid flags", invalidf("--reply-index must be 1 or more"), exitInvalid},
+		{"cancelled", errCancelled, exitCancelled},
+		{"not logged in", fmt.Errorf("wrapped: %w", errNotAuthenticated), exitAuth},
+		{"unauthorized", apiError(401), exitAuth},
+		{"not found", apiError(404), exitFailure},
+		{"rejected content", apiError(422), exitInvalid},
+		{"rate limited", apiError(429), exitRateLimited},
+		{"server error", apiError(500), exitNetwork},
+		{"unavailable", apiError(503), exitNetwork},
+		{"unreachable", &url.Error{Op: "Get", URL: "https://example.social", Err: errors.New("connection refused")}, exitNetwork},
+		{"context cancelled", context.Canceled, exitInterrupted},
+		{"interrupted request", &url.Error{Op: "Post", URL: "https://example.social", Err: context.Canceled}, exitInterrupted},
+	} {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("%s: exitCode(%v) = %d, expected %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestValidationErrorsAreInvalid(t *testing.T) {
+	if _, err := parseImportMap([]string{"public"}); exitCode(err) != exitInvalid {
+		t.Errorf("Expected a mapping without = to be invalid, got %v", err)
+	}
+	if _, err := parseImportMap([]string{"public=everyone"}); exitCode(err) != exitInvalid {
+		t.Errorf("Expected an unknown visibility to be invalid, got %v", err)
+	}
+	if err := runCWRulesAdd(nil, []string{"(unclosed", "CW"}); exitCode(err) != exitInvalid {
+		t.Errorf("Expected a bad pattern to be invalid, got %v", err)
+	}
+	if _, err := historyReplyTarget(nil, 0); exitCode(err) != exitInvalid {
+		t.Errorf("Expected --reply-index 0 to be invalid, got %v", err)
+	}
+}
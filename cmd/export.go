@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+const exportPageSize = 40
+
+// exportRawFile collects every fetched status as JSON lines, so an
+// interrupted export can resume without refetching
+const exportRawFile = "statuses.jsonl"
+
+var (
+	exportFormat  string
+	exportOut     string
+	exportMedia   bool
+	exportRestart bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all of your statuses to a local archive",
+	Long: `Page through every status on your account and write a local archive.
+
+Progress is saved after each page, so an interrupted export picks up where it
+left off the next time it's run with the same --out directory.
+
+Examples:
+  tusk export --out archive/
+  tusk export --format markdown --out archive/ --media
+  tusk export --format csv --out archive/ --restart`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "json", "Archive format: json, csv, or markdown")
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "", "Directory to write the archive to (required)")
+	exportCmd.Flags().BoolVar(&exportMedia, "media", false, "Also download media attachments")
+	exportCmd.Flags().BoolVar(&exportRestart, "restart", false, "Ignore saved progress and start over")
+	exportCmd.MarkFlagRequired("out")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	var render func(io.Writer, []*mastodon.Status) error
+	var filename string
+	switch exportFormat {
+	case "json":
+		render, filename = renderExportJSON, "statuses.json"
+	case "csv":
+		render, filename = renderExportCSV, "statuses.csv"
+	case "markdown", "md":
+		render, filename = renderExportMarkdown, "statuses.md"
+	default:
+		return invalidf("invalid format %q: must be one of json, csv, markdown", exportFormat)
+	}
+
+	outDir, err := filepath.Abs(exportOut)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	// One cursor per archive directory
+	cursorName := "export:" + outDir
+	rawPath := filepath.Join(outDir, exportRawFile)
+
+	maxID := ""
+	if !exportRestart {
+		maxID, err = store.GetCursor(cursorName)
+		if err != nil {
+			return fmt.Errorf("failed to load export progress: %w", err)
+		}
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if maxID == "" {
+		// Fresh start
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	} else {
+		output.Info("Resuming export from status %s...", maxID)
+	}
+
+	rawFile, err := os.OpenFile(rawPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", rawPath, err)
+	}
+	defer rawFile.Close()
+
+	accountID, err := client.AccountID()
+	if err != nil {
+		return err
+	}
+
+	fetched := 0
+	for {
+		statuses, err := client.GetAccountStatusesPage(accountID, maxID, exportPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to fetch statuses (progress saved, run again to resume): %w", err)
+		}
+		if len(statuses) == 0 {
+			break
+		}
+
+		for _, status := range statuses {
+			line, err := json.Marshal(status)
+			if err != nil {
+				return fmt.Errorf("failed to encode status %s: %w", status.ID, err)
+			}
+			if _, err := rawFile.Write(append(line, '\n')); err != nil {
+				return fmt.Errorf("failed to write %s: %w", rawPath, err)
+			}
+		}
+
+		maxID = statuses[len(statuses)-1].ID
+		if err := store.SetCursor(cursorName, maxID); err != nil {
+			return fmt.Errorf("failed to save export progress: %w", err)
+		}
+
+		fetched += len(statuses)
+		output.Info("Fetched %d statuses...", fetched)
+	}
+
+	statuses, err := readExportRaw(rawPath)
+	if err != nil {
+		return err
+	}
+
+	if exportMedia {
+		if err := downloadExportMedia(outDir, statuses); err != nil {
+			return err
+		}
+	}
+
+	archivePath := filepath.Join(outDir, filename)
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", archivePath, err)
+	}
+	defer archive.Close()
+
+	if err := render(archive, statuses); err != nil {
+		return fmt.Errorf("failed to write %s: %w", archivePath, err)
+	}
+
+	if err := store.ClearCursor(cursorName); err != nil {
+		output.Error("Failed to clear export progress: %v", err)
+	}
+
+	output.Success("Exported %d statuses to %s", len(statuses), archivePath)
+	return nil
+}
+
+// readExportRaw loads the fetched statuses, dropping any duplicates left by
+// a page that was written but not recorded before an interruption
+func readExportRaw(rawPath string) ([]*mastodon.Status, error) {
+	file, err := os.Open(rawPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", rawPath, err)
+	}
+	defer file.Close()
+
+	seen := make(map[string]bool)
+	var statuses []*mastodon.Status
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var status mastodon.Status
+		if err := json.Unmarshal(scanner.Bytes(), &status); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", rawPath, err)
+		}
+		if seen[status.ID] {
+			continue
+		}
+		seen[status.ID] = true
+		statuses = append(statuses, &status)
+	}
+
+	return statuses, scanner.Err()
+}
+
+// exportMediaPath is where an attachment is stored, relative to the archive
+func exportMediaPath(attachment *mastodon.MediaAttachment) string {
+	ext := path.Ext(strings.SplitN(attachment.URL, "?", 2)[0])
+	return filepath.Join("media", attachment.ID+ext)
+}
+
+func downloadExportMedia(outDir string, statuses []*mastodon.Status) error {
+	if err := os.MkdirAll(filepath.Join(outDir, "media"), 0755); err != nil {
+		return fmt.Errorf("failed to create media directory: %w", err)
+	}
+
+	httpClient := &http.Client{Timeout: 2 * time.Minute}
+	for _, status := range statuses {
+		for _, attachment := range status.MediaAttachments {
+			dest := filepath.Join(outDir, exportMediaPath(attachment))
+			if _, err := os.Stat(dest); err == nil {
+				// Downloaded by an earlier run
+				continue
+			}
+
+			output.Info("Downloading media %s...", attachment.ID)
+			if err := downloadFile(httpClient, attachment.URL, dest); err != nil {
+				output.Error("Failed to download media %s: %v", attachment.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func downloadFile(httpClient *http.Client, url, dest string) error {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	// Write to a temporary file so an interrupted download isn't mistaken
+	// for a finished one
+	tmp := dest + ".part"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+type exportedMedia struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	File        string `json:"file,omitempty"`
+}
+
+type exportedStatus struct {
+	ID          string          `json:"id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	URL         string          `json:"url"`
+	Visibility  string          `json:"visibility"`
+	SpoilerText string          `json:"spoiler_text,omitempty"`
+	InReplyTo   string          `json:"in_reply_to_id,omitempty"`
+	Text        string          `json:"text"`
+	Content     string          `json:"content"`
+	Media       []exportedMedia `json:"media,omitempty"`
+}
+
+func toExported(status *mastodon.Status) exportedStatus {
+	exported := exportedStatus{
+		ID:          status.ID,
+		CreatedAt:   status.CreatedAt,
+		URL:         status.URL,
+		Visibility:  status.Visibility.String(),
+		SpoilerText: status.SpoilerText,
+		InReplyTo:   status.InReplyTo,
+		Text:        htmlToText(status.Content),
+		Content:     status.Content,
+	}
+	for _, attachment := range status.MediaAttachments {
+		media := exportedMedia{
+			ID:          attachment.ID,
+			Type:        attachment.Type,
+			URL:         attachment.URL,
+			Description: attachment.Description,
+		}
+		if exportMedia {
+			media.File = exportMediaPath(attachment)
+		}
+		exported.Media = append(exported.Media, media)
+	}
+	return exported
+}
+
+func renderExportJSON(w io.Writer, statuses []*mastodon.Status) error {
+	exported := make([]exportedStatus, 0, len(statuses))
+	for _, status := range statuses {
+		exported = append(exported, toExported(status))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(exported)
+}
+
+func renderExportCSV(w io.Writer, statuses []*mastodon.Status) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "created_at", "url", "visibility", "spoiler_text", "in_reply_to_id", "text", "media"})
+
+	for _, status := range statuses {
+		exported := toExported(status)
+		var media []string
+		for _, m := range exported.Media {
+			if m.File != "" {
+				media = append(media, m.File)
+			} else {
+				media = append(media, m.URL)
+			}
+		}
+		writer.Write([]string{
+			exported.ID,
+			exported.CreatedAt.Format(time.RFC3339),
+			exported.URL,
+			exported.Visibility,
+			exported.SpoilerText,
+			exported.InReplyTo,
+			exported.Text,
+			strings.Join(media, " "),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func renderExportMarkdown(w io.Writer, statuses []*mastodon.Status) error {
+	var b strings.Builder
+	b.WriteString("# Status archive\n")
+
+	for _, status := range statuses {
+		exported := toExported(status)
+		fmt.Fprintf(&b, "\n## %s\n\n", exported.CreatedAt.Local().Format("2006-01-02 15:04"))
+		fmt.Fprintf(&b, "[%s](%s) · %s\n\n", exported.ID, exported.URL, exported.Visibility)
+		if exported.SpoilerText != "" {
+			fmt.Fprintf(&b, "**CW: %s**\n\n", exported.SpoilerText)
+		}
+		b.WriteString(exported.Text)
+		b.WriteString("\n")
+		for _, m := range exported.Media {
+			target := m.URL
+			if m.File != "" {
+				target = filepath.ToSlash(m.File)
+			}
+			fmt.Fprintf(&b, "\n![%s](%s)\n", m.Description, target)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/oauth"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/internal/prefetch"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	favsLimit int
+	favsPages int
+	favsTUI   bool
+	favsCont  bool
+)
+
+// favsCursor stores the page after the last one listed, for --continue
+const favsCursor = "favs"
+
+var favsCmd = &cobra.Command{
+	Use:   "favs",
+	Short: "List statuses you've favourited",
+	Long: `List the statuses you've favourited, most recently favourited first.
+
+Examples:
+  tusk favs
+  tusk favs --pages 3
+  tusk favs --continue
+  tusk favs --tui`,
+	Args: cobra.NoArgs,
+	RunE: runFavs,
+}
+
+func init() {
+	favsCmd.Flags().IntVarP(&favsLimit, "limit", "n", 20, "Number of favourites per page")
+	favsCmd.Flags().IntVar(&favsPages, "pages", 1, "Number of pages to list")
+	favsCmd.Flags().BoolVar(&favsTUI, "tui", false, "Browse favourites interactively")
+	favsCmd.Flags().BoolVar(&favsCont, "continue", false, "Start after the last page shown previously")
+}
+
+func runFavs(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	next, err := loadCursor(store, favsCursor, favsCont)
+	if err != nil {
+		return err
+	}
+
+	if favsTUI {
+		return runFavsTUI(store, client, next)
+	}
+
+	var favourites []*mastodon.Status
+	for page := 0; page < favsPages; page++ {
+		statuses, nextURL, err := client.GetFavourites(next, favsLimit)
+		if err != nil {
+			return err
+		}
+		cacheStatuses(store, statuses)
+
+		favourites = append(favourites, statuses...)
+
+		next = nextURL
+		if next == "" {
+			break
+		}
+	}
+
+	saveCursor(store, favsCursor, next)
+	return output.Render(statusListing(favourites, "No favourites."))
+}
+
+// statusListing lists statuses for output.Render, shown by
+// printStatusListing in plain output
+func statusListing(statuses []*mastodon.Status, empty string) *output.Listing {
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID"},
+			{Key: "created_at", Header: "CREATED"},
+			{Key: "acct", Header: "AUTHOR", Prefix: "@"},
+			{Key: "spoiler_text", Header: "CW"},
+			{Key: "content", Header: "CONTENT", Width: 60},
+			{Key: "url", Header: "URL"},
+		},
+		Empty: empty,
+		Plain: func(i int) {
+			printStatusListing(statuses[i])
+		},
+	}
+
+	for _, status := range statuses {
+		listing.Rows = append(listing.Rows, []any{
+			status.ID, status.CreatedAt, statusAuthor(status), status.SpoilerText,
+			stripHTML(status.Content), output.Href(status.URL),
+		})
+	}
+
+	return listing
+}
+
+// printStatusListing prints a status as a line in a listing, with its URL
+// underneath. Content behind a content warning stays folded.
+func printStatusListing(status *mastodon.Status) {
+	content := output.Highlight(truncate(stripHTML(status.Content), 60))
+	output.Plain("%s  @%s  %s", status.CreatedAt.Local().Format("2006-01-02 15:04"),
+		statusAuthor(status), output.Fold(status.SpoilerText, content, false))
+	output.URL("    " + status.URL)
+}
+
+// statusAuthor is the acct of a status's author, tolerating a missing account
+func statusAuthor(status *mastodon.Status) string {
+	if status.Account == nil {
+		return "unknown"
+	}
+	return status.Account.Acct
+}
+
+// TUI for browsing favourites
+
+type favsModel struct {
+	statusPicker
+
+	store   *config.Store
+	client  *mastodon.Client
+	next    string
+	loading bool
+	spinner spinner.Model
+	pages   *prefetch.Fetcher[favsPage]
+	message string
+	err     error
+}
+
+// favsPage is one page of favourites and the URL of the page after it
+type favsPage struct {
+	statuses []*mastodon.Status
+	next     string
+}
+
+// favsLoadedMsg delivers a page fetched by loadFavs
+type favsLoadedMsg struct {
+	page favsPage
+	err  error
+}
+
+func (m favsModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.loadFavs())
+}
+
+// loadFavs gets the next page of favourites in the background, using the
+// prefetched copy if there is one
+func (m favsModel) loadFavs() tea.Cmd {
+	pages, fetch, pageURL := m.pages, m.fetchPage(m.next), m.next
+	return func() tea.Msg {
+		page, err := pages.Get(pageURL, fetch)
+		return favsLoadedMsg{page: page, err: err}
+	}
+}
+
+func (m favsModel) fetchPage(pageURL string) func(context.Context) (favsPage, error) {
+	store, client := m.store, m.client
+	return func(ctx context.Context) (favsPage, error) {
+		statuses, next, err := client.WithContext(ctx).GetFavourites(pageURL, favsLimit)
+		if err != nil {
+			return favsPage{}, err
+		}
+		cacheStatuses(store, statuses)
+		return favsPage{statuses: statuses, next: next}, nil
+	}
+}
+
+func favsItemLines(int) int {
+	return 1
+}
+
+func (m favsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case favsLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		for _, status := range msg.page.statuses {
+			m.statuses = append(m.statuses, statusItem{id: status.ID, url: status.URL, status: status})
+		}
+		m.next = msg.page.next
+
+		// Fetch the following page now so 'n' doesn't have to wait
+		if m.next != "" {
+			m.pages.Start(m.next, m.fetchPage(m.next))
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.width = msg.Width
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" || msg.String() == "q" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+		m.message = ""
+
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.statuses)-1 {
+				m.cursor++
+			}
+
+		case "n":
+			if m.next == "" {
+				m.message = "No more favourites."
+				return m, nil
+			}
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, m.loadFavs())
+
+		case "p":
+			m.preview = !m.preview
+
+		case "o":
+			if len(m.statuses) == 0 {
+				return m, nil
+			}
+			if err := oauth.OpenBrowser(m.statuses[m.cursor].url); err != nil {
+				m.message = fmt.Sprintf("Failed to open browser: %v", err)
+			}
+
+		case "u":
+			if len(m.statuses) == 0 {
+				return m, nil
+			}
+			if err := m.client.UnfavouriteStatus(m.statuses[m.cursor].id); err != nil {
+				m.message = fmt.Sprintf("Failed to unfavourite: %v", err)
+				return m, nil
+			}
+			m.statuses = append(m.statuses[:m.cursor], m.statuses[m.cursor+1:]...)
+			if m.cursor >= len(m.statuses) && m.cursor > 0 {
+				m.cursor--
+			}
+			m.message = "Unfavourited."
+		}
+	}
+
+	if len(m.statuses) > 0 {
+		m.offset = scrollOffset(m.offset, m.cursor, m.listHeight(), favsItemLines)
+	}
+	return m, nil
+}
+
+func (m favsModel) View() string {
+	if m.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress q to quit.\n", m.err)
+	}
+
+	if m.loading && len(m.statuses) == 0 {
+		return fmt.Sprintf("%s Loading favourites...\n", m.spinner.View())
+	}
+
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	b.WriteString(headerStyle.Render("Favourites"))
+	b.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	b.WriteString(helpStyle.Render("↑/k: up  ↓/j: down  n: next page  o: open  p: preview  u: unfavourite  q: quit"))
+	b.WriteString("\n\n")
+
+	if len(m.statuses) == 0 {
+		b.WriteString("No favourites found.\n")
+		return b.String()
+	}
+
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
+	normalStyle := lipgloss.NewStyle()
+
+	end := visibleItems(m.offset, len(m.statuses), m.listHeight(), favsItemLines)
+	for i := m.offset; i < end; i++ {
+		status := m.statuses[i].status
+		cursor := " "
+		if m.cursor == i {
+			cursor = ">"
+		}
+
+		line := fmt.Sprintf("%s @%s: %s", cursor, statusAuthor(status), truncate(stripHTML(status.Content), 70))
+
+		if m.cursor == i {
+			line = cursorStyle.Render(line)
+		} else {
+			line = normalStyle.Render(line)
+		}
+
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.loading:
+		b.WriteString(fmt.Sprintf("%s Loading more...", m.spinner.View()))
+		b.WriteString("\n")
+	case end < len(m.statuses) || m.next != "":
+		more := "more below"
+		if m.next != "" {
+			more = "press n for more"
+		}
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  %d–%d of %d, %s", m.offset+1, end, len(m.statuses), more)))
+		b.WriteString("\n")
+	}
+
+	if m.preview {
+		b.WriteString("\n")
+		b.WriteString(renderPreview(m.highlighted(), m.width))
+	}
+
+	if m.message != "" {
+		b.WriteString("\n")
+		b.WriteString(m.message)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func runFavsTUI(store *config.Store, client *mastodon.Client, start string) error {
+	if err := requireInteractive("--tui"); err != nil {
+		return err
+	}
+
+	pages := prefetch.New[favsPage](1)
+	defer pages.Close()
+
+	m := favsModel{store: store, client: client, next: start, loading: true, spinner: newSpinner(), pages: pages}
+
+	p := tea.NewProgram(m, tea.WithContext(runContext))
+	finalModel, err := p.Run()
+	if err != nil {
+		return fmt.Errorf("error running TUI: %w", err)
+	}
+
+	m = finalModel.(favsModel)
+	if m.err != nil {
+		return m.err
+	}
+
+	saveCursor(store, favsCursor, m.next)
+	return nil
+}
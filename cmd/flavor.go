@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+// The instance's server software is detected once and cached in the config
+// table under these keys, with the instance it belongs to
+const (
+	flavorKey       = "server_flavor"
+	flavorDomainKey = "server_flavor_domain"
+)
+
+// flavorTimeout keeps a slow nodeinfo endpoint from holding up the command
+const flavorTimeout = 5 * time.Second
+
+// serverFlavor returns the software client's instance runs, detecting it the
+// first time tusk talks to the instance. It's unknown if detection fails,
+// and requests are then made as for Mastodon.
+func serverFlavor(store *config.Store, client *mastodon.Client) mastodon.Flavor {
+	if domain, _ := store.Get(flavorDomainKey); domain == client.BaseURL {
+		flavor, _ := store.Get(flavorKey)
+		return mastodon.Flavor(flavor)
+	}
+
+	// A cassette won't have the detection requests in it
+	if replayPath != "" {
+		return mastodon.FlavorUnknown
+	}
+
+	timeout := client.HTTPClient.Timeout
+	client.HTTPClient.Timeout = flavorTimeout
+	defer func() { client.HTTPClient.Timeout = timeout }()
+
+	flavor, err := client.DetectFlavor()
+	if err != nil {
+		output.Debug("Failed to detect the server software: %v", err)
+		return mastodon.FlavorUnknown
+	}
+	output.Debug("%s runs %s", client.BaseURL, flavor)
+
+	// The cache is for the stored login's instance
+	if overridingLogin() {
+		return flavor
+	}
+	store.Set(flavorKey, string(flavor))
+	store.Set(flavorDomainKey, client.BaseURL)
+	return flavor
+}
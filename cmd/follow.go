@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+// followCSVHeader heads the first column of Mastodon's following_accounts.csv.
+// Exports from before Mastodon 3.3 have no header row and only that column.
+const followCSVHeader = "Account address"
+
+// followRateLimitWait is how long to wait after being rate limited when the
+// instance doesn't say, and followMaxWait the longest wait sat out before
+// stopping an import to be resumed later
+var (
+	followRateLimitWait = 5 * time.Minute
+	followMaxWait       = 30 * time.Minute
+)
+
+var (
+	followFromCSV string
+	followDelay   time.Duration
+	followRestart bool
+	followDryRun  bool
+)
+
+var followCmd = &cobra.Command{
+	Use:   "follow [@USER...]",
+	Short: "Follow accounts, or import a list of them from a CSV export",
+	Long: `Follow accounts given by handle or link, or every account in the
+following_accounts.csv of a Mastodon export (for example, after moving to a
+new instance) with --from-csv. Each account from the CSV is followed with the
+boost, notification, and language settings it had.
+
+Follows are spaced out by --delay. When the instance rate limits them, tusk
+waits until it allows more, or stops if that's more than half an hour away.
+Progress through a CSV is saved after each account, so running the same
+command again picks up where it left off.
+
+Examples:
+  tusk follow @alice@example.com
+  tusk follow --from-csv following_accounts.csv --dry-run
+  tusk follow --from-csv following_accounts.csv --delay 5s
+  tusk follow --from-csv following_accounts.csv --restart`,
+	Annotations: needsScopes("write:follows"),
+	RunE:        runFollow,
+}
+
+func init() {
+	followCmd.Flags().StringVar(&followFromCSV, "from-csv", "", "Follow the accounts in a following_accounts.csv export")
+	followCmd.Flags().DurationVar(&followDelay, "delay", 2*time.Second, "Time to wait between follows")
+	followCmd.Flags().BoolVar(&followRestart, "restart", false, "Ignore saved progress through the CSV and start over")
+	followCmd.Flags().BoolVar(&followDryRun, "dry-run", false, "Show what would be followed without following")
+}
+
+// followEntry is an account to follow and the settings to follow it with
+type followEntry struct {
+	Handle    string
+	Reblogs   bool
+	Notify    bool
+	Languages []string
+}
+
+// parseFollowCSV reads the accounts from a following_accounts.csv export,
+// with or without its header row. Columns an older export lacks take
+// Mastodon's defaults: boosts shown and no notifications.
+func parseFollowCSV(r io.Reader) ([]followEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var entries []followEntry
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		handle := strings.TrimSpace(record[0])
+		if line == 1 && strings.EqualFold(handle, followCSVHeader) {
+			continue
+		}
+		if handle == "" {
+			continue
+		}
+
+		entry := followEntry{Handle: handle, Reblogs: true}
+		if len(record) > 1 {
+			entry.Reblogs, err = parseFollowCSVBool(record[1], true)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		if len(record) > 2 {
+			entry.Notify, err = parseFollowCSVBool(record[2], false)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		if len(record) > 3 {
+			for _, language := range strings.Split(record[3], ",") {
+				if language = strings.TrimSpace(language); language != "" {
+					entry.Languages = append(entry.Languages, language)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// parseFollowCSVBool reads a true or false column, which is fallback when
+// empty
+func parseFollowCSVBool(value string, fallback bool) (bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("expected true or false, got %q", value)
+	}
+	return b, nil
+}
+
+func runFollow(cmd *cobra.Command, args []string) error {
+	if followFromCSV == "" && len(args) == 0 {
+		return invalidf("give the accounts to follow, or a following_accounts.csv with --from-csv")
+	}
+	if followFromCSV != "" && len(args) > 0 {
+		return invalidf("--from-csv can't be combined with accounts to follow")
+	}
+	if followDelay < 0 {
+		return invalidf("--delay can't be negative")
+	}
+
+	var entries []followEntry
+	// One cursor per CSV, holding how many of its accounts are done
+	var csvPath, cursorName string
+	if followFromCSV != "" {
+		var err error
+		csvPath, err = filepath.Abs(followFromCSV)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", followFromCSV, err)
+		}
+		file, err := os.Open(csvPath)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", followFromCSV, err)
+		}
+		entries, err = parseFollowCSV(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", followFromCSV, err)
+		}
+		cursorName = "follow-csv:" + csvPath
+	} else {
+		for _, arg := range args {
+			entries = append(entries, followEntry{Handle: arg, Reblogs: true})
+		}
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	done := 0
+	if cursorName != "" && !followRestart {
+		saved, err := store.GetCursor(cursorName)
+		if err != nil {
+			return fmt.Errorf("failed to load follow progress: %w", err)
+		}
+		if saved != "" {
+			done, _ = strconv.Atoi(saved)
+			done = min(max(done, 0), len(entries))
+			output.Info("Resuming after %d of %d account(s)...", done, len(entries))
+		}
+	}
+
+	remaining := entries[done:]
+	if len(remaining) == 0 {
+		output.Info("Nothing to follow.")
+		if cursorName != "" && !followDryRun {
+			saveCursor(store, cursorName, "")
+		}
+		return nil
+	}
+
+	if followDryRun {
+		output.Info("Dry run mode - would follow %d account(s):", len(remaining))
+		for _, entry := range remaining {
+			output.Plain("%s%s", entry.Handle, describeFollowEntry(entry))
+		}
+		return nil
+	}
+
+	if cursorName != "" {
+		ok, err := confirm("", "Follow %d account(s) from %s?", len(remaining), filepath.Base(csvPath))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Info("Follow cancelled.")
+			return errCancelled
+		}
+	}
+
+	client, err := newAPI(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for i, entry := range remaining {
+		if i > 0 && !pause(followDelay) {
+			return followStopped(cursorName, done, len(entries), runContext.Err())
+		}
+
+		account, relationship, err := followHandle(client, entry)
+		if err != nil {
+			if interrupted() || endsRun(err) {
+				return followStopped(cursorName, done, len(entries), err)
+			}
+			output.Error("Failed to follow %s: %v", entry.Handle, err)
+			failed = append(failed, entry.Handle)
+		} else if relationship.Requested && !relationship.Following {
+			output.Success("Requested to follow @%s", account.Acct)
+		} else {
+			output.Success("Followed @%s", account.Acct)
+		}
+
+		done++
+		if cursorName != "" {
+			saveCursor(store, cursorName, strconv.Itoa(done))
+		}
+	}
+
+	if cursorName != "" {
+		saveCursor(store, cursorName, "")
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to follow %d of %d account(s): %s", len(failed), len(remaining), strings.Join(failed, ", "))
+	}
+	if len(remaining) > 1 {
+		output.Info("Followed %d account(s).", len(remaining))
+	}
+	return nil
+}
+
+// describeFollowEntry notes how a dry run would follow an account, where it
+// differs from the defaults
+func describeFollowEntry(entry followEntry) string {
+	var notes []string
+	if !entry.Reblogs {
+		notes = append(notes, "boosts hidden")
+	}
+	if entry.Notify {
+		notes = append(notes, "notify")
+	}
+	if len(entry.Languages) > 0 {
+		notes = append(notes, "languages: "+strings.Join(entry.Languages, ", "))
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(notes, "; ") + ")"
+}
+
+// followHandle finds the account an entry names and follows it, sitting out
+// the instance's rate limits along the way
+func followHandle(client mastodonAPI, entry followEntry) (*mastodon.Account, *mastodon.Relationship, error) {
+	var account *mastodon.Account
+	err := waitOutRateLimit(func() error {
+		var err error
+		account, err = client.ResolveAccount(entry.Handle)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var relationship *mastodon.Relationship
+	err = waitOutRateLimit(func() error {
+		var err error
+		relationship, err = client.FollowAccount(account.ID, mastodon.FollowParams{
+			Reblogs:   entry.Reblogs,
+			Notify:    entry.Notify,
+			Languages: entry.Languages,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return account, relationship, nil
+}
+
+// waitOutRateLimit runs request, and again each time the instance rate
+// limits it, after waiting as long as it asks. A wait longer than
+// followMaxWait is returned as the rate limit error instead.
+func waitOutRateLimit(request func() error) error {
+	for {
+		err := request()
+		apiErr, ok := mastodon.AsAPIError(err)
+		if !ok || !apiErr.RateLimited() {
+			return err
+		}
+
+		wait := apiErr.RetryAfter
+		if wait <= 0 {
+			wait = followRateLimitWait
+		}
+		if wait > followMaxWait {
+			return err
+		}
+		output.Warning("Rate limited by the instance; waiting until %s...", time.Now().Add(wait).Format("15:04:05"))
+		if !pause(wait) {
+			return runContext.Err()
+		}
+	}
+}
+
+// endsRun reports whether err ends a run of requests, such as follows or a
+// thread's deletions, rather than just failing one of them
+func endsRun(err error) bool {
+	apiErr, ok := mastodon.AsAPIError(err)
+	return ok && (apiErr.RateLimited() || apiErr.Unauthorized())
+}
+
+// followStopped explains where a run of follows stopped
+func followStopped(cursorName string, done, total int, err error) error {
+	if cursorName != "" {
+		output.Info("Stopped after %d of %d account(s). Run the same command again to pick up from there.", done, total)
+	}
+	return err
+}
+
+// pause waits for d, returning false if tusk is interrupted first
+func pause(d time.Duration) bool {
+	if d <= 0 {
+		return !interrupted()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-runContext.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+func TestParseFollowCSV(t *testing.T) {
+	entries, err := parseFollowCSV(strings.NewReader(`Account address,Show boosts,Notify on new posts,Languages
+alice@example.com,true,false,
+bob@example.org,false,true,"en, de"
+
+`))
+	if err != nil {
+		t.Fatalf("parseFollowCSV failed: %v", err)
+	}
+	want := []followEntry{
+		{Handle: "alice@example.com", Reblogs: true},
+		{Handle: "bob@example.org", Notify: true, Languages: []string{"en", "de"}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Expected %+v, got %+v", want, entries)
+	}
+
+	// Older exports have one column and no header
+	entries, err = parseFollowCSV(strings.NewReader("alice@example.com\nbob@example.org\n"))
+	if err != nil {
+		t.Fatalf("parseFollowCSV failed: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Handle != "bob@example.org" || !entries[1].Reblogs {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+
+	if _, err := parseFollowCSV(strings.NewReader("alice@example.com,maybe\n")); err == nil {
+		t.Error("Expected a bad boolean to be rejected")
+	}
+}
+
+func TestFollowFromCSVResumes(t *testing.T) {
+	api := newFakeAPI()
+	api.accounts = map[string]*mastodon.Account{
+		"alice@example.com": {ID: "2", Acct: "alice@example.com"},
+		"carol@example.net": {ID: "4", Acct: "carol@example.net"},
+	}
+	api.rateLimits = 1
+	store := useFakeAPI(t, api)
+
+	path := filepath.Join(t.TempDir(), "following_accounts.csv")
+	os.WriteFile(path, []byte(`Account address,Show boosts,Notify on new posts,Languages
+alice@example.com,true,false,
+bob@example.org,true,false,
+carol@example.net,false,true,fr
+`), 0644)
+	// Stopped after alice last time
+	store.SetCursor("follow-csv:"+path, "1")
+
+	followFromCSV, followDelay, assumeYes = path, 0, true
+	defer func() { followFromCSV, followDelay, assumeYes = "", 0, false }()
+
+	err := runFollow(followCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "bob@example.org") {
+		t.Errorf("Expected bob to be reported as failed, got %v", err)
+	}
+
+	want := map[string]mastodon.FollowParams{"4": {Notify: true, Languages: []string{"fr"}}}
+	if !reflect.DeepEqual(api.follows, want) {
+		t.Errorf("Expected follows %+v, got %+v", want, api.follows)
+	}
+	if cursor, _ := store.GetCursor("follow-csv:" + path); cursor != "" {
+		t.Errorf("Expected progress to be cleared once done, got %q", cursor)
+	}
+}
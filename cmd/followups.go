@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/internal/prefetch"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var followupsLimit int
+
+var followupsCmd = &cobra.Command{
+	Use:   "followups",
+	Short: "List replies to your posts that you haven't answered",
+	Long: `List your recent posts whose threads have replies to you that you haven't
+responded to yet, to help keep up with conversations you started.
+
+Examples:
+  tusk followups
+  tusk followups -n 50`,
+	Args: cobra.NoArgs,
+	RunE: runFollowups,
+}
+
+func init() {
+	followupsCmd.Flags().IntVarP(&followupsLimit, "limit", "n", 20, "Number of recent posts to check (max 40)")
+}
+
+func runFollowups(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	accountID, err := client.AccountID()
+	if err != nil {
+		return err
+	}
+
+	output.Info("Checking your recent posts for replies...")
+	statuses, err := client.GetAccountStatusesPage(accountID, "", followupsLimit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch statuses: %w", err)
+	}
+	cacheStatuses(store, statuses)
+
+	// Only conversations you started; replies to your own posts are covered
+	// by the thread of the post they continue
+	var roots []*mastodon.Status
+	for _, status := range statuses {
+		if status.InReplyTo == "" && status.RepliesCount > 0 {
+			roots = append(roots, status)
+		}
+	}
+
+	threads := prefetch.New[*mastodon.StatusContext](prefetchWorkers)
+	defer threads.Close()
+	fetchThread := func(id string) func(context.Context) (*mastodon.StatusContext, error) {
+		return func(ctx context.Context) (*mastodon.StatusContext, error) {
+			return client.WithContext(ctx).GetStatusContext(id)
+		}
+	}
+	for _, root := range roots {
+		threads.Start(root.ID, fetchThread(root.ID))
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "post_id", Header: "POST"},
+			{Key: "post_url", Header: "POST URL", Detail: true},
+			{Key: "id", Header: "REPLY"},
+			{Key: "created_at", Header: "CREATED"},
+			{Key: "acct", Header: "AUTHOR", Prefix: "@"},
+			{Key: "spoiler_text", Header: "CW"},
+			{Key: "content", Header: "CONTENT", Width: 60},
+			{Key: "url", Header: "URL"},
+		},
+	}
+
+	// Plain output groups the replies under the post they answer
+	var pendingRoots, pendingReplies []*mastodon.Status
+	for _, root := range roots {
+		thread, err := threads.Get(root.ID, fetchThread(root.ID))
+		if err != nil {
+			output.Error("Failed to fetch the thread for %s: %v", root.ID, err)
+			continue
+		}
+		cacheStatuses(store, thread.Descendants)
+
+		replies := mastodon.UnansweredReplies(accountID, root, thread.Descendants)
+		if len(replies) == 0 {
+			continue
+		}
+
+		for _, reply := range replies {
+			pendingRoots = append(pendingRoots, root)
+			pendingReplies = append(pendingReplies, reply)
+			listing.Rows = append(listing.Rows, []any{
+				root.ID, root.URL, reply.ID, reply.CreatedAt, statusAuthor(reply), reply.SpoilerText,
+				stripHTML(reply.Content), output.Href(reply.URL),
+			})
+		}
+	}
+
+	listing.Plain = func(i int) {
+		if i == 0 || pendingRoots[i] != pendingRoots[i-1] {
+			output.Plain("")
+			printStatusListing(pendingRoots[i])
+		}
+		reply := pendingReplies[i]
+		content := output.Highlight(truncate(stripHTML(reply.Content), 60))
+		output.Plain("  ↳ %s  @%s: %s", reply.ID, statusAuthor(reply), output.Fold(reply.SpoilerText, content, false))
+		output.URL("      " + reply.URL)
+	}
+
+	if err := output.Render(listing); err != nil {
+		return err
+	}
+
+	pending := len(pendingReplies)
+	if pending == 0 {
+		output.Success("You're all caught up!")
+		return nil
+	}
+
+	output.Plain("")
+	output.Info("%d repl(ies) waiting for you. Reply with 'tusk -r STATUS_ID'.", pending)
+	return nil
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+)
+
+// gitCommit is the part of a commit a status is made from
+type gitCommit struct {
+	Hash    string
+	Subject string
+	Body    string
+}
+
+// gitPostText makes the status for --from-git: the subject and body of the
+// commit ref names, then with --git-link a link to the commit on the origin
+// remote's web page. A body too long for the instance is cut short, since
+// a hook has no one to ask about it.
+func gitPostText(client mastodonAPI, ref string, link bool) (string, error) {
+	commit, err := readCommit(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var commitURL string
+	if link {
+		remote, err := runGit("remote", "get-url", "origin")
+		if err != nil {
+			return "", err
+		}
+		commitURL, err = commitWebURL(remote, commit.Hash)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	limit := 0
+	if instance, err := client.GetInstance(); err == nil {
+		limit = instance.MaxCharacters()
+	}
+	return composeGitPost(commit, commitURL, limit), nil
+}
+
+// readCommit reads the commit ref names from the repository in the current
+// directory
+func readCommit(ref string) (*gitCommit, error) {
+	out, err := runGit("show", "-s", "--format=%H%x00%s%x00%b", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(out, "\x00", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("unexpected output from git show: %q", out)
+	}
+	return &gitCommit{
+		Hash:    parts[0],
+		Subject: strings.TrimSpace(parts[1]),
+		Body:    strings.TrimSpace(parts[2]),
+	}, nil
+}
+
+// runGit runs git with args and returns its trimmed output, or its error
+// message as the error
+func runGit(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// commitWebURL turns a remote's URL, whether HTTPS or SSH, into the web page
+// of a commit. GitHub, GitLab, Codeberg, and other Gitea and Forgejo hosts
+// all show commits at /commit/HASH.
+func commitWebURL(remote, hash string) (string, error) {
+	web := remote
+	if !strings.Contains(remote, "://") {
+		// scp-style: git@host:owner/repo.git
+		host, path, ok := strings.Cut(remote, ":")
+		if !ok {
+			return "", fmt.Errorf("can't make a web link from the origin remote %q", remote)
+		}
+		if _, h, found := strings.Cut(host, "@"); found {
+			host = h
+		}
+		web = "https://" + host + "/" + path
+	}
+
+	u, err := url.Parse(web)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("can't make a web link from the origin remote %q", remote)
+	}
+	switch u.Scheme {
+	case "ssh", "git", "git+ssh", "http", "https":
+	default:
+		return "", fmt.Errorf("can't make a web link from the origin remote %q", remote)
+	}
+	// Credentials and SSH ports don't belong in a web link
+	path := strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+	return "https://" + u.Hostname() + path + "/commit/" + hash, nil
+}
+
+// composeGitPost lays out a commit as a status: the subject, the body after a
+// blank line, and the link on its own line. With a limit, the body is cut
+// short to fit, and left out if even the subject and link barely fit.
+func composeGitPost(commit *gitCommit, commitURL string, limit int) string {
+	head := commit.Subject
+	tail := ""
+	if commitURL != "" {
+		tail = "\n\n" + commitURL
+	}
+
+	body := commit.Body
+	if body != "" && limit > 0 {
+		room := limit - utf8.RuneCountInString(head+tail) - len("\n\n")
+		if utf8.RuneCountInString(body) > room {
+			if room < 20 {
+				body = ""
+			} else {
+				runes := []rune(body)
+				body = strings.TrimSpace(string(runes[:room-1])) + "…"
+			}
+		}
+	}
+
+	if body != "" {
+		head += "\n\n" + body
+	}
+	return head + tail
+}
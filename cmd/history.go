@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit int
+	historyPrune int
+)
+
+var historyCmd = &cobra.Command{
+	Use:     "history-list",
+	Aliases: []string{"history"},
+	Short:   "List your local post history",
+	Long: `List the posts in your local history stack, newest first. The index counts
+back from your latest post, which is 1. Posts that aren't cached show no
+content; 'tusk sync' fetches them.
+
+Use --prune to keep only the latest N posts and forget the rest.
+
+Examples:
+  tusk history-list
+  tusk history-list -n 50
+  tusk history-list --prune 100`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "Number of posts to show (0 for all)")
+	historyCmd.Flags().IntVar(&historyPrune, "prune", 0, "Keep only the latest N posts in the history")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	if cmd.Flags().Changed("prune") {
+		if historyPrune < 0 {
+			return invalidf("--prune must be 0 or more")
+		}
+
+		removed, err := store.PrunePostHistory(historyPrune)
+		if err != nil {
+			return fmt.Errorf("failed to prune post history: %w", err)
+		}
+		output.Success("Removed %s from history", pluralize(removed, "post"))
+		return nil
+	}
+
+	entries, err := store.ListPostHistory(historyLimit)
+	if err != nil {
+		return fmt.Errorf("failed to read post history: %w", err)
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "index", Header: "#"},
+			{Key: "id", Header: "ID"},
+			{Key: "posted_at", Header: "POSTED"},
+			{Key: "content", Header: "CONTENT", Width: 60},
+		},
+		Empty:   "No posts in history.",
+		Tabular: true,
+	}
+
+	for _, entry := range entries {
+		content := strings.Join(strings.Fields(stripHTML(entry.Content)), " ")
+		listing.Rows = append(listing.Rows, []any{entry.Index, entry.StatusID, entry.PostedAt, content})
+	}
+
+	return output.Render(listing)
+}
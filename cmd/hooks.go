@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/hooks"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+// Hook events, each run by the setting named on_<event>
+const (
+	hookPost   = "post"
+	hookDelete = "delete"
+)
+
+// runHook runs the user's hook for event, if they've set one, with the status
+// as JSON on stdin. The status is already posted or deleted, so a failing
+// hook is only worth a warning.
+func runHook(store *config.Store, event string, status *mastodon.Status) {
+	command := getSetting(store, "on_"+event)
+	if command == "" {
+		return
+	}
+
+	payload, err := json.Marshal(status)
+	if err != nil {
+		output.Warning("Failed to encode status for the on_%s hook: %v", event, err)
+		return
+	}
+
+	hook := &hooks.Hook{Command: command}
+	output.Debug("Running on_%s hook: %s", event, command)
+	if err := hook.Run(payload, map[string]string{
+		"TUSK_EVENT":      event,
+		"TUSK_STATUS_ID":  status.ID,
+		"TUSK_STATUS_URL": status.URL,
+	}); err != nil {
+		output.Warning("The on_%s hook failed: %v", event, err)
+	}
+}
@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/outbox"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// importSkip is the mapping target that drops notes of a visibility
+const importSkip = "skip"
+
+var (
+	importMap         []string
+	importAll         bool
+	importSkipReplies bool
+	importDryRun      bool
+	importForce       bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import OUTBOX",
+	Short: "Re-post statuses from an account export",
+	Long: `Re-post statuses from the outbox.json of a Mastodon account export (for example,
+after moving to a new instance). Pick the statuses to post in an interactive
+list, or use --all to post everything.
+
+Each status keeps its visibility unless a --map rule changes it. Rules take the
+form FROM=TO, where TO is a visibility or "skip". Direct messages are skipped
+unless a rule says otherwise. Replies are posted as standalone statuses, since
+the statuses they replied to don't exist on the new account.
+
+Image attachments are uploaded again from the export's media_attachments
+directory; other media is left out.
+
+Examples:
+  tusk import archive/outbox.json
+  tusk import archive/outbox.json --map public=unlisted --map private=skip
+  tusk import archive/outbox.json --all --skip-replies --dry-run`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runImport,
+}
+
+func init() {
+	importCmd.Flags().StringSliceVarP(&importMap, "map", "m", nil, "Visibility rule FROM=TO, where TO is a visibility or skip (repeatable)")
+	importCmd.Flags().BoolVar(&importAll, "all", false, "Post every status without the selection TUI")
+	importCmd.Flags().BoolVar(&importSkipReplies, "skip-replies", false, "Leave out statuses that were replies")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be posted without actually posting")
+	importCmd.Flags().BoolVarP(&importForce, "force", "f", false, "Skip confirmation")
+}
+
+// parseImportMap turns FROM=TO rules into a lookup table. A visibility mapped
+// to "" is skipped.
+func parseImportMap(rules []string) (map[mastodon.Visibility]mastodon.Visibility, error) {
+	mapping := map[mastodon.Visibility]mastodon.Visibility{
+		mastodon.VisibilityDirect: "",
+	}
+
+	for _, rule := range rules {
+		from, to, ok := strings.Cut(rule, "=")
+		if !ok {
+			return nil, invalidf("invalid mapping %q: expected FROM=TO", rule)
+		}
+
+		fromVisibility, err := mastodon.ParseVisibility(from)
+		if err != nil {
+			return nil, invalidf("invalid mapping %q: %v", rule, err)
+		}
+
+		if strings.EqualFold(strings.TrimSpace(to), importSkip) {
+			mapping[fromVisibility] = ""
+			continue
+		}
+		toVisibility, err := mastodon.ParseVisibility(to)
+		if err != nil {
+			return nil, invalidf("invalid mapping %q: %v", rule, err)
+		}
+		mapping[fromVisibility] = toVisibility
+	}
+
+	return mapping, nil
+}
+
+// importItem is a note from the outbox along with how it will be posted
+type importItem struct {
+	note       *outbox.Note
+	text       string
+	visibility mastodon.Visibility
+	selected   bool
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	mapping, err := parseImportMap(importMap)
+	if err != nil {
+		return err
+	}
+
+	notes, err := outbox.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	var items []importItem
+	skipped := 0
+	for _, note := range notes {
+		if importSkipReplies && note.InReplyTo != "" {
+			skipped++
+			continue
+		}
+
+		visibility := note.Visibility
+		if mapped, ok := mapping[visibility]; ok {
+			visibility = mapped
+		}
+		if visibility == "" {
+			skipped++
+			continue
+		}
+
+		items = append(items, importItem{
+			note:       note,
+			text:       htmlToText(note.Content),
+			visibility: visibility,
+			selected:   importAll,
+		})
+	}
+
+	if skipped > 0 {
+		output.Info("Skipping %d status(es) by rule.", skipped)
+	}
+	if len(items) == 0 {
+		output.Info("Nothing to import.")
+		return nil
+	}
+
+	if !importAll {
+		if nonInteractive || !isTerminal() {
+			return cancelledf("selecting statuses requires a terminal; use --all to import everything")
+		}
+		items, err = runImportTUI(items)
+		if err != nil {
+			return err
+		}
+	}
+
+	var selected []importItem
+	for _, item := range items {
+		if item.selected {
+			selected = append(selected, item)
+		}
+	}
+
+	if len(selected) == 0 {
+		output.Info("No statuses selected for import.")
+		return nil
+	}
+
+	if importDryRun {
+		output.Info("Dry run mode - would post %d status(es):", len(selected))
+		for _, item := range selected {
+			output.Plain("%s  %-8s  %s", item.note.Published.Local().Format("2006-01-02"), item.visibility, truncate(item.text, 60))
+		}
+		return nil
+	}
+
+	if !importForce {
+		ok, err := confirm("--force", "Post %d status(es)?", len(selected))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Info("Import cancelled.")
+			return errCancelled
+		}
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	// Attachment URLs are relative to the root of the export
+	archiveDir := filepath.Dir(args[0])
+
+	posted := 0
+	for _, item := range selected {
+		status, err := importNote(client, archiveDir, item)
+		if err != nil {
+			output.Error("Failed to import status from %s: %v", item.note.Published.Local().Format("2006-01-02"), err)
+			continue
+		}
+
+		if err := store.AddPostToHistory(status.ID); err != nil {
+			output.Error("Failed to save post to history: %v", err)
+		}
+		posted++
+		output.Result(status.ID, status.URL)
+		runHook(store, hookPost, status)
+	}
+
+	output.Success("Imported %d of %d status(es)", posted, len(selected))
+	return nil
+}
+
+func importNote(client *mastodon.Client, archiveDir string, item importItem) (*mastodon.Status, error) {
+	var images []postImage
+	for _, attachment := range item.note.Attachments {
+		if !strings.HasPrefix(attachment.MediaType, "image/") {
+			output.Info("Leaving out %s attachment %s", attachment.MediaType, attachment.URL)
+			continue
+		}
+
+		path := filepath.Join(archiveDir, filepath.FromSlash(strings.TrimPrefix(attachment.URL, "/")))
+		images = append(images, postImage{Path: path, Alt: attachment.Name})
+	}
+
+	mediaIDs, err := uploadImages(client, images)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.PostStatus(mastodon.StatusParams{
+		Status:      item.text,
+		Visibility:  item.visibility,
+		SpoilerText: item.note.Summary,
+		MediaIDs:    mediaIDs,
+		Sensitive:   item.note.Sensitive,
+	})
+}
+
+// TUI for choosing which statuses to import
+
+type importModel struct {
+	items    []importItem
+	cursor   int
+	offset   int
+	height   int
+	quitting bool
+	done     bool
+}
+
+func (m importModel) itemLines(i int) int {
+	if m.items[i].note.InReplyTo != "" {
+		return 2
+	}
+	return 1
+}
+
+func (m importModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m importModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+
+		case " ":
+			m.items[m.cursor].selected = !m.items[m.cursor].selected
+
+		case "a":
+			// Select all, or clear the selection if everything is selected
+			all := true
+			for _, item := range m.items {
+				if !item.selected {
+					all = false
+					break
+				}
+			}
+			for i := range m.items {
+				m.items[i].selected = !all
+			}
+
+		case "enter":
+			m.done = true
+			return m, tea.Quit
+		}
+	}
+
+	m.offset = scrollOffset(m.offset, m.cursor, m.height-tuiChromeLines, m.itemLines)
+	return m, nil
+}
+
+func (m importModel) View() string {
+	if m.quitting || m.done {
+		return ""
+	}
+
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	b.WriteString(headerStyle.Render("Import Posts"))
+	b.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	b.WriteString(helpStyle.Render("↑/k: up  ↓/j: down  space: toggle  a: all  enter: import  q: quit"))
+	b.WriteString("\n\n")
+
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	normalStyle := lipgloss.NewStyle()
+
+	end := visibleItems(m.offset, len(m.items), m.height-tuiChromeLines, m.itemLines)
+	for i := m.offset; i < end; i++ {
+		item := m.items[i]
+		cursor := " "
+		if m.cursor == i {
+			cursor = ">"
+		}
+
+		checkbox := "[ ]"
+		if item.selected {
+			checkbox = "[×]"
+		}
+
+		line := fmt.Sprintf("%s %s %s %-8s %s", cursor, checkbox,
+			item.note.Published.Local().Format("2006-01-02"), item.visibility, truncate(item.text, 60))
+
+		if m.cursor == i {
+			line = cursorStyle.Render(line)
+		} else if item.selected {
+			line = selectedStyle.Render(line)
+		} else {
+			line = normalStyle.Render(line)
+		}
+
+		b.WriteString(line)
+		b.WriteString("\n")
+
+		if item.note.InReplyTo != "" {
+			b.WriteString(helpStyle.Render("    ↳ reply (will be posted standalone)"))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.offset > 0 || end < len(m.items) {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  %d–%d of %d", m.offset+1, end, len(m.items))))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func runImportTUI(items []importItem) ([]importItem, error) {
+	p := tea.NewProgram(importModel{items: items}, tea.WithContext(runContext))
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("error running TUI: %w", err)
+	}
+
+	m := finalModel.(importModel)
+	if !m.done {
+		return nil, nil
+	}
+	return m.items, nil
+}
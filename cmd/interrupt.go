@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"biesnecker.com/tusk/internal/output"
+)
+
+// runContext is cancelled when tusk is interrupted with Ctrl+C or SIGTERM.
+// API requests and TUIs stop when it is, so the command can clean up and
+// return instead of being killed partway through.
+var runContext = context.Background()
+
+// handleInterrupts cancels runContext on the first interrupt. A second one
+// quits at once, for anything that isn't watching runContext, such as a
+// prompt waiting for an answer.
+func handleInterrupts() {
+	ctx, cancel := context.WithCancel(context.Background())
+	runContext = ctx
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		// Let the next one through
+		signal.Stop(signals)
+		output.Warning("Stopping. Press Ctrl+C again to quit now.")
+		cancel()
+	}()
+}
+
+// interrupted reports whether tusk was interrupted
+func interrupted() bool {
+	return runContext.Err() != nil
+}
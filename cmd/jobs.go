@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	jobsLimit int
+	jobsClear bool
+)
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Show background post jobs",
+	Long:  `Show the status of posts queued with --async, including the resulting URLs or errors.`,
+	RunE:  runJobs,
+}
+
+var jobsRunCmd = &cobra.Command{
+	Use:    "run ID",
+	Short:  "Run a queued job in the foreground",
+	Args:   cobra.ExactArgs(1),
+	Hidden: true,
+	RunE:   runJobsRun,
+}
+
+func init() {
+	jobsCmd.Flags().IntVarP(&jobsLimit, "limit", "n", 20, "Number of recent jobs to show")
+	jobsCmd.Flags().BoolVar(&jobsClear, "clear", false, "Remove finished and failed jobs")
+	jobsCmd.AddCommand(jobsRunCmd)
+}
+
+// postJob is the payload stored for a queued post
+type postJob struct {
+	Status      string              `json:"status"`
+	InReplyToID string              `json:"in_reply_to_id,omitempty"`
+	Visibility  mastodon.Visibility `json:"visibility,omitempty"`
+	SpoilerText string              `json:"spoiler_text,omitempty"`
+	Language    string              `json:"language,omitempty"`
+	Sensitive   bool                `json:"sensitive,omitempty"`
+	LocalOnly   bool                `json:"local_only,omitempty"`
+	Images      []postImage         `json:"images,omitempty"`
+
+	// IdempotencyKey is chosen when the job is queued, so the worker can't
+	// post it twice however often it's retried
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// Jobs queued by older versions of tusk have at most one image
+	ImagePath string `json:"image_path,omitempty"`
+	AltText   string `json:"alt_text,omitempty"`
+}
+
+// enqueuePost stores a post job and makes sure the background worker is
+// running to send it
+func enqueuePost(store *config.Store, job postJob) error {
+	for i, img := range job.Images {
+		// The worker may not share our working directory
+		absPath, err := filepath.Abs(img.Path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve image path: %w", err)
+		}
+		job.Images[i].Path = absPath
+	}
+
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	id, err := store.AddJob(string(payload))
+	if err != nil {
+		return fmt.Errorf("failed to queue job: %w", err)
+	}
+
+	if err := startDaemon(); err != nil {
+		store.FailJob(id, err.Error())
+		return fmt.Errorf("failed to start background worker: %w", err)
+	}
+
+	output.Success("Post queued as job #%d", id)
+	output.Plain("Run 'tusk jobs' to check on it.")
+	return nil
+}
+
+func runJobs(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	if jobsClear {
+		if err := store.ClearFinishedJobs(); err != nil {
+			return fmt.Errorf("failed to clear jobs: %w", err)
+		}
+		output.Success("Finished jobs cleared!")
+		return nil
+	}
+
+	jobs, err := store.ListJobs(jobsLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID"},
+			{Key: "state", Header: "STATE"},
+			{Key: "created_at", Header: "CREATED"},
+			{Key: "status", Header: "STATUS", Width: 50},
+			{Key: "url", Header: "URL"},
+			{Key: "error", Header: "ERROR"},
+		},
+		Empty: "No background jobs.",
+	}
+
+	payloads := make([]postJob, len(jobs))
+	for i, job := range jobs {
+		json.Unmarshal([]byte(job.Payload), &payloads[i])
+		listing.Rows = append(listing.Rows, []any{
+			job.ID, job.State, job.CreatedAt, payloads[i].Status, output.Href(job.URL), job.Error,
+		})
+	}
+
+	listing.Plain = func(i int) {
+		job := jobs[i]
+		output.Plain("#%d  %-8s  %s  %s", job.ID, job.State,
+			job.CreatedAt.Local().Format("2006-01-02 15:04"), truncate(payloads[i].Status, 50))
+		switch job.State {
+		case config.JobDone:
+			output.URL("    " + job.URL)
+		case config.JobFailed:
+			output.Error("    %s", job.Error)
+		}
+	}
+
+	return output.Render(listing)
+}
+
+func runJobsRun(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return invalidf("invalid job ID %q", args[0])
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	job, err := store.GetJob(id)
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	if job == nil {
+		return fmt.Errorf("no job with ID %d", id)
+	}
+
+	return runJob(store, job)
+}
+
+// runJob claims a pending job, posts it, and records the result
+func runJob(store *config.Store, job *config.Job) error {
+	claimed, err := store.StartJob(job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to start job: %w", err)
+	}
+	if !claimed {
+		return fmt.Errorf("job #%d is already %s", job.ID, job.State)
+	}
+
+	status, err := performPostJob(store, job)
+	if err != nil {
+		store.FailJob(job.ID, err.Error())
+		return err
+	}
+
+	if err := store.CompleteJob(job.ID, status.ID, status.URL); err != nil {
+		return fmt.Errorf("failed to record job result: %w", err)
+	}
+
+	output.Success("Status posted!")
+	output.Result(status.ID, status.URL)
+	runHook(store, hookPost, status)
+	return nil
+}
+
+func performPostJob(store *config.Store, job *config.Job) (*mastodon.Status, error) {
+	var payload postJob
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode job: %w", err)
+	}
+
+	domain, _ := store.Get("domain")
+	accessToken, _ := store.Get("access_token")
+
+	if accessToken == "" {
+		return nil, errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	images := payload.Images
+	if payload.ImagePath != "" {
+		images = append([]postImage{{Path: payload.ImagePath, Alt: payload.AltText}}, images...)
+	}
+
+	mediaIDs, err := uploadImages(client, images)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := client.PostStatus(mastodon.StatusParams{
+		Status:      payload.Status,
+		InReplyToID: payload.InReplyToID,
+		Visibility:  payload.Visibility,
+		SpoilerText: payload.SpoilerText,
+		MediaIDs:    mediaIDs,
+		Language:    payload.Language,
+		Sensitive:   payload.Sensitive,
+		LocalOnly:   payload.LocalOnly,
+
+		IdempotencyKey: payload.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to post status: %w", err)
+	}
+
+	if err := store.AddPostToHistory(status.ID); err != nil {
+		output.Error("Failed to save post to history: %v", err)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,189 @@
+package cmd
+
+// languageCodes are the ISO 639-1 codes, with their English names, offered
+// when completing --lang
+var languageCodes = []string{
+	"aa\tAfar",
+	"ab\tAbkhazian",
+	"ae\tAvestan",
+	"af\tAfrikaans",
+	"ak\tAkan",
+	"am\tAmharic",
+	"an\tAragonese",
+	"ar\tArabic",
+	"as\tAssamese",
+	"av\tAvaric",
+	"ay\tAymara",
+	"az\tAzerbaijani",
+	"ba\tBashkir",
+	"be\tBelarusian",
+	"bg\tBulgarian",
+	"bi\tBislama",
+	"bm\tBambara",
+	"bn\tBengali",
+	"bo\tTibetan",
+	"br\tBreton",
+	"bs\tBosnian",
+	"ca\tCatalan",
+	"ce\tChechen",
+	"ch\tChamorro",
+	"co\tCorsican",
+	"cr\tCree",
+	"cs\tCzech",
+	"cu\tChurch Slavic",
+	"cv\tChuvash",
+	"cy\tWelsh",
+	"da\tDanish",
+	"de\tGerman",
+	"dv\tDivehi",
+	"dz\tDzongkha",
+	"ee\tEwe",
+	"el\tGreek",
+	"en\tEnglish",
+	"eo\tEsperanto",
+	"es\tSpanish",
+	"et\tEstonian",
+	"eu\tBasque",
+	"fa\tPersian",
+	"ff\tFulah",
+	"fi\tFinnish",
+	"fj\tFijian",
+	"fo\tFaroese",
+	"fr\tFrench",
+	"fy\tWestern Frisian",
+	"ga\tIrish",
+	"gd\tScottish Gaelic",
+	"gl\tGalician",
+	"gn\tGuarani",
+	"gu\tGujarati",
+	"gv\tManx",
+	"ha\tHausa",
+	"he\tHebrew",
+	"hi\tHindi",
+	"ho\tHiri Motu",
+	"hr\tCroatian",
+	"ht\tHaitian",
+	"hu\tHungarian",
+	"hy\tArmenian",
+	"hz\tHerero",
+	"ia\tInterlingua",
+	"id\tIndonesian",
+	"ie\tInterlingue",
+	"ig\tIgbo",
+	"ii\tSichuan Yi",
+	"ik\tInupiaq",
+	"io\tIdo",
+	"is\tIcelandic",
+	"it\tItalian",
+	"iu\tInuktitut",
+	"ja\tJapanese",
+	"jv\tJavanese",
+	"ka\tGeorgian",
+	"kg\tKongo",
+	"ki\tKikuyu",
+	"kj\tKuanyama",
+	"kk\tKazakh",
+	"kl\tKalaallisut",
+	"km\tKhmer",
+	"kn\tKannada",
+	"ko\tKorean",
+	"kr\tKanuri",
+	"ks\tKashmiri",
+	"ku\tKurdish",
+	"kv\tKomi",
+	"kw\tCornish",
+	"ky\tKyrgyz",
+	"la\tLatin",
+	"lb\tLuxembourgish",
+	"lg\tGanda",
+	"li\tLimburgish",
+	"ln\tLingala",
+	"lo\tLao",
+	"lt\tLithuanian",
+	"lu\tLuba-Katanga",
+	"lv\tLatvian",
+	"mg\tMalagasy",
+	"mh\tMarshallese",
+	"mi\tMaori",
+	"mk\tMacedonian",
+	"ml\tMalayalam",
+	"mn\tMongolian",
+	"mr\tMarathi",
+	"ms\tMalay",
+	"mt\tMaltese",
+	"my\tBurmese",
+	"na\tNauru",
+	"nb\tNorwegian Bokmål",
+	"nd\tNorth Ndebele",
+	"ne\tNepali",
+	"ng\tNdonga",
+	"nl\tDutch",
+	"nn\tNorwegian Nynorsk",
+	"no\tNorwegian",
+	"nr\tSouth Ndebele",
+	"nv\tNavajo",
+	"ny\tChichewa",
+	"oc\tOccitan",
+	"oj\tOjibwa",
+	"om\tOromo",
+	"or\tOriya",
+	"os\tOssetian",
+	"pa\tPunjabi",
+	"pi\tPali",
+	"pl\tPolish",
+	"ps\tPashto",
+	"pt\tPortuguese",
+	"qu\tQuechua",
+	"rm\tRomansh",
+	"rn\tRundi",
+	"ro\tRomanian",
+	"ru\tRussian",
+	"rw\tKinyarwanda",
+	"sa\tSanskrit",
+	"sc\tSardinian",
+	"sd\tSindhi",
+	"se\tNorthern Sami",
+	"sg\tSango",
+	"si\tSinhala",
+	"sk\tSlovak",
+	"sl\tSlovenian",
+	"sm\tSamoan",
+	"sn\tShona",
+	"so\tSomali",
+	"sq\tAlbanian",
+	"sr\tSerbian",
+	"ss\tSwati",
+	"st\tSouthern Sotho",
+	"su\tSundanese",
+	"sv\tSwedish",
+	"sw\tSwahili",
+	"ta\tTamil",
+	"te\tTelugu",
+	"tg\tTajik",
+	"th\tThai",
+	"ti\tTigrinya",
+	"tk\tTurkmen",
+	"tl\tTagalog",
+	"tn\tTswana",
+	"to\tTongan",
+	"tr\tTurkish",
+	"ts\tTsonga",
+	"tt\tTatar",
+	"tw\tTwi",
+	"ty\tTahitian",
+	"ug\tUyghur",
+	"uk\tUkrainian",
+	"ur\tUrdu",
+	"uz\tUzbek",
+	"ve\tVenda",
+	"vi\tVietnamese",
+	"vo\tVolapük",
+	"wa\tWalloon",
+	"wo\tWolof",
+	"xh\tXhosa",
+	"yi\tYiddish",
+	"yo\tYoruba",
+	"za\tZhuang",
+	"zh\tChinese",
+	"zu\tZulu",
+}
@@ -4,11 +4,13 @@ import (
 	"fmt"
 
 	"biesnecker.com/tusk/internal/config"
-	"biesnecker.com/tusk/internal/mastodon"
 	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
 	"github.com/spf13/cobra"
 )
 
+var latestOpen bool
+
 var latestCmd = &cobra.Command{
 	Use:   "latest",
 	Short: "Display the latest post",
@@ -16,6 +18,10 @@ var latestCmd = &cobra.Command{
 	RunE:  runLatest,
 }
 
+func init() {
+	latestCmd.Flags().BoolVar(&latestOpen, "open", false, "Open the post in your browser")
+}
+
 func runLatest(cmd *cobra.Command, args []string) error {
 	store, err := config.NewStore()
 	if err != nil {
@@ -23,14 +29,16 @@ func runLatest(cmd *cobra.Command, args []string) error {
 	}
 	defer store.Close()
 
-	domain, _ := store.Get("domain")
-	accessToken, _ := store.Get("access_token")
+	domain, accessToken := credentials(store)
 
 	if accessToken == "" {
-		return fmt.Errorf("not authenticated. Run 'tusk auth' first")
+		return errNotAuthenticated
 	}
 
-	client := mastodon.NewClient(domain, accessToken)
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
 
 	// Get the last post ID from history
 	lastPostID, err := store.GetLastPostID()
@@ -50,11 +58,52 @@ func runLatest(cmd *cobra.Command, args []string) error {
 	// Display the status
 	output.Success("Latest post:")
 	output.Plain("ID: %s", status.ID)
-	output.URL(status.URL)
+	output.Result(status.ID, status.URL)
+	if reply := describeReply(store, client, status); reply != "" {
+		output.Info("%s", reply)
+	}
+	printStatusDetails(status)
 	output.Plain("")
 	output.Plain("Content:")
-	content := stripHTML(status.Content)
-	output.Plain("%s", content)
+	content := output.Highlight(stripHTML(status.Content))
+	output.Plain("%s", output.Fold(status.SpoilerText, content, true))
+	printPoll(status.Poll)
+	printCard(status.Card)
+
+	if latestOpen {
+		openInBrowser(status.URL)
+	}
 
 	return nil
 }
+
+// printStatusDetails prints when and how a status was posted, and how it's
+// been received
+func printStatusDetails(status *mastodon.Status) {
+	posted := fmt.Sprintf("Posted %s, %s", status.CreatedAt.Local().Format("2006-01-02 15:04"), status.Visibility)
+	if status.Application != nil && status.Application.Name != "" {
+		posted += " via " + status.Application.Name
+	}
+	output.Plain("%s", posted)
+
+	replies := fmt.Sprintf("%d replies", status.RepliesCount)
+	if status.RepliesCount == 1 {
+		replies = "1 reply"
+	}
+	output.Plain("%s, %s, %s", pluralize(status.FavouritesCount, "favourite"), pluralize(status.ReblogsCount, "boost"), replies)
+}
+
+// printCard prints the preview of a status's link
+func printCard(card *mastodon.Card) {
+	if card == nil || card.URL == "" {
+		return
+	}
+
+	output.Plain("")
+	if card.Title != "" {
+		output.Plain("Link: %s", card.Title)
+	} else {
+		output.Plain("Link:")
+	}
+	output.URL("  " + card.URL)
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"biesnecker.com/tusk/internal/config"
-	"biesnecker.com/tusk/internal/mastodon"
 	"biesnecker.com/tusk/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -33,7 +32,10 @@ func runLogout(cmd *cobra.Command, args []string) error {
 	clientID, _ := store.Get("client_id")
 	clientSecret, _ := store.Get("client_secret")
 
-	client := mastodon.NewClient(domain, accessToken)
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
 
 	output.Info("Revoking access token...")
 	if err := client.RevokeToken(clientID, clientSecret); err != nil {
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mentionsLimit       int
+	mentionsUnread      bool
+	mentionsReplyEditor bool
+	mentionsReplyDryRun bool
+)
+
+var mentionsCmd = &cobra.Command{
+	Use:   "mentions",
+	Short: "List your recent mentions",
+	Long: `List the statuses that mention you, newest first, numbered so you can reply to
+one without copying its ID. Mentions you haven't seen in a listing before are
+marked; listing them marks them seen. Numbers stay as they were listed until
+the next listing, even if more mentions arrive.
+
+Examples:
+  tusk mentions
+  tusk mentions --unread
+  tusk mentions reply 2 "Thanks!"`,
+	Args: cobra.NoArgs,
+	RunE: runMentions,
+}
+
+var mentionsReplyCmd = &cobra.Command{
+	Use:         "reply N [TEXT]",
+	Short:       "Reply to the Nth mention in the last listing",
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runMentionsReply,
+}
+
+func init() {
+	mentionsCmd.Flags().IntVarP(&mentionsLimit, "limit", "n", 20, "Number of mentions to list (max 40)")
+	mentionsCmd.Flags().BoolVar(&mentionsUnread, "unread", false, "Only list mentions you haven't seen")
+
+	mentionsReplyCmd.Flags().BoolVarP(&mentionsReplyEditor, "editor", "e", false, "Compose reply in $EDITOR")
+	mentionsReplyCmd.Flags().BoolVar(&mentionsReplyDryRun, "dry-run", false, "Show what would be sent without actually sending")
+
+	mentionsCmd.AddCommand(mentionsReplyCmd)
+}
+
+// Mentions keep two cursors per account: the newest mention seen in a
+// listing, and the notification IDs of the last listing in order, which
+// 'mentions reply N' numbers from
+func mentionsCursors(store *config.Store) (seen, listed string) {
+	account, _ := store.Account()
+	return "mentions_seen:" + account, "mentions_listed:" + account
+}
+
+func runMentions(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	notifications, err := client.GetNotifications("", []string{mastodon.NotificationMention}, mentionsLimit)
+	if err != nil {
+		return err
+	}
+
+	seenCursor, listedCursor := mentionsCursors(store)
+	lastSeen, _ := store.GetCursor(seenCursor)
+
+	var mentions []*mastodon.Notification
+	var unread []bool
+	for _, n := range notifications {
+		if n.Status == nil {
+			continue
+		}
+		isNew := newerID(n.ID, lastSeen)
+		if mentionsUnread && !isNew {
+			continue
+		}
+		mentions = append(mentions, n)
+		unread = append(unread, isNew)
+	}
+
+	statuses := make([]*mastodon.Status, len(mentions))
+	ids := make([]string, len(mentions))
+	for i, n := range mentions {
+		statuses[i] = n.Status
+		ids[i] = n.ID
+	}
+	cacheStatuses(store, statuses)
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "index", Header: "#"},
+			{Key: "unread", Header: "NEW"},
+			{Key: "notification_id", Header: "NOTIFICATION", Detail: true},
+			{Key: "status_id", Header: "STATUS", Detail: true},
+			{Key: "created_at", Header: "CREATED"},
+			{Key: "acct", Header: "FROM", Prefix: "@"},
+			{Key: "content", Header: "CONTENT", Width: 60},
+			{Key: "url", Header: "URL"},
+		},
+		Empty: "No mentions.",
+	}
+	if mentionsUnread {
+		listing.Empty = "No new mentions."
+	}
+
+	for i, n := range mentions {
+		listing.Rows = append(listing.Rows, []any{
+			i + 1, unread[i], n.ID, n.Status.ID, n.Status.CreatedAt, statusAuthor(n.Status),
+			stripHTML(n.Status.Content), output.Href(n.Status.URL),
+		})
+	}
+
+	listing.Plain = func(i int) {
+		status := mentions[i].Status
+		marker := " "
+		if unread[i] {
+			marker = "*"
+		}
+		content := output.Highlight(truncate(stripHTML(status.Content), 60))
+		output.Plain("%s %2d  %s  @%s  %s", marker, i+1, status.CreatedAt.Local().Format("2006-01-02 15:04"),
+			statusAuthor(status), output.Fold(status.SpoilerText, content, false))
+	}
+
+	if err := output.Render(listing); err != nil {
+		return err
+	}
+
+	if err := store.SetCursor(listedCursor, strings.Join(ids, ",")); err != nil {
+		output.Error("Failed to save the listing: %v", err)
+	}
+	if len(mentions) > 0 && newerID(mentions[0].ID, lastSeen) {
+		if err := store.SetCursor(seenCursor, mentions[0].ID); err != nil {
+			output.Error("Failed to mark mentions seen: %v", err)
+		}
+	}
+	return nil
+}
+
+func runMentionsReply(cmd *cobra.Command, args []string) error {
+	index, err := strconv.Atoi(args[0])
+	if err != nil || index < 1 {
+		return invalidf("N must be a mention's number from 'tusk mentions', 1 or more")
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	_, listedCursor := mentionsCursors(store)
+	listed, err := store.GetCursor(listedCursor)
+	if err != nil {
+		return fmt.Errorf("failed to load the last listing: %w", err)
+	}
+	ids := splitList(listed)
+	if index > len(ids) {
+		return invalidf("there's no mention %d in the last listing. Run 'tusk mentions' first", index)
+	}
+
+	notification, err := client.GetNotification(ids[index-1])
+	if err != nil {
+		return err
+	}
+	target := notification.Status
+	if target == nil {
+		return fmt.Errorf("mention %d's status is gone", index)
+	}
+
+	replyText, err := getStatusText(args[1:], mentionsReplyEditor)
+	if err != nil {
+		return err
+	}
+
+	if replyText == "" {
+		return invalidf("reply text cannot be empty")
+	}
+
+	me, err := client.VerifyCredentials()
+	if err != nil {
+		return err
+	}
+
+	statusText, notified := addReplyMentions(replyText, me, target, true)
+
+	// A reply reaches no one the mention didn't, so private mentions stay
+	// private
+	params := mastodon.StatusParams{
+		Status:      statusText,
+		InReplyToID: target.ID,
+		Visibility:  target.Visibility,
+		SpoilerText: replySpoilerText(target.SpoilerText),
+		Language:    target.Language,
+
+		IdempotencyKey: mastodon.NewIdempotencyKey(),
+	}
+
+	if mentionsReplyDryRun {
+		output.Info("Dry run mode - would send:")
+		output.Plain("In reply to: @%s: %s", statusAuthor(target), truncate(stripHTML(target.Content), 70))
+		output.Plain("Status: %s", statusText)
+		output.Plain("Visibility: %s", params.Visibility)
+		if params.SpoilerText != "" {
+			output.Plain("Content warning: %s", params.SpoilerText)
+		}
+		return nil
+	}
+
+	output.Info("Sending reply...")
+	status, err := client.PostStatus(params)
+	if err != nil {
+		return fmt.Errorf("failed to send reply: %w", err)
+	}
+
+	if err := store.AddPostToHistory(status.ID); err != nil {
+		output.Error("Failed to save post to history: %v", err)
+	}
+	cacheStatuses(store, []*mastodon.Status{status})
+
+	if len(notified) > 0 {
+		output.Success("Reply sent to %s!", formatMentions(notified))
+	} else {
+		output.Success("Reply sent!")
+	}
+	output.Result(status.ID, status.URL)
+	runHook(store, hookPost, status)
+
+	return nil
+}
+
+// newerID reports whether the Mastodon ID a comes after b. IDs are numbers
+// too big for an int64 on some servers, so they're compared as strings; an
+// empty b comes before everything.
+func newerID(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a > b
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import "testing"
+
+func TestNewerID(t *testing.T) {
+	for _, tc := range []struct {
+		a, b  string
+		newer bool
+	}{
+		{"110", "", true},
+		{"110", "109", true},
+		{"99", "100", false},
+		{"109", "110", false},
+		{"110", "110", false},
+		{"113012345678901234", "99999999999999999", true},
+	} {
+		if got := newerID(tc.a, tc.b); got != tc.newer {
+			t.Errorf("newerID(%q, %q) = %v, expected %v", tc.a, tc.b, got, tc.newer)
+		}
+	}
+}
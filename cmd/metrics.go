@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsDays  int
+	metricsClear bool
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Show API request latency for your instance",
+	Long: `Show how long API requests to your instance have been taking, per endpoint.
+
+Tusk records the duration and result of every request it makes, and whether
+it was a retry. Use this to see whether slowness comes from a particular
+endpoint, failing requests, retries, or rate limiting.
+
+Examples:
+  tusk metrics
+  tusk metrics --days 1
+  tusk metrics --clear`,
+	Args: cobra.NoArgs,
+	RunE: runMetrics,
+}
+
+func init() {
+	metricsCmd.Flags().IntVarP(&metricsDays, "days", "d", 7, "Only include requests from the last N days")
+	metricsCmd.Flags().BoolVar(&metricsClear, "clear", false, "Forget all recorded requests")
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	if metricsClear {
+		if err := store.ClearRequestMetrics(); err != nil {
+			return fmt.Errorf("failed to clear metrics: %w", err)
+		}
+		output.Success("Metrics cleared!")
+		return nil
+	}
+
+	since := time.Now().AddDate(0, 0, -metricsDays)
+	summaries, err := store.SummarizeRequestMetrics(since)
+	if err != nil {
+		return fmt.Errorf("failed to read metrics: %w", err)
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "method", Header: "METHOD"},
+			{Key: "endpoint", Header: "ENDPOINT", Width: 40},
+			{Key: "count", Header: "COUNT"},
+			{Key: "p50_ms", Header: "P50"},
+			{Key: "p90_ms", Header: "P90"},
+			{Key: "p99_ms", Header: "P99"},
+			{Key: "max_ms", Header: "MAX"},
+			{Key: "failed", Header: "FAILED"},
+			{Key: "rate_limited", Header: "LIMITED"},
+			{Key: "retries", Header: "RETRIES"},
+		},
+		Empty:   fmt.Sprintf("No requests recorded in the last %d day(s).", metricsDays),
+		Tabular: true,
+	}
+
+	total, failed, limited, retries := 0, 0, 0, 0
+	for _, s := range summaries {
+		listing.Rows = append(listing.Rows, []any{
+			s.Method, s.Endpoint, s.Count, s.P50, s.P90, s.P99, s.Max, s.Failed, s.RateLimited, s.Retries,
+		})
+		total += s.Count
+		failed += s.Failed
+		limited += s.RateLimited
+		retries += s.Retries
+	}
+
+	if err := output.Render(listing); err != nil {
+		return err
+	}
+
+	if len(summaries) > 0 {
+		output.Info("\n%d request(s) in the last %d day(s), %d failed, %d rate limited, %d retries", total, metricsDays, failed, limited, retries)
+	}
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/desktop"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+// notifyPageLimit is the most notifications the API returns at once; any
+// more that arrive between checks are skipped
+const notifyPageLimit = 40
+
+var (
+	notifyWatch    bool
+	notifyInterval time.Duration
+	notifyTypes    []string
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Show new notifications as desktop notifications",
+	Long: `Raise a desktop notification for each notification that's arrived since the
+last check. The first run only notes where your notifications are up to.
+
+Types can be limited with --type or the notify_types setting, and the
+notify_quiet_hours setting (e.g. 22:00-07:00) keeps notifications off the
+desktop at night; they're still printed.
+
+Examples:
+  tusk notify --watch
+  tusk notify --watch --type mention --type follow
+  tusk config set notify_quiet_hours 22:00-07:00`,
+	Args: cobra.NoArgs,
+	RunE: runNotify,
+}
+
+func init() {
+	notifyCmd.Flags().BoolVar(&notifyWatch, "watch", false, "Keep checking until interrupted")
+	notifyCmd.Flags().DurationVar(&notifyInterval, "interval", time.Minute, "How often --watch checks")
+	notifyCmd.Flags().StringSliceVarP(&notifyTypes, "type", "t", nil, "Only notify about this type, e.g. mention, follow, favourite (repeatable)")
+}
+
+func runNotify(cmd *cobra.Command, args []string) error {
+	if notifyInterval < 10*time.Second {
+		return invalidf("--interval must be at least 10s")
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	types := notifyTypes
+	if len(types) == 0 {
+		types = splitList(getSetting(store, "notify_types"))
+	}
+	if err := validateNotificationTypes(types); err != nil {
+		return invalidf("%v", err)
+	}
+	quietHours, _ := parseQuietHours(getSetting(store, "notify_quiet_hours"))
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	account, _ := store.Account()
+	cursorName := "notify:" + account
+	since, err := store.GetCursor(cursorName)
+	if err != nil {
+		return fmt.Errorf("failed to load saved position: %w", err)
+	}
+
+	// Starting from the beginning would raise every old notification
+	if since == "" {
+		latest, err := client.GetNotifications("", nil, 1)
+		if err != nil {
+			return fmt.Errorf("failed to get notifications: %w", err)
+		}
+		if len(latest) > 0 {
+			since = latest[0].ID
+			saveCursor(store, cursorName, since)
+		}
+		output.Info("Only notifications from now on will be shown.")
+	}
+
+	check := func() error {
+		notifications, err := client.GetNotifications(since, types, notifyPageLimit)
+		if err != nil {
+			return fmt.Errorf("failed to get notifications: %w", err)
+		}
+
+		// Oldest first, moving the cursor past each one shown, so a failure
+		// doesn't show any twice
+		quiet := quietHours.contains(time.Now())
+		shown := since
+		defer func() {
+			if shown != since {
+				since = shown
+				saveCursor(store, cursorName, since)
+			}
+		}()
+		for i := len(notifications) - 1; i >= 0; i-- {
+			title, body := describeNotification(notifications[i])
+			output.Plain("%s  %s", title, body)
+			if !quiet {
+				if err := desktop.Notify(title, body); err != nil {
+					return err
+				}
+			}
+			shown = notifications[i].ID
+		}
+		return nil
+	}
+
+	if !notifyWatch {
+		return check()
+	}
+
+	output.Info("Watching for notifications every %s. Press Ctrl+C to stop.", notifyInterval)
+	ticker := time.NewTicker(notifyInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := check(); err != nil {
+			if interrupted() {
+				return nil
+			}
+			if apiErr, ok := mastodon.AsAPIError(err); ok && apiErr.Unauthorized() {
+				return err
+			}
+			// Keep watching through a flaky connection
+			output.Error("%v", err)
+		}
+
+		select {
+		case <-runContext.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// describeNotification is the title and body of a desktop notification
+func describeNotification(n *mastodon.Notification) (title, body string) {
+	who := "Someone"
+	if n.Account != nil {
+		who = "@" + n.Account.Acct
+	}
+
+	switch n.Type {
+	case mastodon.NotificationMention:
+		title = who + " mentioned you"
+	case mastodon.NotificationStatus:
+		title = who + " posted"
+	case mastodon.NotificationReblog:
+		title = who + " boosted your post"
+	case mastodon.NotificationFavourite:
+		title = who + " favourited your post"
+	case mastodon.NotificationFollow:
+		title = who + " followed you"
+	case mastodon.NotificationFollowRequest:
+		title = who + " asked to follow you"
+	case mastodon.NotificationPoll:
+		title = "A poll has ended"
+	case mastodon.NotificationUpdate:
+		title = who + " edited a post"
+	default:
+		title = fmt.Sprintf("%s (%s)", who, n.Type)
+	}
+
+	if n.Status != nil {
+		body = n.Status.SpoilerText
+		if body == "" {
+			body = truncate(stripHTML(n.Status.Content), 120)
+		}
+	}
+	return title, body
+}
+
+func validateNotificationTypes(types []string) error {
+	for _, t := range types {
+		if !slices.Contains(mastodon.NotificationTypes, t) {
+			return fmt.Errorf("unknown notification type %q; expected one of %s", t, strings.Join(mastodon.NotificationTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// splitList reads a comma-separated setting, ignoring blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// quietHours is a daily span, in minutes after midnight, that may wrap past
+// midnight. The zero value is never quiet.
+type quietHours struct {
+	start, end int
+}
+
+// parseQuietHours reads a span such as 22:00-07:00. An empty value means no
+// quiet hours.
+func parseQuietHours(value string) (quietHours, error) {
+	if value == "" {
+		return quietHours{}, nil
+	}
+
+	errSpan := fmt.Errorf("must be a span such as 22:00-07:00")
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return quietHours{}, errSpan
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return quietHours{}, errSpan
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return quietHours{}, errSpan
+	}
+	return quietHours{
+		start: start.Hour()*60 + start.Minute(),
+		end:   end.Hour()*60 + end.Minute(),
+	}, nil
+}
+
+// contains reports whether t, in local time, falls within the quiet hours
+func (q quietHours) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if q.start <= q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
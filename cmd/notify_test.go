@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 15, hour, minute, 0, 0, time.Local)
+	}
+
+	overnight, err := parseQuietHours("22:00-07:00")
+	if err != nil {
+		t.Fatalf("Failed to parse quiet hours: %v", err)
+	}
+	for _, tc := range []struct {
+		time  time.Time
+		quiet bool
+	}{
+		{at(21, 59), false},
+		{at(22, 0), true},
+		{at(3, 0), true},
+		{at(7, 0), false},
+	} {
+		if got := overnight.contains(tc.time); got != tc.quiet {
+			t.Errorf("At %s expected quiet=%v, got %v", tc.time.Format("15:04"), tc.quiet, got)
+		}
+	}
+
+	lunch, _ := parseQuietHours("12:00-13:30")
+	if !lunch.contains(at(13, 0)) || lunch.contains(at(14, 0)) {
+		t.Error("Expected 12:00-13:30 to cover 13:00 and not 14:00")
+	}
+
+	none, _ := parseQuietHours("")
+	if none.contains(at(0, 0)) {
+		t.Error("Expected no quiet hours by default")
+	}
+
+	for _, bad := range []string{"22:00", "late-early", "25:00-07:00"} {
+		if _, err := parseQuietHours(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pinLatest   bool
+	unpinLatest bool
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin [ID]",
+	Short: "Pin a status to your profile",
+	Long: `Pin one of your statuses to the top of your profile.
+
+Examples:
+  tusk pin 109876543210
+  tusk pin --latest`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: needsScopes("write:accounts"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPinAction(args, pinLatest, true)
+	},
+}
+
+var unpinCmd = &cobra.Command{
+	Use:   "unpin [ID]",
+	Short: "Unpin a status from your profile",
+	Long: `Take a pinned status off the top of your profile.
+
+Examples:
+  tusk unpin 109876543210
+  tusk unpin --latest`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: needsScopes("write:accounts"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPinAction(args, unpinLatest, false)
+	},
+}
+
+var pinsCmd = &cobra.Command{
+	Use:   "pins",
+	Short: "List your pinned statuses",
+	Args:  cobra.NoArgs,
+	RunE:  runPins,
+}
+
+func init() {
+	pinCmd.Flags().BoolVarP(&pinLatest, "latest", "l", false, "Pin the most recent post")
+	unpinCmd.Flags().BoolVarP(&unpinLatest, "latest", "l", false, "Unpin the most recent post")
+}
+
+func runPinAction(args []string, latest, pin bool) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	var statusID string
+
+	if latest {
+		lastPostID, err := store.GetLastPostID()
+		if err != nil {
+			return fmt.Errorf("failed to get last post ID: %w", err)
+		}
+		if lastPostID == "" {
+			return fmt.Errorf("no posts in history")
+		}
+		statusID = lastPostID
+	} else if len(args) == 1 {
+		statusID, err = client.ResolveStatusID(args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		return invalidf("must provide status ID or use --latest flag")
+	}
+
+	if !pin {
+		if _, err := client.UnpinStatus(statusID); err != nil {
+			return err
+		}
+		output.Success("Unpinned status %s", statusID)
+		return nil
+	}
+
+	status, err := client.PinStatus(statusID)
+	if err != nil {
+		return err
+	}
+	output.Success("Pinned status %s", statusID)
+	output.Result(status.ID, status.URL)
+	return nil
+}
+
+func runPins(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	accountID, err := client.AccountID()
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	statuses, err := client.GetPinnedStatuses(accountID)
+	if err != nil {
+		return err
+	}
+
+	return output.Render(statusListing(statuses, "No pinned posts."))
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+// pollBarWidth is how many characters a bar for every vote takes
+const pollBarWidth = 20
+
+var pollCmd = &cobra.Command{
+	Use:   "poll ID",
+	Short: "Show the results of a poll",
+	Long: `Show the latest results of the poll in a status, with a bar for each option
+and your own votes marked.
+
+Works without logging in when --instance is given.
+
+Examples:
+  tusk poll 109876543210
+  tusk poll https://example.com/@alice/109876543210`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPoll,
+}
+
+var voteCmd = &cobra.Command{
+	Use:   "vote ID CHOICE[,CHOICE...]",
+	Short: "Vote in a poll",
+	Long: `Vote in the poll in a status, then show its results. A choice is an option's
+number, as 'tusk poll' shows it, or its title. Give several, separated by
+commas, in a poll that allows more than one.
+
+Examples:
+  tusk vote 109876543210 2
+  tusk vote 109876543210 "Tea"
+  tusk vote https://example.com/@alice/109876543210 1,3`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runVote,
+}
+
+func runPoll(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	client, err := newReadClient(store)
+	if err != nil {
+		return err
+	}
+
+	poll, err := fetchPoll(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	printPoll(poll)
+	return nil
+}
+
+func runVote(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	poll, err := fetchPoll(client, args[0])
+	if err != nil {
+		return err
+	}
+	if poll.Expired {
+		return fmt.Errorf("the poll has closed")
+	}
+	if poll.Voted {
+		return invalidf("you've already voted in this poll")
+	}
+
+	choices, err := parseVoteChoices(poll, args[1])
+	if err != nil {
+		return err
+	}
+
+	poll, err = client.VotePoll(poll.ID, choices)
+	if err != nil {
+		return err
+	}
+
+	var titles []string
+	for _, choice := range choices {
+		titles = append(titles, strconv.Quote(poll.Options[choice].Title))
+	}
+	output.Success("Voted for %s", strings.Join(titles, ", "))
+	printPoll(poll)
+	return nil
+}
+
+// fetchPoll finds the poll in a status and fetches its latest results
+func fetchPoll(client *mastodon.Client, ref string) (*mastodon.Poll, error) {
+	statusID, err := client.ResolveStatusID(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := client.GetStatus(statusID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	if status.Poll == nil {
+		return nil, invalidf("status %s doesn't have a poll", statusID)
+	}
+
+	return client.GetPoll(status.Poll.ID)
+}
+
+// parseVoteChoices turns the choices given to vote into the indexes of
+// poll's options. Each is an option's number, counting from 1, or its title;
+// the whole value is tried as a title first, for titles with commas.
+func parseVoteChoices(poll *mastodon.Poll, value string) ([]int, error) {
+	if i := pollOptionIndex(poll, value); i >= 0 {
+		return []int{i}, nil
+	}
+
+	var choices []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		i := pollOptionIndex(poll, part)
+		if n, err := strconv.Atoi(part); i < 0 && err == nil {
+			if n < 1 || n > len(poll.Options) {
+				return nil, invalidf("there's no option %d: the poll has %d", n, len(poll.Options))
+			}
+			i = n - 1
+		}
+		if i < 0 {
+			return nil, invalidf("%q isn't one of the poll's options; give an option's number or title", part)
+		}
+		if !slices.Contains(choices, i) {
+			choices = append(choices, i)
+		}
+	}
+
+	if len(choices) == 0 {
+		return nil, invalidf("give the option to vote for")
+	}
+	if len(choices) > 1 && !poll.Multiple {
+		return nil, invalidf("the poll only allows one choice")
+	}
+	return choices, nil
+}
+
+// pollOptionIndex finds the option titled title, ignoring case, or returns
+// -1
+func pollOptionIndex(poll *mastodon.Poll, title string) int {
+	title = strings.TrimSpace(title)
+	for i, option := range poll.Options {
+		if title != "" && strings.EqualFold(strings.TrimSpace(option.Title), title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// printPoll prints a poll's options, numbered, with a bar for each one's share
+// of the votes so far
+func printPoll(poll *mastodon.Poll) {
+	if poll == nil {
+		return
+	}
+
+	output.Plain("")
+	state := "Poll"
+	switch {
+	case poll.Expired:
+		state = "Poll (closed)"
+	case poll.ExpiresAt != nil:
+		state = fmt.Sprintf("Poll (closes %s)", poll.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	}
+	votes := pluralize(poll.VotesCount, "vote")
+	if poll.Multiple && poll.VotersCount > 0 {
+		votes += " from " + pluralize(poll.VotersCount, "voter")
+	}
+	output.Plain("%s, %s:", state, votes)
+
+	// In a poll with several choices, shares are of the voters, as Mastodon
+	// shows them, so they can add up to more than 100%
+	total := poll.VotesCount
+	if poll.Multiple && poll.VotersCount > 0 {
+		total = poll.VotersCount
+	}
+
+	width := 0
+	for _, option := range poll.Options {
+		width = max(width, utf8.RuneCountInString(option.Title))
+	}
+	for i, option := range poll.Options {
+		mine := ""
+		if slices.Contains(poll.OwnVotes, i) {
+			mine = "  (your vote)"
+		}
+		output.Plain("  %d. %-*s  %s %3d%%  %d%s", i+1, width, option.Title,
+			pollBar(option.VotesCount, total, pollBarWidth), pollPercent(option.VotesCount, total), option.VotesCount, mine)
+	}
+}
+
+// pollBar draws votes' share of total as an ASCII bar width characters wide
+func pollBar(votes, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min((votes*width+total/2)/total, width)
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+// pollPercent is votes' share of total, rounded to a whole percent
+func pollPercent(votes, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return (votes*100 + total/2) / total
+}
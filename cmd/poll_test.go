@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+func TestParseVoteChoices(t *testing.T) {
+	poll := &mastodon.Poll{
+		Multiple: true,
+		Options:  []*mastodon.PollOption{{Title: "Tea"}, {Title: "Coffee, black"}, {Title: "Water"}},
+	}
+
+	for value, want := range map[string][]int{
+		"2":             {1},
+		"1, 3,1":        {0, 2},
+		"water":         {2},
+		"Coffee, black": {1},
+		"Tea,3":         {0, 2},
+	} {
+		got, err := parseVoteChoices(poll, value)
+		if err != nil || !slices.Equal(got, want) {
+			t.Errorf("parseVoteChoices(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"0", "4", "Juice", ","} {
+		if _, err := parseVoteChoices(poll, value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+
+	poll.Multiple = false
+	if _, err := parseVoteChoices(poll, "1,2"); err == nil {
+		t.Error("Expected two choices to be rejected in a single-choice poll")
+	}
+}
+
+func TestPollBar(t *testing.T) {
+	if got := pollBar(1, 3, 9); got != "[###------]" {
+		t.Errorf("Unexpected bar %q", got)
+	}
+	if got := pollBar(0, 0, 4); got != "[----]" {
+		t.Errorf("Expected an empty bar with no votes, got %q", got)
+	}
+	if got := pollPercent(2, 3); got != 67 {
+		t.Errorf("Expected 67%%, got %d%%", got)
+	}
+}
@@ -1,15 +1,20 @@
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"biesnecker.com/tusk/internal/config"
-	"biesnecker.com/tusk/internal/image"
-	"biesnecker.com/tusk/internal/mastodon"
+	"biesnecker.com/tusk/internal/frontmatter"
+	"biesnecker.com/tusk/internal/oauth"
 	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/internal/prefetch"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -18,6 +23,8 @@ import (
 var (
 	replyTo     string
 	replyLast   bool
+	replyIndex  int
+	replyThread bool
 	replyTUI    bool
 	useEditor   bool
 	visibility  string
@@ -26,6 +33,23 @@ var (
 	dryRun      bool
 	imagePath   string
 	altText     string
+	postAsync   bool
+	sensitive   bool
+	postOpen    bool
+	postSeries  string
+	noMentions  bool
+	postFile    string
+	postWatch   string
+	postConfirm bool
+
+	postLocalOnly bool
+	noAutoCW      bool
+	noReplyCW     bool
+	suggestTags   bool
+
+	allowDuplicate bool
+	postFromGit    bool
+	postGitLink    bool
 )
 
 var postCmd = &cobra.Command{
@@ -38,43 +62,126 @@ Examples:
   tusk post -e
   echo "Hello" | tusk post
   tusk post -r STATUS_ID "This is a reply"
-  tusk post -R "Reply to last post"`,
-	RunE: runPost,
+  tusk post -r notif:NOTIFICATION_ID "Replying to a mention"
+  tusk post -R "Reply to last post"
+  tusk post --reply-index 3 "Reply to the third most recent post"
+  tusk post --continue-thread "Next post in my thread"
+  tusk post --async -i big.heic --alt "A photo" "Posting in the background"
+  tusk post --series "Daily Sketch" "Foxes today"
+  tusk post --file post.md
+  tusk post --watch ~/outbox
+  tusk post --from-git --git-link
+  tusk post --from-git v1.2.0`,
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runPost,
 }
 
 func init() {
-	postCmd.Flags().StringVarP(&replyTo, "reply", "r", "", "Reply to a specific status ID")
+	postCmd.Flags().StringVarP(&replyTo, "reply", "r", "", "Reply to a specific status ID or link (or notif:ID for the status of a notification)")
 	postCmd.Flags().BoolVarP(&replyLast, "reply-last", "R", false, "Reply to the last posted status")
+	postCmd.Flags().IntVar(&replyIndex, "reply-index", 0, "Reply to the Nth most recent post in your history (1 is the same as -R)")
+	postCmd.Flags().BoolVar(&replyThread, "continue-thread", false, "Reply to the end of the thread your last post is in")
 	postCmd.Flags().BoolVar(&replyTUI, "reply-tui", false, "Interactive TUI to select post to reply to")
 	postCmd.Flags().BoolVarP(&useEditor, "editor", "e", false, "Compose post in $EDITOR")
-	postCmd.Flags().StringVarP(&visibility, "visibility", "v", "public", "Post visibility (public, unlisted, private, direct)")
+	postCmd.Flags().StringVarP(&visibility, "visibility", "v", "public", "Post visibility: public (p), unlisted (u), private (f), direct (d); defaults to the default_visibility and default_reply_visibility settings")
 	postCmd.Flags().StringVarP(&contentWarn, "cw", "w", "", "Content warning / spoiler text")
 	postCmd.Flags().StringVarP(&language, "lang", "l", "", "ISO 639 language code (e.g., en, es, fr, de, ja)")
 	postCmd.Flags().StringVarP(&imagePath, "image", "i", "", "Path to image file to attach")
 	postCmd.Flags().StringVar(&altText, "alt", "", "Alt text for the image")
 	postCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be posted without actually posting")
+	postCmd.Flags().BoolVar(&sensitive, "sensitive", false, "Mark attached media as sensitive")
+	postCmd.Flags().BoolVar(&postAsync, "async", false, "Queue the post for a background worker and return immediately")
+	postCmd.Flags().BoolVar(&postOpen, "open", false, "Open the status in your browser after posting")
+	postCmd.Flags().StringVar(&postSeries, "series", "", "Append the next label of a numbered series (see 'tusk series')")
+	postCmd.Flags().BoolVar(&noMentions, "no-mentions", false, "Don't add the author and mentions of the status you're replying to")
+	postCmd.Flags().StringVar(&postFile, "file", "", "Read the status, and optional frontmatter, from a file")
+	postCmd.Flags().StringVar(&postWatch, "watch", "", "Post each .md or .txt file dropped into a directory")
+	postCmd.Flags().BoolVar(&postConfirm, "confirm", false, "Show the post and ask before sending it (default from the confirm_before_post setting)")
+	postCmd.Flags().BoolVar(&postLocalOnly, "local-only", false, "Keep the post off other instances (glitch-soc and Hometown only)")
+	postCmd.Flags().BoolVar(&noReplyCW, "no-cw", false, "Don't carry over the content warning of the status you're replying to")
+	postCmd.Flags().BoolVar(&noAutoCW, "no-auto-cw", false, "Don't add content warnings from 'tusk cw-rules'")
+	postCmd.Flags().BoolVar(&allowDuplicate, "allow-duplicate", false, "Post even if the text is the same as one of your recent posts")
+	postCmd.Flags().BoolVar(&postFromGit, "from-git", false, "Post the subject and body of a commit, HEAD unless a ref is given as the argument")
+	postCmd.Flags().BoolVar(&postGitLink, "git-link", false, "With --from-git, link to the commit on the origin remote's web page")
+	postCmd.Flags().BoolVar(&suggestTags, "suggest-tags", false, "List the hashtags you use most, optionally starting with TEXT, instead of posting")
 }
 
 func runPost(cmd *cobra.Command, args []string) error {
+	if suggestTags {
+		return runSuggestTags(args)
+	}
+	if noReplyCW && contentWarn != "" {
+		return invalidf("--cw and --no-cw can't be used together")
+	}
+	if postGitLink && !postFromGit {
+		return invalidf("--git-link only works with --from-git")
+	}
+	if postFromGit {
+		if len(args) > 1 {
+			return invalidf("--from-git takes at most one argument, the commit to post")
+		}
+		if useEditor || postFile != "" || postWatch != "" {
+			return invalidf("--from-git takes the status from a commit; leave out -e, --file, and --watch")
+		}
+	}
+	if postFile != "" || postWatch != "" {
+		if len(args) > 0 || useEditor {
+			return invalidf("--file and --watch take the status from files; leave out the text and -e")
+		}
+		if postFile != "" && postWatch != "" {
+			return invalidf("--file and --watch can't be used together")
+		}
+	}
+	if postWatch != "" {
+		if postConfirm {
+			return invalidf("--confirm can't be used with --watch, since there's no one to ask")
+		}
+		return watchPosts(cmd, postWatch)
+	}
+	return sendPost(cmd, args)
+}
+
+// sendPost posts one status, as set up by the flags
+func sendPost(cmd *cobra.Command, args []string) error {
+	postVisibility, err := mastodon.ParseVisibility(visibility)
+	if err != nil {
+		return err
+	}
+
 	store, err := config.NewStore()
 	if err != nil {
 		return fmt.Errorf("failed to open config store: %w", err)
 	}
 	defer store.Close()
 
-	domain, _ := store.Get("domain")
-	accessToken, _ := store.Get("access_token")
+	domain, accessToken := credentials(store)
 
 	if accessToken == "" {
-		return fmt.Errorf("not authenticated. Run 'tusk auth' first")
+		return errNotAuthenticated
 	}
 
-	client := mastodon.NewClient(domain, accessToken)
+	client, err := newAPI(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	if postLocalOnly {
+		if err := checkLocalOnly(client, domain); err != nil {
+			return err
+		}
+	}
+
+	// Look the series up before anything interactive, so a typo fails fast
+	series, err := loadPostSeries(store, postSeries)
+	if err != nil {
+		return err
+	}
 
 	// Determine reply-to post first (before getting status text)
 	var inReplyToID string
+	var thread threadSnapshot
 	if replyTUI {
-		selectedID, err := runReplyTUI(store, client)
+		selectedID, prefetched, err := runReplyTUI(store, client)
 		if err != nil {
 			return err
 		}
@@ -82,6 +189,9 @@ func runPost(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("no post selected")
 		}
 		inReplyToID = selectedID
+		if useEditor {
+			thread = prefetched
+		}
 	} else if replyLast {
 		lastPostID, err := store.GetLastPostID()
 		if err != nil {
@@ -91,95 +201,317 @@ func runPost(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("no last post found. Post something first or use -r to reply to a specific status")
 		}
 		inReplyToID = lastPostID
+	} else if replyThread {
+		inReplyToID, err = threadTailTarget(store, client)
+		if err != nil {
+			return err
+		}
+	} else if replyIndex != 0 {
+		inReplyToID, err = historyReplyTarget(store, replyIndex)
+		if err != nil {
+			return err
+		}
 	} else if replyTo != "" {
-		inReplyToID = replyTo
+		inReplyToID, err = resolveReplyTarget(client, replyTo)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Editor sessions can run long, so note what the thread looks like now
+	// and check for replies that came in while composing
+	if inReplyToID != "" && useEditor && thread == nil {
+		thread = snapshotThread(client, inReplyToID)
 	}
 
 	// Get status text after selecting reply-to post
-	statusText, err := getStatusText(args, useEditor)
+	var statusText string
+	if postFromGit {
+		ref := "HEAD"
+		if len(args) > 0 {
+			ref = args[0]
+		}
+		statusText, err = gitPostText(client, ref, postGitLink)
+	} else if postFile != "" {
+		statusText, err = readPostFile(postFile)
+	} else if useEditor {
+		statusText, err = getTextFromEditorWithComments("", postEditorComments(store, client, inReplyToID))
+	} else {
+		statusText, err = getStatusText(args, false)
+	}
 	if err != nil {
 		return err
 	}
 
+	// Settings written in the editor take precedence over flags
+	base := postSettings{
+		Visibility:  postVisibility,
+		SpoilerText: contentWarn,
+		Sensitive:   sensitive,
+		Language:    language,
+	}
+	visibilityChanged := cmd.Flags().Changed("visibility")
+	var images []postImage
+	if imagePath != "" {
+		images = append(images, postImage{Path: imagePath, Alt: altText})
+	}
+	var scheduleAt time.Time
+	if useEditor || postFile != "" {
+		meta, body, err := frontmatter.Parse(statusText)
+		if err != nil {
+			if useEditor {
+				output.Info("Your draft was:")
+				output.Plain("%s", statusText)
+			}
+			return err
+		}
+		statusText = body
+		if meta != nil {
+			// Images in a file are found next to it
+			dir := ""
+			if postFile != "" {
+				dir = filepath.Dir(postFile)
+			}
+			visibilitySet, err := applyFrontmatter(meta, dir, &base, &images)
+			if err != nil {
+				return err
+			}
+			visibilityChanged = visibilityChanged || visibilitySet
+			scheduleAt = meta.Schedule
+		}
+	}
+	if !visibilityChanged {
+		base.Visibility = defaultVisibility(store, inReplyToID != "")
+	}
+	if !scheduleAt.IsZero() && postAsync {
+		return invalidf("a scheduled post is already sent later by your instance; leave out --async")
+	}
+	// The worker posts with the stored login
+	if overridingLogin() && postAsync {
+		return invalidf("the background worker can't use --instance or --token; leave out --async")
+	}
+
+	if thread != nil && statusText != "" {
+		fresh, err := newThreadActivity(client, inReplyToID, thread)
+		if err != nil {
+			output.Error("Failed to check the thread for new replies: %v", err)
+		} else if len(fresh) > 0 {
+			cacheStatuses(store, fresh)
+			output.Info("The thread has %d new repl(ies) since you started writing:", len(fresh))
+			for _, status := range fresh {
+				author := "unknown"
+				if status.Account != nil {
+					author = status.Account.Acct
+				}
+				output.Plain("  @%s: %s", author, truncate(stripHTML(status.Content), 70))
+			}
+
+			if !dryRun {
+				ok, err := confirm("", "Send your reply anyway?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					// Don't lose what was written in the editor
+					output.Info("Post cancelled. Your draft was:")
+					output.Plain("%s", statusText)
+					return errCancelled
+				}
+			}
+		}
+	}
+
 	if statusText == "" {
-		return fmt.Errorf("status text cannot be empty")
+		return invalidf("status text cannot be empty")
 	}
 
-	// Verify the status exists if replying
+	// Like the web client, a reply mentions everyone in the conversation so
+	// they're notified, unless --no-mentions says otherwise
 	if inReplyToID != "" {
-		_, err := client.GetStatus(inReplyToID)
+		target, err := client.GetStatus(inReplyToID)
 		if err != nil {
 			return fmt.Errorf("failed to get status to reply to: %w", err)
 		}
+		me, err := client.VerifyCredentials()
+		if err != nil {
+			return err
+		}
+
+		var notified []string
+		statusText, notified = addReplyMentions(statusText, me, target, !noMentions)
+		if len(notified) > 0 {
+			output.Info("This reply will notify %s", formatMentions(notified))
+		} else {
+			output.Info("This reply won't notify anyone")
+		}
+
+		base.Visibility = replyVisibility(base.Visibility, target.Visibility, visibilityChanged)
+		if base.SpoilerText == "" && !noReplyCW && target.SpoilerText != "" {
+			base.SpoilerText = replySpoilerText(target.SpoilerText)
+			output.Info("Keeping the content warning: %s", base.SpoilerText)
+		}
 	}
 
-	// Handle image upload
-	var mediaIDs []string
-	if imagePath != "" {
-		// Check for alt text
-		if altText == "" {
-			output.Prompt("Warning: No alt text provided for image. Continue without alt text? (y/N): ")
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
-			response = strings.TrimSpace(strings.ToLower(response))
-
-			if response != "y" && response != "yes" {
-				output.Info("Post cancelled. Please add --alt \"your alt text\" and try again.")
-				return nil
-			}
+	var seriesNumber int
+	if series != nil {
+		seriesNumber = series.Next()
+		statusText += "\n\n" + series.Label(seriesNumber)
+	}
+
+	if err := checkDuplicate(store, statusText); err != nil {
+		return err
+	}
+
+	// Apply any settings attached to hashtags in the post
+	settings, applied, err := applyHashtagProfiles(store, statusText, base, visibilityChanged)
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 && !dryRun {
+		output.Info("Applied hashtag profiles: %s", strings.Join(applied, "; "))
+	}
+
+	var cwRules []string
+	if !noAutoCW {
+		settings, cwRules, err = applyCWRules(store, statusText, settings)
+		if err != nil {
+			return err
+		}
+		if len(cwRules) > 0 && !dryRun {
+			output.Info("Applied CW rules: %s", strings.Join(cwRules, "; "))
 		}
+	}
 
-		// Process the image (convert HEIC, strip EXIF)
-		output.Info("Processing image...")
-		processedImage, err := image.ProcessImage(imagePath)
+	// Check for alt text
+	var missingAlt []string
+	for _, img := range images {
+		if img.Alt == "" {
+			missingAlt = append(missingAlt, img.Path)
+		}
+	}
+	if len(missingAlt) > 0 && watchingPosts {
+		return invalidf("no alt text for %s; add alt: to each image in the frontmatter", strings.Join(missingAlt, ", "))
+	}
+	if len(missingAlt) > 0 {
+		missing := "image"
+		if len(images) > 1 {
+			missing = strings.Join(missingAlt, ", ")
+		}
+		ok, err := confirm("--alt", "Warning: No alt text provided for %s. Continue without alt text?", missing)
 		if err != nil {
-			return fmt.Errorf("failed to process image: %w", err)
+			return err
+		}
+		if !ok {
+			if useEditor {
+				output.Info("Post cancelled. Add alt: to each image in the frontmatter. Your draft was:")
+				output.Plain("%s", statusText)
+				return errCancelled
+			}
+			output.Info("Post cancelled. Please add --alt \"your alt text\" and try again.")
+			return errCancelled
+		}
+	}
+
+	preview := postPreview{
+		Text:        statusText,
+		InReplyToID: inReplyToID,
+		Settings:    settings,
+		Profiles:    applied,
+		CWRules:     cwRules,
+		Images:      images,
+		ScheduleAt:  scheduleAt,
+		LocalOnly:   postLocalOnly,
+	}
+
+	// Last chance to check everything, before anything is uploaded
+	if !dryRun && confirmBeforePost(cmd, store) {
+		if !assumeYes && !isTerminal() {
+			return cancelledf("can't ask for confirmation without a terminal; use --confirm=false to post anyway")
 		}
 
-		// Upload the image
-		output.Info("Uploading image...")
-		media, err := client.UploadMedia(
-			processedImage.Data,
-			processedImage.Filename,
-			processedImage.MimeType,
-			altText,
-		)
+		output.Info("About to post:")
+		printPostPreview(preview, output.Note)
+		ok, err := confirm("--confirm=false", "Post this?")
 		if err != nil {
-			return fmt.Errorf("failed to upload image: %w", err)
+			return err
+		}
+		if !ok {
+			if useEditor {
+				output.Info("Post cancelled. Your draft was:")
+				output.Plain("%s", statusText)
+				return errCancelled
+			}
+			output.Info("Post cancelled.")
+			return errCancelled
 		}
+	}
 
-		mediaIDs = []string{media.ID}
-		output.Info("Image uploaded successfully")
+	// Hand the post off to a background worker. The series number is taken
+	// now, since the label is already in the queued text. The reply target
+	// has been checked above; images are checked here, as the worker can't
+	// ask what to do about one it can't use.
+	if postAsync && !dryRun {
+		if err := checkImages(client, images); err != nil {
+			return err
+		}
+		if series != nil {
+			if err := store.AdvanceSeries(series.Name, seriesNumber); err != nil {
+				return fmt.Errorf("failed to update series: %w", err)
+			}
+		}
+		return enqueuePost(store, postJob{
+			Status:      statusText,
+			InReplyToID: inReplyToID,
+			Visibility:  settings.Visibility,
+			SpoilerText: settings.SpoilerText,
+			Language:    settings.Language,
+			Sensitive:   settings.Sensitive,
+			LocalOnly:   postLocalOnly,
+			Images:      images,
+
+			IdempotencyKey: mastodon.NewIdempotencyKey(),
+		})
+	}
+
+	mediaIDs, err := uploadImages(client, images)
+	if err != nil {
+		return err
 	}
 
 	params := mastodon.StatusParams{
 		Status:      statusText,
 		InReplyToID: inReplyToID,
-		Visibility:  visibility,
-		SpoilerText: contentWarn,
+		Visibility:  settings.Visibility,
+		SpoilerText: settings.SpoilerText,
 		MediaIDs:    mediaIDs,
-		Language:    language,
+		Language:    settings.Language,
+		Sensitive:   settings.Sensitive,
+		LocalOnly:   postLocalOnly,
+
+		// One key per compose session, so a retried request can't post twice
+		IdempotencyKey: mastodon.NewIdempotencyKey(),
 	}
 
 	if dryRun {
 		output.Info("Dry run mode - would post:")
-		output.Plain("Status: %s", statusText)
-		if inReplyToID != "" {
-			output.Plain("In reply to: %s", inReplyToID)
-		}
-		output.Plain("Visibility: %s", visibility)
-		if contentWarn != "" {
-			output.Plain("Content warning: %s", contentWarn)
-		}
-		if language != "" {
-			output.Plain("Language: %s", language)
+		printPostPreview(preview, output.Plain)
+		return nil
+	}
+
+	if !scheduleAt.IsZero() {
+		output.Info("Scheduling status...")
+		scheduled, err := client.ScheduleStatus(params, scheduleAt)
+		if err != nil {
+			return fmt.Errorf("failed to schedule status: %w", err)
 		}
-		if imagePath != "" {
-			output.Plain("Image: %s", imagePath)
-			if altText != "" {
-				output.Plain("Alt text: %s", altText)
+		if series != nil {
+			if err := store.AdvanceSeries(series.Name, seriesNumber); err != nil {
+				output.Error("Failed to update series %q: %v", series.Name, err)
 			}
 		}
+		output.Success("Status scheduled for %s", scheduled.ScheduledAt.Local().Format("2006-01-02 15:04"))
+		// There's no status, or URL, until the instance posts it
+		output.Result(scheduled.ID, "")
 		return nil
 	}
 
@@ -192,99 +524,227 @@ func runPost(cmd *cobra.Command, args []string) error {
 	if err := store.AddPostToHistory(status.ID); err != nil {
 		output.Error("Failed to save post to history: %v", err)
 	}
+	cacheStatuses(store, []*mastodon.Status{status})
+
+	if series != nil {
+		if err := store.AdvanceSeries(series.Name, seriesNumber); err != nil {
+			output.Error("Failed to update series %q: %v", series.Name, err)
+		}
+	}
 
 	output.Success("Status posted!")
-	output.URL(status.URL)
+	output.Result(status.ID, status.URL)
+	runHook(store, hookPost, status)
+
+	if postOpen {
+		openInBrowser(status.URL)
+	}
 
 	return nil
 }
 
-// TUI for selecting a post to reply to
+// postPreview is everything about a post that's shown before it's sent
+type postPreview struct {
+	Text        string
+	InReplyToID string
+	Settings    postSettings
+	Profiles    []string
+	CWRules     []string
+	Images      []postImage
+	ScheduleAt  time.Time
+	LocalOnly   bool
+}
 
-type replyStatusItem struct {
-	id      string
-	content string
-	url     string
+// printPostPreview shows a post as it will be sent, using show: output.Plain
+// for a dry run, whose result it is, or output.Note ahead of a question
+func printPostPreview(p postPreview, show func(format string, a ...interface{})) {
+	show("Status: %s", p.Text)
+	if p.InReplyToID != "" {
+		show("In reply to: %s", p.InReplyToID)
+	}
+	show("Visibility: %s", p.Settings.Visibility)
+	if p.Settings.SpoilerText != "" {
+		show("Content warning: %s", p.Settings.SpoilerText)
+	}
+	if p.Settings.Language != "" {
+		show("Language: %s", p.Settings.Language)
+	}
+	if p.Settings.Sensitive {
+		show("Sensitive: yes")
+	}
+	if p.LocalOnly {
+		show("Local only: yes")
+	}
+	if len(p.Profiles) > 0 {
+		show("Hashtag profiles:")
+		for _, rule := range p.Profiles {
+			show("  %s", rule)
+		}
+	}
+	if len(p.CWRules) > 0 {
+		show("CW rules:")
+		for _, rule := range p.CWRules {
+			show("  %s", rule)
+		}
+	}
+	for _, img := range p.Images {
+		show("Image: %s", img.Path)
+		if img.Alt != "" {
+			show("Alt text: %s", img.Alt)
+		} else {
+			show("Alt text: none")
+		}
+	}
+	if !p.ScheduleAt.IsZero() {
+		show("Scheduled for: %s", p.ScheduleAt.Local().Format("2006-01-02 15:04"))
+	}
 }
 
-type replySelectModel struct {
-	store    *config.Store
-	client   *mastodon.Client
-	statuses []replyStatusItem
-	cursor   int
-	syncing  bool
-	err      error
-	selected bool
+// checkLocalOnly fails unless the instance can keep posts local. Vanilla
+// Mastodon ignores local_only, so the post would federate after all.
+func checkLocalOnly(client mastodonAPI, domain string) error {
+	instance, err := client.GetInstance()
+	if err != nil {
+		return err
+	}
+	if !instance.SupportsLocalOnly() {
+		return fmt.Errorf("%s doesn't support local-only posts (version %s); only glitch-soc and Hometown do", domain, instance.Version)
+	}
+	return nil
+}
+
+// confirmBeforePost reports whether to ask before sending a post: --confirm
+// if it was given, and otherwise the confirm_before_post setting, which
+// --watch leaves out since there's no one to ask
+func confirmBeforePost(cmd *cobra.Command, store *config.Store) bool {
+	if cmd.Flags().Changed("confirm") {
+		return postConfirm
+	}
+	return !watchingPosts && getSetting(store, "confirm_before_post") == "on"
 }
 
-type replySyncCompleteMsg struct {
-	err error
+// postImage is an image to attach to a post
+type postImage struct {
+	Path string `json:"path"`
+	Alt  string `json:"alt,omitempty"`
 }
 
-func loadReplyStatuses(store *config.Store, client *mastodon.Client) ([]replyStatusItem, error) {
-	statuses, err := client.GetAccountStatuses(50)
-	if err != nil {
-		return nil, err
+// applyFrontmatter applies the settings from a post's frontmatter over those
+// from flags. Images are added after any given with --image, with relative
+// paths taken from dir if it's set. It reports whether the visibility was
+// set.
+func applyFrontmatter(meta *frontmatter.Meta, dir string, settings *postSettings, images *[]postImage) (bool, error) {
+	visibilitySet := false
+	if meta.Visibility != "" {
+		v, err := mastodon.ParseVisibility(meta.Visibility)
+		if err != nil {
+			return false, err
+		}
+		settings.Visibility = v
+		visibilitySet = true
+	}
+	if meta.SpoilerText != "" {
+		settings.SpoilerText = meta.SpoilerText
+	}
+	if meta.Language != "" {
+		settings.Language = meta.Language
 	}
+	if meta.Sensitive != nil {
+		settings.Sensitive = *meta.Sensitive
+	}
+	for _, img := range meta.Images {
+		path := img.Path
+		if dir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		*images = append(*images, postImage{Path: path, Alt: img.Alt})
+	}
+	return visibilitySet, nil
+}
 
-	items := make([]replyStatusItem, 0, len(statuses))
-	for _, status := range statuses {
-		content := stripHTML(status.Content)
-		items = append(items, replyStatusItem{
-			id:      status.ID,
-			content: content,
-			url:     status.URL,
-		})
+// readPostFile reads the text of a post written in a file
+func readPostFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read post file: %w", err)
 	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// TUI for selecting a post to reply to
 
-	return items, nil
+type replySelectModel struct {
+	statusPicker
+
+	store    *config.Store
+	client   mastodonAPI
+	loading  bool
+	spinner  spinner.Model
+	err      error
+	selected bool
+	message  string
+	threads  *prefetch.Fetcher[*mastodon.StatusContext]
 }
 
-func initialReplyModel(store *config.Store, client *mastodon.Client) replySelectModel {
-	statuses, err := loadReplyStatuses(store, client)
+func initialReplyModel(store *config.Store, client mastodonAPI) replySelectModel {
 	return replySelectModel{
-		store:    store,
-		client:   client,
-		statuses: statuses,
-		cursor:   0,
-		err:      err,
-		selected: false,
+		store:   store,
+		client:  client,
+		loading: true,
+		spinner: newSpinner(),
 	}
 }
 
 func (m replySelectModel) Init() tea.Cmd {
-	return nil
+	return tea.Batch(m.spinner.Tick, loadStatuses(m.store, m.client))
 }
 
-func doReplySync(store *config.Store, client *mastodon.Client) tea.Cmd {
-	return func() tea.Msg {
-		_, err := loadReplyStatuses(store, client)
-		return replySyncCompleteMsg{err: err}
+// prefetchThreads fetches the threads of the first few visible posts in the
+// background, so the one picked is usually ready for the new-reply check
+func (m replySelectModel) prefetchThreads() {
+	client := m.client
+	for i := m.offset; i < len(m.statuses) && i < m.offset+prefetchThreadCount; i++ {
+		id := m.statuses[i].id
+		m.threads.Start(id, func(ctx context.Context) (*mastodon.StatusContext, error) {
+			return apiWithContext(client, ctx).GetStatusContext(id)
+		})
 	}
 }
 
 func (m replySelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case replySyncCompleteMsg:
-		m.syncing = false
+	case statusesLoadedMsg:
+		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
-			// Reload statuses
-			statuses, err := loadReplyStatuses(m.store, m.client)
-			if err != nil {
-				m.err = err
-			} else {
-				m.statuses = statuses
-				m.cursor = 0
-			}
+			m.statuses = msg.items
+			m.cursor = 0
+			m.offset = 0
+			m.prefetchThreads()
 		}
 		return m, nil
 
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.width = msg.Width
+
 	case tea.KeyMsg:
-		if m.syncing {
+		if m.loading {
+			if msg.String() == "ctrl+c" || msg.String() == "q" {
+				return m, tea.Quit
+			}
 			return m, nil
 		}
+		m.message = ""
 
 		switch msg.String() {
 		case "ctrl+c", "q":
@@ -300,10 +760,20 @@ func (m replySelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor++
 			}
 
+		case "p":
+			m.preview = !m.preview
+
+		case "o":
+			if len(m.statuses) > 0 {
+				if err := oauth.OpenBrowser(m.statuses[m.cursor].url); err != nil {
+					m.message = fmt.Sprintf("Failed to open browser: %v", err)
+				}
+			}
+
 		case "s":
 			// Sync posts
-			m.syncing = true
-			return m, doReplySync(m.store, m.client)
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, loadStatuses(m.store, m.client))
 
 		case "enter", " ":
 			// Select current post
@@ -312,6 +782,10 @@ func (m replySelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if len(m.statuses) > 0 {
+		m.offset = scrollOffset(m.offset, m.cursor, m.listHeight(), m.itemLines)
+		m.prefetchThreads()
+	}
 	return m, nil
 }
 
@@ -320,8 +794,8 @@ func (m replySelectModel) View() string {
 		return fmt.Sprintf("Error: %v\n\nPress q to quit.\n", m.err)
 	}
 
-	if m.syncing {
-		return "Syncing posts...\n"
+	if m.loading {
+		return fmt.Sprintf("%s Loading posts...\n", m.spinner.View())
 	}
 
 	var b strings.Builder
@@ -333,7 +807,7 @@ func (m replySelectModel) View() string {
 
 	// Instructions
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	b.WriteString(helpStyle.Render("↑/k: up  ↓/j: down  enter/space: select  s: sync  q: quit"))
+	b.WriteString(helpStyle.Render("↑/k: up  ↓/j: down  enter/space: select  o: open  p: preview  s: sync  q: quit"))
 	b.WriteString("\n\n")
 
 	if len(m.statuses) == 0 {
@@ -345,7 +819,9 @@ func (m replySelectModel) View() string {
 	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
 	normalStyle := lipgloss.NewStyle()
 
-	for i, status := range m.statuses {
+	end := visibleItems(m.offset, len(m.statuses), m.listHeight(), m.itemLines)
+	for i := m.offset; i < end; i++ {
+		status := m.statuses[i]
 		cursor := " "
 		if m.cursor == i {
 			cursor = ">"
@@ -362,27 +838,63 @@ func (m replySelectModel) View() string {
 
 		b.WriteString(line)
 		b.WriteString("\n")
+
+		if status.replyLine != "" {
+			b.WriteString(helpStyle.Render("    " + status.replyLine))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.offset > 0 || end < len(m.statuses) {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  %d–%d of %d", m.offset+1, end, len(m.statuses))))
+		b.WriteString("\n")
+	}
+
+	if m.preview {
+		b.WriteString("\n")
+		b.WriteString(renderPreview(m.highlighted(), m.width))
+	}
+
+	if m.message != "" {
+		b.WriteString("\n")
+		b.WriteString(m.message)
+		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
-func runReplyTUI(store *config.Store, client *mastodon.Client) (string, error) {
-	p := tea.NewProgram(initialReplyModel(store, client))
+// runReplyTUI returns the ID of the picked post, along with its thread as of
+// when it was on screen if that was prefetched (nil otherwise)
+func runReplyTUI(store *config.Store, client mastodonAPI) (string, threadSnapshot, error) {
+	if err := requireInteractive("--reply-tui"); err != nil {
+		return "", nil, err
+	}
+
+	threads := prefetch.New[*mastodon.StatusContext](prefetchWorkers)
+	defer threads.Close()
+
+	model := initialReplyModel(store, client)
+	model.threads = threads
+
+	p := tea.NewProgram(model, tea.WithContext(runContext))
 	finalModel, err := p.Run()
 	if err != nil {
-		return "", fmt.Errorf("error running TUI: %w", err)
+		return "", nil, fmt.Errorf("error running TUI: %w", err)
 	}
 
 	m := finalModel.(replySelectModel)
 	if m.err != nil {
-		return "", m.err
+		return "", nil, m.err
 	}
 
 	if !m.selected || len(m.statuses) == 0 {
-		return "", nil
+		return "", nil, nil
 	}
 
-	return m.statuses[m.cursor].id, nil
+	id := m.statuses[m.cursor].id
+	if statusContext, err, ok := threads.Peek(id); ok && err == nil {
+		return id, snapshotFromContext(statusContext), nil
+	}
+	return id, nil, nil
 }
-
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileVisibility  string
+	profileContentWarn string
+	profileSensitive   bool
+	profileLanguage    string
+)
+
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Manage per-hashtag posting profiles",
+	Long: `Attach posting settings to hashtags. Whenever a post uses a tag with a profile,
+its settings are applied automatically: visibility is narrowed, and a content
+warning, sensitive flag, or language is filled in if you didn't set one.
+
+Examples:
+  tusk profiles set nsfw --sensitive --cw "NSFW"
+  tusk profiles set work -v unlisted
+  tusk profiles
+  tusk profiles remove work`,
+	Args: cobra.NoArgs,
+	RunE: runProfilesList,
+}
+
+var profilesSetCmd = &cobra.Command{
+	Use:   "set TAG",
+	Short: "Create or replace a hashtag profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProfilesSet,
+}
+
+var profilesRemoveCmd = &cobra.Command{
+	Use:   "remove TAG",
+	Short: "Remove a hashtag profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProfilesRemove,
+}
+
+func init() {
+	profilesSetCmd.Flags().StringVarP(&profileVisibility, "visibility", "v", "", "Post visibility: public (p), unlisted (u), private (f), direct (d)")
+	profilesSetCmd.Flags().StringVarP(&profileContentWarn, "cw", "w", "", "Content warning / spoiler text")
+	profilesSetCmd.Flags().BoolVar(&profileSensitive, "sensitive", false, "Mark attached media as sensitive")
+	profilesSetCmd.Flags().StringVarP(&profileLanguage, "lang", "l", "", "ISO 639 language code (e.g., en, es, fr, de, ja)")
+
+	profilesCmd.AddCommand(profilesSetCmd)
+	profilesCmd.AddCommand(profilesRemoveCmd)
+}
+
+func runProfilesList(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	profiles, err := store.ListHashtagProfiles()
+	if err != nil {
+		return fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "tag", Header: "TAG", Prefix: "#"},
+			{Key: "visibility", Header: "VISIBILITY", Detail: true},
+			{Key: "spoiler_text", Header: "CW", Detail: true},
+			{Key: "sensitive", Header: "SENSITIVE", Detail: true},
+			{Key: "language", Header: "LANGUAGE", Detail: true},
+			{Key: "summary", Header: "SUMMARY"},
+		},
+		Empty: "No hashtag profiles. Add one with 'tusk profiles set TAG'.",
+	}
+
+	for _, profile := range profiles {
+		listing.Rows = append(listing.Rows, []any{
+			profile.Tag, string(profile.Visibility), profile.SpoilerText, profile.Sensitive, profile.Language,
+			describeProfile(profile),
+		})
+	}
+
+	return output.Render(listing)
+}
+
+func runProfilesSet(cmd *cobra.Command, args []string) error {
+	profile := config.HashtagProfile{
+		Tag:         args[0],
+		SpoilerText: profileContentWarn,
+		Sensitive:   profileSensitive,
+		Language:    profileLanguage,
+	}
+
+	if profileVisibility != "" {
+		v, err := mastodon.ParseVisibility(profileVisibility)
+		if err != nil {
+			return err
+		}
+		profile.Visibility = v.String()
+	}
+
+	if profile.Visibility == "" && profile.SpoilerText == "" && !profile.Sensitive && profile.Language == "" {
+		return invalidf("a profile needs at least one of --visibility, --cw, --sensitive, or --lang")
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.SetHashtagProfile(profile); err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	output.Success("Profile saved for #%s", strings.ToLower(strings.TrimPrefix(args[0], "#")))
+	return nil
+}
+
+func runProfilesRemove(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.RemoveHashtagProfile(args[0]); err != nil {
+		return fmt.Errorf("failed to remove profile: %w", err)
+	}
+
+	output.Success("Profile removed for #%s", strings.ToLower(strings.TrimPrefix(args[0], "#")))
+	return nil
+}
+
+func describeProfile(profile *config.HashtagProfile) string {
+	var parts []string
+	if profile.Visibility != "" {
+		parts = append(parts, "visibility "+profile.Visibility)
+	}
+	if profile.SpoilerText != "" {
+		parts = append(parts, fmt.Sprintf("CW %q", profile.SpoilerText))
+	}
+	if profile.Sensitive {
+		parts = append(parts, "sensitive")
+	}
+	if profile.Language != "" {
+		parts = append(parts, "language "+profile.Language)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// postSettings are the status fields a hashtag profile can influence
+type postSettings struct {
+	Visibility  mastodon.Visibility
+	SpoilerText string
+	Sensitive   bool
+	Language    string
+}
+
+// applyHashtagProfiles evaluates the profiles for every hashtag in text.
+// Visibility is only ever narrowed, and not at all when it was set explicitly;
+// the content warning and language are only filled in when empty. It returns
+// the resulting settings and a description of each rule that took effect.
+func applyHashtagProfiles(store *config.Store, text string, settings postSettings, visibilityExplicit bool) (postSettings, []string, error) {
+	var applied []string
+	var warnings []string
+	fillWarning := settings.SpoilerText == ""
+
+	for _, tag := range extractHashtags(text) {
+		profile, err := store.GetHashtagProfile(tag)
+		if err != nil {
+			return settings, nil, fmt.Errorf("failed to load hashtag profile: %w", err)
+		}
+		if profile == nil {
+			continue
+		}
+
+		var changes []string
+		if profile.Visibility != "" && !visibilityExplicit {
+			narrowed := mastodon.Narrowest(settings.Visibility, mastodon.Visibility(profile.Visibility))
+			if narrowed != settings.Visibility {
+				settings.Visibility = narrowed
+				changes = append(changes, "visibility "+narrowed.String())
+			}
+		}
+		if profile.SpoilerText != "" && fillWarning && !containsString(warnings, profile.SpoilerText) {
+			warnings = append(warnings, profile.SpoilerText)
+			changes = append(changes, fmt.Sprintf("CW %q", profile.SpoilerText))
+		}
+		if profile.Sensitive && !settings.Sensitive {
+			settings.Sensitive = true
+			changes = append(changes, "sensitive")
+		}
+		if profile.Language != "" && settings.Language == "" {
+			settings.Language = profile.Language
+			changes = append(changes, "language "+profile.Language)
+		}
+
+		if len(changes) > 0 {
+			applied = append(applied, fmt.Sprintf("#%s → %s", tag, strings.Join(changes, ", ")))
+		}
+	}
+
+	if len(warnings) > 0 {
+		settings.SpoilerText = strings.Join(warnings, ", ")
+	}
+
+	return settings, applied, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
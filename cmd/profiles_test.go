@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+func TestApplyHashtagProfiles(t *testing.T) {
+	t.Setenv(config.DatabaseEnv, filepath.Join(t.TempDir(), "tusk.db"))
+	store, err := config.NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, profile := range []config.HashtagProfile{
+		{Tag: "spoilers", Visibility: "unlisted", SpoilerText: "spoilers"},
+		{Tag: "tv", SpoilerText: "spoilers", Language: "en"},
+		{Tag: "politics", Visibility: "private", SpoilerText: "politics", Sensitive: true, Language: "de"},
+	} {
+		if err := store.SetHashtagProfile(profile); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+	}
+
+	public := postSettings{Visibility: mastodon.VisibilityPublic}
+
+	tests := []struct {
+		name     string
+		text     string
+		settings postSettings
+		explicit bool
+		want     postSettings
+		applied  int
+	}{
+		{
+			name:     "no profiles",
+			text:     "Just a post about #cats",
+			settings: public,
+			want:     public,
+		},
+		{
+			name:     "several profiles",
+			text:     "#Spoilers and #politics",
+			settings: public,
+			want:     postSettings{Visibility: mastodon.VisibilityPrivate, SpoilerText: "spoilers, politics", Sensitive: true, Language: "de"},
+			applied:  2,
+		},
+		{
+			name:     "same warning once",
+			text:     "#spoilers from #tv",
+			settings: public,
+			want:     postSettings{Visibility: mastodon.VisibilityUnlisted, SpoilerText: "spoilers", Language: "en"},
+			applied:  2,
+		},
+		{
+			name:     "first language wins",
+			text:     "#tv #politics",
+			settings: public,
+			want:     postSettings{Visibility: mastodon.VisibilityPrivate, SpoilerText: "spoilers, politics", Sensitive: true, Language: "en"},
+			applied:  2,
+		},
+		{
+			name:     "never widened",
+			text:     "#spoilers",
+			settings: postSettings{Visibility: mastodon.VisibilityDirect},
+			want:     postSettings{Visibility: mastodon.VisibilityDirect, SpoilerText: "spoilers"},
+			applied:  1,
+		},
+		{
+			name:     "explicit visibility kept",
+			text:     "#politics",
+			settings: public,
+			explicit: true,
+			want:     postSettings{Visibility: mastodon.VisibilityPublic, SpoilerText: "politics", Sensitive: true, Language: "de"},
+			applied:  1,
+		},
+		{
+			name:     "own warning and language kept",
+			text:     "#spoilers #politics",
+			settings: postSettings{Visibility: mastodon.VisibilityPublic, SpoilerText: "mine", Language: "fr"},
+			want:     postSettings{Visibility: mastodon.VisibilityPrivate, SpoilerText: "mine", Sensitive: true, Language: "fr"},
+			applied:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		got, applied, err := applyHashtagProfiles(store, tt.text, tt.settings, tt.explicit)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+		if len(applied) != tt.applied {
+			t.Errorf("%s: expected %d profiles to take effect, got %q", tt.name, tt.applied, applied)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"biesnecker.com/tusk/internal/output"
+	"github.com/charmbracelet/x/term"
+)
+
+var (
+	// assumeYes answers yes to every confirmation (--yes)
+	assumeYes bool
+	// nonInteractive makes anything that would wait for an answer fail
+	// instead (--non-interactive)
+	nonInteractive bool
+)
+
+// confirm asks a yes or no question, the format and args giving the
+// question without its "(y/N)", and reports whether the answer was yes.
+// With --yes the answer is yes without asking. With --non-interactive it
+// fails, naming skip, the command's own flag for going ahead, if it has one.
+func confirm(skip, format string, args ...any) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if nonInteractive {
+		return false, notAsked(skip, fmt.Sprintf(format, args...))
+	}
+
+	output.Prompt(format+" (y/N): ", args...)
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
+
+// notAsked is the error for a question --non-interactive kept from being
+// asked
+func notAsked(skip, question string) error {
+	hint := "--yes"
+	if skip != "" {
+		hint = skip + " or --yes"
+	}
+	// The error says as much as a warning would
+	question = strings.TrimPrefix(question, "Warning: ")
+	return cancelledf("not asking %q in non-interactive mode; pass %s to go ahead", question, hint)
+}
+
+// requireInteractive fails with --non-interactive for a flag, such as --tui,
+// that only works by asking
+func requireInteractive(flag string) error {
+	if nonInteractive {
+		return invalidf("%s can't be used in non-interactive mode", flag)
+	}
+	return nil
+}
+
+// readKey asks a question answered with a single key, the format and args
+// giving the question, and returns the key pressed, lowercased. Where the
+// terminal can't read single keys, it reads a line and takes its first
+// character. Enter, Ctrl+C, and Ctrl+D are returned as 0.
+func readKey(format string, args ...any) rune {
+	output.Prompt(format, args...)
+
+	fd := os.Stdin.Fd()
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		response = strings.TrimSpace(response)
+		if response == "" {
+			return 0
+		}
+		return unicode.ToLower([]rune(response)[0])
+	}
+
+	key := make([]byte, 1)
+	_, err = os.Stdin.Read(key)
+	term.Restore(fd, state)
+	output.Prompt("\n")
+	if err != nil || key[0] == '\r' || key[0] == '\n' || key[0] == 3 || key[0] == 4 {
+		return 0
+	}
+	return unicode.ToLower(rune(key[0]))
+}
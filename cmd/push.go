@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/desktop"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/internal/webpush"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+// Where a push subscription's secrets and endpoint are kept
+const (
+	pushPrivateKeyKey = "push_private_key"
+	pushAuthKey       = "push_auth"
+	pushEndpointKey   = "push_endpoint"
+)
+
+var (
+	pushEndpoint string
+	pushTypes    []string
+	pushPolicy   string
+)
+
+var pushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Receive notifications by Web Push instead of polling",
+	Long: `Have your instance push notifications to tusk as they happen.
+
+Subscribing generates a key pair and secret that only this computer has, so
+only it can read what's pushed. The instance has to be able to reach the
+endpoint you give: the background worker listens on the push_listen setting
+(127.0.0.1:8795 by default), so forward a public URL to it, for example with
+a reverse proxy or a tunnel.
+
+Pushed notifications are raised on the desktop like 'tusk notify' does,
+honouring the notify_quiet_hours setting.
+
+Examples:
+  tusk push subscribe --endpoint https://push.example.com/tusk
+  tusk push subscribe --endpoint https://push.example.com/tusk --type mention --policy followed
+  tusk push unsubscribe`,
+}
+
+var pushSubscribeCmd = &cobra.Command{
+	Use:         "subscribe",
+	Short:       "Subscribe to push notifications",
+	Args:        cobra.NoArgs,
+	Annotations: needsScopes("push"),
+	RunE:        runPushSubscribe,
+}
+
+var pushUnsubscribeCmd = &cobra.Command{
+	Use:         "unsubscribe",
+	Short:       "Stop push notifications",
+	Args:        cobra.NoArgs,
+	Annotations: needsScopes("push"),
+	RunE:        runPushUnsubscribe,
+}
+
+func init() {
+	pushSubscribeCmd.Flags().StringVar(&pushEndpoint, "endpoint", "", "Public URL the instance pushes to, forwarded to push_listen (required)")
+	pushSubscribeCmd.Flags().StringSliceVarP(&pushTypes, "type", "t", nil, "Only push this notification type, e.g. mention, follow (repeatable; defaults to notify_types)")
+	pushSubscribeCmd.Flags().StringVar(&pushPolicy, "policy", mastodon.PushPolicyAll, "Whose notifications to push: all, followed, follower, or none")
+	pushSubscribeCmd.MarkFlagRequired("endpoint")
+
+	pushCmd.AddCommand(pushSubscribeCmd)
+	pushCmd.AddCommand(pushUnsubscribeCmd)
+}
+
+func runPushSubscribe(cmd *cobra.Command, args []string) error {
+	endpoint, err := url.Parse(pushEndpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return invalidf("--endpoint must be an https:// URL the instance can reach")
+	}
+	switch pushPolicy {
+	case mastodon.PushPolicyAll, mastodon.PushPolicyFollowed, mastodon.PushPolicyFollower, mastodon.PushPolicyNone:
+	default:
+		return invalidf("--policy must be all, followed, follower, or none")
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	types := pushTypes
+	if len(types) == 0 {
+		types = splitList(getSetting(store, "notify_types"))
+	}
+	if err := validateNotificationTypes(types); err != nil {
+		return invalidf("%v", err)
+	}
+	if len(types) == 0 {
+		types = mastodon.NotificationTypes
+	}
+
+	domain, accessToken := credentials(store)
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	keys, err := webpush.GenerateKeys()
+	if err != nil {
+		return err
+	}
+
+	subscription, err := client.SubscribePush(mastodon.PushSubscriptionParams{
+		Endpoint: endpoint.String(),
+		P256DH:   keys.Public(),
+		Auth:     keys.Auth(),
+		Alerts:   types,
+		Policy:   pushPolicy,
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, value := range map[string]string{
+		pushPrivateKeyKey: keys.Private(),
+		pushAuthKey:       keys.Auth(),
+		pushEndpointKey:   subscription.Endpoint,
+	} {
+		if err := store.Set(key, value); err != nil {
+			return fmt.Errorf("failed to save push subscription: %w", err)
+		}
+	}
+
+	output.Success("Subscribed to push notifications at %s", subscription.Endpoint)
+	output.Info("The background worker receives them on %s; forward the endpoint there.", getSetting(store, "push_listen"))
+
+	if err := startDaemon(); err != nil {
+		output.Warning("Failed to start the background worker: %v", err)
+	}
+	return nil
+}
+
+func runPushUnsubscribe(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	if err := client.UnsubscribePush(); err != nil {
+		// Already gone on the instance; still forget it here
+		if apiErr, ok := mastodon.AsAPIError(err); !ok || !apiErr.NotFound() {
+			return err
+		}
+	}
+
+	for _, key := range []string{pushPrivateKeyKey, pushAuthKey, pushEndpointKey} {
+		if err := store.Delete(key); err != nil {
+			return fmt.Errorf("failed to remove push subscription: %w", err)
+		}
+	}
+
+	// The background worker notices the keys are gone and stops listening
+	output.Success("Unsubscribed from push notifications")
+	return nil
+}
+
+// loadPushKeys returns the keys of the stored push subscription, or nil if
+// there isn't one
+func loadPushKeys(store *config.Store) *webpush.Keys {
+	private, _ := store.Get(pushPrivateKeyKey)
+	auth, _ := store.Get(pushAuthKey)
+	if private == "" || auth == "" {
+		return nil
+	}
+
+	keys, err := webpush.ParseKeys(private, auth)
+	if err != nil {
+		output.Error("Ignoring the saved push subscription: %v", err)
+		return nil
+	}
+	return keys
+}
+
+// deliverPush raises a decrypted push on the desktop and moves the notify
+// cursor past it, so 'tusk notify' doesn't raise it again
+func deliverPush(store *config.Store, message []byte) {
+	var payload mastodon.PushPayload
+	if err := json.Unmarshal(message, &payload); err != nil {
+		output.Error("Failed to read a push: %v", err)
+		return
+	}
+
+	output.Plain("%s  %s", payload.Title, payload.Body)
+	quietHours, _ := parseQuietHours(getSetting(store, "notify_quiet_hours"))
+	if !quietHours.contains(time.Now()) {
+		if err := desktop.Notify(payload.Title, payload.Body); err != nil {
+			output.Error("%v", err)
+		}
+	}
+
+	account, _ := store.Account()
+	cursorName := "notify:" + account
+	id := payload.NotificationID.String()
+	if since, err := store.GetCursor(cursorName); err == nil && since != "" && newerID(id, since) {
+		saveCursor(store, cursorName, id)
+	}
+}
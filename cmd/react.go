@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var reactRemove bool
+
+var reactCmd = &cobra.Command{
+	Use:   "react ID [EMOJI]",
+	Short: "React to a status with an emoji",
+	Long: `React to a status with an emoji, or list the reactions it has.
+
+Emoji reactions work on Pleroma, Akkoma, and glitch-soc instances. A custom
+emoji can be given by its shortcode, with or without colons.
+
+Examples:
+  tusk react 109876543210 👍
+  tusk react 109876543210 :blobcat:
+  tusk react 109876543210 :blobcat: --remove
+  tusk react 109876543210`,
+	Args:        cobra.RangeArgs(1, 2),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runReact,
+}
+
+func init() {
+	reactCmd.Flags().BoolVarP(&reactRemove, "remove", "d", false, "Remove your reaction instead")
+}
+
+func runReact(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	api, err := client.DetectReactions()
+	if err != nil {
+		return err
+	}
+	if api == mastodon.ReactionsUnsupported {
+		return fmt.Errorf("%s doesn't support emoji reactions; only Pleroma, Akkoma, and glitch-soc do", domain)
+	}
+
+	statusID, err := client.ResolveStatusID(args[0])
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		if reactRemove {
+			return invalidf("give the emoji to remove")
+		}
+		return listReactions(client, api, statusID)
+	}
+	emoji := args[1]
+
+	if reactRemove {
+		if _, err := client.Unreact(api, statusID, emoji); err != nil {
+			return err
+		}
+		output.Success("Removed %s from status %s", emoji, statusID)
+		return nil
+	}
+
+	status, err := client.React(api, statusID, emoji)
+	if err != nil {
+		return err
+	}
+	output.Success("Reacted %s to status %s", emoji, statusID)
+	output.Result(status.ID, status.URL)
+	return nil
+}
+
+func listReactions(client *mastodon.Client, api mastodon.ReactionAPI, statusID string) error {
+	reactions, err := client.GetReactions(api, statusID)
+	if err != nil {
+		return err
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "emoji", Header: "EMOJI"},
+			{Key: "count", Header: "COUNT"},
+			{Key: "me", Header: "YOU"},
+			{Key: "accounts", Header: "FROM", Width: 60, Detail: true},
+		},
+		Empty:   "No reactions.",
+		Tabular: true,
+	}
+
+	for _, reaction := range reactions {
+		emoji := reaction.Name
+		// Custom emoji are named by shortcode, unicode ones by themselves
+		if reaction.URL != "" {
+			emoji = ":" + emoji + ":"
+		}
+
+		var accounts []string
+		for _, account := range reaction.Accounts {
+			accounts = append(accounts, "@"+account.Acct)
+		}
+
+		listing.Rows = append(listing.Rows, []any{emoji, reaction.Count, reaction.Me, strings.Join(accounts, " ")})
+	}
+
+	return output.Render(listing)
+}
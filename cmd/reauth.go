@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// offerReauth asks to log in again when cmd failed because the instance
+// rejected the stored access token, and reports whether it did. Mastodon
+// doesn't issue refresh tokens, so this means running the OAuth flow again.
+func offerReauth(cmd *cobra.Command, err error) bool {
+	apiErr, ok := mastodon.AsAPIError(err)
+	if !ok || !apiErr.Unauthorized() {
+		return false
+	}
+
+	// Only the user's own login can be fixed this way, and only if there's
+	// someone to ask
+	if cmd == authCmd || cmd == logoutCmd || instanceDomain != "" || replayPath != "" {
+		return false
+	}
+	// Logging in again opens a browser, which --yes can't answer for
+	if nonInteractive || assumeYes {
+		return false
+	}
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return false
+	}
+
+	store, storeErr := config.NewStore()
+	if storeErr != nil {
+		return false
+	}
+	defer store.Close()
+
+	domain, _ := store.Get("domain")
+	accessToken, _ := store.Get("access_token")
+	if domain == "" || accessToken == "" {
+		return false
+	}
+
+	output.Warning("Your login has expired or was revoked.")
+	output.Prompt("Log in to %s again now? (Y/n): ", strings.TrimPrefix(domain, "https://"))
+
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "" && response != "y" && response != "yes" {
+		return false
+	}
+
+	if authErr := authenticate(store, domain, loginScopes(store)); authErr != nil {
+		output.Error("Failed to log in again: %v", authErr)
+		return false
+	}
+
+	output.Info("Run the command again to retry it.")
+	return true
+}
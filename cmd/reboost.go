@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+// minReboostInterval keeps a rotation from flooding followers' timelines
+const minReboostInterval = time.Hour
+
+// reboostRetry is how long a one-off reboost that failed waits before it's
+// tried again
+const reboostRetry = time.Hour
+
+// reboostAttempts is how many times a one-off reboost is tried before it's
+// given up on, so one that keeps failing doesn't keep the worker up forever
+const reboostAttempts = 24
+
+var (
+	reboostAt    string
+	reboostEvery string
+)
+
+var reboostCmd = &cobra.Command{
+	Use:   "reboost ID",
+	Short: "Boost a post again later, once or on a rotation",
+	Long: `Schedule a status to be unboosted and boosted again, so it shows up at the
+top of your followers' timelines. With --every, it keeps coming round, for
+evergreen posts such as an introduction or a pinned guide.
+
+The background worker does the boosting, and keeps running while any reboosts
+are scheduled. TIME is a time of day (the next one to come), a date and time
+in local time, or a duration from now. INTERVAL is a duration such as 12h, or
+a number of days such as 7d.
+
+Examples:
+  tusk reboost 109876543210 --at 18:00
+  tusk reboost https://mastodon.social/@me/109876543210 --every 7d
+  tusk reboost 109876543210 --at "2026-10-20 09:00" --every 14d
+  tusk reboost list
+  tusk reboost cancel 3`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runReboost,
+}
+
+var reboostListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled reboosts",
+	Args:  cobra.NoArgs,
+	RunE:  runReboostList,
+}
+
+var reboostCancelCmd = &cobra.Command{
+	Use:   "cancel ID",
+	Short: "Cancel a scheduled reboost",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runReboostCancel,
+}
+
+func init() {
+	reboostCmd.Flags().StringVar(&reboostAt, "at", "", "When to reboost: a time such as 18:00, a date and time, or a duration such as 2h")
+	reboostCmd.Flags().StringVar(&reboostEvery, "every", "", "Keep reboosting this often, e.g. 7d or 12h (at least 1h)")
+
+	reboostCmd.AddCommand(reboostListCmd)
+	reboostCmd.AddCommand(reboostCancelCmd)
+}
+
+func runReboost(cmd *cobra.Command, args []string) error {
+	if reboostAt == "" && reboostEvery == "" {
+		return invalidf("say when to reboost with --at, --every, or both")
+	}
+
+	now := time.Now()
+	var every time.Duration
+	if reboostEvery != "" {
+		var err error
+		if every, err = parseReboostInterval(reboostEvery); err != nil {
+			return invalidf("%v", err)
+		}
+	}
+	nextAt := now.Add(every)
+	if reboostAt != "" {
+		var err error
+		if nextAt, err = parseRemindAt(reboostAt, now); err != nil {
+			return invalidf("%v", err)
+		}
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+	// The worker boosts with the stored login
+	if overridingLogin() {
+		return invalidf("the background worker can't use --instance or --token")
+	}
+
+	client, err := newAPI(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	statusID, err := client.ResolveStatusID(args[0])
+	if err != nil {
+		return err
+	}
+
+	id, err := store.AddReboost(statusID, nextAt, every)
+	if err != nil {
+		return fmt.Errorf("failed to save reboost: %w", err)
+	}
+
+	when := nextAt.Local().Format("2006-01-02 15:04")
+	if every > 0 {
+		output.Success("Reboost #%d of %s set for %s, then every %s", id, statusID, when, formatReboostInterval(every))
+	} else {
+		output.Success("Reboost #%d of %s set for %s", id, statusID, when)
+	}
+
+	if err := startDaemon(); err != nil {
+		output.Warning("Failed to start the background worker: %v", err)
+	}
+	return nil
+}
+
+func runReboostList(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	reboosts, err := store.ListReboosts()
+	if err != nil {
+		return fmt.Errorf("failed to list reboosts: %w", err)
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID"},
+			{Key: "status_id", Header: "STATUS"},
+			{Key: "next_at", Header: "NEXT"},
+			{Key: "every", Header: "EVERY"},
+			{Key: "last_at", Header: "LAST", Detail: true},
+			{Key: "error", Header: "ERROR"},
+		},
+		Empty: "No reboosts scheduled.",
+	}
+	for _, reboost := range reboosts {
+		var lastAt any
+		if !reboost.LastAt.IsZero() {
+			lastAt = reboost.LastAt
+		}
+		listing.Rows = append(listing.Rows, []any{
+			reboost.ID, reboost.StatusID, reboost.NextAt, formatReboostInterval(reboost.Every), lastAt, reboost.Error,
+		})
+	}
+
+	listing.Plain = func(i int) {
+		reboost := reboosts[i]
+		output.Plain("#%d  %s  %s  %s", reboost.ID, reboost.StatusID,
+			reboost.NextAt.Local().Format("2006-01-02 15:04"), formatReboostInterval(reboost.Every))
+		if reboost.Error != "" {
+			output.Error("    %s", reboost.Error)
+		}
+	}
+
+	return output.Render(listing)
+}
+
+func runReboostCancel(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil {
+		return invalidf("invalid reboost ID %q", args[0])
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	removed, err := store.RemoveReboost(id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel reboost: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("no reboost with ID %d", id)
+	}
+
+	output.Success("Reboost #%d cancelled", id)
+	return nil
+}
+
+// parseReboostInterval reads --every: a duration such as 12h, or a number of
+// days such as 7d
+func parseReboostInterval(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	every, err := time.ParseDuration(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		every = time.Duration(n) * 24 * time.Hour
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid --every %q: use a duration such as 12h or a number of days such as 7d", value)
+	}
+	if every < minReboostInterval {
+		return 0, invalidf("--every must be at least %s", formatReboostInterval(minReboostInterval))
+	}
+	return every, nil
+}
+
+// formatReboostInterval writes an interval the way --every takes it, in days
+// where it's a whole number of them
+func formatReboostInterval(every time.Duration) string {
+	switch {
+	case every == 0:
+		return "once"
+	case every%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", every/(24*time.Hour))
+	case every%time.Hour == 0:
+		return fmt.Sprintf("%dh", every/time.Hour)
+	}
+	return every.String()
+}
+
+// hasReboosts reports whether any reboosts are scheduled, which keeps the
+// background worker running
+func hasReboosts(store *config.Store) bool {
+	reboosts, err := store.ListReboosts()
+	return err == nil && len(reboosts) > 0
+}
+
+// runDueReboosts unboosts and boosts again each reboost that's come due,
+// then schedules its next turn or, for a one-off, removes it
+func runDueReboosts(store *config.Store, now time.Time) {
+	due, err := store.DueReboosts(now)
+	if err != nil {
+		output.Error("Failed to read scheduled reboosts: %v", err)
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	domain, _ := store.Get("domain")
+	accessToken, _ := store.Get("access_token")
+	if accessToken == "" {
+		output.Error("Reboosts are due, but you're not logged in")
+		return
+	}
+	client, err := newAPI(store, domain, accessToken)
+	if err != nil {
+		output.Error("%v", err)
+		return
+	}
+
+	for _, reboost := range due {
+		err := reboostStatus(client, reboost.StatusID)
+		if apiErr, ok := mastodon.AsAPIError(err); ok && apiErr.NotFound() {
+			// The status is gone, so there's nothing left to boost
+			output.Error("Reboost #%d: status %s no longer exists", reboost.ID, reboost.StatusID)
+			store.RemoveReboost(reboost.ID)
+			continue
+		}
+
+		if err == nil && reboost.Every == 0 {
+			output.Success("Boosted %s again", reboost.StatusID)
+			store.RemoveReboost(reboost.ID)
+			continue
+		}
+		if err != nil && reboost.Every == 0 && givesUpReboost(reboost, err) {
+			output.Error("Reboost #%d: %v; giving up", reboost.ID, err)
+			store.RemoveReboost(reboost.ID)
+			continue
+		}
+
+		errMsg := ""
+		if err != nil {
+			errMsg = err.Error()
+			output.Error("Reboost #%d: %v", reboost.ID, err)
+		} else {
+			output.Success("Boosted %s again", reboost.StatusID)
+		}
+		if err := store.RecordReboost(reboost.ID, now, nextReboost(reboost, now, err != nil), errMsg); err != nil {
+			output.Error("Failed to record reboost #%d: %v", reboost.ID, err)
+		}
+	}
+}
+
+// reboostStatus undoes any boost of a status and boosts it again, so it's
+// shown as new
+func reboostStatus(client mastodonAPI, statusID string) error {
+	if _, err := client.UnreblogStatus(statusID); err != nil {
+		return err
+	}
+	_, err := client.ReblogStatus(statusID)
+	return err
+}
+
+// givesUpReboost reports whether a one-off reboost that failed with err
+// should be dropped rather than retried: the instance refused it for a reason
+// trying again won't change, or it has used up its attempts
+func givesUpReboost(reboost *config.Reboost, err error) bool {
+	if apiErr, ok := mastodon.AsAPIError(err); ok && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && !apiErr.RateLimited() {
+		return true
+	}
+	return reboost.Failures+1 >= reboostAttempts
+}
+
+// nextReboost is when a reboost attempted at now is next due. One that failed
+// is retried, since the post may have been left unboosted; a rotation retries
+// no later than its next turn. A rotation skips any turns missed while the
+// worker wasn't running rather than catching up on them all at once.
+func nextReboost(reboost *config.Reboost, now time.Time, failed bool) time.Time {
+	if reboost.Every == 0 {
+		return now.Add(reboostRetry)
+	}
+	next := reboost.NextAt.Add(reboost.Every)
+	for !next.After(now) {
+		next = next.Add(reboost.Every)
+	}
+	if retry := now.Add(reboostRetry); failed && retry.Before(next) {
+		return retry
+	}
+	return next
+}
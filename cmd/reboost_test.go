@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+func TestParseReboostInterval(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"7d":   7 * 24 * time.Hour,
+		"12h":  12 * time.Hour,
+		"90m":  90 * time.Minute,
+		" 1d ": 24 * time.Hour,
+	} {
+		got, err := parseReboostInterval(value)
+		if err != nil || got != want {
+			t.Errorf("parseReboostInterval(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"", "30m", "0d", "1.5d", "weekly"} {
+		if _, err := parseReboostInterval(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestNextReboost(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	once := &config.Reboost{NextAt: now.Add(-time.Minute)}
+	if got := nextReboost(once, now, true); !got.Equal(now.Add(reboostRetry)) {
+		t.Errorf("Expected a failed one-off to be retried at %v, got %v", now.Add(reboostRetry), got)
+	}
+
+	// Two turns were missed while the worker wasn't running
+	daily := &config.Reboost{NextAt: now.Add(-49 * time.Hour), Every: 24 * time.Hour}
+	if got, want := nextReboost(daily, now, false), now.Add(23*time.Hour); !got.Equal(want) {
+		t.Errorf("Expected the next turn at %v, got %v", want, got)
+	}
+
+	// A failed turn may have left the post unboosted, so it's tried again
+	// soon rather than a week later
+	weekly := &config.Reboost{NextAt: now.Add(-time.Minute), Every: 7 * 24 * time.Hour}
+	if got := nextReboost(weekly, now, true); !got.Equal(now.Add(reboostRetry)) {
+		t.Errorf("Expected a failed rotation to be retried at %v, got %v", now.Add(reboostRetry), got)
+	}
+	// but never later than its next turn
+	hourly := &config.Reboost{NextAt: now.Add(-30 * time.Minute), Every: time.Hour}
+	if got, want := nextReboost(hourly, now, true), now.Add(30*time.Minute); !got.Equal(want) {
+		t.Errorf("Expected a failed rotation's retry no later than %v, got %v", want, got)
+	}
+}
+
+func TestRunDueReboosts(t *testing.T) {
+	api := newFakeAPI(&mastodon.Status{ID: "7"}, &mastodon.Status{ID: "8"})
+	store := useFakeAPI(t, api)
+
+	now := time.Now()
+	store.AddReboost("7", now.Add(-time.Minute), 0)
+	store.AddReboost("8", now.Add(-time.Minute), 7*24*time.Hour)
+	// Deleted since it was scheduled
+	store.AddReboost("9", now.Add(-time.Minute), 7*24*time.Hour)
+	store.AddReboost("8", now.Add(time.Hour), 0)
+
+	runDueReboosts(store, now)
+
+	if want := []string{"unreblog 7", "reblog 7", "unreblog 8", "reblog 8"}; !slices.Equal(api.reblogs, want) {
+		t.Errorf("Expected %q, got %q", want, api.reblogs)
+	}
+
+	reboosts, _ := store.ListReboosts()
+	if len(reboosts) != 2 {
+		t.Fatalf("Expected the rotation and the later one-off to be left, got %+v", reboosts)
+	}
+	if r := reboosts[1]; r.StatusID != "8" || r.Every == 0 || r.Error != "" || r.LastAt.IsZero() || r.NextAt.Before(now.Add(6*24*time.Hour)) {
+		t.Errorf("Expected the rotation to be rescheduled a week on, got %+v", r)
+	}
+}
+
+func TestGivesUpReboost(t *testing.T) {
+	fresh := &config.Reboost{}
+	worn := &config.Reboost{Failures: reboostAttempts - 1}
+
+	tests := []struct {
+		name    string
+		reboost *config.Reboost
+		err     error
+		want    bool
+	}{
+		{"forbidden", fresh, &mastodon.APIError{StatusCode: 403}, true},
+		{"rate limited", fresh, &mastodon.APIError{StatusCode: 429}, false},
+		{"server error", fresh, &mastodon.APIError{StatusCode: 503}, false},
+		{"network error", fresh, errors.New("connection refused"), false},
+		{"out of attempts", worn, &mastodon.APIError{StatusCode: 503}, true},
+	}
+	for _, tt := range tests {
+		if got := givesUpReboost(tt.reboost, tt.err); got != tt.want {
+			t.Errorf("%s: givesUpReboost() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// checkStore runs before every command. Opening the store moves the
+// database over from where older versions of tusk kept it, if there isn't one
+// yet, and if the database is damaged it offers to move it aside and start a
+// fresh one, since otherwise every command fails.
+func checkStore(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err == nil {
+		if from := store.MovedFrom(); from != "" {
+			if to, err := config.DatabasePath(); err == nil {
+				output.Info("Moved tusk's database from %s to %s", from, to)
+			}
+		}
+		return store.Close()
+	}
+
+	var corrupt *config.CorruptError
+	if !errors.As(err, &corrupt) {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+
+	output.Error("Tusk's database is damaged: %v", corrupt.Err)
+
+	if !assumeYes && !isTerminal() {
+		return fmt.Errorf("%w. Run tusk in a terminal to recover it", err)
+	}
+
+	ok, err := confirm("", "Back up the damaged file and start a fresh database, keeping any settings that can be read?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return cancelledf("database %s is damaged; recovery declined", corrupt.Path)
+	}
+
+	recovery, err := config.Recover()
+	if err != nil {
+		return fmt.Errorf("failed to recover database: %w", err)
+	}
+
+	output.Success("Started a fresh database.")
+	output.Plain("The damaged file was kept at %s", recovery.BackupPath)
+	if len(recovery.Salvaged) > 0 {
+		output.Plain("Recovered settings: %s", strings.Join(recovery.Salvaged, ", "))
+	}
+	if store, err := config.NewStore(); err == nil {
+		accessToken, _ := store.Get("access_token")
+		store.Close()
+		if accessToken == "" {
+			output.Info("Your login couldn't be recovered. Run 'tusk auth' to log in again.")
+		}
+	}
+	output.Plain("Post history, jobs, and cached statuses were not recovered.")
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/internal/workflow"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	redraftLatest      bool
+	redraftEditor      bool
+	redraftVisibility  string
+	redraftContentWarn string
+	redraftLanguage    string
+)
+
+var redraftCmd = &cobra.Command{
+	Use:   "redraft [ID] [TEXT]",
+	Short: "Delete a status and post it again",
+	Long: `Delete a status and post a new version of it, keeping its media, reply target,
+and (unless overridden) visibility and content warning. This is the only way to
+change a post's visibility.
+
+The steps run as a unit: if posting the new version fails, the original is
+posted again so nothing is lost.
+
+Examples:
+  tusk redraft --latest -v unlisted
+  tusk redraft STATUS_ID -e
+  tusk redraft STATUS_ID "Fixed the typo"`,
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runRedraft,
+}
+
+func init() {
+	redraftCmd.Flags().BoolVar(&redraftLatest, "latest", false, "Redraft the most recent post")
+	redraftCmd.Flags().BoolVarP(&redraftEditor, "editor", "e", false, "Compose the new version in $EDITOR")
+	redraftCmd.Flags().StringVarP(&redraftVisibility, "visibility", "v", "", "Post visibility: public (p), unlisted (u), private (f), direct (d)")
+	redraftCmd.Flags().StringVarP(&redraftContentWarn, "cw", "w", "", "Content warning / spoiler text")
+	redraftCmd.Flags().StringVar(&redraftLanguage, "lang", "", "ISO 639 language code (e.g., en, es, fr, de, ja)")
+}
+
+func runRedraft(cmd *cobra.Command, args []string) error {
+	var newVisibility mastodon.Visibility
+	if redraftVisibility != "" {
+		v, err := mastodon.ParseVisibility(redraftVisibility)
+		if err != nil {
+			return err
+		}
+		newVisibility = v
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	var statusID string
+	if redraftLatest {
+		lastPostID, err := store.GetLastPostID()
+		if err != nil {
+			return fmt.Errorf("failed to get last post ID: %w", err)
+		}
+		if lastPostID == "" {
+			return fmt.Errorf("no posts in history")
+		}
+		statusID = lastPostID
+	} else if len(args) > 0 {
+		statusID, err = client.ResolveStatusID(args[0])
+		if err != nil {
+			return err
+		}
+		args = args[1:]
+	} else {
+		return invalidf("must provide status ID or use --latest")
+	}
+
+	original, err := client.GetStatus(statusID)
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+
+	// The source is the plain text the status was written in, which is what
+	// we want to post again
+	source, err := client.GetStatusSource(statusID)
+	if err != nil {
+		return fmt.Errorf("failed to get status source: %w", err)
+	}
+
+	var mediaIDs []string
+	for _, attachment := range original.MediaAttachments {
+		mediaIDs = append(mediaIDs, attachment.ID)
+	}
+
+	originalParams := mastodon.StatusParams{
+		Status:      source.Text,
+		InReplyToID: original.InReplyTo,
+		Visibility:  original.Visibility,
+		SpoilerText: source.SpoilerText,
+		MediaIDs:    mediaIDs,
+		Language:    original.Language,
+		Sensitive:   original.Sensitive,
+	}
+
+	newParams := originalParams
+	if redraftEditor {
+		newParams.Status, err = getTextFromEditorWithInitial(source.Text)
+		if err != nil {
+			return err
+		}
+	} else if len(args) > 0 || !isTerminal() {
+		newParams.Status, err = getStatusText(args, false)
+		if err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(newParams.Status) == "" {
+		newParams.Status = source.Text
+	}
+	if newVisibility != "" {
+		newParams.Visibility = newVisibility
+	}
+	if redraftContentWarn != "" {
+		newParams.SpoilerText = redraftContentWarn
+	}
+	if redraftLanguage != "" {
+		newParams.Language = redraftLanguage
+	}
+
+	var deletedOriginal *mastodon.Status
+	var redrafted *mastodon.Status
+	var restored *mastodon.Status
+
+	// Media can only be attached to one status, so the original has to go
+	// before the new version can reuse it. Deleting can't be undone, so the
+	// compensation is to post the original again.
+	steps := workflow.New()
+	steps.Add("delete original",
+		func() error {
+			deleted, err := client.DeleteStatus(statusID)
+			deletedOriginal = deleted
+			return err
+		},
+		func() error {
+			status, err := client.PostStatus(originalParams)
+			if err != nil {
+				return err
+			}
+			restored = status
+			return nil
+		},
+	)
+	steps.Add("post new version",
+		func() error {
+			status, err := client.PostStatus(newParams)
+			if err != nil {
+				return err
+			}
+			redrafted = status
+			return nil
+		},
+		func() error {
+			_, err := client.DeleteStatus(redrafted.ID)
+			return err
+		},
+	)
+
+	output.Info("Redrafting status %s...", statusID)
+	if err := steps.Run(); err != nil {
+		output.Error("Redraft failed:")
+		output.Plain("%s", strings.TrimRight(steps.Summary(), "\n"))
+
+		if restored != nil {
+			if err := store.RemovePostFromHistory(statusID); err == nil {
+				store.AddPostToHistory(restored.ID)
+			}
+			output.Info("The original was posted again as %s", restored.ID)
+			output.URL(restored.URL)
+		}
+		return fmt.Errorf("failed to redraft status: %w", err)
+	}
+
+	// Local history is bookkeeping, not part of the unit of work
+	if err := store.RemovePostFromHistory(statusID); err != nil {
+		output.Error("Failed to remove old post from history: %v", err)
+	}
+	if err := store.AddPostToHistory(redrafted.ID); err != nil {
+		output.Error("Failed to save post to history: %v", err)
+	}
+
+	output.Success("Status redrafted!")
+	output.Result(redrafted.ID, redrafted.URL)
+	runHook(store, hookDelete, deletedOriginal)
+	runHook(store, hookPost, redrafted)
+
+	return nil
+}
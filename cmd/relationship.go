@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var relationshipCmd = &cobra.Command{
+	Use:   "relationship @USER|URL",
+	Short: "Show how you and an account are connected",
+	Long: `Show whether you follow an account and it follows you, any follow request
+waiting on either side, whether either of you blocks the other, whether you
+mute it, and your private note about it.
+
+Examples:
+  tusk relationship @alice@example.com
+  tusk relationship https://example.com/@alice`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRelationship,
+}
+
+var noteCmd = &cobra.Command{
+	Use:   "note @USER|URL TEXT",
+	Short: "Set your private note about an account",
+	Long: `Set the note about an account that only you can see, shown on its profile
+in Mastodon's web interface and by 'tusk relationship'. An empty TEXT removes
+the note.
+
+Examples:
+  tusk note @alice@example.com "Met at the October meetup"
+  tusk note @alice@example.com ""`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: needsScopes("write:accounts"),
+	RunE:        runNote,
+}
+
+func runRelationship(cmd *cobra.Command, args []string) error {
+	client, account, err := resolveLoggedIn(args[0])
+	if err != nil {
+		return err
+	}
+
+	relationships, err := client.GetRelationships([]string{account.ID})
+	if err != nil {
+		return err
+	}
+	if len(relationships) == 0 {
+		return fmt.Errorf("no relationship with @%s found", account.Acct)
+	}
+
+	output.Info("@%s", account.Acct)
+	output.Result(account.ID, account.URL)
+	for _, line := range describeRelationship(relationships[0]) {
+		output.Plain("%s", line)
+	}
+	return nil
+}
+
+func runNote(cmd *cobra.Command, args []string) error {
+	client, account, err := resolveLoggedIn(args[0])
+	if err != nil {
+		return err
+	}
+
+	comment := strings.TrimSpace(args[1])
+	if _, err := client.SetAccountNote(account.ID, comment); err != nil {
+		return err
+	}
+
+	if comment == "" {
+		output.Success("Removed your note on @%s", account.Acct)
+	} else {
+		output.Success("Saved your note on @%s", account.Acct)
+	}
+	return nil
+}
+
+// resolveLoggedIn finds an account with the stored login, for commands about
+// how it relates to you
+func resolveLoggedIn(ref string) (*mastodon.Client, *mastodon.Account, error) {
+	store, err := config.NewStore()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return nil, nil, errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	account, err := client.ResolveAccount(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, account, nil
+}
+
+// describeRelationship lists a relationship's parts, one per line
+func describeRelationship(r *mastodon.Relationship) []string {
+	following := yesNo(r.Following)
+	switch {
+	case r.Following:
+		var details []string
+		if !r.ShowingReblogs {
+			details = append(details, "boosts hidden")
+		}
+		if r.Notifying {
+			details = append(details, "notified of posts")
+		}
+		if len(r.Languages) > 0 {
+			details = append(details, "only in "+strings.Join(r.Languages, ", "))
+		}
+		if len(details) > 0 {
+			following += " (" + strings.Join(details, "; ") + ")"
+		}
+	case r.Requested:
+		following = "requested, awaiting approval"
+	}
+
+	followedBy := yesNo(r.FollowedBy)
+	if r.RequestedBy && !r.FollowedBy {
+		followedBy = "requested, awaiting your approval"
+	}
+
+	blocking := yesNo(r.Blocking)
+	if r.DomainBlocking {
+		blocking += " (their instance is blocked)"
+	}
+
+	muting := yesNo(r.Muting)
+	if r.Muting && r.MutingNotifications {
+		muting += " (including notifications)"
+	}
+
+	lines := []string{
+		"Following:    " + following,
+		"Follows you:  " + followedBy,
+		"Blocking:     " + blocking,
+		"Blocks you:   " + yesNo(r.BlockedBy),
+		"Muting:       " + muting,
+	}
+	if note := strings.TrimSpace(r.Note); note != "" {
+		lines = append(lines, "Note:         "+note)
+	}
+	return lines
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+func TestDescribeRelationship(t *testing.T) {
+	got := describeRelationship(&mastodon.Relationship{
+		Following:           true,
+		Notifying:           true,
+		RequestedBy:         true,
+		Muting:              true,
+		MutingNotifications: true,
+		Note:                "Met at the October meetup\n",
+	})
+	want := []string{
+		"Following:    yes (boosts hidden; notified of posts)",
+		"Follows you:  requested, awaiting your approval",
+		"Blocking:     no",
+		"Blocks you:   no",
+		"Muting:       yes (including notifications)",
+		"Note:         Met at the October meetup",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	got = describeRelationship(&mastodon.Relationship{Requested: true, ShowingReblogs: true})
+	if got[0] != "Following:    requested, awaiting approval" || len(got) != 5 {
+		t.Errorf("Unexpected description of a pending follow %q", got)
+	}
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// reminderSnooze is how far the s key puts a due reminder off
+const reminderSnooze = time.Hour
+
+var remindAt string
+
+var remindCmd = &cobra.Command{
+	Use:   "remind TEXT --at TIME",
+	Short: "Remind yourself to post something later",
+	Long: `Save a reminder to post TEXT. Once it's due, the next tusk command you run in a
+terminal shows it and asks what to do, with a single key: p posts it as it is,
+e opens it in your editor first, s snoozes it for an hour, d dismisses it, and
+any other key leaves it for next time.
+
+TIME is a time of day (the next one to come), a date and time in local time,
+or a duration from now.
+
+Examples:
+  tusk remind "Post the release notes" --at 17:00
+  tusk remind "Share the conference photos" --at "2026-10-16 09:30"
+  tusk remind "Reply to the thread about the outage" --at 2h
+  tusk remind list`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRemind,
+}
+
+var remindListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your reminders",
+	Args:  cobra.NoArgs,
+	RunE:  runRemindList,
+}
+
+var remindCancelCmd = &cobra.Command{
+	Use:   "cancel ID",
+	Short: "Cancel a reminder",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRemindCancel,
+}
+
+func init() {
+	remindCmd.Flags().StringVar(&remindAt, "at", "", "When to be reminded: a time such as 17:00, a date and time, or a duration such as 2h")
+	remindCmd.MarkFlagRequired("at")
+
+	remindCmd.AddCommand(remindListCmd)
+	remindCmd.AddCommand(remindCancelCmd)
+}
+
+func runRemind(cmd *cobra.Command, args []string) error {
+	text := strings.TrimSpace(strings.Join(args, " "))
+	if text == "" {
+		return invalidf("reminder text cannot be empty")
+	}
+
+	dueAt, err := parseRemindAt(remindAt, time.Now())
+	if err != nil {
+		return invalidf("%v", err)
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	id, err := store.AddReminder(text, dueAt)
+	if err != nil {
+		return fmt.Errorf("failed to save reminder: %w", err)
+	}
+
+	output.Success("Reminder #%d set for %s", id, dueAt.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+func runRemindList(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	reminders, err := store.ListReminders()
+	if err != nil {
+		return fmt.Errorf("failed to list reminders: %w", err)
+	}
+
+	now := time.Now()
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID"},
+			{Key: "due_at", Header: "DUE"},
+			{Key: "due", Header: "DUE NOW", Detail: true},
+			{Key: "text", Header: "TEXT", Width: 60},
+		},
+		Empty: "No reminders.",
+	}
+	for _, reminder := range reminders {
+		listing.Rows = append(listing.Rows, []any{reminder.ID, reminder.DueAt, !reminder.DueAt.After(now), reminder.Text})
+	}
+
+	listing.Plain = func(i int) {
+		reminder := reminders[i]
+		marker := " "
+		if !reminder.DueAt.After(now) {
+			marker = "*"
+		}
+		output.Plain("%s #%d  %s  %s", marker, reminder.ID, reminder.DueAt.Local().Format("2006-01-02 15:04"), truncate(reminder.Text, 60))
+	}
+
+	return output.Render(listing)
+}
+
+func runRemindCancel(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil {
+		return invalidf("invalid reminder ID %q", args[0])
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	removed, err := store.RemoveReminder(id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel reminder: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("no reminder #%d. See 'tusk remind list'", id)
+	}
+
+	output.Success("Reminder #%d cancelled", id)
+	return nil
+}
+
+// parseRemindAt reads --at: a time of day, which is the next one after now,
+// a date and time in local time, or a duration from now
+func parseRemindAt(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return time.Time{}, invalidf("--at must be in the future")
+		}
+		return now.Add(d), nil
+	}
+
+	if clock, err := time.ParseInLocation("15:04", value, time.Local); err == nil {
+		local := now.In(time.Local)
+		at := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", time.RFC3339} {
+		if at, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			if !at.After(now) {
+				return time.Time{}, invalidf("--at %s has already passed", value)
+			}
+			return at, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --at %q: use a time such as 17:00, a date and time such as 2006-01-02 15:04, or a duration such as 2h", value)
+}
+
+// Commands that reminders would get in the way of, on top of the banner's
+var remindersSkipped = []string{"remind"}
+
+// offerReminders shows the reminders that have come due and asks what to do
+// with each. It only asks in a terminal, and never holds up a command that
+// was started with --yes or --non-interactive or is writing JSON or YAML.
+func offerReminders(cmd *cobra.Command) {
+	if replayPath != "" || recordPath != "" || instanceDomain != "" || cmd.Hidden {
+		return
+	}
+	if assumeYes || nonInteractive || !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return
+	}
+	if format, _ := output.ParseFormat(outputFormat); format != output.FormatPlain && format != output.FormatTable {
+		return
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if containsString(bannerSkipped, c.Name()) || containsString(remindersSkipped, c.Name()) {
+			return
+		}
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return
+	}
+	defer store.Close()
+
+	if token, _ := store.Get("access_token"); token == "" {
+		return
+	}
+
+	due, err := store.DueReminders(time.Now())
+	if err != nil {
+		return
+	}
+
+	for _, reminder := range due {
+		output.Info("Reminder #%d, due %s:", reminder.ID, reminder.DueAt.Local().Format("2006-01-02 15:04"))
+		output.Note("  %s", reminder.Text)
+
+		switch readKey("[p]ost, [e]dit and post, [s]nooze an hour, [d]ismiss, or any other key for later: ") {
+		case 'p':
+			postReminder(store, reminder, reminder.Text)
+		case 'e':
+			text, err := getTextFromEditorWithComments(reminder.Text, nil)
+			if err != nil {
+				output.Error("%v", err)
+				continue
+			}
+			postReminder(store, reminder, text)
+		case 's':
+			if err := store.SnoozeReminder(reminder.ID, time.Now().Add(reminderSnooze)); err != nil {
+				output.Error("Failed to snooze reminder: %v", err)
+			}
+		case 'd':
+			if _, err := store.RemoveReminder(reminder.ID); err != nil {
+				output.Error("Failed to dismiss reminder: %v", err)
+			}
+		case 0:
+			// Enter or Ctrl+C leaves the rest for later too
+			return
+		}
+	}
+}
+
+// postReminder posts text for a reminder, with hashtag profiles and CW rules
+// applied as for any post, and removes the reminder once it's posted. Failures
+// are reported rather than returned, so the command that was run still runs.
+func postReminder(store *config.Store, reminder *config.Reminder, text string) {
+	if text == "" {
+		output.Info("Nothing to post; the reminder is kept.")
+		return
+	}
+
+	domain, accessToken := credentials(store)
+	client, err := newAPI(store, domain, accessToken)
+	if err != nil {
+		output.Error("%v", err)
+		return
+	}
+
+	settings, _, err := applyHashtagProfiles(store, text, postSettings{Visibility: defaultVisibility(store, false)}, false)
+	if err == nil {
+		settings, _, err = applyCWRules(store, text, settings)
+	}
+	if err != nil {
+		output.Error("%v", err)
+		return
+	}
+
+	output.Info("Posting status...")
+	status, err := client.PostStatus(mastodon.StatusParams{
+		Status:      text,
+		Visibility:  settings.Visibility,
+		SpoilerText: settings.SpoilerText,
+		Language:    settings.Language,
+		Sensitive:   settings.Sensitive,
+
+		IdempotencyKey: mastodon.NewIdempotencyKey(),
+	})
+	if err != nil {
+		output.Error("Failed to post status: %v", err)
+		return
+	}
+
+	if err := store.AddPostToHistory(status.ID); err != nil {
+		output.Error("Failed to save post to history: %v", err)
+	}
+	cacheStatuses(store, []*mastodon.Status{status})
+	if _, err := store.RemoveReminder(reminder.ID); err != nil {
+		output.Error("Failed to remove reminder: %v", err)
+	}
+
+	output.Success("Status posted!")
+	output.URL(status.URL)
+	runHook(store, hookPost, status)
+}
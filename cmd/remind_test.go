@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRemindAt(t *testing.T) {
+	now := time.Date(2026, 10, 15, 16, 0, 0, 0, time.Local)
+
+	for value, want := range map[string]time.Time{
+		"17:00":            time.Date(2026, 10, 15, 17, 0, 0, 0, time.Local),
+		"09:30":            time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local),
+		"16:00":            time.Date(2026, 10, 16, 16, 0, 0, 0, time.Local),
+		"2h":               now.Add(2 * time.Hour),
+		"2026-10-20 08:15": time.Date(2026, 10, 20, 8, 15, 0, 0, time.Local),
+	} {
+		got, err := parseRemindAt(value, now)
+		if err != nil {
+			t.Errorf("parseRemindAt(%q) failed: %v", value, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parseRemindAt(%q) = %s, expected %s", value, got, want)
+		}
+	}
+
+	for _, value := range []string{"", "soon", "-1h", "2026-10-14 08:15"} {
+		if _, err := parseRemindAt(value, now); err == nil {
+			t.Errorf("Expected an error for %q, got nil", value)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+// notificationPrefix marks a reply target given as a notification ID rather
+// than a status ID
+const notificationPrefix = "notif:"
+
+// resolveReplyTarget turns a -r argument into a status ID. "notif:ID" is
+// looked up and replaced by the status the notification is about, and a link
+// to a status by its ID on your instance.
+func resolveReplyTarget(client mastodonAPI, target string) (string, error) {
+	if !strings.HasPrefix(target, notificationPrefix) {
+		return client.ResolveStatusID(target)
+	}
+
+	notificationID := strings.TrimPrefix(target, notificationPrefix)
+	if notificationID == "" {
+		return "", fmt.Errorf("missing notification ID after %q", notificationPrefix)
+	}
+
+	notification, err := client.GetNotification(notificationID)
+	if err != nil {
+		return "", err
+	}
+
+	from := "unknown"
+	if notification.Account != nil {
+		from = notification.Account.Acct
+	}
+	if notification.Status == nil {
+		return "", fmt.Errorf("notification %s (%s from @%s) isn't about a status", notificationID, notification.Type, from)
+	}
+
+	output.Info("Replying to the status from @%s's %s notification", from, notification.Type)
+	return notification.Status.ID, nil
+}
+
+// replyVisibility is the visibility for a reply to a status with parent's.
+// Like the web client, a reply is no more visible than what it replies to,
+// unless a visibility was chosen; choosing a wider one than a followers-only
+// or direct parent gets a warning, since it shows the conversation to people
+// the author didn't.
+func replyVisibility(chosen, parent mastodon.Visibility, explicit bool) mastodon.Visibility {
+	if !explicit {
+		narrowed := mastodon.Narrowest(chosen, parent)
+		if narrowed != chosen {
+			output.Info("Replying as %s, like the status you're replying to", narrowed)
+		}
+		return narrowed
+	}
+
+	if (parent == mastodon.VisibilityPrivate || parent == mastodon.VisibilityDirect) && mastodon.Narrowest(chosen, parent) != chosen {
+		output.Warning("This reply is %s, but the status it replies to is %s", chosen, describeVisibility(parent))
+	}
+	return chosen
+}
+
+// defaultVisibility is the visibility of a post without --visibility: the
+// default_reply_visibility setting for a reply, if it's set, and otherwise
+// default_visibility
+func defaultVisibility(store *config.Store, reply bool) mastodon.Visibility {
+	value := getSetting(store, "default_visibility")
+	if reply {
+		if replyValue := getSetting(store, "default_reply_visibility"); replyValue != "" {
+			value = replyValue
+		}
+	}
+	v, err := mastodon.ParseVisibility(value)
+	if err != nil {
+		return mastodon.VisibilityPublic
+	}
+	return v
+}
+
+// replySpoilerText is the content warning a reply carries over from its
+// parent's, marked "re:" as the web client does, but only once however long
+// the thread
+func replySpoilerText(parent string) string {
+	if parent == "" || strings.HasPrefix(strings.ToLower(parent), "re: ") {
+		return parent
+	}
+	return "re: " + parent
+}
+
+// describeVisibility names a visibility the way the web interface does
+func describeVisibility(v mastodon.Visibility) string {
+	switch v {
+	case mastodon.VisibilityPrivate:
+		return "followers-only"
+	case mastodon.VisibilityDirect:
+		return "direct"
+	}
+	return v.String()
+}
+
+// historyReplyTarget returns the status ID of the Nth most recent post in the
+// history, for --reply-index
+func historyReplyTarget(store *config.Store, index int) (string, error) {
+	if index < 1 {
+		return "", invalidf("--reply-index must be 1 or more")
+	}
+
+	statusID, err := store.GetPostIDAt(index)
+	if err != nil {
+		return "", fmt.Errorf("failed to read post history: %w", err)
+	}
+	if statusID == "" {
+		return "", fmt.Errorf("there's no post %d in your history. See 'tusk history-list'", index)
+	}
+	return statusID, nil
+}
+
+// threadTailTarget returns the status ID for --continue-thread: the end of
+// the chain of replies to yourself that your latest post is part of, which
+// may have grown from another app since tusk last posted
+func threadTailTarget(store *config.Store, client mastodonAPI) (string, error) {
+	lastPostID, err := store.GetLastPostID()
+	if err != nil {
+		return "", fmt.Errorf("failed to get last post ID: %w", err)
+	}
+	if lastPostID == "" {
+		return "", fmt.Errorf("no last post found. Post something first to start a thread")
+	}
+
+	me, err := client.VerifyCredentials()
+	if err != nil {
+		return "", err
+	}
+	context, err := client.GetStatusContext(lastPostID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	tail := mastodon.ThreadTail(me.ID, lastPostID, context.Descendants)
+	if tail != lastPostID {
+		output.Info("Continuing the thread from your later post %s", tail)
+	}
+	return tail, nil
+}
+
+// addReplyMentions returns the text of a reply to target, with the accounts
+// in the conversation mentioned at the start if inherit is set, and who the
+// reply will notify. Accounts the text already mentions aren't added again.
+func addReplyMentions(text string, me *mastodon.Account, target *mastodon.Status, inherit bool) (string, []string) {
+	mentioned := make(map[string]bool)
+	for _, mention := range extractMentions(text) {
+		mentioned[strings.ToLower(mention)] = true
+	}
+
+	if inherit {
+		var missing []string
+		for _, acct := range mastodon.ReplyAudience(me.ID, target) {
+			if !mentioned[strings.ToLower(acct)] {
+				missing = append(missing, "@"+acct)
+			}
+		}
+		if len(missing) > 0 {
+			text = strings.Join(missing, " ") + " " + text
+		}
+	}
+
+	var notified []string
+	seen := make(map[string]bool)
+	for _, mention := range extractMentions(text) {
+		key := strings.ToLower(mention)
+		if seen[key] || strings.EqualFold(mention, me.Acct) {
+			continue
+		}
+		seen[key] = true
+		notified = append(notified, mention)
+	}
+	return text, notified
+}
+
+// formatMentions lists accounts as "@a, @b, and @c"
+func formatMentions(accts []string) string {
+	mentions := make([]string, len(accts))
+	for i, acct := range accts {
+		mentions[i] = "@" + acct
+	}
+
+	switch len(mentions) {
+	case 1:
+		return mentions[0]
+	case 2:
+		return mentions[0] + " and " + mentions[1]
+	default:
+		return strings.Join(mentions[:len(mentions)-1], ", ") + ", and " + mentions[len(mentions)-1]
+	}
+}
+
+// cacheStatuses remembers statuses locally so replies to them can be
+// described later without another API call
+func cacheStatuses(store *config.Store, statuses []*mastodon.Status) {
+	for _, status := range statuses {
+		if status.Account == nil {
+			continue
+		}
+		store.CacheStatus(status.ID, status.Account.Acct, status.Content)
+	}
+}
+
+// describeReply returns a "↳ reply to @user: snippet" line for a reply, or ""
+// if the status isn't one. The parent is looked up in the local cache first;
+// client may be nil to skip fetching it from the server on a cache miss.
+func describeReply(store *config.Store, client mastodonAPI, status *mastodon.Status) string {
+	if status.InReplyTo == "" {
+		return ""
+	}
+
+	cached, _ := store.GetCachedStatus(status.InReplyTo)
+	if cached == nil && client != nil {
+		parent, err := client.GetStatus(status.InReplyTo)
+		if err == nil && parent.Account != nil {
+			store.CacheStatus(parent.ID, parent.Account.Acct, parent.Content)
+			cached = &config.CachedStatus{StatusID: parent.ID, Acct: parent.Account.Acct, Content: parent.Content}
+		}
+	}
+
+	if cached == nil {
+		return fmt.Sprintf("↳ reply to status %s", status.InReplyTo)
+	}
+	return fmt.Sprintf("↳ reply to @%s: %s", cached.Acct, truncate(stripHTML(cached.Content), 60))
+}
+
+// threadSnapshot records which replies a thread had, so activity that arrives
+// while a reply is being composed can be spotted
+type threadSnapshot map[string]bool
+
+// snapshotThread returns the IDs of the replies under statusID. A nil snapshot
+// means the thread couldn't be fetched and shouldn't be compared later.
+func snapshotThread(client mastodonAPI, statusID string) threadSnapshot {
+	context, err := client.GetStatusContext(statusID)
+	if err != nil {
+		return nil
+	}
+	return snapshotFromContext(context)
+}
+
+func snapshotFromContext(context *mastodon.StatusContext) threadSnapshot {
+	snapshot := make(threadSnapshot)
+	for _, status := range context.Descendants {
+		snapshot[status.ID] = true
+	}
+	return snapshot
+}
+
+// newThreadActivity returns replies under statusID that aren't in snapshot
+func newThreadActivity(client mastodonAPI, statusID string, snapshot threadSnapshot) ([]*mastodon.Status, error) {
+	context, err := client.GetStatusContext(statusID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fresh []*mastodon.Status
+	for _, status := range context.Descendants {
+		if !snapshot[status.ID] {
+			fresh = append(fresh, status)
+		}
+	}
+	return fresh, nil
+}
+
+// postEditorComments returns the context shown as comments when composing a
+// post in the editor: the status being replied to, the instance's character
+// limit, and the tags you use most. Whatever can't be fetched is left out.
+func postEditorComments(store *config.Store, client mastodonAPI, inReplyToID string) []string {
+	var comments []string
+	if inReplyToID != "" {
+		if status, err := client.GetStatus(inReplyToID); err == nil {
+			author := "unknown"
+			if status.Account != nil {
+				author = status.Account.Acct
+			}
+			comments = append(comments, fmt.Sprintf("Replying to @%s:", author))
+			for _, line := range strings.Split(htmlToText(status.Content), "\n") {
+				comments = append(comments, "  "+line)
+			}
+			comments = append(comments, "")
+		}
+	}
+	if instance, err := client.GetInstance(); err == nil {
+		if limit := instance.MaxCharacters(); limit > 0 {
+			comments = append(comments, fmt.Sprintf("Your instance allows posts of up to %d characters.", limit))
+		}
+	}
+	if tags := editorTagComment(store); tags != "" {
+		comments = append(comments, tags)
+	}
+	return comments
+}
@@ -1,26 +1,174 @@
 package cmd
 
 import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
 	"github.com/spf13/cobra"
 )
 
+var (
+	quiet        bool
+	verbose      bool
+	noColor      bool
+	outputFormat string
+	debug        bool
+	debugLogPath string
+	databaseFlag string
+
+	// debugLogger receives the HTTP trace when --debug is set
+	debugLogger *slog.Logger
+
+	// commandStarted is set once the command line has been parsed
+	commandStarted bool
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "tusk [TEXT]",
 	Short: "A CLI client for Mastodon",
 	Long:  `Tusk is a command-line interface for interacting with Mastodon instances.`,
 	Args:  cobra.ArbitraryArgs,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The command line is valid, so errors from here on are the
+		// command's own and don't need the usage repeated
+		commandStarted = true
+		cmd.SilenceUsage = true
+
+		// The worker finishes its current job when stopped instead
+		if cmd != daemonRunCmd {
+			handleInterrupts()
+		}
+
+		if err := applyOutputFlags(); err != nil {
+			return err
+		}
+		if err := applyDebugFlags(); err != nil {
+			return err
+		}
+		if err := applyDatabaseFlag(); err != nil {
+			return err
+		}
+		if err := applyInstanceFlags(); err != nil {
+			return err
+		}
+		// Catch a damaged database before any command tries to use it
+		if err := checkStore(cmd, args); err != nil {
+			return err
+		}
+		if err := checkScopes(cmd); err != nil {
+			return err
+		}
+		showBanner(cmd)
+		offerReminders(cmd)
+		return nil
+	},
+	Annotations: needsScopes("write:statuses"),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// If no subcommand is provided, run the post command
 		return runPost(cmd, args)
 	},
+	// Execute prints errors, with advice where there is some
+	SilenceErrors: true,
 	// Disable flag parsing errors for unknown commands that might be text
 	FParseErrWhitelist: cobra.FParseErrWhitelist{
 		UnknownFlags: true,
 	},
 }
 
-func Execute() error {
-	return rootCmd.Execute()
+// applyOutputFlags sets up internal/output from --quiet, --verbose,
+// --no-color, and --output
+func applyOutputFlags() error {
+	if quiet && verbose {
+		return invalidf("--quiet and --verbose can't be used together")
+	}
+
+	format, err := output.ParseFormat(outputFormat)
+	if err != nil {
+		return err
+	}
+	output.SetFormat(format)
+
+	switch {
+	case quiet:
+		output.SetLevel(output.LevelQuiet)
+	case verbose:
+		output.SetLevel(output.LevelVerbose)
+	}
+
+	if noColor {
+		output.DisableColor()
+		// The TUIs and anything tusk starts check the environment instead
+		os.Setenv("NO_COLOR", "1")
+	}
+	return nil
+}
+
+// applyDebugFlags sets up the HTTP trace from --debug and --debug-log
+func applyDebugFlags() error {
+	if !debug && debugLogPath == "" {
+		return nil
+	}
+
+	w := os.Stderr
+	if debugLogPath != "" {
+		// Left open for the life of the process; writes aren't buffered
+		f, err := os.OpenFile(debugLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open debug log: %w", err)
+		}
+		w = f
+	}
+
+	debugLogger = slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return nil
+}
+
+// applyDatabaseFlag points tusk at the database chosen with --db. It goes
+// through the environment so the background worker uses it too.
+func applyDatabaseFlag() error {
+	if databaseFlag == "" {
+		return nil
+	}
+
+	path, err := filepath.Abs(databaseFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --db path: %w", err)
+	}
+	return os.Setenv(config.DatabaseEnv, path)
+}
+
+// Execute runs the command line, reports any error, and returns the code to
+// exit with; see exitCodes
+func Execute() int {
+	registerCompletions()
+
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return exitOK
+	}
+
+	// Cobra fails before running anything on a bad command, flag, or
+	// argument
+	if !commandStarted {
+		err = &codedError{code: exitInvalid, err: err}
+	}
+	// Once logged in again, the advice about an expired login is moot
+	if !offerReauth(cmd, err) {
+		err = explainError(err)
+	}
+
+	code := exitCode(err)
+	switch {
+	case code == exitInterrupted:
+		fmt.Fprintln(os.Stderr, "Interrupted.")
+	case !reported(err):
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	return code
 }
 
 func init() {
@@ -29,20 +177,101 @@ func init() {
 	rootCmd.AddCommand(postCmd)
 	rootCmd.AddCommand(editCmd)
 	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(dmCmd)
 	rootCmd.AddCommand(latestCmd)
 	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(clearCmd)
 	rootCmd.AddCommand(logoutCmd)
+	rootCmd.AddCommand(jobsCmd)
+	rootCmd.AddCommand(profilesCmd)
+	rootCmd.AddCommand(cwRulesCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(selfUpdateCmd)
+	rootCmd.AddCommand(redraftCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(favsCmd)
+	rootCmd.AddCommand(metricsCmd)
+	rootCmd.AddCommand(chatCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(seriesCmd)
+	rootCmd.AddCommand(timelineCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(userCmd)
+	rootCmd.AddCommand(trendsCmd)
+	rootCmd.AddCommand(whoisCmd)
+	rootCmd.AddCommand(followCmd)
+	rootCmd.AddCommand(relationshipCmd)
+	rootCmd.AddCommand(noteCmd)
+	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(followupsCmd)
+	rootCmd.AddCommand(notifyCmd)
+	rootCmd.AddCommand(pushCmd)
+	rootCmd.AddCommand(reboostCmd)
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+	rootCmd.AddCommand(pinsCmd)
+	rootCmd.AddCommand(reactCmd)
+	rootCmd.AddCommand(pollCmd)
+	rootCmd.AddCommand(voteCmd)
+	rootCmd.AddCommand(conversationsCmd)
+	rootCmd.AddCommand(mentionsCmd)
+	rootCmd.AddCommand(remindCmd)
+	rootCmd.AddCommand(shareCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(engagementCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(docsCmd)
+
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print results, warnings, and errors")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Also print debug details, such as API requests, to stderr")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors (also set by the NO_COLOR environment variable)")
+	rootCmd.PersistentFlags().BoolVar(&insecureTLS, "insecure", false, "Don't verify the instance's TLS certificate (prefer 'tusk config set ca_bundle')")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Trace every API request, with status, latency, and rate limits, to stderr")
+	rootCmd.PersistentFlags().StringVar(&debugLogPath, "debug-log", "", "Append the --debug trace to this file instead of stderr (implies --debug)")
+	rootCmd.PersistentFlags().StringVar(&databaseFlag, "db", "", "Use this database file instead of the default (also set by TUSK_DB)")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Answer yes to every confirmation, such as missing alt text or deleting a status")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Fail instead of asking anything, opening an editor, or starting a TUI")
+	rootCmd.PersistentFlags().StringVar(&instanceDomain, "instance", "", "Use this instance instead of the stored login, for public data only without --token (also set by TUSK_INSTANCE)")
+	rootCmd.PersistentFlags().StringVar(&instanceToken, "token", "", "Access token to use with --instance instead of the stored login (also set by TUSK_TOKEN)")
+	// No shorthand: export already uses -o for its directory
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "plain", "Format for listings: plain, json, yaml, or table; id or url for just the IDs or URLs of what a command posts or lists")
+
+	// Hidden flags for capturing and replaying API interactions
+	rootCmd.PersistentFlags().StringVar(&recordPath, "record", "", "Record API interactions to a cassette file")
+	rootCmd.PersistentFlags().StringVar(&replayPath, "replay", "", "Replay API interactions from a cassette file")
+	rootCmd.PersistentFlags().MarkHidden("record")
+	rootCmd.PersistentFlags().MarkHidden("replay")
 
 	// Add post command flags to root command so they work without "post"
-	rootCmd.Flags().StringVarP(&replyTo, "reply", "r", "", "Reply to a specific status ID")
+	rootCmd.Flags().StringVarP(&replyTo, "reply", "r", "", "Reply to a specific status ID or link (or notif:ID for the status of a notification)")
 	rootCmd.Flags().BoolVarP(&replyLast, "reply-last", "R", false, "Reply to the last posted status")
+	rootCmd.Flags().IntVar(&replyIndex, "reply-index", 0, "Reply to the Nth most recent post in your history (1 is the same as -R)")
+	rootCmd.Flags().BoolVar(&replyThread, "continue-thread", false, "Reply to the end of the thread your last post is in")
 	rootCmd.Flags().BoolVar(&replyTUI, "reply-tui", false, "Interactive TUI to select post to reply to")
 	rootCmd.Flags().BoolVarP(&useEditor, "editor", "e", false, "Compose post in $EDITOR")
-	rootCmd.Flags().StringVarP(&visibility, "visibility", "v", "public", "Post visibility (public, unlisted, private, direct)")
+	rootCmd.Flags().StringVarP(&visibility, "visibility", "v", "public", "Post visibility: public (p), unlisted (u), private (f), direct (d); defaults to the default_visibility and default_reply_visibility settings")
 	rootCmd.Flags().StringVarP(&contentWarn, "cw", "w", "", "Content warning / spoiler text")
 	rootCmd.Flags().StringVarP(&language, "lang", "l", "", "ISO 639 language code (e.g., en, es, fr, de, ja)")
 	rootCmd.Flags().StringVarP(&imagePath, "image", "i", "", "Path to image file to attach")
 	rootCmd.Flags().StringVar(&altText, "alt", "", "Alt text for the image")
 	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be posted without actually posting")
+	rootCmd.Flags().BoolVar(&sensitive, "sensitive", false, "Mark attached media as sensitive")
+	rootCmd.Flags().BoolVar(&postAsync, "async", false, "Queue the post for a background worker and return immediately")
+	rootCmd.Flags().BoolVar(&postOpen, "open", false, "Open the status in your browser after posting")
+	rootCmd.Flags().StringVar(&postSeries, "series", "", "Append the next label of a numbered series (see 'tusk series')")
+	rootCmd.Flags().BoolVar(&noMentions, "no-mentions", false, "Don't add the author and mentions of the status you're replying to")
+	rootCmd.Flags().StringVar(&postFile, "file", "", "Read the status, and optional frontmatter, from a file")
+	rootCmd.Flags().StringVar(&postWatch, "watch", "", "Post each .md or .txt file dropped into a directory")
+	rootCmd.Flags().BoolVar(&postConfirm, "confirm", false, "Show the post and ask before sending it (default from the confirm_before_post setting)")
+	rootCmd.Flags().BoolVar(&postLocalOnly, "local-only", false, "Keep the post off other instances (glitch-soc and Hometown only)")
+	rootCmd.Flags().BoolVar(&noReplyCW, "no-cw", false, "Don't carry over the content warning of the status you're replying to")
+	rootCmd.Flags().BoolVar(&noAutoCW, "no-auto-cw", false, "Don't add content warnings from 'tusk cw-rules'")
+	rootCmd.Flags().BoolVar(&allowDuplicate, "allow-duplicate", false, "Post even if the text is the same as one of your recent posts")
+	rootCmd.Flags().BoolVar(&postFromGit, "from-git", false, "Post the subject and body of a commit, HEAD unless a ref is given as the argument")
+	rootCmd.Flags().BoolVar(&postGitLink, "git-link", false, "With --from-git, link to the commit on the origin remote's web page")
+	rootCmd.Flags().BoolVar(&suggestTags, "suggest-tags", false, "List the hashtags you use most, optionally starting with TEXT, instead of posting")
 }
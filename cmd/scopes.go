@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// defaultScopes are what a new login asks for: enough for every command
+// that doesn't say it needs more
+const defaultScopes = "read write"
+
+// scopesKey is where the scopes of the stored access token are kept. It's
+// empty when they aren't known, as for a token made outside tusk.
+const scopesKey = "scopes"
+
+// scopesAnnotation marks a command with the scopes it needs beyond reading,
+// space-separated
+const scopesAnnotation = "tusk_scopes"
+
+// needsScopes is the annotation for a command that needs scopes
+func needsScopes(scopes string) map[string]string {
+	return map[string]string{scopesAnnotation: scopes}
+}
+
+// loginScopes returns the scopes to ask for when logging in again: the
+// stored token's, so nothing it could do is lost, plus extra
+func loginScopes(store *config.Store, extra ...string) string {
+	stored, _ := store.Get(scopesKey)
+	scopes := mastodon.ParseScopes(stored)
+	if len(scopes) == 0 {
+		scopes = mastodon.ParseScopes(defaultScopes)
+	}
+	for _, scope := range extra {
+		if !mastodon.HasScope(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return strings.Join(scopes, " ")
+}
+
+// parseScopesFlag checks the scopes given to auth --scopes
+func parseScopesFlag(value string) (string, error) {
+	scopes := mastodon.ParseScopes(value)
+	if len(scopes) == 0 {
+		return "", invalidf("--scopes cannot be empty")
+	}
+	for _, scope := range scopes {
+		if !mastodon.ValidScope(scope) {
+			return "", invalidf("unknown scope %q: scopes are read, write, follow, push, and profile, or narrower ones such as write:statuses", scope)
+		}
+	}
+	return strings.Join(scopes, " "), nil
+}
+
+// checkScopes makes sure the stored login has the scopes cmd needs before
+// it runs. In a terminal, it offers to log in again with them; otherwise the
+// error says how to. Logins whose scopes aren't known aren't checked.
+func checkScopes(cmd *cobra.Command) error {
+	needed := mastodon.ParseScopes(cmd.Annotations[scopesAnnotation])
+	if len(needed) == 0 || overridingLogin() || replayPath != "" {
+		return nil
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return nil
+	}
+	defer store.Close()
+
+	if token, _ := store.Get("access_token"); token == "" {
+		return nil
+	}
+	stored, _ := store.Get(scopesKey)
+	if stored == "" {
+		return nil
+	}
+	missing := mastodon.MissingScopes(mastodon.ParseScopes(stored), needed)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	scopes := loginScopes(store, missing...)
+	problem := fmt.Errorf("your login doesn't allow %s, which '%s' needs. Run 'tusk auth --scopes \"%s\"'", strings.Join(missing, " "), cmd.CommandPath(), scopes)
+
+	// Logging in again opens a browser, which --yes can't answer for
+	if nonInteractive || assumeYes || !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return &codedError{code: exitAuth, err: problem}
+	}
+
+	domain, _ := store.Get("domain")
+	output.Warning("Your login doesn't allow %s, which '%s' needs.", strings.Join(missing, " "), cmd.CommandPath())
+	output.Prompt("Log in to %s again with it now? (Y/n): ", strings.TrimPrefix(domain, "https://"))
+
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "" && response != "y" && response != "yes" {
+		return &codedError{code: exitAuth, err: problem}
+	}
+
+	if err := authenticate(store, domain, scopes); err != nil {
+		return fmt.Errorf("failed to log in again: %w", err)
+	}
+	return nil
+}
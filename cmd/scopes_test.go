@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"biesnecker.com/tusk/internal/config"
+)
+
+func TestCheckScopes(t *testing.T) {
+	t.Setenv(config.DatabaseEnv, filepath.Join(t.TempDir(), "tusk.db"))
+	store, err := config.NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	store.Set("domain", "https://example.social")
+	store.Set("access_token", "token")
+
+	nonInteractive = true
+	defer func() { nonInteractive = false }()
+
+	// Tokens made outside tusk aren't checked
+	if err := checkScopes(pinCmd); err != nil {
+		t.Errorf("Expected no check without stored scopes, got %v", err)
+	}
+
+	store.Set(scopesKey, "read write:statuses")
+	if err := checkScopes(postCmd); err != nil {
+		t.Errorf("Expected posting to be allowed, got %v", err)
+	}
+	err = checkScopes(pinCmd)
+	if err == nil {
+		t.Fatal("Expected pinning without write:accounts to be refused, got nil")
+	}
+	if code := exitCode(err); code != exitAuth {
+		t.Errorf("Expected exit code %d, got %d", exitAuth, code)
+	}
+
+	if got := loginScopes(store, "write:accounts", "read:statuses"); got != "read write:statuses write:accounts" {
+		t.Errorf("Expected the stored scopes plus the missing one, got %q", got)
+	}
+}
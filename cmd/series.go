@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	seriesTemplate string
+	seriesStart    int
+)
+
+var seriesCmd = &cobra.Command{
+	Use:   "series",
+	Short: "Manage numbered post series",
+	Long: `Manage series of numbered posts, for daily-posting projects. Posting with
+--series appends the next label (like "Day 12") and advances the counter.
+
+The template's {n} is replaced with the post's number.
+
+Examples:
+  tusk series create "Daily Sketch"
+  tusk series create "Reading" --template "Book {n} of 52"
+  tusk post --series "Daily Sketch" -i sketch.jpg --alt "A fox" "Foxes today"
+  tusk series
+  tusk series remove "Reading"`,
+	Args: cobra.NoArgs,
+	RunE: runSeriesList,
+}
+
+var seriesCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Start a new series",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSeriesCreate,
+}
+
+var seriesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show each series and its progress",
+	Args:  cobra.NoArgs,
+	RunE:  runSeriesList,
+}
+
+var seriesRemoveCmd = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Remove a series",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSeriesRemove,
+}
+
+func init() {
+	seriesCreateCmd.Flags().StringVarP(&seriesTemplate, "template", "t", config.DefaultSeriesTemplate, "Label appended to each post; {n} is the post's number")
+	seriesCreateCmd.Flags().IntVar(&seriesStart, "start", 1, "Number of the first post")
+
+	seriesCmd.AddCommand(seriesCreateCmd)
+	seriesCmd.AddCommand(seriesListCmd)
+	seriesCmd.AddCommand(seriesRemoveCmd)
+}
+
+func runSeriesCreate(cmd *cobra.Command, args []string) error {
+	if seriesStart < 1 {
+		return invalidf("--start must be at least 1")
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.CreateSeries(args[0], seriesTemplate, seriesStart); err != nil {
+		return fmt.Errorf("failed to create series: %w", err)
+	}
+
+	series, err := store.GetSeries(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load series: %w", err)
+	}
+
+	output.Success("Series %q created. Its next post will be labelled %q", series.Name, series.Label(series.Next()))
+	return nil
+}
+
+func runSeriesList(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	list, err := store.ListSeries()
+	if err != nil {
+		return fmt.Errorf("failed to list series: %w", err)
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "name", Header: "NAME"},
+			{Key: "template", Header: "TEMPLATE", Detail: true},
+			{Key: "count", Header: "COUNT", Detail: true},
+			{Key: "next", Header: "NEXT"},
+			{Key: "last_posted_at", Header: "LAST POSTED"},
+		},
+		Empty: "No series. Start one with 'tusk series create NAME'.",
+	}
+
+	for _, series := range list {
+		listing.Rows = append(listing.Rows, []any{
+			series.Name, series.Template, series.Count, series.Label(series.Next()), series.LastPostedAt,
+		})
+	}
+
+	listing.Plain = func(i int) {
+		series := list[i]
+		last := "nothing posted yet"
+		if !series.LastPostedAt.IsZero() {
+			last = "last posted " + series.LastPostedAt.Local().Format("2006-01-02 15:04")
+		}
+		output.Plain("%s  next: %q  (%s)", series.Name, series.Label(series.Next()), last)
+	}
+
+	return output.Render(listing)
+}
+
+func runSeriesRemove(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	series, err := store.GetSeries(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load series: %w", err)
+	}
+	if series == nil {
+		return fmt.Errorf("no series named %q", args[0])
+	}
+
+	if err := store.RemoveSeries(series.Name); err != nil {
+		return fmt.Errorf("failed to remove series: %w", err)
+	}
+
+	output.Success("Series %q removed", series.Name)
+	return nil
+}
+
+// loadPostSeries looks up the series named by --series, or returns nil when
+// the flag wasn't given
+func loadPostSeries(store *config.Store, name string) (*config.Series, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	series, err := store.GetSeries(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load series: %w", err)
+	}
+	if series == nil {
+		return nil, fmt.Errorf("no series named %q. Create it with 'tusk series create'", name)
+	}
+	return series, nil
+}
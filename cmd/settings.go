@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+// setting is a user preference that can be changed with 'tusk config set'.
+// Values live in the config table alongside the login details, which can't be
+// set this way.
+type setting struct {
+	Default     string
+	Description string
+	Validate    func(string) error
+}
+
+var settings = map[string]setting{
+	"auth_callback_port": {
+		Default:     "",
+		Description: "Port the browser returns to when logging in (empty for a random one)",
+		Validate:    validatePort,
+	},
+	"auth_page": {
+		Default:     "",
+		Description: "html/template file for the page the browser shows after logging in (empty for the built-in one)",
+		Validate:    validateAuthPage,
+	},
+	"banner": {
+		Default:     "on",
+		Description: "Show a one-line banner when your account needs attention (on/off)",
+		Validate:    validateOnOff,
+	},
+	"banner_interval": {
+		Default:     "30",
+		Description: "Minutes between banner checks",
+		Validate:    validatePositiveInt,
+	},
+	"ca_bundle": {
+		Default:     "",
+		Description: "PEM file of extra CA certificates to trust for your instance",
+		Validate:    validateCABundle,
+	},
+	"socks_proxy": {
+		Default:     "",
+		Description: "SOCKS5 proxy for all requests, e.g. Tor's 127.0.0.1:9050 for .onion instances",
+		Validate:    validateSOCKSProxy,
+	},
+	"content_type": {
+		Default:     "",
+		Description: "Format of your posts on Pleroma, Akkoma, and GoToSocial (text/plain, text/markdown, or text/html)",
+		Validate:    validateContentType,
+	},
+	"response_cache": {
+		Default:     "on",
+		Description: "Reuse instance info, custom emoji, and account lookups between runs (on/off)",
+		Validate:    validateOnOff,
+	},
+	"confirm_before_post": {
+		Default:     "off",
+		Description: "Show each post and ask before sending it (on/off)",
+		Validate:    validateOnOff,
+	},
+	"default_visibility": {
+		Default:     "public",
+		Description: "Visibility of posts and shares without --visibility",
+		Validate:    validateVisibility,
+	},
+	"default_reply_visibility": {
+		Default:     "",
+		Description: "Visibility of replies without --visibility, e.g. unlisted (empty for default_visibility)",
+		Validate: func(value string) error {
+			if value == "" {
+				return nil
+			}
+			return validateVisibility(value)
+		},
+	},
+	"duplicate_check": {
+		Default:     duplicateAsk,
+		Description: "What to do when a post's text matches one of your recent posts (ask/abort/off)",
+		Validate:    validateDuplicateCheck,
+	},
+	"duplicate_check_posts": {
+		Default:     "20",
+		Description: "How many of your latest posts the duplicate check compares against",
+		Validate:    validatePositiveInt,
+	},
+	"notify_types": {
+		Default:     "",
+		Description: "Notification types 'tusk notify' shows, comma-separated (empty for all)",
+		Validate:    func(value string) error { return validateNotificationTypes(splitList(value)) },
+	},
+	"notify_quiet_hours": {
+		Default:     "",
+		Description: "Times 'tusk notify' keeps off the desktop, e.g. 22:00-07:00",
+		Validate: func(value string) error {
+			_, err := parseQuietHours(value)
+			return err
+		},
+	},
+	"push_listen": {
+		Default:     "127.0.0.1:8795",
+		Description: "Address the background worker receives push notifications on",
+		Validate:    validateListenAddress,
+	},
+	"on_post": {
+		Default:     "",
+		Description: "Shell command to run after posting, with the status as JSON on stdin",
+		Validate:    validateCommand,
+	},
+	"on_delete": {
+		Default:     "",
+		Description: "Shell command to run after deleting, with the status as JSON on stdin",
+		Validate:    validateCommand,
+	},
+}
+
+func validateOnOff(value string) error {
+	if value != "on" && value != "off" {
+		return fmt.Errorf("must be on or off")
+	}
+	return nil
+}
+
+func validatePositiveInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("must be a positive whole number")
+	}
+	return nil
+}
+
+func validatePort(value string) error {
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("must be a port between 1 and 65535, or empty for a random one")
+	}
+	return nil
+}
+
+func validateAuthPage(value string) error {
+	if value == "" {
+		return nil
+	}
+	if !filepath.IsAbs(value) {
+		return fmt.Errorf("must be an absolute path")
+	}
+	_, err := loadAuthPage(value)
+	return err
+}
+
+func validateCABundle(value string) error {
+	if value == "" {
+		return nil
+	}
+	if !filepath.IsAbs(value) {
+		return fmt.Errorf("must be an absolute path")
+	}
+	return mastodon.ValidateCAFile(value)
+}
+
+func validateSOCKSProxy(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := mastodon.ParseSOCKSProxy(value)
+	return err
+}
+
+func validateListenAddress(value string) error {
+	if _, port, err := net.SplitHostPort(value); err != nil || port == "" {
+		return fmt.Errorf("must be a host and port, such as 127.0.0.1:8795")
+	}
+	return nil
+}
+
+func validateVisibility(value string) error {
+	_, err := mastodon.ParseVisibility(value)
+	return err
+}
+
+func validateContentType(value string) error {
+	switch value {
+	case "", "text/plain", "text/markdown", "text/html":
+		return nil
+	}
+	return fmt.Errorf("must be text/plain, text/markdown, or text/html")
+}
+
+// validateCommand accepts any shell command; the shell will complain soon
+// enough about a bad one
+func validateCommand(value string) error {
+	return nil
+}
+
+// getSetting returns a setting's value, or its default if it hasn't been set
+func getSetting(store *config.Store, name string) string {
+	value, err := store.Get(name)
+	if err != nil || value == "" {
+		return settings[name].Default
+	}
+	return value
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or change settings",
+	Long: `Show or change tusk's settings.
+
+Examples:
+  tusk config
+  tusk config get banner
+  tusk config set banner off`,
+	Args: cobra.NoArgs,
+	RunE: runConfigList,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Show a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+}
+
+func lookupSetting(name string) (setting, error) {
+	s, ok := settings[name]
+	if !ok {
+		return setting{}, invalidf("unknown setting %q. Run 'tusk config' to see them all", name)
+	}
+	return s, nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "name", Header: "NAME"},
+			{Key: "value", Header: "VALUE"},
+			{Key: "default", Header: "DEFAULT", Detail: true},
+			{Key: "description", Header: "DESCRIPTION"},
+		},
+	}
+
+	for _, name := range names {
+		listing.Rows = append(listing.Rows, []any{
+			name, getSetting(store, name), settings[name].Default, settings[name].Description,
+		})
+	}
+
+	listing.Plain = func(i int) {
+		name := names[i]
+		output.Plain("%-16s %-6s %s", name, getSetting(store, name), settings[name].Description)
+	}
+
+	return output.Render(listing)
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	if _, err := lookupSetting(args[0]); err != nil {
+		return err
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	output.Plain("%s", getSetting(store, args[0]))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	name, value := args[0], args[1]
+
+	s, err := lookupSetting(name)
+	if err != nil {
+		return err
+	}
+	if err := s.Validate(value); err != nil {
+		return invalidf("invalid value for %s: %w", name, err)
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.Set(name, value); err != nil {
+		return fmt.Errorf("failed to save setting: %w", err)
+	}
+
+	if value == "" {
+		output.Success("%s reset to its default", name)
+		return nil
+	}
+	output.Success("%s set to %s", name, value)
+	return nil
+}
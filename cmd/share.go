@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/linkcard"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	shareVisibility  string
+	shareContentWarn string
+	shareLanguage    string
+	shareDryRun      bool
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share URL [COMMENT]",
+	Short: "Share a link, previewing its card first",
+	Long: `Share a link in a post. tusk fetches the page first and shows the card your
+instance will likely attach to the post, from the page's title, description,
+and image, then asks before posting.
+
+The post is your comment, or the page's title if you don't give one, followed
+by the link.
+
+Examples:
+  tusk share https://example.com/article
+  tusk share https://example.com/article "Worth reading all the way through"
+  tusk share --dry-run https://example.com/article`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: needsScopes("write:statuses"),
+	RunE:        runShare,
+}
+
+func init() {
+	shareCmd.Flags().StringVarP(&shareVisibility, "visibility", "v", "public", "Post visibility: public (p), unlisted (u), private (f), direct (d); defaults to the default_visibility setting")
+	shareCmd.Flags().StringVarP(&shareContentWarn, "cw", "w", "", "Content warning / spoiler text")
+	shareCmd.Flags().StringVarP(&shareLanguage, "lang", "l", "", "ISO 639 language code (e.g., en, es, fr, de, ja)")
+	shareCmd.Flags().BoolVar(&shareDryRun, "dry-run", false, "Preview the post and its card without posting")
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	pageURL, err := shareURL(args[0])
+	if err != nil {
+		return err
+	}
+	comment := strings.TrimSpace(strings.Join(args[1:], " "))
+
+	postVisibility, err := mastodon.ParseVisibility(shareVisibility)
+	if err != nil {
+		return err
+	}
+
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" && !shareDryRun {
+		return errNotAuthenticated
+	}
+
+	output.Info("Fetching %s...", pageURL)
+	card, err := fetchCard(store, pageURL)
+	if err != nil {
+		// Instances make cards on their own; not being able to preview one
+		// is no reason not to share the link
+		output.Warning("Couldn't preview the link card: %v", err)
+		card = nil
+	}
+
+	statusText := shareText(pageURL, comment, card)
+	if !cmd.Flags().Changed("visibility") {
+		postVisibility = defaultVisibility(store, false)
+	}
+
+	settings, _, err := applyHashtagProfiles(store, statusText, postSettings{
+		Visibility:  postVisibility,
+		SpoilerText: shareContentWarn,
+		Language:    shareLanguage,
+	}, cmd.Flags().Changed("visibility"))
+	if err == nil {
+		settings, _, err = applyCWRules(store, statusText, settings)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The preview is a dry run's result, but otherwise goes with the question
+	show := output.Note
+	if shareDryRun {
+		show = output.Plain
+	}
+	show("")
+	show("%s", statusText)
+	show("")
+	show("Visibility: %s", settings.Visibility)
+	if settings.SpoilerText != "" {
+		show("Content warning: %s", settings.SpoilerText)
+	}
+	printSharedCard(card, show)
+
+	if shareDryRun {
+		output.Info("Dry run mode - nothing posted.")
+		return nil
+	}
+
+	if err := checkDuplicate(store, statusText); err != nil {
+		return err
+	}
+
+	output.Note("")
+	ok, err := confirm("", "Post this?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		output.Info("Post cancelled.")
+		return errCancelled
+	}
+
+	client, err := newAPI(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	output.Info("Posting status...")
+	status, err := client.PostStatus(mastodon.StatusParams{
+		Status:      statusText,
+		Visibility:  settings.Visibility,
+		SpoilerText: settings.SpoilerText,
+		Language:    settings.Language,
+		Sensitive:   settings.Sensitive,
+
+		IdempotencyKey: mastodon.NewIdempotencyKey(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to post status: %w", err)
+	}
+
+	if err := store.AddPostToHistory(status.ID); err != nil {
+		output.Error("Failed to save post to history: %v", err)
+	}
+	cacheStatuses(store, []*mastodon.Status{status})
+
+	output.Success("Status posted!")
+	output.Result(status.ID, status.URL)
+	runHook(store, hookPost, status)
+	return nil
+}
+
+// shareURL checks that a link to share is a web page's, adding https:// if
+// it was left off
+func shareURL(ref string) (string, error) {
+	if !strings.Contains(ref, "://") {
+		ref = "https://" + ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", invalidf("%q is not a web link", ref)
+	}
+	return u.String(), nil
+}
+
+// fetchCard gets the card for a page over the same kind of connection as the
+// instance's, so a proxy or CA bundle set up for tusk applies. Tests replace
+// it to stay off the network.
+var fetchCard = func(store *config.Store, pageURL string) (*mastodon.Card, error) {
+	page := mastodon.NewClient(pageURL, "")
+	if err := page.UseTransport(mastodon.TransportOptions{
+		Insecure:   insecureTLS,
+		CAFile:     getSetting(store, "ca_bundle"),
+		SOCKSProxy: getSetting(store, "socks_proxy"),
+	}); err != nil {
+		return nil, err
+	}
+	page.UseContext(runContext)
+	return linkcard.Fetch(page.HTTPClient, pageURL)
+}
+
+// shareText is the post for a shared link: the comment, or failing that the
+// page's title, then the link on its own line
+func shareText(pageURL, comment string, card *mastodon.Card) string {
+	if comment == "" && card != nil {
+		comment = card.Title
+	}
+	if comment == "" {
+		return pageURL
+	}
+	return comment + "\n\n" + pageURL
+}
+
+// printSharedCard shows the card a shared link will likely get, in more
+// detail than printCard gives a card that's already attached
+func printSharedCard(card *mastodon.Card, show func(format string, a ...interface{})) {
+	if card == nil {
+		return
+	}
+
+	show("")
+	show("Link card:")
+	if card.ProviderName != "" {
+		show("  %s", card.ProviderName)
+	}
+	if card.Title != "" {
+		show("  %s", card.Title)
+	} else {
+		show("  (no title)")
+	}
+	if card.Description != "" {
+		show("  %s", truncate(card.Description, 200))
+	}
+	if card.Image != "" {
+		show("  Image: %s", card.Image)
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/internal/stats"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsPosts int
+	statsDays  int
+	statsTags  int
+)
+
+// statsPageSize is the most statuses the API returns at once
+const statsPageSize = 40
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about your account",
+	Long: `Show when you post, which hashtags you use most, how much engagement your posts
+get, and how your follower count has changed.
+
+The post statistics cover your most recent posts, boosts left out. Follower
+counts are saved each time you run this, one snapshot a day, so growth shows
+up once you've run it over a few days.
+
+Examples:
+  tusk stats
+  tusk stats -n 500
+  tusk stats --days 30`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().IntVarP(&statsPosts, "posts", "n", 200, "Number of recent posts to include")
+	statsCmd.Flags().IntVarP(&statsDays, "days", "d", 90, "Show follower growth over the last N days")
+	statsCmd.Flags().IntVar(&statsTags, "tags", 5, "Number of top hashtags to show")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	domain, accessToken := credentials(store)
+
+	if accessToken == "" {
+		return errNotAuthenticated
+	}
+
+	client, err := newClient(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
+
+	me, err := client.VerifyCredentials()
+	if err != nil {
+		return err
+	}
+
+	if err := store.RecordAccountSnapshot(config.AccountSnapshot{
+		AccountID: me.ID,
+		Followers: me.FollowersCount,
+		Following: me.FollowingCount,
+		Statuses:  me.StatusesCount,
+	}); err != nil {
+		output.Warning("Failed to save follower count: %v", err)
+	}
+
+	var maxID string
+	statuses := make([]*mastodon.Status, 0, statsPosts)
+	for len(statuses) < statsPosts {
+		page, err := client.GetAccountStatusesPage(me.ID, maxID, min(statsPageSize, statsPosts-len(statuses)))
+		if err != nil {
+			return fmt.Errorf("failed to fetch statuses: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		statuses = append(statuses, page...)
+		maxID = page[len(page)-1].ID
+	}
+
+	output.Plain("@%s: %d followers, %d following, %d posts", me.Acct, me.FollowersCount, me.FollowingCount, me.StatusesCount)
+	printFollowerGrowth(store, me.ID)
+
+	summary := stats.Summarize(statuses, time.Local)
+	if summary.Posts == 0 {
+		output.Plain("")
+		output.Plain("No posts yet.")
+		return nil
+	}
+
+	output.Plain("")
+	output.Plain("Last %d posts, %s to %s: %.1f a week", summary.Posts,
+		summary.First.Format("2006-01-02"), summary.Last.Format("2006-01-02"), summary.PerWeek())
+	if n, err := store.CountPostHistory(summary.First); err == nil && n > 0 {
+		output.Plain("%d posted with tusk in that time", n)
+	}
+	output.Plain("Average %.1f favourites, %.1f boosts, and %.1f replies per post",
+		summary.AverageFavourites(), summary.AverageReblogs(), summary.AverageReplies())
+
+	output.Plain("")
+	output.Plain("By day of the week")
+	maxDay := 0
+	for _, n := range summary.ByWeekday {
+		maxDay = max(maxDay, n)
+	}
+	// Monday first, as most calendars outside the US show it
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7)
+		n := summary.ByWeekday[day]
+		output.Plain("  %s  %-30s %d", day.String()[:3], strings.Repeat("█", n*30/maxDay), n)
+	}
+
+	output.Plain("")
+	output.Plain("By hour of the day (busiest %02d:00)", summary.BusiestHour())
+	output.Plain("  %s", output.Sparkline(summary.ByHour[:]))
+	output.Plain("  0     6     12    18  23")
+
+	if len(summary.Tags) > 0 && statsTags > 0 {
+		output.Plain("")
+		output.Plain("Top hashtags")
+		for _, tag := range summary.Tags[:min(statsTags, len(summary.Tags))] {
+			output.Plain("  %s  %d", output.Highlight("#"+tag.Name), tag.Count)
+		}
+	}
+
+	return nil
+}
+
+// printFollowerGrowth shows how the follower count has changed over the last
+// --days, from the snapshots saved by earlier runs
+func printFollowerGrowth(store *config.Store, accountID string) {
+	snapshots, err := store.ListAccountSnapshots(accountID, time.Now().AddDate(0, 0, -statsDays))
+	if err != nil {
+		output.Warning("Failed to read follower history: %v", err)
+		return
+	}
+	if len(snapshots) < 2 {
+		output.Plain("Follower growth will show once you've run 'tusk stats' on another day")
+		return
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	counts := make(output.Sparkline, len(snapshots))
+	for i, snapshot := range snapshots {
+		counts[i] = snapshot.Followers
+	}
+
+	output.Plain("Followers %s  %+d since %s", counts, last.Followers-first.Followers,
+		first.TakenAt.Local().Format("2006-01-02"))
+}
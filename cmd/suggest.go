@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/internal/stats"
+)
+
+// suggestTagsLimit is how many tags --suggest-tags shows
+const suggestTagsLimit = 20
+
+// editorTagsLimit is how many tags the editor's comments suggest
+const editorTagsLimit = 8
+
+// suggestedTags ranks the hashtags in your posts by how often and how
+// recently you used them. Only posts in the local cache are counted, so
+// 'tusk sync' fills in ones posted elsewhere.
+func suggestedTags(store *config.Store) ([]stats.TagSuggestion, error) {
+	entries, err := store.ListPostHistory(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read post history: %w", err)
+	}
+
+	var posts []stats.TaggedPost
+	for _, entry := range entries {
+		if entry.Content == "" {
+			continue
+		}
+		posts = append(posts, stats.TaggedPost{
+			Tags: extractHashtags(stripHTML(entry.Content)),
+			At:   entry.PostedAt,
+		})
+	}
+	return stats.RankTags(posts, time.Now()), nil
+}
+
+// runSuggestTags lists the tags you use most, for --suggest-tags. With a
+// prefix, only tags starting with it are listed.
+func runSuggestTags(args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	tags, err := suggestedTags(store)
+	if err != nil {
+		return err
+	}
+
+	prefix := ""
+	if len(args) > 0 {
+		prefix = strings.ToLower(strings.TrimPrefix(args[0], "#"))
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "tag", Header: "TAG", Prefix: "#"},
+			{Key: "uses", Header: "USES"},
+			{Key: "last_used", Header: "LAST USED"},
+			{Key: "score", Header: "SCORE", Detail: true},
+		},
+		Empty:   "No hashtags in your cached posts. Run 'tusk sync' to fetch your recent ones.",
+		Tabular: true,
+	}
+
+	for _, tag := range tags {
+		if !strings.HasPrefix(strings.ToLower(tag.Name), prefix) {
+			continue
+		}
+		listing.Rows = append(listing.Rows, []any{tag.Name, tag.Count, tag.LastUsed, math.Round(tag.Score*100) / 100})
+		if len(listing.Rows) == suggestTagsLimit {
+			break
+		}
+	}
+
+	return output.Render(listing)
+}
+
+// editorTagComment suggests your usual tags while composing in the editor,
+// or returns "" if there are none
+func editorTagComment(store *config.Store) string {
+	tags, err := suggestedTags(store)
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+
+	var names []string
+	for i, tag := range tags {
+		if i == editorTagsLimit {
+			break
+		}
+		names = append(names, "#"+tag.Name)
+	}
+	return "Tags you use most: " + strings.Join(names, " ")
+}
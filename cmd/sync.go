@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"biesnecker.com/tusk/internal/config"
-	"biesnecker.com/tusk/internal/mastodon"
 	"biesnecker.com/tusk/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -29,14 +28,16 @@ func runSync(cmd *cobra.Command, args []string) error {
 	}
 	defer store.Close()
 
-	domain, _ := store.Get("domain")
-	accessToken, _ := store.Get("access_token")
+	domain, accessToken := credentials(store)
 
 	if accessToken == "" {
-		return fmt.Errorf("not authenticated. Run 'tusk auth' first")
+		return errNotAuthenticated
 	}
 
-	client := mastodon.NewClient(domain, accessToken)
+	client, err := newAPI(store, domain, accessToken)
+	if err != nil {
+		return err
+	}
 
 	output.Info("Fetching your recent posts...")
 	statuses, err := client.GetAccountStatuses(syncLimit)
@@ -50,6 +51,7 @@ func runSync(cmd *cobra.Command, args []string) error {
 	}
 
 	output.Info("Syncing %d posts to local history...", len(statuses))
+	cacheStatuses(store, statuses)
 
 	// Add statuses in reverse order (oldest first) so the newest is last in the stack
 	syncedCount := 0
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timelineLimit   int
+	timelineLocal   bool
+	tagLimit        int
+	userLimit       int
+	userWithReplies bool
+	userMediaOnly   bool
+)
+
+var timelineCmd = &cobra.Command{
+	Use:   "timeline",
+	Short: "Show an instance's public timeline",
+	Long: `Show the newest public statuses, from the whole known network or, with
+--local, only from accounts on the instance.
+
+Works without logging in when --instance is given.
+
+Examples:
+  tusk timeline
+  tusk timeline --local -n 40
+  tusk timeline --instance mastodon.social`,
+	Args: cobra.NoArgs,
+	RunE: runTimeline,
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag TAG",
+	Short: "Show recent public statuses with a hashtag",
+	Long: `Show the newest public statuses using a hashtag.
+
+Works without logging in when --instance is given.
+
+Examples:
+  tusk tag caturday
+  tusk tag "#golang" --instance mastodon.social`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTag,
+}
+
+var userCmd = &cobra.Command{
+	Use:   "user @USER|URL",
+	Short: "Show an account's recent public statuses",
+	Long: `Show the newest statuses an account has written, leaving out its boosts
+and, unless --with-replies is given, its replies to others.
+
+The account can be a handle or a link to the profile. Works without logging
+in when --instance is given, though accounts the instance hasn't seen before
+can only be found when logged in.
+
+Examples:
+  tusk user @alice@example.com
+  tusk user @alice@example.com --with-replies -n 40
+  tusk user https://example.com/@alice --media-only
+  tusk user @Gargron --instance mastodon.social`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUser,
+}
+
+func init() {
+	timelineCmd.Flags().IntVarP(&timelineLimit, "limit", "n", 20, "Number of statuses to show (max 40)")
+	timelineCmd.Flags().BoolVar(&timelineLocal, "local", false, "Only show statuses from accounts on the instance")
+
+	tagCmd.Flags().IntVarP(&tagLimit, "limit", "n", 20, "Number of statuses to show (max 40)")
+
+	userCmd.Flags().IntVarP(&userLimit, "limit", "n", 20, "Number of statuses to show (max 40)")
+	userCmd.Flags().BoolVar(&userWithReplies, "with-replies", false, "Include replies to other accounts")
+	userCmd.Flags().BoolVar(&userMediaOnly, "media-only", false, "Only show statuses with attachments")
+}
+
+func runTimeline(cmd *cobra.Command, args []string) error {
+	return listPublicStatuses(func(client *mastodon.Client) ([]*mastodon.Status, error) {
+		return client.GetPublicTimeline(timelineLocal, timelineLimit)
+	})
+}
+
+func runTag(cmd *cobra.Command, args []string) error {
+	return listPublicStatuses(func(client *mastodon.Client) ([]*mastodon.Status, error) {
+		return client.GetTagTimeline(args[0], tagLimit)
+	})
+}
+
+func runUser(cmd *cobra.Command, args []string) error {
+	return listPublicStatuses(func(client *mastodon.Client) ([]*mastodon.Status, error) {
+		account, err := client.ResolveAccount(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return client.GetAccountTimeline(account.ID, mastodon.AccountTimelineParams{
+			Limit:          userLimit,
+			ExcludeReplies: !userWithReplies,
+			OnlyMedia:      userMediaOnly,
+		})
+	})
+}
+
+// listPublicStatuses prints the statuses fetched from a public timeline
+func listPublicStatuses(fetch func(*mastodon.Client) ([]*mastodon.Status, error)) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	client, err := newReadClient(store)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := fetch(client)
+	if err != nil {
+		return err
+	}
+
+	return output.Render(statusListing(statuses, "No statuses found."))
+}
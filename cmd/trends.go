@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/spf13/cobra"
+)
+
+var trendsLimit int
+
+var trendsCmd = &cobra.Command{
+	Use:   "trends [tags|posts|links]",
+	Short: "Show what's trending on an instance",
+	Long: `Show the hashtags, posts, or links trending on an instance. Tags and links show
+how many people used them today, with a sparkline of the past week; posts show
+their boosts, favourites, and replies. Tags are shown by default.
+
+Works without logging in when --instance is given.
+
+Examples:
+  tusk trends
+  tusk trends posts
+  tusk trends links --instance mastodon.social -n 5`,
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"tags", "posts", "links"},
+	RunE:      runTrends,
+}
+
+func init() {
+	trendsCmd.Flags().IntVarP(&trendsLimit, "limit", "n", 10, "Number of trends to show (max 20, or 40 for posts)")
+}
+
+func runTrends(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	client, err := newReadClient(store)
+	if err != nil {
+		return err
+	}
+
+	kind := "tags"
+	if len(args) == 1 {
+		kind = args[0]
+	}
+
+	var listing *output.Listing
+	switch kind {
+	case "posts":
+		listing, err = trendingPostsListing(client)
+	case "links":
+		listing, err = trendingLinksListing(client)
+	default:
+		listing, err = trendingTagsListing(client)
+	}
+	if err != nil {
+		return err
+	}
+
+	return output.Render(listing)
+}
+
+func trendingTagsListing(client *mastodon.Client) (*output.Listing, error) {
+	tags, err := client.GetTrendingTags(trendsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "name", Header: "TAG", Prefix: "#"},
+			{Key: "uses_today", Header: "POSTS TODAY"},
+			{Key: "accounts_today", Header: "PEOPLE TODAY"},
+			{Key: "history", Header: "WEEK"},
+			{Key: "url", Header: "URL", Detail: true},
+		},
+		Empty: "Nothing is trending.",
+	}
+
+	for _, tag := range tags {
+		uses, accounts := tag.History.Today()
+		listing.Rows = append(listing.Rows, []any{
+			tag.Name, uses, accounts, output.Sparkline(tag.History.Uses()), output.Href(tag.URL),
+		})
+	}
+
+	listing.Plain = func(i int) {
+		tag := tags[i]
+		uses, accounts := tag.History.Today()
+		output.Plain("#%-24s %s  %d posts by %d people today", tag.Name,
+			output.Sparkline(tag.History.Uses()), uses, accounts)
+	}
+
+	return listing, nil
+}
+
+func trendingPostsListing(client *mastodon.Client) (*output.Listing, error) {
+	statuses, err := client.GetTrendingStatuses(trendsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID", Detail: true},
+			{Key: "acct", Header: "AUTHOR", Prefix: "@"},
+			{Key: "reblogs", Header: "BOOSTS"},
+			{Key: "favourites", Header: "FAVS"},
+			{Key: "replies", Header: "REPLIES"},
+			{Key: "content", Header: "CONTENT", Width: 60},
+			{Key: "url", Header: "URL"},
+		},
+		Empty: "No posts are trending.",
+	}
+
+	for _, status := range statuses {
+		listing.Rows = append(listing.Rows, []any{
+			status.ID, statusAuthor(status), status.ReblogsCount, status.FavouritesCount,
+			status.RepliesCount, stripHTML(status.Content), output.Href(status.URL),
+		})
+	}
+
+	listing.Plain = func(i int) {
+		status := statuses[i]
+		output.Plain("@%s  %d boosts, %d favs, %d replies", statusAuthor(status),
+			status.ReblogsCount, status.FavouritesCount, status.RepliesCount)
+		output.Plain("    %s", output.Fold(status.SpoilerText, output.Highlight(truncate(stripHTML(status.Content), 70)), false))
+		output.URL("    " + status.URL)
+	}
+
+	return listing, nil
+}
+
+func trendingLinksListing(client *mastodon.Client) (*output.Listing, error) {
+	links, err := client.GetTrendingLinks(trendsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := &output.Listing{
+		Columns: []output.Column{
+			{Key: "title", Header: "TITLE", Width: 50},
+			{Key: "provider", Header: "SOURCE"},
+			{Key: "uses_today", Header: "SHARES TODAY"},
+			{Key: "accounts_today", Header: "PEOPLE TODAY"},
+			{Key: "history", Header: "WEEK"},
+			{Key: "url", Header: "URL"},
+		},
+		Empty: "No links are trending.",
+	}
+
+	for _, link := range links {
+		uses, accounts := link.History.Today()
+		listing.Rows = append(listing.Rows, []any{
+			link.Title, link.ProviderName, uses, accounts, output.Sparkline(link.History.Uses()), output.Href(link.URL),
+		})
+	}
+
+	listing.Plain = func(i int) {
+		link := links[i]
+		uses, accounts := link.History.Today()
+		source := ""
+		if link.ProviderName != "" {
+			source = " (" + link.ProviderName + ")"
+		}
+		output.Plain("%s%s", truncate(link.Title, 70), source)
+		output.Plain("    %s  %d shares by %d people today", output.Sparkline(link.History.Uses()), uses, accounts)
+		output.URL("    " + link.URL)
+	}
+
+	return listing, nil
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/pkg/mastodon"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Shared pieces of the status pickers: loading in the background and
+// scrolling lists that don't fit in the terminal.
+
+// tuiChromeLines is how many lines the header, help, and footer of a picker
+// take up, leaving the rest of the window for the list
+const tuiChromeLines = 7
+
+// Background prefetching in pickers: how many fetches run at once, and how
+// many visible posts get their threads fetched ahead of time
+const (
+	prefetchWorkers     = 3
+	prefetchThreadCount = 5
+)
+
+// statusItem is one of your statuses in a picker
+type statusItem struct {
+	id        string
+	content   string
+	url       string
+	selected  bool
+	replyLine string
+	status    *mastodon.Status
+}
+
+// lines is how many lines the item takes up in a list
+func (s statusItem) lines() int {
+	if s.replyLine != "" {
+		return 2
+	}
+	return 1
+}
+
+// statusesLoadedMsg delivers the result of loadStatuses
+type statusesLoadedMsg struct {
+	items []statusItem
+	err   error
+}
+
+// loadStatuses fetches your recent statuses without blocking the TUI
+func loadStatuses(store *config.Store, client mastodonAPI) tea.Cmd {
+	return func() tea.Msg {
+		statuses, err := client.GetAccountStatuses(50)
+		if err != nil {
+			return statusesLoadedMsg{err: err}
+		}
+		cacheStatuses(store, statuses)
+
+		// Parents that aren't cached are fetched, a few at a time
+		items := make([]statusItem, len(statuses))
+		workers := make(chan struct{}, prefetchWorkers)
+		var wg sync.WaitGroup
+		for i, status := range statuses {
+			items[i] = statusItem{
+				id:      status.ID,
+				content: stripHTML(status.Content),
+				url:     status.URL,
+				status:  status,
+			}
+			if status.InReplyTo == "" {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				workers <- struct{}{}
+				defer func() { <-workers }()
+
+				items[i].replyLine = describeReply(store, client, status)
+			}()
+		}
+		wg.Wait()
+		return statusesLoadedMsg{items: items}
+	}
+}
+
+func newSpinner() spinner.Model {
+	return spinner.New(
+		spinner.WithSpinner(spinner.Dot),
+		spinner.WithStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("12"))),
+	)
+}
+
+// scrollOffset returns the first item to show so that the cursor stays on
+// screen, moving the previous offset as little as possible. lines gives the
+// height of each item; available is the number of lines for the list, or 0
+// if the window size isn't known yet.
+func scrollOffset(offset, cursor, available int, lines func(int) int) int {
+	if available <= 0 || cursor < offset {
+		return min(offset, cursor)
+	}
+
+	used := 0
+	for i := cursor; i >= offset; i-- {
+		used += lines(i)
+		if used > available {
+			return min(i+1, cursor)
+		}
+	}
+	return offset
+}
+
+// visibleItems returns the end (exclusive) of the items that fit on screen
+// starting at offset
+func visibleItems(offset, count, available int, lines func(int) int) int {
+	if available <= 0 {
+		return count
+	}
+
+	used := 0
+	for i := offset; i < count; i++ {
+		used += lines(i)
+		if used > available {
+			return max(i, offset+1)
+		}
+	}
+	return count
+}
+
+// The preview pane shows at most this many lines of a status's content, and
+// wraps to this width when the window size isn't known yet
+const (
+	previewContentLines = 10
+	previewDefaultWidth = 80
+)
+
+// renderPreview renders the detail pane for the highlighted status: the full
+// content wrapped to the window, when and how it was posted, media alt text,
+// and engagement counts
+func renderPreview(status *mastodon.Status, width int) string {
+	if status == nil {
+		return ""
+	}
+	if width <= 0 {
+		width = previewDefaultWidth
+	}
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	wrap := lipgloss.NewStyle().Width(width)
+
+	var b strings.Builder
+	b.WriteString(dimStyle.Render(strings.Repeat("─", width)))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render(fmt.Sprintf("%s · %s · %d replies · %d boosts · %d favourites",
+		status.CreatedAt.Local().Format("2006-01-02 15:04"), status.Visibility,
+		status.RepliesCount, status.ReblogsCount, status.FavouritesCount)))
+	b.WriteString("\n")
+
+	if status.SpoilerText != "" {
+		b.WriteString(wrap.Render("CW: " + status.SpoilerText))
+		b.WriteString("\n")
+	}
+
+	content := strings.Split(wrap.Render(htmlToText(status.Content)), "\n")
+	if len(content) > previewContentLines {
+		content = append(content[:previewContentLines-1], dimStyle.Render("…"))
+	}
+	b.WriteString(strings.Join(content, "\n"))
+	b.WriteString("\n")
+
+	for _, media := range status.MediaAttachments {
+		alt := media.Description
+		if alt == "" {
+			alt = "(no alt text)"
+		}
+		b.WriteString(wrap.Render(fmt.Sprintf("[%s] %s", media.Type, alt)))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// previewLines is how many lines the preview pane takes from the list, or 0
+// when it's hidden
+func previewLines(shown bool, status *mastodon.Status, width int) int {
+	if !shown || status == nil {
+		return 0
+	}
+	return strings.Count(renderPreview(status, width), "\n") + 1
+}
+
+// statusPicker is the state the TUIs that list statuses share: the list, the
+// cursor and scroll position in it, the window size, and whether the preview
+// pane is shown
+type statusPicker struct {
+	statuses []statusItem
+	cursor   int
+	offset   int
+	height   int
+	width    int
+	preview  bool
+}
+
+// highlighted is the status under the cursor, or nil if the list is empty
+func (p statusPicker) highlighted() *mastodon.Status {
+	if len(p.statuses) == 0 {
+		return nil
+	}
+	return p.statuses[p.cursor].status
+}
+
+// listHeight is how many lines the list can use, after the chrome and the
+// preview pane
+func (p statusPicker) listHeight() int {
+	return p.height - tuiChromeLines - previewLines(p.preview, p.highlighted(), p.width)
+}
+
+// itemLines is how many lines the item at i takes up in the list
+func (p statusPicker) itemLines(i int) int {
+	return p.statuses[i].lines()
+}
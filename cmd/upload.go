@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"biesnecker.com/tusk/internal/image"
+	"biesnecker.com/tusk/internal/output"
+)
+
+// uploadWorkers bounds how many images are processed and uploaded at once
+const uploadWorkers = 4
+
+// uploadImages processes and uploads images concurrently, returning their
+// media IDs in the same order. Every image is tried even when one fails, so
+// all the problems are reported together.
+func uploadImages(client mastodonAPI, images []postImage) ([]string, error) {
+	if len(images) == 0 {
+		return nil, nil
+	}
+
+	// Servers differ in how large an image they accept, so check first rather
+	// than failing partway through an upload
+	sizeLimit := imageSizeLimit(client)
+
+	if len(images) == 1 {
+		output.Info("Uploading image...")
+	} else {
+		output.Info("Uploading %d images...", len(images))
+	}
+
+	mediaIDs := make([]string, len(images))
+	errs := make([]error, len(images))
+	workers := make(chan struct{}, uploadWorkers)
+	var wg sync.WaitGroup
+
+	for i, img := range images {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			mediaIDs[i], errs[i] = uploadImage(client, img.Path, img.Alt, sizeLimit)
+			if errs[i] != nil && len(images) > 1 {
+				errs[i] = fmt.Errorf("%s: %w", filepath.Base(img.Path), errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if len(images) == 1 {
+		output.Info("Image uploaded successfully")
+	} else {
+		output.Info("%d images uploaded successfully", len(images))
+	}
+	return mediaIDs, nil
+}
+
+// imageSizeLimit is the largest image the instance accepts, in bytes, or 0
+// if it doesn't say
+func imageSizeLimit(client mastodonAPI) int64 {
+	instance, err := client.GetInstance()
+	if err != nil {
+		return 0
+	}
+	return instance.ImageSizeLimit()
+}
+
+// checkImages processes each image as uploading it would, without uploading
+// anything, so that a post queued for the background worker is refused now
+// rather than failing later over an image that can't be used
+func checkImages(client mastodonAPI, images []postImage) error {
+	if len(images) == 0 {
+		return nil
+	}
+
+	sizeLimit := imageSizeLimit(client)
+	var errs []error
+	for _, img := range images {
+		if _, err := processImage(img.Path, sizeLimit); err != nil {
+			if len(images) > 1 {
+				err = fmt.Errorf("%s: %w", filepath.Base(img.Path), err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// processImage converts HEIC and strips EXIF from an image, refusing it if
+// it's over sizeLimit bytes afterwards, unless that's 0
+func processImage(path string, sizeLimit int64) (*image.ProcessedImage, error) {
+	processedImage, err := image.ProcessImage(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process image: %w", err)
+	}
+
+	if size := int64(len(processedImage.Data)); sizeLimit > 0 && size > sizeLimit {
+		return nil, fmt.Errorf("image is %s after processing, but the instance accepts at most %s", formatBytes(size), formatBytes(sizeLimit))
+	}
+	return processedImage, nil
+}
+
+// uploadImage processes an image as processImage does and uploads it,
+// returning the media ID
+func uploadImage(client mastodonAPI, path, description string, sizeLimit int64) (string, error) {
+	processedImage, err := processImage(path, sizeLimit)
+	if err != nil {
+		return "", err
+	}
+
+	media, err := client.UploadMedia(
+		processedImage.Data,
+		processedImage.Filename,
+		processedImage.MimeType,
+		description,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload image: %w", err)
+	}
+
+	return media.ID, nil
+}
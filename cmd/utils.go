@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +10,9 @@ import (
 	"regexp"
 	"strings"
 
+	"biesnecker.com/tusk/internal/diff"
+	"biesnecker.com/tusk/internal/oauth"
+	"biesnecker.com/tusk/internal/output"
 	"github.com/mattn/go-isatty"
 )
 
@@ -33,6 +37,39 @@ func stripHTML(html string) string {
 	return text
 }
 
+// htmlToText converts status HTML to editable text, keeping paragraph and
+// line breaks that stripHTML collapses
+func htmlToText(html string) string {
+	text := regexp.MustCompile(`(?i)<br\s*/?>`).ReplaceAllString(html, "\n")
+	text = regexp.MustCompile(`(?i)</p>\s*<p[^>]*>`).ReplaceAllString(text, "\n\n")
+	text = regexp.MustCompile(`<[^>]*>`).ReplaceAllString(text, "")
+
+	text = strings.ReplaceAll(text, "&lt;", "<")
+	text = strings.ReplaceAll(text, "&gt;", ">")
+	text = strings.ReplaceAll(text, "&amp;", "&")
+	text = strings.ReplaceAll(text, "&quot;", "\"")
+	text = strings.ReplaceAll(text, "&#39;", "'")
+	text = strings.ReplaceAll(text, "&nbsp;", " ")
+
+	return strings.TrimSpace(text)
+}
+
+// printDiff shows a colorized unified diff between two versions of a text
+func printDiff(oldLabel, newLabel, oldText, newText string) {
+	output.Plain("--- %s", oldLabel)
+	output.Plain("+++ %s", newLabel)
+	for _, line := range diff.Lines(oldText, newText) {
+		switch line.Op {
+		case diff.Delete:
+			output.Removed("-%s", line.Text)
+		case diff.Insert:
+			output.Added("+%s", line.Text)
+		default:
+			output.Plain(" %s", line.Text)
+		}
+	}
+}
+
 // truncate truncates a string to maxLen, adding "..." if truncated
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -41,6 +78,25 @@ func truncate(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+// formatBytes shows a size in megabytes, or kilobytes below one megabyte
+func formatBytes(n int64) string {
+	if n < 1000*1000 {
+		return fmt.Sprintf("%d KB", (n+999)/1000)
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1000*1000))
+}
+
+// printChange prints a field's old and new values, or notes that it is unchanged
+func printChange(label, oldValue, newValue string) {
+	if oldValue == newValue {
+		output.Plain("%s: unchanged", label)
+		return
+	}
+	output.Plain("%s:", label)
+	output.Plain("  - %s", oldValue)
+	output.Plain("  + %s", newValue)
+}
+
 // getStatusText gets status text from args, editor, or stdin
 func getStatusText(args []string, useEditor bool) (string, error) {
 	if useEditor {
@@ -58,13 +114,36 @@ func getStatusText(args []string, useEditor bool) (string, error) {
 	return strings.Join(args, " "), nil
 }
 
+// errEditorAborted is returned when the editor is closed with nothing
+// written. As with git commit, that cancels rather than fails, so it's
+// reported as a cancellation rather than an error.
+var errEditorAborted = &codedError{code: exitCancelled, err: errors.New("the message was empty"), reported: true}
+
+// editorHelp is shown at the end of every editor session
+var editorHelp = []string{
+	"Lines starting with '#' and a space are left out. #hashtags are kept.",
+	"Leave the message empty to cancel.",
+}
+
 // getTextFromEditor opens the user's $EDITOR to compose text
 func getTextFromEditor() (string, error) {
-	return getTextFromEditorWithInitial("")
+	return getTextFromEditorWithComments("", nil)
 }
 
 // getTextFromEditorWithInitial opens the user's $EDITOR with initial content
 func getTextFromEditorWithInitial(initialContent string) (string, error) {
+	return getTextFromEditorWithComments(initialContent, nil)
+}
+
+// getTextFromEditorWithComments opens the user's $EDITOR with initial
+// content, followed by comment lines giving context for what's being
+// written. Comment lines are stripped from the result, and if nothing is
+// left it returns errEditorAborted.
+func getTextFromEditorWithComments(initialContent string, comments []string) (string, error) {
+	if err := requireInteractive("the editor"); err != nil {
+		return "", err
+	}
+
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = "vim"
@@ -76,13 +155,16 @@ func getTextFromEditorWithInitial(initialContent string) (string, error) {
 	}
 	tmpFilePath := tmpFile.Name()
 
-	// Write initial content if provided
-	if initialContent != "" {
-		if err := os.WriteFile(tmpFilePath, []byte(initialContent), 0600); err != nil {
-			tmpFile.Close()
-			os.Remove(tmpFilePath)
-			return "", fmt.Errorf("failed to write initial content: %w", err)
-		}
+	var template strings.Builder
+	template.WriteString(initialContent)
+	template.WriteString("\n\n")
+	for _, line := range append(comments, editorHelp...) {
+		template.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+	if err := os.WriteFile(tmpFilePath, []byte(template.String()), 0600); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFilePath)
+		return "", fmt.Errorf("failed to write initial content: %w", err)
 	}
 
 	tmpFile.Close()
@@ -102,7 +184,26 @@ func getTextFromEditorWithInitial(initialContent string) (string, error) {
 		return "", fmt.Errorf("failed to read temp file: %w", err)
 	}
 
-	return strings.TrimSpace(string(content)), nil
+	text := stripEditorComments(string(content))
+	if text == "" {
+		output.Info("Cancelled: %s.", errEditorAborted)
+		return "", errEditorAborted
+	}
+	return text, nil
+}
+
+// stripEditorComments removes comment lines from editor text: "#" alone or
+// followed by a space. A hashtag at the start of a line isn't a comment.
+func stripEditorComments(text string) string {
+	var kept []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "#" || strings.HasPrefix(trimmed, "# ") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
 }
 
 // getTextFromStdin reads text from stdin
@@ -124,3 +225,43 @@ func getTextFromStdin() (string, error) {
 
 	return strings.TrimSpace(builder.String()), nil
 }
+
+var mentionRegex = regexp.MustCompile(`(?:^|[^\w@/])@(\w+(?:@[\w.-]+\w)?)`)
+
+// extractMentions returns the accounts mentioned in text, without the leading @
+func extractMentions(text string) []string {
+	var mentions []string
+	for _, match := range mentionRegex.FindAllStringSubmatch(text, -1) {
+		mentions = append(mentions, match[1])
+	}
+	return mentions
+}
+
+var hashtagRegex = regexp.MustCompile(`(?:^|[^\w&/#])#(\w+)`)
+
+// extractHashtags returns the hashtags used in text, lowercased and without
+// the leading #, in order of first use
+func extractHashtags(text string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, match := range hashtagRegex.FindAllStringSubmatch(text, -1) {
+		tag := strings.ToLower(match[1])
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// openInBrowser opens a status URL in the default browser, reporting rather
+// than failing when that isn't possible
+func openInBrowser(url string) {
+	if url == "" {
+		output.Error("Status has no URL to open")
+		return
+	}
+	if err := oauth.OpenBrowser(url); err != nil {
+		output.Error("Failed to open browser: %v", err)
+	}
+}
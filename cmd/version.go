@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	buildinfo "runtime/debug"
+	"time"
+
+	"biesnecker.com/tusk/internal/output"
+	"biesnecker.com/tusk/internal/update"
+	"github.com/spf13/cobra"
+)
+
+// version is set at build time with
+// -ldflags "-X biesnecker.com/tusk/cmd.version=v1.2.3"
+var version = ""
+
+// updateRepo is where tusk's releases are published
+const updateRepo = "biesnecker/tusk"
+
+// updateTimeout bounds each request to GitHub
+const updateTimeout = 30 * time.Second
+
+var (
+	versionCheck    bool
+	selfUpdateDry   bool
+	selfUpdateForce bool
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show tusk's version",
+	Long: `Show the version of tusk you're running. With --check, also look up the
+latest release on GitHub. tusk never checks for updates on its own.`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update tusk to the latest release",
+	Long: `Download the latest release of tusk for this platform from GitHub, check it
+against the release's checksums, and replace the running binary with it.
+
+Examples:
+  tusk self-update --dry-run
+  tusk self-update`,
+	Args: cobra.NoArgs,
+	RunE: runSelfUpdate,
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "Check GitHub for a newer release")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateDry, "dry-run", false, "Show what would be downloaded without replacing anything")
+	selfUpdateCmd.Flags().BoolVarP(&selfUpdateForce, "force", "f", false, "Don't ask for confirmation, and update development builds too")
+}
+
+// currentVersion is the version tusk was built as: set with -ldflags, or
+// the module version Go stamps into the build, which is a pseudo-version
+// for untagged commits. It's "dev" when neither is known.
+func currentVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := buildinfo.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+func newUpdateChecker() *update.Checker {
+	return &update.Checker{
+		APIURL:     "https://api.github.com",
+		Repo:       updateRepo,
+		HTTPClient: &http.Client{Timeout: updateTimeout},
+	}
+}
+
+// latestRelease looks up the latest release and parses its version and the
+// running one. The running version is nil for development builds, which
+// can't be compared.
+func latestRelease(checker *update.Checker) (release *update.Release, latest update.Version, current *update.Version, err error) {
+	release, err = checker.Latest()
+	if err != nil {
+		return nil, update.Version{}, nil, err
+	}
+	latest, err = update.ParseVersion(release.TagName)
+	if err != nil {
+		return nil, update.Version{}, nil, fmt.Errorf("latest release has an unexpected tag: %w", err)
+	}
+	if v, err := update.ParseVersion(currentVersion()); err == nil {
+		current = &v
+	}
+	return release, latest, current, nil
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	output.Plain("tusk %s (%s/%s)", currentVersion(), runtime.GOOS, runtime.GOARCH)
+	if !versionCheck {
+		return nil
+	}
+
+	release, latest, current, err := latestRelease(newUpdateChecker())
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case current == nil:
+		output.Info("The latest release is %s. This is a development build, so it can't be compared.", latest)
+	case latest.Compare(*current) > 0:
+		output.Info("A newer version is available: %s. Run 'tusk self-update' to install it.", latest)
+		output.URL(release.HTMLURL)
+	default:
+		output.Success("You're up to date.")
+	}
+	return nil
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	checker := newUpdateChecker()
+
+	output.Info("Checking for a newer release...")
+	release, latest, current, err := latestRelease(checker)
+	if err != nil {
+		return err
+	}
+
+	if current == nil && !selfUpdateForce {
+		return fmt.Errorf("this is a development build (%s); use --force to replace it with %s", currentVersion(), latest)
+	}
+	if current != nil && latest.Compare(*current) <= 0 {
+		output.Success("Already up to date (%s).", current)
+		return nil
+	}
+
+	name := update.AssetName(runtime.GOOS, runtime.GOARCH)
+	asset := release.Asset(name)
+	if asset == nil {
+		return fmt.Errorf("release %s has no binary for %s/%s", latest, runtime.GOOS, runtime.GOARCH)
+	}
+	checksums := release.Asset(update.ChecksumsName)
+	if checksums == nil {
+		return fmt.Errorf("release %s has no %s, so the download can't be verified", latest, update.ChecksumsName)
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the tusk binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	if selfUpdateDry {
+		output.Info("Dry run mode - would update:")
+		output.Plain("From: %s", currentVersion())
+		output.Plain("To: %s", latest)
+		output.Plain("Download: %s", asset.URL)
+		output.Plain("Replace: %s", path)
+		return nil
+	}
+
+	if !selfUpdateForce {
+		if !assumeYes && !isTerminal() {
+			return cancelledf("can't ask for confirmation without a terminal; use --force to update anyway")
+		}
+		ok, err := confirm("--force", "Update %s from %s to %s?", path, currentVersion(), latest)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Info("Update cancelled.")
+			return errCancelled
+		}
+	}
+
+	output.Info("Downloading %s...", asset.Name)
+	data, err := checker.Download(asset)
+	if err != nil {
+		return err
+	}
+	sums, err := checker.Download(checksums)
+	if err != nil {
+		return err
+	}
+	if err := update.VerifyChecksum(data, sums, name); err != nil {
+		return err
+	}
+
+	if err := update.Replace(path, data); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	output.Success("Updated to %s", latest)
+	return nil
+}
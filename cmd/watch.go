@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// watchInterval is how often --watch looks for new files
+const watchInterval = 2 * time.Second
+
+// watchingPosts is set while --watch posts files, when there's no one to
+// answer prompts
+var watchingPosts bool
+
+// watchFile is what --watch last saw of a file. A file is posted once it
+// stops changing, so one still being written isn't sent half done.
+type watchFile struct {
+	size    int64
+	modTime time.Time
+}
+
+// watchPosts posts each post file that appears in dir until interrupted.
+// Files are moved to dir/posted once sent, or dir/failed if they couldn't be,
+// so nothing is posted twice, even across restarts.
+func watchPosts(cmd *cobra.Command, dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return invalidf("%s isn't a directory", dir)
+	}
+
+	postedDir := filepath.Join(dir, "posted")
+	failedDir := filepath.Join(dir, "failed")
+	if !dryRun {
+		for _, d := range []string{postedDir, failedDir} {
+			if err := os.MkdirAll(d, 0700); err != nil {
+				return fmt.Errorf("failed to create %s: %w", d, err)
+			}
+		}
+	}
+
+	watchingPosts = true
+	defer func() { watchingPosts = false }()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	output.Info("Watching %s for posts. Press Ctrl+C to stop.", dir)
+
+	seen := make(map[string]watchFile)
+	// With --dry-run files stay put, so remember which were shown
+	handled := make(map[string]watchFile)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, path := range readyPostFiles(dir, seen) {
+			if handled[path] == seen[path] {
+				continue
+			}
+
+			output.Info("Posting %s", filepath.Base(path))
+			postFile = path
+			err := sendPost(cmd, nil)
+			postFile = ""
+
+			if dryRun {
+				handled[path] = seen[path]
+				continue
+			}
+			// Leave a post cut short by Ctrl+C to be tried again next time
+			if err != nil && interrupted() {
+				output.Info("Stopped watching %s; %s wasn't posted", dir, filepath.Base(path))
+				return nil
+			}
+
+			target := postedDir
+			if err != nil {
+				output.Error("%s: %v", filepath.Base(path), err)
+				target = failedDir
+			}
+			if err := moveAside(path, target); err != nil {
+				// Stop rather than post the same file again
+				return fmt.Errorf("failed to move %s out of the way: %w", path, err)
+			}
+			delete(seen, path)
+		}
+
+		select {
+		case <-stop:
+			output.Info("Stopped watching %s", dir)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readyPostFiles returns the post files in dir, in name order, that haven't
+// changed since the last look, and records what it sees in seen
+func readyPostFiles(dir string, seen map[string]watchFile) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		output.Error("Failed to read %s: %v", dir, err)
+		return nil
+	}
+
+	var ready []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !isPostFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		now := watchFile{size: info.Size(), modTime: info.ModTime()}
+		if last, ok := seen[path]; ok && last == now {
+			ready = append(ready, path)
+		}
+		seen[path] = now
+	}
+	return ready
+}
+
+// isPostFile reports whether --watch should post a file. Images for posts
+// can sit beside them, and editors' temporary files are left alone.
+func isPostFile(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".md" || ext == ".txt"
+}
+
+// moveAside moves a file into dir, adding a timestamp to its name if a file
+// there already has it
+func moveAside(path, dir string) error {
+	target := filepath.Join(dir, filepath.Base(path))
+	if _, err := os.Stat(target); err == nil {
+		ext := filepath.Ext(target)
+		target = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(target, ext), time.Now().Format("20060102-150405"), ext)
+	}
+	return os.Rename(path, target)
+}
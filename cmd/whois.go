@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+
+	"biesnecker.com/tusk/internal/config"
+	"biesnecker.com/tusk/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var whoisLimit int
+
+var whoisCmd = &cobra.Command{
+	Use:   "whois @USER|URL",
+	Short: "Show an account's profile and recent posts",
+	Long: `Show an account's profile and its most recent public posts.
+
+The account can be a handle or a link to the profile. Works without logging
+in when --instance is given, though accounts the instance hasn't seen before
+can only be found when logged in.
+
+Examples:
+  tusk whois @alice@example.com
+  tusk whois https://example.com/@alice
+  tusk whois @Gargron --instance mastodon.social`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWhois,
+}
+
+func init() {
+	whoisCmd.Flags().IntVarP(&whoisLimit, "limit", "n", 5, "Number of recent posts to show (0 for none)")
+}
+
+func runWhois(cmd *cobra.Command, args []string) error {
+	store, err := config.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open config store: %w", err)
+	}
+	defer store.Close()
+
+	client, err := newReadClient(store)
+	if err != nil {
+		return err
+	}
+
+	account, err := client.ResolveAccount(args[0])
+	if err != nil {
+		return err
+	}
+
+	name := account.DisplayName
+	if name == "" {
+		name = account.Username
+	}
+	if account.Bot {
+		name += " (bot)"
+	}
+
+	output.Info("%s  @%s", name, account.Acct)
+	output.Result(account.ID, account.URL)
+	if !account.CreatedAt.IsZero() {
+		output.Plain("Joined: %s", account.CreatedAt.Local().Format("2006-01-02"))
+	}
+	output.Plain("Posts: %d  Following: %d  Followers: %d", account.StatusesCount, account.FollowingCount, account.FollowersCount)
+	if note := stripHTML(account.Note); note != "" {
+		output.Plain("\n%s", note)
+	}
+
+	if whoisLimit <= 0 {
+		return nil
+	}
+
+	statuses, err := client.GetAccountStatusesPage(account.ID, "", whoisLimit)
+	if err != nil {
+		return err
+	}
+
+	if len(statuses) > 0 {
+		output.Plain("")
+		for _, status := range statuses {
+			printStatusListing(status)
+		}
+	}
+
+	return nil
+}
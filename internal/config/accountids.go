@@ -0,0 +1,34 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// accountIDPrefix starts the config keys caching the account each login
+// belongs to. The rest of the key is a hash of the instance and access
+// token, so the token itself isn't stored again.
+const accountIDPrefix = "account_id:"
+
+func accountIDKey(domain, accessToken string) string {
+	sum := sha256.Sum256([]byte(domain + "\n" + accessToken))
+	return accountIDPrefix + hex.EncodeToString(sum[:])
+}
+
+// AccountID returns the cached ID of the account an access token for the
+// instance at domain logs in as, or "" if it isn't known
+func (s *Store) AccountID(domain, accessToken string) string {
+	id, _ := s.Get(accountIDKey(domain, accessToken))
+	return id
+}
+
+// SetAccountID caches the ID of the account an access token logs in as
+func (s *Store) SetAccountID(domain, accessToken, accountID string) {
+	s.Set(accountIDKey(domain, accessToken), accountID)
+}
+
+// ForgetAccountID drops the cached account ID for an access token, once the
+// instance stops accepting it
+func (s *Store) ForgetAccountID(domain, accessToken string) {
+	s.Delete(accountIDKey(domain, accessToken))
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAccountIDCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if id := store.AccountID("https://one.example", "token"); id != "" {
+		t.Errorf("Expected no cached ID, got %q", id)
+	}
+
+	store.SetAccountID("https://one.example", "token", "42")
+	if id := store.AccountID("https://one.example", "token"); id != "42" {
+		t.Errorf("Expected the cached ID, got %q", id)
+	}
+
+	// Another token or instance is another login
+	if id := store.AccountID("https://one.example", "other"); id != "" {
+		t.Errorf("Expected nothing for another token, got %q", id)
+	}
+	if id := store.AccountID("https://two.example", "token"); id != "" {
+		t.Errorf("Expected nothing for another instance, got %q", id)
+	}
+
+	// The token isn't stored in the key
+	if key := accountIDKey("https://one.example", "token"); strings.Contains(key, "token") {
+		t.Errorf("Expected the token to be hashed, got %q", key)
+	}
+
+	store.ForgetAccountID("https://one.example", "token")
+	if id := store.AccountID("https://one.example", "token"); id != "" {
+		t.Errorf("Expected the ID to be forgotten, got %q", id)
+	}
+}
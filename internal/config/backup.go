@@ -0,0 +1,179 @@
+package config
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Backup writes a copy of the database to path, which must not exist yet.
+// The copy is consistent even while other processes write. Your login and
+// settings go in its config table, so the one file is all a restore needs,
+// and only you can read it.
+func (s *Store) Backup(path string) error {
+	if err := createPrivate(path); err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec("VACUUM INTO ?", path); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	settings, err := s.settings.read()
+	if err != nil {
+		return err
+	}
+	if err := saveSettingsTo(path, settings); err != nil {
+		return fmt.Errorf("failed to back up settings: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces the database, and your login and settings, with the backup
+// at path. The database it replaces is kept beside it with the settings it
+// went with, and its path is returned ("" if there wasn't one). Backups from
+// older versions of tusk are brought up to date; ones from newer versions are
+// refused.
+func Restore(path string) (string, error) {
+	if err := checkBackup(path); err != nil {
+		return "", err
+	}
+
+	dbPath, err := DatabasePath()
+	if err != nil {
+		return "", err
+	}
+
+	// Copy the backup next to the database first, so a failure can't leave
+	// tusk without one
+	incoming := dbPath + ".restoring"
+	os.Remove(incoming)
+	if err := copyDatabase(path, incoming); err != nil {
+		os.Remove(incoming)
+		return "", err
+	}
+
+	settingsPath, err := SettingsPath()
+	if err != nil {
+		os.Remove(incoming)
+		return "", err
+	}
+
+	// The database moved aside takes the current settings with it, so that
+	// restoring it in turn brings them back
+	if _, err := os.Stat(dbPath); err == nil {
+		settings, err := settingsFile{path: settingsPath}.read()
+		if err == nil {
+			err = saveSettingsTo(dbPath, settings)
+		}
+		if err != nil {
+			os.Remove(incoming)
+			return "", fmt.Errorf("failed to keep the current settings: %w", err)
+		}
+	}
+
+	previousPath := fmt.Sprintf("%s.before-restore-%s", dbPath, time.Now().Format("20060102-150405"))
+	if err := os.Rename(dbPath, previousPath); os.IsNotExist(err) {
+		previousPath = ""
+	} else if err != nil {
+		os.Remove(incoming)
+		return "", fmt.Errorf("failed to move the current database aside: %w", err)
+	}
+
+	// The journal belongs to the database just moved aside
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if _, err := os.Stat(dbPath + suffix); err == nil {
+			if previousPath != "" {
+				os.Rename(dbPath+suffix, previousPath+suffix)
+			} else {
+				os.Remove(dbPath + suffix)
+			}
+		}
+	}
+
+	if err := os.Rename(incoming, dbPath); err != nil {
+		return "", fmt.Errorf("failed to put the restored database in place: %w", err)
+	}
+
+	// Opening the store applies any migrations the backup is missing, and
+	// without a settings file it takes the backup's settings
+	if err := os.Remove(settingsPath); err != nil && !os.IsNotExist(err) {
+		return previousPath, fmt.Errorf("failed to replace the settings: %w", err)
+	}
+	store, err := NewStore()
+	if err != nil {
+		return previousPath, fmt.Errorf("failed to open the restored database: %w", err)
+	}
+	store.Close()
+
+	return previousPath, nil
+}
+
+// checkBackup makes sure path is an intact tusk database this version can
+// use
+func checkBackup(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", databaseURI(path, "mode=ro"))
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow("PRAGMA quick_check").Scan(&result); err != nil {
+		return fmt.Errorf("%s isn't a tusk backup: %w", path, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("backup %s is damaged: %s", path, result)
+	}
+
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'config'").Scan(&tables); err != nil || tables == 0 {
+		return fmt.Errorf("%s isn't a tusk backup", path)
+	}
+
+	// Backups from before schema versioning have no schema_migrations
+	var version int
+	err = db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err == nil && version > migrations[len(migrations)-1].Version {
+		return fmt.Errorf("backup %s is from a newer version of tusk; upgrade tusk to restore it", path)
+	}
+	return nil
+}
+
+// copyDatabase writes a compacted copy of the database at from to to
+func copyDatabase(from, to string) error {
+	db, err := sql.Open("sqlite", databaseURI(from, "mode=ro"))
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer db.Close()
+
+	if err := createPrivate(to); err != nil {
+		return err
+	}
+	if _, err := db.Exec("VACUUM INTO ?", to); err != nil {
+		return fmt.Errorf("failed to copy backup: %w", err)
+	}
+	return nil
+}
+
+// createPrivate creates an empty file at path that only you can read, for
+// VACUUM INTO to fill. SQLite would create the file with the umask's
+// permissions, leaving the copy readable by others until it was tightened.
+func createPrivate(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
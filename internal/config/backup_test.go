@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv(DatabaseEnv, filepath.Join(tmpDir, "tusk.db"))
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	store.Set("domain", "example.com")
+	store.AddPostToHistory("111")
+
+	backupPath := filepath.Join(tmpDir, "backup.db")
+	if err := store.Backup(backupPath); err != nil {
+		t.Fatalf("Failed to back up: %v", err)
+	}
+	if info, err := os.Stat(backupPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected a private backup, got %v, %v", info, err)
+	}
+	if err := store.Backup(backupPath); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an existing backup to be kept, got %v", err)
+	}
+
+	// Change things after the backup, then restore it
+	store.Set("domain", "other.example")
+	store.AddPostToHistory("222")
+	store.Close()
+
+	previous, err := Restore(backupPath)
+	if err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+
+	store, err = NewStore()
+	if err != nil {
+		t.Fatalf("Failed to open restored store: %v", err)
+	}
+	defer store.Close()
+
+	if domain, _ := store.Get("domain"); domain != "example.com" {
+		t.Errorf("Expected the backed up domain, got %q", domain)
+	}
+	if last, _ := store.GetLastPostID(); last != "111" {
+		t.Errorf("Expected the backed up history, got %q", last)
+	}
+
+	// The replaced database is kept, with the settings it went with
+	if _, err := os.Stat(previous); err != nil {
+		t.Errorf("Expected the previous database at %s: %v", previous, err)
+	}
+	if kept := salvageConfig(previous); kept["domain"] != "other.example" {
+		t.Errorf("Expected the previous settings kept with it, got %v", kept)
+	}
+}
+
+func TestRestoreRejectsOtherFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv(DatabaseEnv, filepath.Join(tmpDir, "tusk.db"))
+
+	notDB := filepath.Join(tmpDir, "notes.txt")
+	os.WriteFile(notDB, []byte("hello, this is not a database at all, just some text"), 0600)
+	if _, err := Restore(notDB); err == nil {
+		t.Error("Expected a text file to be rejected")
+	}
+
+	if _, err := Restore(filepath.Join(tmpDir, "missing.db")); err == nil {
+		t.Error("Expected a missing file to be rejected")
+	}
+
+	// A newer tusk's backup
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	store.db.Exec("INSERT INTO schema_migrations (version, name) VALUES (9999, 'from the future')")
+	future := filepath.Join(tmpDir, "future.db")
+	store.Backup(future)
+	store.Close()
+
+	if _, err := Restore(future); err == nil || !strings.Contains(err.Error(), "newer version") {
+		t.Errorf("Expected a newer backup to be refused, got %v", err)
+	}
+}
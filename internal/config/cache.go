@@ -0,0 +1,67 @@
+package config
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CachedStatus is a minimal local copy of a status, enough to describe it
+// without another API call
+type CachedStatus struct {
+	StatusID string
+	Acct     string
+	Content  string
+	CachedAt time.Time
+}
+
+// CacheStatus stores or refreshes the cached copy of a status
+func (s *Store) CacheStatus(statusID, acct, content string) error {
+	_, err := s.db.Exec(
+		"INSERT OR REPLACE INTO status_cache (status_id, acct, content, cached_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)",
+		statusID, acct, content,
+	)
+	return err
+}
+
+// GetCachedStatus returns the cached copy of a status, or nil if there is none
+func (s *Store) GetCachedStatus(statusID string) (*CachedStatus, error) {
+	var cached CachedStatus
+	err := s.db.QueryRow(
+		"SELECT status_id, acct, content, cached_at FROM status_cache WHERE status_id = ?",
+		statusID,
+	).Scan(&cached.StatusID, &cached.Acct, &cached.Content, &cached.CachedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+// UncacheStatus forgets the cached copy of a status, such as one that's been
+// deleted
+func (s *Store) UncacheStatus(statusID string) error {
+	_, err := s.db.Exec("DELETE FROM status_cache WHERE status_id = ?", statusID)
+	return err
+}
+
+// CachedAccounts returns the accounts of cached statuses, most recently
+// cached first
+func (s *Store) CachedAccounts() ([]string, error) {
+	rows, err := s.db.Query("SELECT acct FROM status_cache WHERE acct != '' GROUP BY acct ORDER BY MAX(rowid) DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []string
+	for rows.Next() {
+		var acct string
+		if err := rows.Scan(&acct); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, acct)
+	}
+	return accounts, rows.Err()
+}
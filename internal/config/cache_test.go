@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStatusCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	missing, err := store.GetCachedStatus("123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if missing != nil {
+		t.Errorf("Expected nil for uncached status, got %+v", missing)
+	}
+
+	if err := store.CacheStatus("123", "alice@example.com", "<p>first</p>"); err != nil {
+		t.Fatalf("Failed to cache status: %v", err)
+	}
+	if err := store.CacheStatus("123", "alice@example.com", "<p>edited</p>"); err != nil {
+		t.Fatalf("Failed to refresh cached status: %v", err)
+	}
+
+	cached, err := store.GetCachedStatus("123")
+	if err != nil {
+		t.Fatalf("Failed to get cached status: %v", err)
+	}
+	if cached == nil {
+		t.Fatal("Expected cached status, got nil")
+	}
+	if cached.Acct != "alice@example.com" {
+		t.Errorf("Expected acct 'alice@example.com', got %q", cached.Acct)
+	}
+	if cached.Content != "<p>edited</p>" {
+		t.Errorf("Expected refreshed content, got %q", cached.Content)
+	}
+
+	if err := store.UncacheStatus("123"); err != nil {
+		t.Fatalf("Failed to uncache status: %v", err)
+	}
+	if cached, _ := store.GetCachedStatus("123"); cached != nil {
+		t.Errorf("Expected the status to be forgotten, got %+v", cached)
+	}
+}
+
+func TestCachedAccounts(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	store.CacheStatus("1", "alice@example.com", "<p>one</p>")
+	store.CacheStatus("2", "bob", "<p>two</p>")
+	store.CacheStatus("3", "alice@example.com", "<p>three</p>")
+	store.CacheStatus("4", "", "<p>unknown</p>")
+
+	accounts, err := store.CachedAccounts()
+	if err != nil {
+		t.Fatalf("Failed to list accounts: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0] != "alice@example.com" || accounts[1] != "bob" {
+		t.Errorf("Expected each account once, latest first, got %v", accounts)
+	}
+}
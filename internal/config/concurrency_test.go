@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStoreUsesWAL(t *testing.T) {
+	t.Setenv(DatabaseEnv, filepath.Join(t.TempDir(), "tusk.db"))
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	var mode string
+	if err := store.db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("Failed to read journal mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("Expected WAL journal mode, got %q", mode)
+	}
+}
+
+// Separate stores stand in for separate tusk processes, like the background
+// worker and a command run at the same time
+func TestConcurrentStores(t *testing.T) {
+	t.Setenv(DatabaseEnv, filepath.Join(t.TempDir(), "tusk.db"))
+
+	const workers, writes = 4, 25
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*writes)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			store, err := NewStore()
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer store.Close()
+
+			for i := 0; i < writes; i++ {
+				if err := store.AddPostToHistory(fmt.Sprintf("%d-%d", w, i)); err != nil {
+					errs <- err
+				}
+				id, err := store.AddJob("{}")
+				if err != nil {
+					errs <- err
+					continue
+				}
+				if _, err := store.StartJob(id); err != nil {
+					errs <- err
+				}
+				if _, err := store.GetLastPostID(); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Concurrent access failed: %v", err)
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	jobs, err := store.ListJobs(workers * writes * 2)
+	if err != nil {
+		t.Fatalf("Failed to list jobs: %v", err)
+	}
+	if len(jobs) != workers*writes {
+		t.Errorf("Expected %d jobs, got %d", workers*writes, len(jobs))
+	}
+}
+
+func TestDatabaseURI(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/home/me/tusk.db", "file:///home/me/tusk.db?mode=ro"},
+		{"/Users/me/Library/Application Support/tusk/tusk.db", "file:///Users/me/Library/Application%20Support/tusk/tusk.db?mode=ro"},
+		{"/tmp/odd#name?.db", "file:///tmp/odd%23name%3F.db?mode=ro"},
+	}
+
+	for _, tt := range tests {
+		if got := databaseURI(tt.path, "mode=ro"); got != tt.want {
+			t.Errorf("databaseURI(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
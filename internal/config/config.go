@@ -3,19 +3,47 @@ package config
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
 type Store struct {
-	db *sql.DB
+	db       *sql.DB
+	settings settingsFile
+
+	// movedFrom is where the database was moved from when this store was
+	// opened; see MovedFrom
+	movedFrom string
+
+	// account overrides the stored account for this process; see UseAccount
+	account string
+
+	// metricsRecorded counts the request timings recorded by this store, so
+	// old ones are pruned only now and then; see RecordRequestMetric
+	metricsRecorded atomic.Int64
 }
 
-func getConfigDir() (string, error) {
-	var baseDir string
+// DatabaseEnv names the environment variable that overrides where the
+// database is kept. --db sets it too, so processes tusk starts use the same
+// database.
+const DatabaseEnv = "TUSK_DB"
+
+const databaseName = "tusk.db"
+
+// dataDir returns the directory tusk keeps its database in by default.
+// XDG_DATA_HOME is honored on every platform; without it each platform's
+// usual place for application data is used.
+func dataDir() (string, error) {
+	if xdgData := os.Getenv("XDG_DATA_HOME"); xdgData != "" {
+		return filepath.Join(xdgData, "tusk"), nil
+	}
 
 	switch runtime.GOOS {
 	case "darwin":
@@ -23,49 +51,195 @@ func getConfigDir() (string, error) {
 		if err != nil {
 			return "", err
 		}
-		baseDir = filepath.Join(home, ".local", "share")
-	case "linux":
-		if xdgData := os.Getenv("XDG_DATA_HOME"); xdgData != "" {
-			baseDir = xdgData
-		} else {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return "", err
-			}
-			baseDir = filepath.Join(home, ".local", "share")
-		}
+		return filepath.Join(home, "Library", "Application Support", "tusk"), nil
 	case "windows":
-		baseDir = os.Getenv("APPDATA")
-		if baseDir == "" {
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
 			return "", fmt.Errorf("APPDATA environment variable not set")
 		}
+		return filepath.Join(appData, "tusk"), nil
 	default:
-		return "", fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "share", "tusk"), nil
+	}
+}
+
+// legacyDataDir returns where older versions of tusk kept the database, which
+// differs from dataDir on macOS and wherever XDG_DATA_HOME is set but wasn't
+// used
+func legacyDataDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", fmt.Errorf("APPDATA environment variable not set")
+		}
+		return filepath.Join(appData, "tusk"), nil
+	case "linux":
+		if xdgData := os.Getenv("XDG_DATA_HOME"); xdgData != "" {
+			return filepath.Join(xdgData, "tusk"), nil
+		}
 	}
 
-	configDir := filepath.Join(baseDir, "tusk")
-	if err := os.MkdirAll(configDir, 0700); err != nil {
+	home, err := os.UserHomeDir()
+	if err != nil {
 		return "", err
 	}
+	return filepath.Join(home, ".local", "share", "tusk"), nil
+}
+
+// DatabasePath returns the path of the database, creating its directory if
+// needed
+func DatabasePath() (string, error) {
+	path := os.Getenv(DatabaseEnv)
+	if path != "" {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %w", DatabaseEnv, err)
+		}
+		path = abs
+	} else {
+		dir, err := dataDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get data directory: %w", err)
+		}
+		path = filepath.Join(dir, databaseName)
+	}
 
-	return configDir, nil
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// DataPath returns the path of a file in tusk's data directory, next to the
+// database
+func DataPath(name string) (string, error) {
+	dbPath, err := DatabasePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(dbPath), name), nil
+}
+
+// migrateLegacyDatabase moves the database from where older versions of tusk
+// kept it, if that's the only copy. It returns the old path when it moved the
+// database, and "" otherwise. A database chosen with TUSK_DB is left alone.
+func migrateLegacyDatabase() (string, error) {
+	if os.Getenv(DatabaseEnv) != "" {
+		return "", nil
+	}
+
+	legacyDir, err := legacyDataDir()
+	if err != nil {
+		return "", nil
+	}
+	dir, err := dataDir()
+	if err != nil || dir == legacyDir {
+		return "", nil
+	}
+
+	moved, err := migrateDatabase(legacyDir, dir)
+	if err != nil || !moved {
+		return "", err
+	}
+	return filepath.Join(legacyDir, databaseName), nil
+}
+
+// migrateDatabase moves the database in fromDir to toDir, unless toDir
+// already has one. It reports whether it moved anything.
+func migrateDatabase(fromDir, toDir string) (bool, error) {
+	from := filepath.Join(fromDir, databaseName)
+	to := filepath.Join(toDir, databaseName)
+	if _, err := os.Stat(to); err == nil {
+		return false, nil
+	}
+	if _, err := os.Stat(from); err != nil {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(toDir, 0700); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", toDir, err)
+	}
+
+	// SQLite's journal files belong with the database; the main file goes
+	// last so a failure part way leaves the old copy in charge
+	for _, suffix := range []string{"-wal", "-shm", ""} {
+		if err := moveFile(from+suffix, to+suffix); err != nil && !os.IsNotExist(err) {
+			return false, fmt.Errorf("failed to move database from %s: %w", from, err)
+		}
+	}
+	return true, nil
+}
+
+// moveFile renames a file, copying it when the rename can't cross devices
+func moveFile(from, to string) error {
+	if err := os.Rename(from, to); err == nil || os.IsNotExist(err) {
+		return err
+	}
+
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(to, data, 0600); err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
+
+// connectionOptions let several tusk processes, like the background worker
+// and an interactive command, share the database. WAL lets reads carry on
+// during a write; busy_timeout waits out another writer rather than failing
+// with "database is locked"; and transactions take the write lock when they
+// begin, since one that reads first and then writes can't wait for the lock
+// and fails straight away.
+const connectionOptions = "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_txlock=immediate"
+
+// databaseURI makes a SQLite URI for the database at path, escaped so that
+// characters like # and ? in it aren't taken for part of the URI
+func databaseURI(path, query string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows drive letters
+		path = "/" + path
+	}
+	uri := url.URL{Scheme: "file", Path: path, RawQuery: query}
+	return uri.String()
 }
 
 func NewStore() (*Store, error) {
-	configDir, err := getConfigDir()
+	dbPath, err := DatabasePath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get config directory: %w", err)
+		return nil, err
+	}
+	settingsPath, err := SettingsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only a missing database can be one an older tusk kept elsewhere
+	var movedFrom string
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		if movedFrom, err = migrateLegacyDatabase(); err != nil {
+			return nil, err
+		}
 	}
 
-	dbPath := filepath.Join(configDir, "tusk.db")
-	db, err := sql.Open("sqlite", dbPath)
+	db, err := sql.Open("sqlite", databaseURI(dbPath, connectionOptions))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	store := &Store{db: db}
+	store := &Store{db: db, settings: settingsFile{path: settingsPath}, movedFrom: movedFrom}
 	if err := store.initDB(); err != nil {
 		db.Close()
+		if isCorruption(err) {
+			return nil, &CorruptError{Path: dbPath, Err: err}
+		}
 		return nil, err
 	}
 
@@ -73,77 +247,78 @@ func NewStore() (*Store, error) {
 }
 
 func (s *Store) initDB() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS config (
-		key TEXT PRIMARY KEY,
-		value TEXT NOT NULL
-	);
-
-	CREATE TABLE IF NOT EXISTS post_history (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		status_id TEXT NOT NULL UNIQUE,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-	`
-
-	if _, err := s.db.Exec(schema); err != nil {
+	if err := s.migrate(); err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
 
-	// Migrate old last_post table if it exists
-	var tableName string
-	err := s.db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name='last_post'").Scan(&tableName)
-	if err == nil {
-		// Migrate data from old table
-		var statusID string
-		err := s.db.QueryRow("SELECT status_id FROM last_post WHERE id = 1").Scan(&statusID)
-		if err == nil && statusID != "" {
-			s.db.Exec("INSERT OR IGNORE INTO post_history (status_id) VALUES (?)", statusID)
+	// Settings move out of the database once, the first time a version of
+	// tusk that keeps them in a file opens it
+	if _, err := os.Stat(s.settings.path); os.IsNotExist(err) {
+		if err := s.moveSettings(); err != nil {
+			return fmt.Errorf("failed to move settings out of the database: %w", err)
 		}
-		s.db.Exec("DROP TABLE last_post")
 	}
-
 	return nil
 }
 
+// MovedFrom returns where the database was moved from, when opening the store
+// moved it over from where older versions of tusk kept it, and "" otherwise
+func (s *Store) MovedFrom() string {
+	return s.movedFrom
+}
+
 func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// Set, Get, and Delete work on the settings file rather than the database;
+// see SettingsPath
+
 func (s *Store) Set(key, value string) error {
-	_, err := s.db.Exec(
-		"INSERT OR REPLACE INTO config (key, value) VALUES (?, ?)",
-		key, value,
-	)
-	return err
+	return s.settings.update(func(settings map[string]string) error {
+		settings[key] = value
+		return nil
+	})
 }
 
 func (s *Store) Get(key string) (string, error) {
-	var value string
-	err := s.db.QueryRow("SELECT value FROM config WHERE key = ?", key).Scan(&value)
-	if err == sql.ErrNoRows {
-		return "", nil
+	settings, err := s.settings.read()
+	if err != nil {
+		return "", err
 	}
-	return value, err
+	return settings[key], nil
 }
 
 func (s *Store) Delete(key string) error {
-	_, err := s.db.Exec("DELETE FROM config WHERE key = ?", key)
-	return err
+	return s.settings.update(func(settings map[string]string) error {
+		delete(settings, key)
+		return nil
+	})
 }
 
 func (s *Store) AddPostToHistory(statusID string) error {
-	_, err := s.db.Exec(
-		"INSERT OR IGNORE INTO post_history (status_id, created_at) VALUES (?, CURRENT_TIMESTAMP)",
-		statusID,
+	account, err := s.Account()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(
+		"INSERT OR IGNORE INTO post_history (account, status_id, created_at) VALUES (?, ?, CURRENT_TIMESTAMP)",
+		account, statusID,
 	)
 	return err
 }
 
 func (s *Store) GetLastPostID() (string, error) {
+	account, err := s.Account()
+	if err != nil {
+		return "", err
+	}
+
 	var statusID string
-	err := s.db.QueryRow(
-		"SELECT status_id FROM post_history ORDER BY id DESC LIMIT 1",
+	err = s.db.QueryRow(
+		"SELECT status_id FROM post_history WHERE account = ? ORDER BY id DESC LIMIT 1",
+		account,
 	).Scan(&statusID)
 	if err == sql.ErrNoRows {
 		return "", nil
@@ -151,13 +326,39 @@ func (s *Store) GetLastPostID() (string, error) {
 	return statusID, err
 }
 
+// CountPostHistory returns how many posts tusk has made since the given time
+func (s *Store) CountPostHistory(since time.Time) (int, error) {
+	account, err := s.Account()
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	err = s.db.QueryRow(
+		"SELECT COUNT(*) FROM post_history WHERE account = ? AND created_at >= ?",
+		account, since.UTC().Format("2006-01-02 15:04:05"),
+	).Scan(&n)
+	return n, err
+}
+
 func (s *Store) RemovePostFromHistory(statusID string) error {
-	_, err := s.db.Exec("DELETE FROM post_history WHERE status_id = ?", statusID)
+	account, err := s.Account()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec("DELETE FROM post_history WHERE account = ? AND status_id = ?", account, statusID)
 	return err
 }
 
+// ClearPostHistory forgets the current account's posts
 func (s *Store) ClearPostHistory() error {
-	_, err := s.db.Exec("DELETE FROM post_history")
+	account, err := s.Account()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec("DELETE FROM post_history WHERE account = ?", account)
 	return err
 }
 
@@ -176,5 +377,44 @@ func (s *Store) ClearAll() error {
 		return err
 	}
 
-	return tx.Commit()
+	if _, err := tx.Exec("DELETE FROM jobs"); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM status_cache"); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM cursors"); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM request_metrics"); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM account_snapshots"); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM response_cache"); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM reminders"); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM reboosts"); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return s.settings.update(func(settings map[string]string) error {
+		clear(settings)
+		return nil
+	})
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewStore(t *testing.T) {
@@ -361,3 +362,28 @@ func TestClearPostHistory(t *testing.T) {
 		t.Errorf("Expected empty last post after clear, got %q", lastPost)
 	}
 }
+
+func TestCountPostHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	store.AddPostToHistory("1")
+	store.AddPostToHistory("2")
+	store.db.Exec("INSERT INTO post_history (status_id, created_at) VALUES ('0', datetime('now', '-30 days'))")
+
+	n, err := store.CountPostHistory(time.Now().Add(-7 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to count post history: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 posts in the last week, got %d", n)
+	}
+}
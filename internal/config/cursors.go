@@ -0,0 +1,29 @@
+package config
+
+import "database/sql"
+
+// SetCursor saves a named pagination cursor so a long-running command can
+// pick up where it left off
+func (s *Store) SetCursor(name, value string) error {
+	_, err := s.db.Exec(
+		"INSERT OR REPLACE INTO cursors (name, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)",
+		name, value,
+	)
+	return err
+}
+
+// GetCursor returns a saved cursor, or "" if there is none
+func (s *Store) GetCursor(name string) (string, error) {
+	var value string
+	err := s.db.QueryRow("SELECT value FROM cursors WHERE name = ?", name).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+// ClearCursor forgets a saved cursor
+func (s *Store) ClearCursor(name string) error {
+	_, err := s.db.Exec("DELETE FROM cursors WHERE name = ?", name)
+	return err
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCursors(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	value, err := store.GetCursor("export")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != "" {
+		t.Errorf("Expected empty cursor, got %q", value)
+	}
+
+	if err := store.SetCursor("export", "100"); err != nil {
+		t.Fatalf("Failed to set cursor: %v", err)
+	}
+	if err := store.SetCursor("export", "50"); err != nil {
+		t.Fatalf("Failed to advance cursor: %v", err)
+	}
+
+	value, err = store.GetCursor("export")
+	if err != nil {
+		t.Fatalf("Failed to get cursor: %v", err)
+	}
+	if value != "50" {
+		t.Errorf("Expected cursor '50', got %q", value)
+	}
+
+	if err := store.ClearCursor("export"); err != nil {
+		t.Fatalf("Failed to clear cursor: %v", err)
+	}
+
+	value, _ = store.GetCursor("export")
+	if value != "" {
+		t.Errorf("Expected empty cursor after clear, got %q", value)
+	}
+}
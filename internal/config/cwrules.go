@@ -0,0 +1,80 @@
+package config
+
+import "encoding/json"
+
+// cwRulesKey is the config key holding the content warning rules, as a
+// JSON list in the order they were added
+const cwRulesKey = "cw_rules"
+
+// CWRule puts a content warning on posts whose text matches a pattern
+type CWRule struct {
+	Pattern     string `json:"pattern"`
+	SpoilerText string `json:"spoiler_text"`
+}
+
+// ListCWRules returns the content warning rules in the order they were added
+func (s *Store) ListCWRules() ([]CWRule, error) {
+	value, err := s.Get(cwRulesKey)
+	if err != nil || value == "" {
+		return nil, err
+	}
+
+	var rules []CWRule
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SetCWRule adds a rule, or changes the content warning of the rule with the
+// same pattern
+func (s *Store) SetCWRule(rule CWRule) error {
+	rules, err := s.ListCWRules()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range rules {
+		if rules[i].Pattern == rule.Pattern {
+			rules[i] = rule
+			replaced = true
+		}
+	}
+	if !replaced {
+		rules = append(rules, rule)
+	}
+	return s.saveCWRules(rules)
+}
+
+// RemoveCWRule deletes the rule with a pattern, and reports whether there
+// was one
+func (s *Store) RemoveCWRule(pattern string) (bool, error) {
+	rules, err := s.ListCWRules()
+	if err != nil {
+		return false, err
+	}
+
+	var kept []CWRule
+	for _, rule := range rules {
+		if rule.Pattern != pattern {
+			kept = append(kept, rule)
+		}
+	}
+	if len(kept) == len(rules) {
+		return false, nil
+	}
+	return true, s.saveCWRules(kept)
+}
+
+func (s *Store) saveCWRules(rules []CWRule) error {
+	if len(rules) == 0 {
+		return s.Delete(cwRulesKey)
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	return s.Set(cwRulesKey, string(data))
+}
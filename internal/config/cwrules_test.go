@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCWRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if rules, err := store.ListCWRules(); err != nil || len(rules) != 0 {
+		t.Fatalf("Expected no rules, got %v, %v", rules, err)
+	}
+
+	store.SetCWRule(CWRule{Pattern: "politics|election", SpoilerText: "politics"})
+	store.SetCWRule(CWRule{Pattern: "recipe", SpoilerText: "food"})
+	store.SetCWRule(CWRule{Pattern: "politics|election", SpoilerText: "US politics"})
+
+	rules, err := store.ListCWRules()
+	if err != nil {
+		t.Fatalf("Failed to list rules: %v", err)
+	}
+	if len(rules) != 2 || rules[0].SpoilerText != "US politics" || rules[1].Pattern != "recipe" {
+		t.Errorf("Expected the first rule to be replaced in place, got %+v", rules)
+	}
+
+	if removed, err := store.RemoveCWRule("recipe"); err != nil || !removed {
+		t.Errorf("Expected the rule to be removed, got %v, %v", removed, err)
+	}
+	if removed, _ := store.RemoveCWRule("recipe"); removed {
+		t.Error("Expected nothing to remove the second time")
+	}
+
+	store.RemoveCWRule("politics|election")
+	if value, _ := store.Get(cwRulesKey); value != "" {
+		t.Errorf("Expected the key to be cleared with the last rule, got %q", value)
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"database/sql"
+	"time"
+)
+
+// accountKey is the setting holding the account whose posts the history
+// shows. Each login keeps its own history, so logging in to another account
+// doesn't mix its posts with the last one's.
+const accountKey = "account"
+
+// Account returns the key of the account the post history belongs to, or ""
+// if it isn't known yet
+func (s *Store) Account() (string, error) {
+	if s.account != "" {
+		return s.account, nil
+	}
+	return s.Get(accountKey)
+}
+
+// UseAccount makes the history that of another account until the store is
+// closed, without changing the stored one. It's for logins that are only
+// used once, such as a token given on the command line.
+func (s *Store) UseAccount(domain, accountID string) {
+	s.account = domain + "/" + accountID
+}
+
+// SetAccount makes the history that of the account with the given ID on the
+// instance at domain. The first account set takes over the posts recorded
+// before accounts were known.
+func (s *Store) SetAccount(domain, accountID string) error {
+	key := domain + "/" + accountID
+
+	return s.settings.update(func(settings map[string]string) error {
+		previous := settings[accountKey]
+		if previous == key {
+			return nil
+		}
+
+		if previous == "" {
+			if _, err := s.db.Exec("UPDATE OR IGNORE post_history SET account = ? WHERE account = ''", key); err != nil {
+				return err
+			}
+		}
+		settings[accountKey] = key
+		return nil
+	})
+}
+
+// HistoryEntry is a post in the local history, with its cached copy if there
+// is one
+type HistoryEntry struct {
+	// Index counts back from the latest post, which is 1
+	Index    int
+	StatusID string
+	PostedAt time.Time
+
+	// Content is the cached HTML of the status, or "" if it isn't cached
+	Content string
+}
+
+// ListPostHistory returns the latest limit posts in the history, newest
+// first. A limit of 0 returns them all.
+func (s *Store) ListPostHistory(limit int) ([]*HistoryEntry, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+
+	account, err := s.Account()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(
+		`SELECT h.status_id, h.created_at, c.content FROM post_history h
+		LEFT JOIN status_cache c ON c.status_id = h.status_id
+		WHERE h.account = ? ORDER BY h.id DESC LIMIT ?`,
+		account, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*HistoryEntry
+	for rows.Next() {
+		var entry HistoryEntry
+		var content sql.NullString
+		if err := rows.Scan(&entry.StatusID, &entry.PostedAt, &content); err != nil {
+			return nil, err
+		}
+		entry.Index = len(entries) + 1
+		entry.Content = content.String
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetPostIDAt returns the ID of a post in the history by its index, counting
+// back from the latest post, which is 1. It returns "" if the history is
+// shorter than that.
+func (s *Store) GetPostIDAt(index int) (string, error) {
+	if index < 1 {
+		return "", nil
+	}
+
+	account, err := s.Account()
+	if err != nil {
+		return "", err
+	}
+
+	var statusID string
+	err = s.db.QueryRow(
+		"SELECT status_id FROM post_history WHERE account = ? ORDER BY id DESC LIMIT 1 OFFSET ?",
+		account, index-1,
+	).Scan(&statusID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return statusID, err
+}
+
+// PrunePostHistory removes all but the latest keep posts from the history
+// and returns how many it removed
+func (s *Store) PrunePostHistory(keep int) (int, error) {
+	account, err := s.Account()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.db.Exec(
+		`DELETE FROM post_history WHERE account = ? AND id NOT IN (
+			SELECT id FROM post_history WHERE account = ? ORDER BY id DESC LIMIT ?
+		)`,
+		account, account, keep,
+	)
+	if err != nil {
+		return 0, err
+	}
+	removed, err := result.RowsAffected()
+	return int(removed), err
+}
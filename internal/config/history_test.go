@@ -0,0 +1,190 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestListPostHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, id := range []string{"1", "2", "3"} {
+		store.AddPostToHistory(id)
+	}
+	store.CacheStatus("2", "me", "<p>second</p>")
+
+	entries, err := store.ListPostHistory(0)
+	if err != nil {
+		t.Fatalf("Failed to list history: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].StatusID != "3" || entries[0].Index != 1 || entries[2].StatusID != "1" || entries[2].Index != 3 {
+		t.Errorf("Expected newest first, got %+v, %+v", entries[0], entries[2])
+	}
+	if entries[1].Content != "<p>second</p>" || entries[0].Content != "" {
+		t.Errorf("Unexpected cached content %q, %q", entries[1].Content, entries[0].Content)
+	}
+	if entries[0].PostedAt.IsZero() {
+		t.Error("Expected the post time to be set")
+	}
+
+	latest, _ := store.ListPostHistory(2)
+	if len(latest) != 2 || latest[1].StatusID != "2" {
+		t.Errorf("Expected the 2 latest entries, got %d", len(latest))
+	}
+}
+
+func TestGetPostIDAt(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, id := range []string{"1", "2", "3"} {
+		store.AddPostToHistory(id)
+	}
+
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{1, "3"},
+		{3, "1"},
+		{4, ""},
+		{0, ""},
+	}
+	for _, tt := range tests {
+		got, err := store.GetPostIDAt(tt.index)
+		if err != nil {
+			t.Fatalf("GetPostIDAt(%d) failed: %v", tt.index, err)
+		}
+		if got != tt.want {
+			t.Errorf("GetPostIDAt(%d) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestPrunePostHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for _, id := range []string{"1", "2", "3", "4"} {
+		store.AddPostToHistory(id)
+	}
+
+	removed, err := store.PrunePostHistory(2)
+	if err != nil {
+		t.Fatalf("Failed to prune history: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 removed, got %d", removed)
+	}
+
+	entries, _ := store.ListPostHistory(0)
+	if len(entries) != 2 || entries[0].StatusID != "4" || entries[1].StatusID != "3" {
+		t.Errorf("Expected the 2 latest posts to be kept, got %d", len(entries))
+	}
+
+	removed, _ = store.PrunePostHistory(10)
+	if removed != 0 {
+		t.Errorf("Expected nothing removed, got %d", removed)
+	}
+}
+
+func TestPostHistoryPerAccount(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	// Posts from before the account was known belong to the first one set
+	store.AddPostToHistory("1")
+	if err := store.SetAccount("https://one.example", "42"); err != nil {
+		t.Fatalf("Failed to set account: %v", err)
+	}
+	store.AddPostToHistory("2")
+
+	if last, _ := store.GetLastPostID(); last != "2" {
+		t.Errorf("Expected last post 2, got %q", last)
+	}
+	if entries, _ := store.ListPostHistory(0); len(entries) != 2 {
+		t.Errorf("Expected the first account to have 2 posts, got %d", len(entries))
+	}
+
+	// Logging in elsewhere starts an empty history, even for the same ID
+	store.SetAccount("https://two.example", "42")
+	if last, _ := store.GetLastPostID(); last != "" {
+		t.Errorf("Expected no posts for the second account, got %q", last)
+	}
+	store.AddPostToHistory("2")
+	store.AddPostToHistory("3")
+	store.ClearPostHistory()
+
+	// and coming back finds the old posts where they were
+	store.SetAccount("https://one.example", "42")
+	entries, _ := store.ListPostHistory(0)
+	if len(entries) != 2 || entries[0].StatusID != "2" || entries[1].StatusID != "1" {
+		t.Errorf("Expected the first account's posts to be kept, got %d", len(entries))
+	}
+	if account, _ := store.Account(); account != "https://one.example/42" {
+		t.Errorf("Unexpected account %q", account)
+	}
+}
+
+func TestUseAccount(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	store.SetAccount("https://one.example", "42")
+	store.AddPostToHistory("1")
+
+	store.UseAccount("https://two.example", "7")
+	store.AddPostToHistory("2")
+	if entries, _ := store.ListPostHistory(0); len(entries) != 1 || entries[0].StatusID != "2" {
+		t.Errorf("Expected only the overriding account's post, got %d", len(entries))
+	}
+
+	// The stored account is left alone
+	if account, _ := store.Get("account"); account != "https://one.example/42" {
+		t.Errorf("Unexpected stored account %q", account)
+	}
+}
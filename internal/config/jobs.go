@@ -0,0 +1,136 @@
+package config
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Job states
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// Job is a queued operation completed by a background worker
+type Job struct {
+	ID        int64
+	Payload   string
+	State     string
+	StatusID  string
+	URL       string
+	Error     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// AddJob queues a new pending job and returns its ticket ID
+func (s *Store) AddJob(payload string) (int64, error) {
+	result, err := s.db.Exec("INSERT INTO jobs (payload) VALUES (?)", payload)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetJob returns the job with the given ID, or nil if there is none
+func (s *Store) GetJob(id int64) (*Job, error) {
+	row := s.db.QueryRow(
+		"SELECT id, payload, state, status_id, url, error, created_at, updated_at FROM jobs WHERE id = ?",
+		id,
+	)
+
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// ListJobs returns the most recent jobs, newest first
+func (s *Store) ListJobs(limit int) ([]*Job, error) {
+	rows, err := s.db.Query(
+		"SELECT id, payload, state, status_id, url, error, created_at, updated_at FROM jobs ORDER BY id DESC LIMIT ?",
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// NextPendingJob returns the oldest job waiting to run, or nil if there is none
+func (s *Store) NextPendingJob() (*Job, error) {
+	row := s.db.QueryRow(
+		"SELECT id, payload, state, status_id, url, error, created_at, updated_at FROM jobs WHERE state = ? ORDER BY id LIMIT 1",
+		JobPending,
+	)
+
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// StartJob marks a pending job as running. It reports false if the job was
+// already claimed by another worker.
+func (s *Store) StartJob(id int64) (bool, error) {
+	result, err := s.db.Exec(
+		"UPDATE jobs SET state = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND state = ?",
+		JobRunning, id, JobPending,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// CompleteJob records the status a job produced
+func (s *Store) CompleteJob(id int64, statusID, url string) error {
+	_, err := s.db.Exec(
+		"UPDATE jobs SET state = ?, status_id = ?, url = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		JobDone, statusID, url, id,
+	)
+	return err
+}
+
+// FailJob records why a job could not be completed
+func (s *Store) FailJob(id int64, errMsg string) error {
+	_, err := s.db.Exec(
+		"UPDATE jobs SET state = ?, error = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		JobFailed, errMsg, id,
+	)
+	return err
+}
+
+// ClearFinishedJobs removes completed and failed jobs
+func (s *Store) ClearFinishedJobs() error {
+	_, err := s.db.Exec("DELETE FROM jobs WHERE state IN (?, ?)", JobDone, JobFailed)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row rowScanner) (*Job, error) {
+	var job Job
+	err := row.Scan(&job.ID, &job.Payload, &job.State, &job.StatusID, &job.URL, &job.Error, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestJobLifecycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	id, err := store.AddJob(`{"status":"hello"}`)
+	if err != nil {
+		t.Fatalf("Failed to add job: %v", err)
+	}
+
+	job, err := store.GetJob(id)
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if job.State != JobPending {
+		t.Errorf("Expected state %q, got %q", JobPending, job.State)
+	}
+	if job.Payload != `{"status":"hello"}` {
+		t.Errorf("Expected payload to round-trip, got %q", job.Payload)
+	}
+
+	started, err := store.StartJob(id)
+	if err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+	if !started {
+		t.Error("Expected first StartJob to claim the job")
+	}
+
+	started, err = store.StartJob(id)
+	if err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+	if started {
+		t.Error("Expected second StartJob to report the job as already claimed")
+	}
+
+	if err := store.CompleteJob(id, "123", "https://example.com/@me/123"); err != nil {
+		t.Fatalf("Failed to complete job: %v", err)
+	}
+
+	job, err = store.GetJob(id)
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if job.State != JobDone {
+		t.Errorf("Expected state %q, got %q", JobDone, job.State)
+	}
+	if job.StatusID != "123" {
+		t.Errorf("Expected status ID '123', got %q", job.StatusID)
+	}
+}
+
+func TestListAndClearJobs(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	first, _ := store.AddJob("first")
+	second, _ := store.AddJob("second")
+
+	if err := store.FailJob(first, "boom"); err != nil {
+		t.Fatalf("Failed to fail job: %v", err)
+	}
+
+	jobs, err := store.ListJobs(10)
+	if err != nil {
+		t.Fatalf("Failed to list jobs: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d", len(jobs))
+	}
+	if jobs[0].ID != second {
+		t.Errorf("Expected newest job first, got job %d", jobs[0].ID)
+	}
+
+	if err := store.ClearFinishedJobs(); err != nil {
+		t.Fatalf("Failed to clear finished jobs: %v", err)
+	}
+
+	jobs, err = store.ListJobs(10)
+	if err != nil {
+		t.Fatalf("Failed to list jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != second {
+		t.Errorf("Expected only the pending job to remain, got %v", jobs)
+	}
+
+	missing, err := store.GetJob(9999)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if missing != nil {
+		t.Errorf("Expected nil for unknown job, got %v", missing)
+	}
+}
+
+func TestNextPendingJob(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if job, err := store.NextPendingJob(); err != nil || job != nil {
+		t.Fatalf("Expected no pending job, got %+v, %v", job, err)
+	}
+
+	first, _ := store.AddJob("first")
+	second, _ := store.AddJob("second")
+
+	job, err := store.NextPendingJob()
+	if err != nil {
+		t.Fatalf("Failed to get next job: %v", err)
+	}
+	if job == nil || job.ID != first {
+		t.Fatalf("Expected oldest job %d, got %+v", first, job)
+	}
+
+	store.StartJob(first)
+	job, _ = store.NextPendingJob()
+	if job == nil || job.ID != second {
+		t.Errorf("Expected job %d once the first was claimed, got %+v", second, job)
+	}
+}
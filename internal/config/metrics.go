@@ -0,0 +1,140 @@
+package config
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// maxRequestMetrics bounds how many request timings are kept; older ones are
+// dropped as new ones are recorded
+const maxRequestMetrics = 10000
+
+// metricsPruneEvery is how many timings a store records between prunings.
+// The first one a store records always prunes, so short-lived commands keep
+// the table in bounds too.
+const metricsPruneEvery = 100
+
+// EndpointSummary aggregates the recorded requests to one API endpoint
+type EndpointSummary struct {
+	Method      string
+	Endpoint    string
+	Count       int
+	P50         time.Duration
+	P90         time.Duration
+	P99         time.Duration
+	Max         time.Duration
+	Failed      int // no response, or a server error
+	RateLimited int
+	Retries     int // requests that repeated one that failed
+}
+
+// RecordRequestMetric saves the timing of one API request. retry is whether
+// it repeated a request that failed.
+func (s *Store) RecordRequestMetric(method, endpoint string, status int, duration time.Duration, retry bool) error {
+	_, err := s.db.Exec(
+		"INSERT INTO request_metrics (method, endpoint, status, duration_ms, retry) VALUES (?, ?, ?, ?, ?)",
+		method, endpoint, status, duration.Milliseconds(), retry,
+	)
+	if err != nil {
+		return err
+	}
+
+	if (s.metricsRecorded.Add(1)-1)%metricsPruneEvery != 0 {
+		return nil
+	}
+	_, err = s.db.Exec(
+		"DELETE FROM request_metrics WHERE id <= (SELECT MAX(id) FROM request_metrics) - ?",
+		maxRequestMetrics,
+	)
+	return err
+}
+
+// SummarizeRequestMetrics returns per-endpoint latency percentiles for the
+// requests recorded since the given time, slowest median first
+func (s *Store) SummarizeRequestMetrics(since time.Time) ([]*EndpointSummary, error) {
+	rows, err := s.db.Query(
+		`SELECT method, endpoint, status, duration_ms, retry FROM request_metrics
+		WHERE created_at >= ? ORDER BY duration_ms`,
+		since.UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type key struct{ method, endpoint string }
+	durations := make(map[key][]time.Duration)
+	summaries := make(map[key]*EndpointSummary)
+
+	for rows.Next() {
+		var method, endpoint string
+		var status int
+		var ms int64
+		var retry bool
+		if err := rows.Scan(&method, &endpoint, &status, &ms, &retry); err != nil {
+			return nil, err
+		}
+
+		k := key{method, endpoint}
+		summary, ok := summaries[k]
+		if !ok {
+			summary = &EndpointSummary{Method: method, Endpoint: endpoint}
+			summaries[k] = summary
+		}
+
+		summary.Count++
+		if retry {
+			summary.Retries++
+		}
+		switch {
+		case status == 429:
+			summary.RateLimited++
+		case status == 0 || status >= 500:
+			summary.Failed++
+		}
+
+		// Rows come back sorted by duration
+		durations[k] = append(durations[k], time.Duration(ms)*time.Millisecond)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*EndpointSummary, 0, len(summaries))
+	for k, summary := range summaries {
+		sorted := durations[k]
+		summary.P50 = percentile(sorted, 50)
+		summary.P90 = percentile(sorted, 90)
+		summary.P99 = percentile(sorted, 99)
+		summary.Max = sorted[len(sorted)-1]
+		result = append(result, summary)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].P50 != result[j].P50 {
+			return result[i].P50 > result[j].P50
+		}
+		return result[i].Endpoint < result[j].Endpoint
+	})
+
+	return result, nil
+}
+
+// ClearRequestMetrics forgets all recorded request timings
+func (s *Store) ClearRequestMetrics() error {
+	_, err := s.db.Exec("DELETE FROM request_metrics")
+	return err
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for i := 1; i <= 10; i++ {
+		if err := store.RecordRequestMetric("GET", "/api/v1/statuses/:id", 200, time.Duration(i*100)*time.Millisecond, false); err != nil {
+			t.Fatalf("Failed to record metric: %v", err)
+		}
+	}
+	store.RecordRequestMetric("POST", "/api/v1/statuses", 429, 2*time.Second, false)
+	store.RecordRequestMetric("POST", "/api/v1/statuses", 0, 30*time.Second, true)
+
+	summaries, err := store.SummarizeRequestMetrics(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to summarize metrics: %v", err)
+	}
+
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 endpoints, got %d", len(summaries))
+	}
+
+	// POST has the slower median (2s against 500ms)
+	post, get := summaries[0], summaries[1]
+	if post.Method != "POST" || post.RateLimited != 1 || post.Failed != 1 || post.Retries != 1 {
+		t.Errorf("Unexpected POST summary %+v", post)
+	}
+
+	if get.Count != 10 {
+		t.Errorf("Expected 10 GET requests, got %d", get.Count)
+	}
+	if get.P50 != 500*time.Millisecond {
+		t.Errorf("Expected p50 500ms, got %v", get.P50)
+	}
+	if get.P90 != 900*time.Millisecond {
+		t.Errorf("Expected p90 900ms, got %v", get.P90)
+	}
+	if get.Max != time.Second {
+		t.Errorf("Expected max 1s, got %v", get.Max)
+	}
+
+	// Nothing recorded in the future
+	summaries, err = store.SummarizeRequestMetrics(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to summarize metrics: %v", err)
+	}
+	if len(summaries) != 0 {
+		t.Errorf("Expected no summaries, got %d", len(summaries))
+	}
+
+	if err := store.ClearRequestMetrics(); err != nil {
+		t.Fatalf("Failed to clear metrics: %v", err)
+	}
+	summaries, _ = store.SummarizeRequestMetrics(time.Time{})
+	if len(summaries) != 0 {
+		t.Errorf("Expected metrics to be cleared, got %d", len(summaries))
+	}
+}
+
+func TestRequestMetricsPruned(t *testing.T) {
+	t.Setenv(DatabaseEnv, filepath.Join(t.TempDir(), "tusk.db"))
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	// Timings left over from earlier commands, over the limit
+	_, err = store.db.Exec(`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < ?)
+		INSERT INTO request_metrics (method, endpoint, status, duration_ms) SELECT 'GET', '/api/v1/old', 200, 1 FROM n`,
+		maxRequestMetrics+50)
+	if err != nil {
+		t.Fatalf("Failed to add old metrics: %v", err)
+	}
+
+	count := func() int {
+		var n int
+		store.db.QueryRow("SELECT COUNT(*) FROM request_metrics").Scan(&n)
+		return n
+	}
+
+	// The first timing a store records prunes the table
+	store.RecordRequestMetric("GET", "/api/v1/new", 200, time.Millisecond, false)
+	if n := count(); n != maxRequestMetrics {
+		t.Errorf("Expected %d metrics after the first recording, got %d", maxRequestMetrics, n)
+	}
+
+	// The next ones don't, until another round comes up
+	for range metricsPruneEvery - 1 {
+		store.RecordRequestMetric("GET", "/api/v1/new", 200, time.Millisecond, false)
+	}
+	if n := count(); n != maxRequestMetrics+metricsPruneEvery-1 {
+		t.Errorf("Expected no pruning in between, got %d metrics", n)
+	}
+	store.RecordRequestMetric("GET", "/api/v1/new", 200, time.Millisecond, false)
+	if n := count(); n != maxRequestMetrics {
+		t.Errorf("Expected %d metrics after pruning again, got %d", maxRequestMetrics, n)
+	}
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migration is one step in the database's schema history. Migrations run in
+// order, each in its own transaction, and the versions applied are recorded
+// in schema_migrations, so every database is brought forward from wherever
+// it is. Never edit a released migration; add a new one instead.
+type migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx) error
+}
+
+// The first three use IF NOT EXISTS, since databases from before
+// schema_migrations already have their tables
+var migrations = []migration{
+	{1, "initial schema", execMigration(`
+	CREATE TABLE IF NOT EXISTS config (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS post_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		status_id TEXT NOT NULL UNIQUE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		payload TEXT NOT NULL,
+		state TEXT NOT NULL DEFAULT 'pending',
+		status_id TEXT NOT NULL DEFAULT '',
+		url TEXT NOT NULL DEFAULT '',
+		error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS hashtag_profiles (
+		tag TEXT PRIMARY KEY,
+		visibility TEXT NOT NULL DEFAULT '',
+		spoiler_text TEXT NOT NULL DEFAULT '',
+		sensitive INTEGER NOT NULL DEFAULT 0,
+		language TEXT NOT NULL DEFAULT ''
+	);
+
+	CREATE TABLE IF NOT EXISTS status_cache (
+		status_id TEXT PRIMARY KEY,
+		acct TEXT NOT NULL,
+		content TEXT NOT NULL,
+		cached_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS cursors (
+		name TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS series (
+		name TEXT PRIMARY KEY COLLATE NOCASE,
+		template TEXT NOT NULL,
+		count INTEGER NOT NULL DEFAULT 0,
+		last_posted_at TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS request_metrics (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		method TEXT NOT NULL,
+		endpoint TEXT NOT NULL,
+		status INTEGER NOT NULL,
+		duration_ms INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	`)},
+	{2, "move last_post into post_history", migrateLastPost},
+	{3, "account snapshots", execMigration(`
+	CREATE TABLE IF NOT EXISTS account_snapshots (
+		account_id TEXT NOT NULL,
+		day TEXT NOT NULL,
+		followers INTEGER NOT NULL,
+		following INTEGER NOT NULL,
+		statuses INTEGER NOT NULL,
+		taken_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (account_id, day)
+	);
+	`)},
+	{4, "post history per account", execMigration(`
+	CREATE TABLE post_history_accounts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		account TEXT NOT NULL DEFAULT '',
+		status_id TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (account, status_id)
+	);
+
+	INSERT INTO post_history_accounts (id, status_id, created_at)
+		SELECT id, status_id, created_at FROM post_history;
+
+	DROP TABLE post_history;
+	ALTER TABLE post_history_accounts RENAME TO post_history;
+	`)},
+	{5, "response cache", execMigration(`
+	CREATE TABLE response_cache (
+		key TEXT PRIMARY KEY,
+		body BLOB NOT NULL,
+		content_type TEXT NOT NULL DEFAULT '',
+		etag TEXT NOT NULL DEFAULT '',
+		stored_at TIMESTAMP NOT NULL
+	);
+	`)},
+	{6, "reminders", execMigration(`
+	CREATE TABLE reminders (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		account TEXT NOT NULL DEFAULT '',
+		text TEXT NOT NULL,
+		due_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	`)},
+	{7, "reboosts", execMigration(`
+	CREATE TABLE reboosts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		account TEXT NOT NULL DEFAULT '',
+		status_id TEXT NOT NULL,
+		next_at TIMESTAMP NOT NULL,
+		every_seconds INTEGER NOT NULL DEFAULT 0,
+		last_at TIMESTAMP,
+		error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	`)},
+	{8, "reboost failures", execMigration(`
+	ALTER TABLE reboosts ADD COLUMN failures INTEGER NOT NULL DEFAULT 0;
+	`)},
+	{9, "request retries", execMigration(`
+	ALTER TABLE request_metrics ADD COLUMN retry INTEGER NOT NULL DEFAULT 0;
+	`)},
+}
+
+// execMigration is a migration that runs SQL statements
+func execMigration(statements string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(statements)
+		return err
+	}
+}
+
+// migrateLastPost carries the single ID kept by the oldest versions of tusk
+// into the post history
+func migrateLastPost(tx *sql.Tx) error {
+	var name string
+	err := tx.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name='last_post'").Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var statusID string
+	err = tx.QueryRow("SELECT status_id FROM last_post WHERE id = 1").Scan(&statusID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if statusID != "" {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO post_history (status_id) VALUES (?)", statusID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("DROP TABLE last_post")
+	return err
+}
+
+// SchemaVersion returns the newest migration applied to the database
+func (s *Store) SchemaVersion() (int, error) {
+	var version int
+	err := s.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	return version, err
+}
+
+// migrate applies the migrations the database doesn't have yet
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	latest := migrations[len(migrations)-1].Version
+	for _, m := range migrations {
+		if err := s.applyMigration(m, latest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs m unless it's been applied already. The version is
+// checked inside the transaction, so when two processes start at once only
+// one of them applies it.
+func (s *Store) applyMigration(m migration, latest int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current); err != nil {
+		return err
+	}
+	if current > latest {
+		return fmt.Errorf("the database is at schema version %d, but this tusk only knows up to %d; upgrade tusk to use it", current, latest)
+	}
+	if current >= m.Version {
+		return nil
+	}
+
+	if err := m.Up(tx); err != nil {
+		return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.Version, m.Name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateFreshDatabase(t *testing.T) {
+	t.Setenv(DatabaseEnv, filepath.Join(t.TempDir(), "tusk.db"))
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	version, err := store.SchemaVersion()
+	if err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if latest := migrations[len(migrations)-1].Version; version != latest {
+		t.Errorf("Expected schema version %d, got %d", latest, version)
+	}
+
+	// Opening again applies nothing new
+	again, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	again.Close()
+
+	var applied int
+	store.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&applied)
+	if applied != len(migrations) {
+		t.Errorf("Expected %d migrations recorded, got %d", len(migrations), applied)
+	}
+}
+
+func TestMigrateUnversionedDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "tusk.db")
+	t.Setenv(DatabaseEnv, dbPath)
+
+	// A database from before schema_migrations, still holding the oldest
+	// last_post table
+	db, err := sql.Open("sqlite", databaseURI(dbPath, ""))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = db.Exec(`
+	CREATE TABLE config (key TEXT PRIMARY KEY, value TEXT NOT NULL);
+	CREATE TABLE post_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		status_id TEXT NOT NULL UNIQUE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE last_post (id INTEGER PRIMARY KEY, status_id TEXT);
+	INSERT INTO config (key, value) VALUES ('domain', 'example.com');
+	INSERT INTO last_post (id, status_id) VALUES (1, '12345');
+	`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to set up old database: %v", err)
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to migrate old database: %v", err)
+	}
+	defer store.Close()
+
+	if domain, _ := store.Get("domain"); domain != "example.com" {
+		t.Errorf("Expected settings to survive, got domain %q", domain)
+	}
+	if last, _ := store.GetLastPostID(); last != "12345" {
+		t.Errorf("Expected last_post to move into the history, got %q", last)
+	}
+
+	var name string
+	err = store.db.QueryRow("SELECT name FROM sqlite_master WHERE name = 'last_post'").Scan(&name)
+	if err != sql.ErrNoRows {
+		t.Errorf("Expected last_post to be dropped, got %v", err)
+	}
+}
+
+func TestMigrateNewerDatabase(t *testing.T) {
+	t.Setenv(DatabaseEnv, filepath.Join(t.TempDir(), "tusk.db"))
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	store.db.Exec("INSERT INTO schema_migrations (version, name) VALUES (9999, 'from the future')")
+	store.Close()
+
+	_, err = NewStore()
+	if err == nil || !strings.Contains(err.Error(), "upgrade tusk") {
+		t.Errorf("Expected an error asking to upgrade, got %v", err)
+	}
+}
+
+func TestMigrationVersionsIncrease(t *testing.T) {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("Expected migration %q to be version %d, got %d", m.Name, i+1, m.Version)
+		}
+	}
+}
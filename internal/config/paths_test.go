@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDatabasePathFromEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv(DatabaseEnv, filepath.Join(tmpDir, "elsewhere", "work.db"))
+
+	dbPath, err := DatabasePath()
+	if err != nil {
+		t.Fatalf("Failed to get database path: %v", err)
+	}
+	if dbPath != filepath.Join(tmpDir, "elsewhere", "work.db") {
+		t.Errorf("Expected the TUSK_DB path, got %s", dbPath)
+	}
+
+	// Other files go next to the database
+	lockPath, _ := DataPath("daemon.lock")
+	if lockPath != filepath.Join(tmpDir, "elsewhere", "daemon.lock") {
+		t.Errorf("Expected daemon.lock beside the database, got %s", lockPath)
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	store.Close()
+	if _, err := os.Stat(dbPath); err != nil {
+		t.Errorf("Expected the database at %s: %v", dbPath, err)
+	}
+}
+
+func TestDatabasePathRelative(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	t.Setenv(DatabaseEnv, "tusk-test.db")
+
+	dbPath, err := DatabasePath()
+	if err != nil {
+		t.Fatalf("Failed to get database path: %v", err)
+	}
+	if !filepath.IsAbs(dbPath) || filepath.Base(dbPath) != "tusk-test.db" {
+		t.Errorf("Expected an absolute path, got %s", dbPath)
+	}
+}
+
+func TestDatabasePathXDG(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv(DatabaseEnv, "")
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmpDir, "data"))
+
+	dbPath, err := DatabasePath()
+	if err != nil {
+		t.Fatalf("Failed to get database path: %v", err)
+	}
+	if dbPath != filepath.Join(tmpDir, "data", "tusk", "tusk.db") {
+		t.Errorf("Expected the database under XDG_DATA_HOME, got %s", dbPath)
+	}
+}
+
+func TestMigrateDatabase(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir := filepath.Join(tmpDir, "old")
+	newDir := filepath.Join(tmpDir, "new")
+
+	if moved, err := migrateDatabase(oldDir, newDir); moved || err != nil {
+		t.Fatalf("Expected nothing to move, got %v, %v", moved, err)
+	}
+
+	os.MkdirAll(oldDir, 0700)
+	os.WriteFile(filepath.Join(oldDir, "tusk.db"), []byte("db"), 0600)
+	os.WriteFile(filepath.Join(oldDir, "tusk.db-wal"), []byte("wal"), 0600)
+
+	moved, err := migrateDatabase(oldDir, newDir)
+	if err != nil || !moved {
+		t.Fatalf("Expected the database to move, got %v, %v", moved, err)
+	}
+
+	for name, want := range map[string]string{"tusk.db": "db", "tusk.db-wal": "wal"} {
+		data, err := os.ReadFile(filepath.Join(newDir, name))
+		if err != nil || string(data) != want {
+			t.Errorf("Expected %s to be moved, got %q, %v", name, data, err)
+		}
+		if _, err := os.Stat(filepath.Join(oldDir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected old %s to be gone", name)
+		}
+	}
+
+	// An existing database is never replaced
+	os.WriteFile(filepath.Join(oldDir, "tusk.db"), []byte("older"), 0600)
+	if moved, _ := migrateDatabase(oldDir, newDir); moved {
+		t.Error("Expected the existing database to be kept")
+	}
+}
+
+func TestSettingsPathXDG(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv(DatabaseEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+
+	settingsPath, err := SettingsPath()
+	if err != nil {
+		t.Fatalf("Failed to get settings path: %v", err)
+	}
+	if settingsPath != filepath.Join(tmpDir, "config", "tusk", "config.json") {
+		t.Errorf("Expected the settings under XDG_CONFIG_HOME, got %s", settingsPath)
+	}
+}
+
+func TestSettingsPathFromEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv(DatabaseEnv, filepath.Join(tmpDir, "work.db"))
+
+	settingsPath, err := SettingsPath()
+	if err != nil {
+		t.Fatalf("Failed to get settings path: %v", err)
+	}
+	if settingsPath != filepath.Join(tmpDir, "work.json") {
+		t.Errorf("Expected the settings beside the TUSK_DB database, got %s", settingsPath)
+	}
+}
+
+func TestSettingsKeptOutOfDatabase(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv(DatabaseEnv, filepath.Join(tmpDir, "tusk.db"))
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	store.Set("access_token", "secret")
+
+	var rows int
+	store.db.QueryRow("SELECT COUNT(*) FROM config").Scan(&rows)
+	if rows != 0 {
+		t.Errorf("Expected nothing in the database's config table, got %d rows", rows)
+	}
+	info, err := os.Stat(filepath.Join(tmpDir, "tusk.json"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected a private settings file, got %v, %v", info, err)
+	}
+	store.Close()
+}
+
+func TestSettingsMovedOutOfDatabase(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv(DatabaseEnv, filepath.Join(tmpDir, "tusk.db"))
+
+	// A database from before settings had their own file
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	store.db.Exec("INSERT INTO config (key, value) VALUES ('domain', 'example.com'), ('access_token', 'secret')")
+	store.Close()
+
+	store, err = NewStore()
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer store.Close()
+
+	if token, _ := store.Get("access_token"); token != "secret" {
+		t.Errorf("Expected the login to move to the settings file, got %q", token)
+	}
+	var rows int
+	store.db.QueryRow("SELECT COUNT(*) FROM config").Scan(&rows)
+	if rows != 0 {
+		t.Errorf("Expected the config table to be emptied, got %d rows", rows)
+	}
+}
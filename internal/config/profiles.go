@@ -0,0 +1,70 @@
+package config
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// HashtagProfile holds posting settings applied to any post using its tag
+type HashtagProfile struct {
+	Tag         string
+	Visibility  string
+	SpoilerText string
+	Sensitive   bool
+	Language    string
+}
+
+// normalizeTag lowercases a tag and strips its leading #
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(tag, "#"))
+}
+
+// SetHashtagProfile creates or replaces the profile for a tag
+func (s *Store) SetHashtagProfile(profile HashtagProfile) error {
+	_, err := s.db.Exec(
+		"INSERT OR REPLACE INTO hashtag_profiles (tag, visibility, spoiler_text, sensitive, language) VALUES (?, ?, ?, ?, ?)",
+		normalizeTag(profile.Tag), profile.Visibility, profile.SpoilerText, profile.Sensitive, profile.Language,
+	)
+	return err
+}
+
+// GetHashtagProfile returns the profile for a tag, or nil if there is none
+func (s *Store) GetHashtagProfile(tag string) (*HashtagProfile, error) {
+	var profile HashtagProfile
+	err := s.db.QueryRow(
+		"SELECT tag, visibility, spoiler_text, sensitive, language FROM hashtag_profiles WHERE tag = ?",
+		normalizeTag(tag),
+	).Scan(&profile.Tag, &profile.Visibility, &profile.SpoilerText, &profile.Sensitive, &profile.Language)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ListHashtagProfiles returns every profile, ordered by tag
+func (s *Store) ListHashtagProfiles() ([]*HashtagProfile, error) {
+	rows, err := s.db.Query("SELECT tag, visibility, spoiler_text, sensitive, language FROM hashtag_profiles ORDER BY tag")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []*HashtagProfile
+	for rows.Next() {
+		var profile HashtagProfile
+		if err := rows.Scan(&profile.Tag, &profile.Visibility, &profile.SpoilerText, &profile.Sensitive, &profile.Language); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, &profile)
+	}
+	return profiles, rows.Err()
+}
+
+// RemoveHashtagProfile deletes the profile for a tag
+func (s *Store) RemoveHashtagProfile(tag string) error {
+	_, err := s.db.Exec("DELETE FROM hashtag_profiles WHERE tag = ?", normalizeTag(tag))
+	return err
+}
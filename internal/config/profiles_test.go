@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestHashtagProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	profile := HashtagProfile{
+		Tag:         "#NSFW",
+		SpoilerText: "NSFW",
+		Sensitive:   true,
+	}
+	if err := store.SetHashtagProfile(profile); err != nil {
+		t.Fatalf("Failed to set profile: %v", err)
+	}
+
+	if err := store.SetHashtagProfile(HashtagProfile{Tag: "work", Visibility: "unlisted"}); err != nil {
+		t.Fatalf("Failed to set profile: %v", err)
+	}
+
+	// Lookups are case-insensitive and ignore the leading #
+	retrieved, err := store.GetHashtagProfile("nsfw")
+	if err != nil {
+		t.Fatalf("Failed to get profile: %v", err)
+	}
+	if retrieved == nil {
+		t.Fatal("Expected profile for nsfw, got nil")
+	}
+	if !retrieved.Sensitive || retrieved.SpoilerText != "NSFW" {
+		t.Errorf("Unexpected profile: %+v", retrieved)
+	}
+
+	profiles, err := store.ListHashtagProfiles()
+	if err != nil {
+		t.Fatalf("Failed to list profiles: %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Tag != "nsfw" || profiles[1].Tag != "work" {
+		t.Errorf("Unexpected profiles: %+v", profiles)
+	}
+
+	if err := store.RemoveHashtagProfile("#Work"); err != nil {
+		t.Fatalf("Failed to remove profile: %v", err)
+	}
+
+	removed, err := store.GetHashtagProfile("work")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if removed != nil {
+		t.Errorf("Expected nil after remove, got %+v", removed)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Reboost is a status to unboost and boost again at a set time, so it shows
+// up in followers' timelines again. One with an interval repeats, rotating an
+// evergreen post back to the top; one without runs once.
+type Reboost struct {
+	ID       int64
+	StatusID string
+	NextAt   time.Time
+	// Every is the time between reboosts, or zero for a one-off
+	Every time.Duration
+	// LastAt is when it was last boosted again, or zero if it hasn't been
+	LastAt time.Time
+	// Error is why the last attempt failed, if it did
+	Error string
+	// Failures is how many attempts in a row have failed
+	Failures  int
+	CreatedAt time.Time
+}
+
+// AddReboost schedules a reboost for the current account and returns its ID
+func (s *Store) AddReboost(statusID string, nextAt time.Time, every time.Duration) (int64, error) {
+	account, err := s.Account()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.db.Exec(
+		"INSERT INTO reboosts (account, status_id, next_at, every_seconds) VALUES (?, ?, ?, ?)",
+		account, statusID, nextAt.UTC(), int64(every/time.Second),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// ListReboosts returns the current account's reboosts, soonest first
+func (s *Store) ListReboosts() ([]*Reboost, error) {
+	account, err := s.Account()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(
+		"SELECT id, status_id, next_at, every_seconds, last_at, error, failures, created_at FROM reboosts WHERE account = ? ORDER BY next_at, id",
+		account,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reboosts []*Reboost
+	for rows.Next() {
+		var reboost Reboost
+		var everySeconds int64
+		var lastAt sql.NullTime
+		if err := rows.Scan(&reboost.ID, &reboost.StatusID, &reboost.NextAt, &everySeconds, &lastAt, &reboost.Error, &reboost.Failures, &reboost.CreatedAt); err != nil {
+			return nil, err
+		}
+		reboost.Every = time.Duration(everySeconds) * time.Second
+		reboost.LastAt = lastAt.Time
+		reboosts = append(reboosts, &reboost)
+	}
+	return reboosts, rows.Err()
+}
+
+// DueReboosts returns the current account's reboosts that are due at now,
+// soonest first
+func (s *Store) DueReboosts(now time.Time) ([]*Reboost, error) {
+	reboosts, err := s.ListReboosts()
+	if err != nil {
+		return nil, err
+	}
+
+	var due []*Reboost
+	for _, reboost := range reboosts {
+		if reboost.NextAt.After(now) {
+			break
+		}
+		due = append(due, reboost)
+	}
+	return due, nil
+}
+
+// RecordReboost notes an attempt at a reboost, made at lastAt, and when to
+// try next. errMsg is why it failed, or empty if it didn't; a success resets
+// the count of failures.
+func (s *Store) RecordReboost(id int64, lastAt, nextAt time.Time, errMsg string) error {
+	_, err := s.db.Exec(
+		"UPDATE reboosts SET last_at = ?, next_at = ?, error = ?, failures = CASE WHEN ? = '' THEN 0 ELSE failures + 1 END WHERE id = ?",
+		lastAt.UTC(), nextAt.UTC(), errMsg, errMsg, id,
+	)
+	return err
+}
+
+// RemoveReboost deletes one of the current account's reboosts. It reports
+// false if there was no such reboost.
+func (s *Store) RemoveReboost(id int64) (bool, error) {
+	account, err := s.Account()
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.db.Exec("DELETE FROM reboosts WHERE id = ? AND account = ?", id, account)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
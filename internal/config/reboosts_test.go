@@ -0,0 +1,66 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReboosts(t *testing.T) {
+	t.Setenv(DatabaseEnv, filepath.Join(t.TempDir(), "tusk.db"))
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	now := time.Now().Truncate(time.Second)
+	weekly, err := store.AddReboost("100", now.Add(time.Hour), 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to add reboost: %v", err)
+	}
+	once, err := store.AddReboost("200", now.Add(-time.Minute), 0)
+	if err != nil {
+		t.Fatalf("Failed to add reboost: %v", err)
+	}
+
+	due, err := store.DueReboosts(now)
+	if err != nil {
+		t.Fatalf("Failed to get due reboosts: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != once || due[0].Every != 0 || !due[0].LastAt.IsZero() {
+		t.Errorf("Expected only the one-off to be due, got %+v", due)
+	}
+
+	next := now.Add(8 * 24 * time.Hour)
+	if err := store.RecordReboost(weekly, now, next, "rate limited"); err != nil {
+		t.Fatalf("Failed to record reboost: %v", err)
+	}
+
+	reboosts, err := store.ListReboosts()
+	if err != nil {
+		t.Fatalf("Failed to list reboosts: %v", err)
+	}
+	if len(reboosts) != 2 || reboosts[0].ID != once {
+		t.Fatalf("Expected the one-off first, got %+v", reboosts)
+	}
+	if r := reboosts[1]; r.Every != 7*24*time.Hour || !r.NextAt.Equal(next) || !r.LastAt.Equal(now) || r.Error != "rate limited" || r.Failures != 1 {
+		t.Errorf("Unexpected recorded reboost %+v", r)
+	}
+
+	// A success clears the failures
+	store.RecordReboost(weekly, next, next.Add(7*24*time.Hour), "")
+	reboosts, _ = store.ListReboosts()
+	if r := reboosts[1]; r.Error != "" || r.Failures != 0 {
+		t.Errorf("Expected the failures to be cleared, got %+v", r)
+	}
+
+	removed, err := store.RemoveReboost(once)
+	if err != nil || !removed {
+		t.Errorf("Expected the reboost to be removed, got %v, %v", removed, err)
+	}
+	if removed, _ := store.RemoveReboost(once); removed {
+		t.Error("Expected a second removal to find nothing")
+	}
+}
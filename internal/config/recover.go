@@ -0,0 +1,128 @@
+package config
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// SQLite primary result codes for a damaged database file
+const (
+	sqliteCorrupt = 11
+	sqliteNotADB  = 26
+)
+
+// CorruptError is returned by NewStore when the database file is damaged.
+// Recover can replace it with a fresh one.
+type CorruptError struct {
+	Path string
+	Err  error
+}
+
+func (e *CorruptError) Error() string {
+	return fmt.Sprintf("database %s is corrupted: %v", e.Path, e.Err)
+}
+
+func (e *CorruptError) Unwrap() error {
+	return e.Err
+}
+
+func isCorruption(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff
+		return code == sqliteCorrupt || code == sqliteNotADB
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "malformed") || strings.Contains(msg, "not a database")
+}
+
+// Recovery describes what Recover did
+type Recovery struct {
+	BackupPath string
+	Salvaged   []string // config keys carried over to the settings file
+}
+
+// Recover moves a damaged database aside and creates a fresh one. Your login
+// and settings are kept in their own file, but a database older versions of
+// tusk left may still hold them, so whatever config rows can be read from it
+// are salvaged into the settings file. Post history, jobs, and caches are not
+// carried over.
+func Recover() (*Recovery, error) {
+	dbPath, err := DatabasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	backupPath := fmt.Sprintf("%s.corrupt-%s", dbPath, time.Now().Format("20060102-150405"))
+	if err := os.Rename(dbPath, backupPath); err != nil {
+		return nil, fmt.Errorf("failed to back up damaged database: %w", err)
+	}
+
+	// A journal left next to the old file would be applied to the new one
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if _, err := os.Stat(dbPath + suffix); err == nil {
+			os.Rename(dbPath+suffix, backupPath+suffix)
+		}
+	}
+
+	salvaged := salvageConfig(backupPath)
+
+	store, err := NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new database: %w", err)
+	}
+	defer store.Close()
+
+	recovery := &Recovery{BackupPath: backupPath}
+	for key, value := range salvaged {
+		if existing, err := store.Get(key); err != nil || existing != "" {
+			continue
+		}
+		if err := store.Set(key, value); err == nil {
+			recovery.Salvaged = append(recovery.Salvaged, key)
+		}
+	}
+
+	return recovery, nil
+}
+
+// salvageConfig reads as many config rows as it can from a damaged database.
+// Rows are read one at a time so a bad page only loses the rows on it.
+func salvageConfig(path string) map[string]string {
+	salvaged := make(map[string]string)
+
+	db, err := sql.Open("sqlite", databaseURI(path, "mode=ro"))
+	if err != nil {
+		return salvaged
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT key FROM config")
+	if err != nil {
+		return salvaged
+	}
+	var keys []string
+	for rows.Next() {
+		var key string
+		if rows.Scan(&key) == nil {
+			keys = append(keys, key)
+		}
+	}
+	rows.Close()
+
+	for _, key := range keys {
+		var value string
+		if db.QueryRow("SELECT value FROM config WHERE key = ?", key).Scan(&value) == nil {
+			salvaged[key] = value
+		}
+	}
+
+	return salvaged
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestRecoverCorruptDatabase(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	oldXDG := os.Getenv("XDG_DATA_HOME")
+	defer os.Setenv("HOME", oldHome)
+	defer os.Setenv("XDG_DATA_HOME", oldXDG)
+	os.Setenv("HOME", tmpDir)
+	os.Unsetenv("XDG_DATA_HOME")
+
+	dbPath, err := DatabasePath()
+	if err != nil {
+		t.Fatalf("Failed to get database path: %v", err)
+	}
+
+	garbage := make([]byte, 4096)
+	for i := range garbage {
+		garbage[i] = byte(i * 7)
+	}
+	if err := os.WriteFile(dbPath, garbage, 0600); err != nil {
+		t.Fatalf("Failed to write damaged database: %v", err)
+	}
+
+	_, err = NewStore()
+	var corrupt *CorruptError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("Expected CorruptError, got %v", err)
+	}
+
+	recovery, err := Recover()
+	if err != nil {
+		t.Fatalf("Failed to recover: %v", err)
+	}
+
+	if _, err := os.Stat(recovery.BackupPath); err != nil {
+		t.Errorf("Expected backup at %s: %v", recovery.BackupPath, err)
+	}
+	if len(recovery.Salvaged) != 0 {
+		t.Errorf("Expected nothing salvaged from garbage, got %v", recovery.Salvaged)
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Expected a working store after recovery: %v", err)
+	}
+	store.Close()
+}
+
+func TestSalvageConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	oldXDG := os.Getenv("XDG_DATA_HOME")
+	defer os.Setenv("HOME", oldHome)
+	defer os.Setenv("XDG_DATA_HOME", oldXDG)
+	os.Setenv("HOME", tmpDir)
+	os.Unsetenv("XDG_DATA_HOME")
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	// As an older version of tusk would have kept them
+	store.db.Exec("INSERT INTO config (key, value) VALUES ('domain', 'https://example.com'), ('access_token', 'secret')")
+	store.Close()
+
+	dbPath, _ := DatabasePath()
+	salvaged := salvageConfig(dbPath)
+
+	if salvaged["domain"] != "https://example.com" || salvaged["access_token"] != "secret" {
+		t.Errorf("Unexpected salvaged config: %v", salvaged)
+	}
+
+	if got := salvageConfig(dbPath + ".missing"); len(got) != 0 {
+		t.Errorf("Expected nothing from a missing file, got %v", got)
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"time"
+)
+
+// Reminder is a note to yourself to post something, shown once it's due
+type Reminder struct {
+	ID        int64
+	Text      string
+	DueAt     time.Time
+	CreatedAt time.Time
+}
+
+// AddReminder saves a reminder for the current account and returns its ID
+func (s *Store) AddReminder(text string, dueAt time.Time) (int64, error) {
+	account, err := s.Account()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.db.Exec(
+		"INSERT INTO reminders (account, text, due_at) VALUES (?, ?, ?)",
+		account, text, dueAt.UTC(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// ListReminders returns the current account's reminders, soonest first
+func (s *Store) ListReminders() ([]*Reminder, error) {
+	account, err := s.Account()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(
+		"SELECT id, text, due_at, created_at FROM reminders WHERE account = ? ORDER BY due_at, id",
+		account,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*Reminder
+	for rows.Next() {
+		var reminder Reminder
+		if err := rows.Scan(&reminder.ID, &reminder.Text, &reminder.DueAt, &reminder.CreatedAt); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, &reminder)
+	}
+	return reminders, rows.Err()
+}
+
+// DueReminders returns the current account's reminders that are due at now,
+// soonest first
+func (s *Store) DueReminders(now time.Time) ([]*Reminder, error) {
+	reminders, err := s.ListReminders()
+	if err != nil {
+		return nil, err
+	}
+
+	var due []*Reminder
+	for _, reminder := range reminders {
+		if reminder.DueAt.After(now) {
+			break
+		}
+		due = append(due, reminder)
+	}
+	return due, nil
+}
+
+// SnoozeReminder moves a reminder to a later time
+func (s *Store) SnoozeReminder(id int64, until time.Time) error {
+	_, err := s.db.Exec("UPDATE reminders SET due_at = ? WHERE id = ?", until.UTC(), id)
+	return err
+}
+
+// RemoveReminder deletes one of the current account's reminders. It reports
+// false if there was no such reminder.
+func (s *Store) RemoveReminder(id int64) (bool, error) {
+	account, err := s.Account()
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.db.Exec("DELETE FROM reminders WHERE id = ? AND account = ?", id, account)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReminders(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	now := time.Date(2026, 10, 15, 17, 0, 0, 0, time.Local)
+	later, err := store.AddReminder("Post the release notes", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to add reminder: %v", err)
+	}
+	due, err := store.AddReminder("Share the photos", now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to add reminder: %v", err)
+	}
+
+	reminders, err := store.ListReminders()
+	if err != nil {
+		t.Fatalf("Failed to list reminders: %v", err)
+	}
+	if len(reminders) != 2 || reminders[0].ID != due || reminders[1].ID != later {
+		t.Fatalf("Expected both reminders, soonest first, got %+v", reminders)
+	}
+	if !reminders[1].DueAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected the reminder due at %s, got %s", now.Add(time.Hour), reminders[1].DueAt)
+	}
+
+	dueNow, err := store.DueReminders(now)
+	if err != nil {
+		t.Fatalf("Failed to get due reminders: %v", err)
+	}
+	if len(dueNow) != 1 || dueNow[0].Text != "Share the photos" {
+		t.Errorf("Expected only the overdue reminder, got %+v", dueNow)
+	}
+
+	if err := store.SnoozeReminder(due, now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Failed to snooze reminder: %v", err)
+	}
+	if dueNow, _ := store.DueReminders(now); len(dueNow) != 0 {
+		t.Errorf("Expected no due reminders after snoozing, got %+v", dueNow)
+	}
+
+	removed, err := store.RemoveReminder(later)
+	if err != nil || !removed {
+		t.Fatalf("Failed to remove reminder: %v", err)
+	}
+	if removed, _ := store.RemoveReminder(later); removed {
+		t.Error("Expected removing a reminder twice to report false")
+	}
+	if reminders, _ := store.ListReminders(); len(reminders) != 1 {
+		t.Errorf("Expected one reminder left, got %d", len(reminders))
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import "biesnecker.com/tusk/pkg/mastodon"
+
+// CachedResponse returns the cached response for a request URL, or nil if
+// there is none. It lets the store serve as the client's ResponseCache.
+func (s *Store) CachedResponse(key string) *mastodon.CachedResponse {
+	var cached mastodon.CachedResponse
+	err := s.db.QueryRow(
+		"SELECT body, content_type, etag, stored_at FROM response_cache WHERE key = ?",
+		key,
+	).Scan(&cached.Body, &cached.ContentType, &cached.ETag, &cached.StoredAt)
+	if err != nil {
+		return nil
+	}
+	return &cached
+}
+
+// StoreResponse caches or refreshes the response for a request URL
+func (s *Store) StoreResponse(key string, resp *mastodon.CachedResponse) {
+	s.db.Exec(
+		"INSERT OR REPLACE INTO response_cache (key, body, content_type, etag, stored_at) VALUES (?, ?, ?, ?, ?)",
+		key, resp.Body, resp.ContentType, resp.ETag, resp.StoredAt.UTC(),
+	)
+}
+
+// ClearResponseCache forgets every cached response
+func (s *Store) ClearResponseCache() error {
+	_, err := s.db.Exec("DELETE FROM response_cache")
+	return err
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+func TestResponseCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	key := "https://one.example/api/v1/instance"
+	if cached := store.CachedResponse(key); cached != nil {
+		t.Errorf("Expected nothing cached, got %+v", cached)
+	}
+
+	storedAt := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	store.StoreResponse(key, &mastodon.CachedResponse{
+		Body:        []byte(`{"uri":"one.example"}`),
+		ContentType: "application/json",
+		ETag:        `W/"abc"`,
+		StoredAt:    storedAt,
+	})
+
+	cached := store.CachedResponse(key)
+	if cached == nil {
+		t.Fatal("Expected the response to be cached")
+	}
+	if string(cached.Body) != `{"uri":"one.example"}` || cached.ETag != `W/"abc"` {
+		t.Errorf("Expected the stored body and ETag, got %+v", cached)
+	}
+	if !cached.StoredAt.Equal(storedAt) {
+		t.Errorf("Expected stored at %v, got %v", storedAt, cached.StoredAt)
+	}
+
+	if err := store.ClearResponseCache(); err != nil {
+		t.Fatalf("Failed to clear the cache: %v", err)
+	}
+	if cached := store.CachedResponse(key); cached != nil {
+		t.Errorf("Expected the cache to be empty, got %+v", cached)
+	}
+}
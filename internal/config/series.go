@@ -0,0 +1,105 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSeriesTemplate labels each post in a series when no template is given
+const DefaultSeriesTemplate = "Day {n}"
+
+// Series is a named run of posts, each labelled with its number
+type Series struct {
+	Name         string
+	Template     string
+	Count        int
+	LastPostedAt time.Time
+}
+
+// Label is the template filled in for post n of the series
+func (s *Series) Label(n int) string {
+	return strings.ReplaceAll(s.Template, "{n}", strconv.Itoa(n))
+}
+
+// Next is the number the next post in the series will get
+func (s *Series) Next() int {
+	return s.Count + 1
+}
+
+// CreateSeries adds a series whose first post will be numbered start
+func (s *Store) CreateSeries(name, template string, start int) error {
+	if template == "" {
+		template = DefaultSeriesTemplate
+	}
+
+	_, err := s.db.Exec(
+		"INSERT INTO series (name, template, count) VALUES (?, ?, ?)",
+		name, template, start-1,
+	)
+	if err != nil {
+		if existing, getErr := s.GetSeries(name); getErr == nil && existing != nil {
+			return fmt.Errorf("series %q already exists", existing.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetSeries returns a series by name, ignoring case, or nil if there is none
+func (s *Store) GetSeries(name string) (*Series, error) {
+	row := s.db.QueryRow("SELECT name, template, count, last_posted_at FROM series WHERE name = ?", name)
+	series, err := scanSeries(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return series, err
+}
+
+// ListSeries returns every series, ordered by name
+func (s *Store) ListSeries() ([]*Series, error) {
+	rows, err := s.db.Query("SELECT name, template, count, last_posted_at FROM series ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*Series
+	for rows.Next() {
+		series, err := scanSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, series)
+	}
+	return list, rows.Err()
+}
+
+// AdvanceSeries records that post n of a series was posted
+func (s *Store) AdvanceSeries(name string, n int) error {
+	_, err := s.db.Exec(
+		"UPDATE series SET count = ?, last_posted_at = ? WHERE name = ?",
+		n, time.Now().UTC(), name,
+	)
+	return err
+}
+
+// RemoveSeries deletes a series
+func (s *Store) RemoveSeries(name string) error {
+	_, err := s.db.Exec("DELETE FROM series WHERE name = ?", name)
+	return err
+}
+
+func scanSeries(row rowScanner) (*Series, error) {
+	var series Series
+	var lastPosted sql.NullTime
+	if err := row.Scan(&series.Name, &series.Template, &series.Count, &lastPosted); err != nil {
+		return nil, err
+	}
+	if lastPosted.Valid {
+		series.LastPostedAt = lastPosted.Time
+	}
+	return &series, nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSeries(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.CreateSeries("Daily Sketch", "", 1); err != nil {
+		t.Fatalf("Failed to create series: %v", err)
+	}
+	if err := store.CreateSeries("Reading", "Book {n} of 52", 3); err != nil {
+		t.Fatalf("Failed to create series: %v", err)
+	}
+	if err := store.CreateSeries("daily sketch", "", 1); err == nil {
+		t.Error("Expected an error creating a duplicate series")
+	}
+
+	// Lookups ignore case
+	series, err := store.GetSeries("daily sketch")
+	if err != nil {
+		t.Fatalf("Failed to get series: %v", err)
+	}
+	if series == nil {
+		t.Fatal("Expected series, got nil")
+	}
+	if series.Next() != 1 || series.Label(series.Next()) != "Day 1" {
+		t.Errorf("Unexpected next post: %d %q", series.Next(), series.Label(series.Next()))
+	}
+	if !series.LastPostedAt.IsZero() {
+		t.Errorf("Expected no last post, got %v", series.LastPostedAt)
+	}
+
+	if err := store.AdvanceSeries("Daily Sketch", series.Next()); err != nil {
+		t.Fatalf("Failed to advance series: %v", err)
+	}
+	series, _ = store.GetSeries("Daily Sketch")
+	if series.Count != 1 || series.Label(series.Next()) != "Day 2" || series.LastPostedAt.IsZero() {
+		t.Errorf("Unexpected series after advancing: %+v", series)
+	}
+
+	reading, _ := store.GetSeries("Reading")
+	if reading.Label(reading.Next()) != "Book 3 of 52" {
+		t.Errorf("Expected 'Book 3 of 52', got %q", reading.Label(reading.Next()))
+	}
+
+	list, err := store.ListSeries()
+	if err != nil {
+		t.Fatalf("Failed to list series: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "Daily Sketch" || list[1].Name != "Reading" {
+		t.Errorf("Unexpected series list: %+v", list)
+	}
+
+	if err := store.RemoveSeries("reading"); err != nil {
+		t.Fatalf("Failed to remove series: %v", err)
+	}
+	if missing, _ := store.GetSeries("Reading"); missing != nil {
+		t.Errorf("Expected series to be removed, got %+v", missing)
+	}
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"biesnecker.com/tusk/internal/filelock"
+)
+
+const settingsName = "config.json"
+
+// configDir returns the directory tusk keeps your login and settings in by
+// default. XDG_CONFIG_HOME is honored on every platform; without it each
+// platform's usual place for configuration is used.
+func configDir() (string, error) {
+	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
+		return filepath.Join(xdgConfig, "tusk"), nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tusk"), nil
+}
+
+// SettingsPath returns the path of the file holding your login and settings,
+// creating its directory if needed. A database chosen with TUSK_DB has its own
+// file beside it, named after it, so each database stays a separate account.
+func SettingsPath() (string, error) {
+	var path string
+	if os.Getenv(DatabaseEnv) != "" {
+		dbPath, err := DatabasePath()
+		if err != nil {
+			return "", err
+		}
+		path = strings.TrimSuffix(dbPath, filepath.Ext(dbPath)) + ".json"
+		if path == dbPath {
+			path += ".json"
+		}
+	} else {
+		dir, err := configDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get config directory: %w", err)
+		}
+		path = filepath.Join(dir, settingsName)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// settingsFile is the JSON file of config keys, kept apart from the database
+// so that configuration and data live where each platform expects them. Only
+// you can read it, since it holds your access token.
+type settingsFile struct {
+	path string
+}
+
+// read returns every key in the file, or none if it doesn't exist yet
+func (f settingsFile) read() (map[string]string, error) {
+	settings := make(map[string]string)
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("settings file %s is damaged: %w", f.path, err)
+	}
+	return settings, nil
+}
+
+// update changes the file's keys with change. A lock beside the file keeps
+// other tusk processes from changing it at the same time, and the new
+// contents replace the old in one rename, so readers never see half a file.
+func (f settingsFile) update(change func(settings map[string]string) error) error {
+	lock, err := os.OpenFile(f.path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open settings lock: %w", err)
+	}
+	defer lock.Close()
+	if err := filelock.Lock(lock); err != nil {
+		return fmt.Errorf("failed to lock settings: %w", err)
+	}
+	defer filelock.Unlock(lock)
+
+	settings, err := f.read()
+	if err != nil {
+		return err
+	}
+	if err := change(settings); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	incoming := f.path + ".tmp"
+	if err := writePrivateFile(incoming, append(data, '\n')); err != nil {
+		os.Remove(incoming)
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+	if err := os.Rename(incoming, f.path); err != nil {
+		os.Remove(incoming)
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+	return nil
+}
+
+// writePrivateFile writes data to a file only you can read, syncing it so a
+// crash can't leave it empty once renamed into place
+func writePrivateFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// moveSettings moves the rows of the database's config table into the
+// settings file. Older versions of tusk kept your login and settings there,
+// and backups still carry them there. Keys already in the file are kept.
+func (s *Store) moveSettings() error {
+	rows, err := s.db.Query("SELECT key, value FROM config")
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			rows.Close()
+			return err
+		}
+		stored[key] = value
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(stored) == 0 {
+		return nil
+	}
+
+	err = s.settings.update(func(settings map[string]string) error {
+		for key, value := range stored {
+			if _, ok := settings[key]; !ok {
+				settings[key] = value
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec("DELETE FROM config")
+	return err
+}
+
+// saveSettingsTo writes settings into the config table of the database at
+// path, so that a copy of the database carries them
+func saveSettingsTo(path string, settings map[string]string) error {
+	db, err := sql.Open("sqlite", databaseURI(path, "_pragma=busy_timeout(5000)"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM config"); err != nil {
+		return err
+	}
+	for key, value := range settings {
+		if _, err := tx.Exec("INSERT INTO config (key, value) VALUES (?, ?)", key, value); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
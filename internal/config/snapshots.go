@@ -0,0 +1,48 @@
+package config
+
+import "time"
+
+// AccountSnapshot is an account's counts at one point in time, kept to show
+// how its followers grow
+type AccountSnapshot struct {
+	AccountID string
+	Followers int
+	Following int
+	Statuses  int
+	TakenAt   time.Time
+}
+
+// RecordAccountSnapshot saves an account's current counts. Only the latest
+// snapshot of each day is kept.
+func (s *Store) RecordAccountSnapshot(snapshot AccountSnapshot) error {
+	_, err := s.db.Exec(
+		`INSERT OR REPLACE INTO account_snapshots (account_id, day, followers, following, statuses)
+		VALUES (?, date('now'), ?, ?, ?)`,
+		snapshot.AccountID, snapshot.Followers, snapshot.Following, snapshot.Statuses,
+	)
+	return err
+}
+
+// ListAccountSnapshots returns an account's snapshots taken since the given
+// time, oldest first
+func (s *Store) ListAccountSnapshots(accountID string, since time.Time) ([]*AccountSnapshot, error) {
+	rows, err := s.db.Query(
+		`SELECT account_id, followers, following, statuses, taken_at FROM account_snapshots
+		WHERE account_id = ? AND taken_at >= ? ORDER BY taken_at`,
+		accountID, since.UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []*AccountSnapshot
+	for rows.Next() {
+		var snapshot AccountSnapshot
+		if err := rows.Scan(&snapshot.AccountID, &snapshot.Followers, &snapshot.Following, &snapshot.Statuses, &snapshot.TakenAt); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAccountSnapshots(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	// A second snapshot on the same day replaces the first
+	store.RecordAccountSnapshot(AccountSnapshot{AccountID: "42", Followers: 10, Following: 5, Statuses: 100})
+	if err := store.RecordAccountSnapshot(AccountSnapshot{AccountID: "42", Followers: 12, Following: 5, Statuses: 101}); err != nil {
+		t.Fatalf("Failed to record snapshot: %v", err)
+	}
+	store.RecordAccountSnapshot(AccountSnapshot{AccountID: "7", Followers: 3})
+
+	// An older snapshot, as if taken last week
+	store.db.Exec(`INSERT INTO account_snapshots (account_id, day, followers, following, statuses, taken_at)
+		VALUES ('42', date('now', '-7 days'), 8, 4, 90, datetime('now', '-7 days'))`)
+
+	snapshots, err := store.ListAccountSnapshots("42", time.Now().Add(-30*24*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to list snapshots: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %d", len(snapshots))
+	}
+	if snapshots[0].Followers != 8 || snapshots[1].Followers != 12 || snapshots[1].Statuses != 101 {
+		t.Errorf("Unexpected snapshots %+v, %+v", snapshots[0], snapshots[1])
+	}
+	if snapshots[1].TakenAt.IsZero() {
+		t.Error("Expected the snapshot time to be set")
+	}
+
+	recent, _ := store.ListAccountSnapshots("42", time.Now().Add(-24*time.Hour))
+	if len(recent) != 1 {
+		t.Errorf("Expected 1 snapshot in the last day, got %d", len(recent))
+	}
+}
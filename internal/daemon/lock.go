@@ -0,0 +1,186 @@
+// Package daemon keeps background tusk processes to one per account, using an
+// advisory lock on a file that also records the running process's PID.
+//
+// The operating system holds the lock for as long as the process keeps the
+// file open and drops it when the process exits, however it exits, so a lock
+// is never stale and the file is never removed: removing it would let a
+// starter that had already opened the old file and one that creates a new
+// file both think they hold the lock.
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"biesnecker.com/tusk/internal/filelock"
+)
+
+// infoWait is how long to wait for a process that has just taken the lock to
+// finish writing its details
+const infoWait = time.Second
+
+// Info describes the process holding a lock. PID is 0 when the holder hasn't
+// written its details yet.
+type Info struct {
+	PID       int       `json:"pid"`
+	Account   string    `json:"account"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// RunningError is returned by Acquire when another live process holds the lock
+type RunningError struct {
+	Info *Info
+}
+
+func (e *RunningError) Error() string {
+	if e.Info.PID == 0 {
+		return "a daemon is already running"
+	}
+	return fmt.Sprintf("a daemon is already running (PID %d)", e.Info.PID)
+}
+
+// Lock is a held lock, released with Release or when the process exits
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes the lock at path for the current process, recording its PID
+// and account in the file
+func Acquire(path, account string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	locked, err := filelock.TryLock(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	if !locked {
+		file.Close()
+		return nil, &RunningError{Info: waitForInfo(path)}
+	}
+
+	info := &Info{PID: os.Getpid(), Account: account, StartedAt: time.Now()}
+	if err := writeInfo(file, info); err != nil {
+		filelock.Unlock(file)
+		file.Close()
+		return nil, fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return &Lock{file: file}, nil
+}
+
+// Release clears the recorded details and gives up the lock
+func (l *Lock) Release() error {
+	truncErr := l.file.Truncate(0)
+	unlockErr := filelock.Unlock(l.file)
+	closeErr := l.file.Close()
+	return errors.Join(truncErr, unlockErr, closeErr)
+}
+
+// Status returns the process holding the lock at path, or nil if none does
+func Status(path string) (*Info, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	defer file.Close()
+
+	locked, err := filelock.TryLock(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check lock %s: %w", path, err)
+	}
+	if locked {
+		// Nobody holds it; whatever the file says is left over
+		filelock.Unlock(file)
+		return nil, nil
+	}
+	return waitForInfo(path), nil
+}
+
+// Stop asks the process holding the lock at path to exit and waits up to
+// timeout for it to go. It returns the stopped process, or nil if none was
+// running.
+func Stop(path string, timeout time.Duration) (*Info, error) {
+	info, err := Status(path)
+	if err != nil || info == nil {
+		return nil, err
+	}
+	if info.PID == 0 {
+		return nil, errors.New("the daemon is still starting; try again in a moment")
+	}
+
+	if err := terminate(info.PID); err != nil {
+		return nil, fmt.Errorf("failed to stop PID %d: %w", info.PID, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !processAlive(info.PID) {
+			return info, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return nil, fmt.Errorf("PID %d did not exit within %s", info.PID, timeout)
+}
+
+// writeInfo replaces the lock file's contents with info
+func writeInfo(file *os.File, info *Info) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := file.WriteAt(data, 0); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// waitForInfo reads the details of the process holding the lock at path,
+// giving one that has only just taken it a moment to write them. If they
+// still can't be read, the holder is reported with PID 0.
+func waitForInfo(path string) *Info {
+	deadline := time.Now().Add(infoWait)
+	for {
+		if info := readInfo(path); info != nil {
+			return info
+		}
+		if time.Now().After(deadline) {
+			return &Info{}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// readInfo reads a lock file's details, or returns nil if they're missing or
+// only partly written
+func readInfo(path string) *Info {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil
+	}
+
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil || info.PID <= 0 {
+		return nil
+	}
+	return &info
+}
@@ -0,0 +1,124 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"biesnecker.com/tusk/internal/filelock"
+)
+
+func TestAcquireAndRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.lock")
+
+	lock, err := Acquire(path, "me@example.com")
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+
+	info, err := Status(path)
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+	if info == nil || info.PID != os.Getpid() || info.Account != "me@example.com" {
+		t.Errorf("Unexpected lock info: %+v", info)
+	}
+
+	// A second daemon is turned away while the first is alive
+	_, err = Acquire(path, "me@example.com")
+	var running *RunningError
+	if !errors.As(err, &running) {
+		t.Fatalf("Expected RunningError, got %v", err)
+	}
+	if running.Info.PID != os.Getpid() {
+		t.Errorf("Expected holder PID %d, got %d", os.Getpid(), running.Info.PID)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Failed to release lock: %v", err)
+	}
+	if info, _ := Status(path); info != nil {
+		t.Errorf("Expected no holder after release, got %+v", info)
+	}
+
+	if _, err := Acquire(path, "me@example.com"); err != nil {
+		t.Errorf("Failed to acquire released lock: %v", err)
+	}
+}
+
+func TestAcquireStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.lock")
+
+	// Borrow the PID of a process that has already exited
+	child := exec.Command(os.Args[0], "-test.run=^$")
+	if err := child.Run(); err != nil {
+		t.Fatalf("Failed to run child process: %v", err)
+	}
+	stale := fmt.Sprintf(`{"pid":%d,"account":"me@example.com","started_at":%q}`, child.Process.Pid, time.Now().Format(time.RFC3339))
+	if err := os.WriteFile(path, []byte(stale), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := Status(path); err != nil || info != nil {
+		t.Errorf("Expected stale lock to report no holder, got %+v, %v", info, err)
+	}
+
+	os.WriteFile(path, []byte(stale), 0600)
+	lock, err := Acquire(path, "me@example.com")
+	if err != nil {
+		t.Fatalf("Failed to take over stale lock: %v", err)
+	}
+	defer lock.Release()
+
+	if info, _ := Status(path); info == nil || info.PID != os.Getpid() {
+		t.Errorf("Expected lock to belong to this process, got %+v", info)
+	}
+}
+
+func TestAcquireUnreadableLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.lock")
+	os.WriteFile(path, []byte("{"), 0600)
+
+	lock, err := Acquire(path, "")
+	if err != nil {
+		t.Fatalf("Failed to take over half-written lock: %v", err)
+	}
+	lock.Release()
+}
+
+func TestAcquireWhileHolderStarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.lock")
+
+	// Another starter has taken the lock but not yet written its details
+	holder, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+	if locked, err := filelock.TryLock(holder); err != nil || !locked {
+		t.Fatalf("Failed to lock: %v, %v", locked, err)
+	}
+
+	_, err = Acquire(path, "")
+	var running *RunningError
+	if !errors.As(err, &running) {
+		t.Fatalf("Expected RunningError for a lock still being written, got %v", err)
+	}
+	if running.Info.PID != 0 {
+		t.Errorf("Expected an unknown PID, got %d", running.Info.PID)
+	}
+	if _, err := Stop(path, time.Second); err == nil {
+		t.Error("Expected Stop to refuse a holder whose PID isn't known")
+	}
+
+	filelock.Unlock(holder)
+	lock, err := Acquire(path, "")
+	if err != nil {
+		t.Fatalf("Failed to acquire lock once released: %v", err)
+	}
+	lock.Release()
+}
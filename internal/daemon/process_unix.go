@@ -0,0 +1,30 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	// EPERM means it exists but belongs to someone else
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// terminate asks a process to exit, giving it the chance to finish up
+func terminate(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
+
+// Detach starts cmd in a session of its own, so closing the terminal that
+// started it, or Ctrl+C there, doesn't reach it
+func Detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
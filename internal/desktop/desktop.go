@@ -0,0 +1,82 @@
+// Package desktop raises native desktop notifications with the tools each
+// platform ships: notify-send on Linux and the BSDs, osascript on macOS, and
+// a PowerShell toast on Windows.
+package desktop
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// timeout bounds how long a notifier may take, so a hung one can't stall a
+// watch loop
+const timeout = 10 * time.Second
+
+// Notify shows a notification with a title and body
+func Notify(title, body string) error {
+	name, args, env, err := command(runtime.GOOS, title, body)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%s isn't installed, so desktop notifications can't be shown", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s failed: %s", name, msg)
+		}
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
+
+// command is the program, arguments, and any extra environment variables
+// that show a notification on goos
+func command(goos, title, body string) (string, []string, []string, error) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return "osascript", []string{"-e", script}, nil, nil
+	case "windows":
+		env := []string{"TUSK_TITLE=" + title, "TUSK_BODY=" + body}
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", toastScript}, env, nil
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return "notify-send", []string{"--app-name=tusk", "--", title, body}, nil, nil
+	}
+	return "", nil, nil, fmt.Errorf("desktop notifications aren't supported on %s", goos)
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// toastScript shows a toast through the Windows Runtime, which PowerShell
+// can reach without any modules installed. The title and body come from
+// other people's posts and names, so they're passed in the environment
+// rather than written into the script, where quoting them safely is harder
+// than it looks: PowerShell also ends a quoted string at curly quotes.
+var toastScript = strings.Join([]string{
+	"[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null",
+	"$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)",
+	"$text = $xml.GetElementsByTagName('text')",
+	"$text.Item(0).AppendChild($xml.CreateTextNode($env:TUSK_TITLE)) | Out-Null",
+	"$text.Item(1).AppendChild($xml.CreateTextNode($env:TUSK_BODY)) | Out-Null",
+	"$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)",
+	"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('tusk').Show($toast)",
+}, "; ")
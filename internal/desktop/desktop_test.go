@@ -0,0 +1,60 @@
+package desktop
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCommandLinux(t *testing.T) {
+	name, args, _, err := command("linux", "-title", "body")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if name != "notify-send" {
+		t.Errorf("Expected notify-send, got %s", name)
+	}
+	// A title starting with - mustn't be read as a flag
+	if got := strings.Join(args, " "); got != "--app-name=tusk -- -title body" {
+		t.Errorf("Unexpected arguments %q", got)
+	}
+}
+
+func TestCommandDarwinQuotes(t *testing.T) {
+	name, args, _, err := command("darwin", `Say "hi"`, `back\slash`)
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if name != "osascript" {
+		t.Errorf("Expected osascript, got %s", name)
+	}
+	want := `display notification "back\\slash" with title "Say \"hi\""`
+	if len(args) != 2 || args[1] != want {
+		t.Errorf("Expected script %q, got %q", want, args)
+	}
+}
+
+func TestCommandWindowsKeepsTextOutOfScript(t *testing.T) {
+	// Curly quotes end a PowerShell string just as ' does
+	title := "It’s me"
+	body := "‘); Remove-Item C:\\important; (’"
+	name, args, env, err := command("windows", title, body)
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if name != "powershell" {
+		t.Errorf("Expected powershell, got %s", name)
+	}
+	if script := args[len(args)-1]; strings.Contains(script, "’") || strings.Contains(script, "Remove-Item") {
+		t.Errorf("Expected the text kept out of the script, got %q", script)
+	}
+	if want := []string{"TUSK_TITLE=" + title, "TUSK_BODY=" + body}; !slices.Equal(env, want) {
+		t.Errorf("Expected %q in the environment, got %q", want, env)
+	}
+}
+
+func TestCommandUnsupported(t *testing.T) {
+	if _, _, _, err := command("plan9", "title", "body"); err == nil {
+		t.Error("Expected an error for an unsupported platform")
+	}
+}
@@ -0,0 +1,82 @@
+package diff
+
+import "strings"
+
+// Op describes how a line changed between two texts
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Line is a single line of a diff
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines computes a line-by-line diff that turns a into b, using the longest
+// common subsequence of lines. Posts are short, so the quadratic table is fine.
+func Lines(a, b string) []Line {
+	oldLines := splitLines(a)
+	newLines := splitLines(b)
+
+	// lcs[i][j] is the LCS length of oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(oldLines) && j < len(newLines) {
+		switch {
+		case oldLines[i] == newLines[j]:
+			lines = append(lines, Line{Op: Equal, Text: oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: Delete, Text: oldLines[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: newLines[j]})
+			j++
+		}
+	}
+	for ; i < len(oldLines); i++ {
+		lines = append(lines, Line{Op: Delete, Text: oldLines[i]})
+	}
+	for ; j < len(newLines); j++ {
+		lines = append(lines, Line{Op: Insert, Text: newLines[j]})
+	}
+
+	return lines
+}
+
+// Changed reports whether a diff contains any insertions or deletions
+func Changed(lines []Line) bool {
+	for _, line := range lines {
+		if line.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
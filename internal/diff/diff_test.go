@@ -0,0 +1,57 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected []Line
+	}{
+		{
+			name:     "identical",
+			a:        "one\ntwo",
+			b:        "one\ntwo",
+			expected: []Line{{Equal, "one"}, {Equal, "two"}},
+		},
+		{
+			name:     "changed line",
+			a:        "one\ntwo\nthree",
+			b:        "one\n2\nthree",
+			expected: []Line{{Equal, "one"}, {Delete, "two"}, {Insert, "2"}, {Equal, "three"}},
+		},
+		{
+			name:     "appended line",
+			a:        "one",
+			b:        "one\ntwo",
+			expected: []Line{{Equal, "one"}, {Insert, "two"}},
+		},
+		{
+			name:     "removed everything",
+			a:        "one\ntwo",
+			b:        "",
+			expected: []Line{{Delete, "one"}, {Delete, "two"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lines(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Lines(%q, %q) = %v, expected %v", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChanged(t *testing.T) {
+	if Changed(Lines("same", "same")) {
+		t.Error("Expected identical texts to be unchanged")
+	}
+	if !Changed(Lines("before", "after")) {
+		t.Error("Expected different texts to be changed")
+	}
+}
@@ -0,0 +1,4 @@
+// Package filelock takes operating system advisory locks on open files, so
+// separate tusk processes can take turns with a file. The system drops a lock
+// when the file is closed or its process exits, however it exits.
+package filelock
@@ -0,0 +1,40 @@
+//go:build !windows
+
+package filelock
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// TryLock takes an exclusive lock on file without waiting, reporting false
+// if another open file holds it
+func TryLock(file *os.File) (bool, error) {
+	err := flock(file, syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Lock takes an exclusive lock on file, waiting for any other holder to let
+// it go
+func Lock(file *os.File) error {
+	return flock(file, syscall.LOCK_EX)
+}
+
+// Unlock gives up a lock taken with TryLock or Lock
+func Unlock(file *os.File) error {
+	return flock(file, syscall.LOCK_UN)
+}
+
+// flock retries a flock call interrupted by a signal
+func flock(file *os.File, how int) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), how)
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
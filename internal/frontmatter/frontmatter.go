@@ -0,0 +1,265 @@
+// Package frontmatter reads a post's settings from a block at the top of its
+// text, like the frontmatter of a static site generator, so a whole post can
+// be written in one editor session.
+//
+// The block is either YAML between "---" lines or TOML between "+++" lines.
+// Only the simple forms posts need are understood: "key: value" or
+// "key = value" pairs, and a list of images.
+//
+//	---
+//	visibility: unlisted
+//	cw: Food
+//	images:
+//	  - path: lunch.jpg
+//	    alt: A bowl of ramen
+//	---
+package frontmatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Image is an image to attach, with its alt text
+type Image struct {
+	Path string
+	Alt  string
+}
+
+// Meta is the settings given in a post's frontmatter. Empty fields weren't
+// given.
+type Meta struct {
+	Visibility  string
+	SpoilerText string
+	Language    string
+	Sensitive   *bool
+	Images      []Image
+	Schedule    time.Time
+}
+
+// scheduleLayouts are the forms a schedule time can take. Those without a
+// zone are in local time.
+var scheduleLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// Parse splits text into its frontmatter and body. Text without frontmatter
+// is returned as is, with nil Meta.
+func Parse(text string) (*Meta, string, error) {
+	delimiter, block, body, ok := split(text)
+	if !ok {
+		return nil, text, nil
+	}
+
+	var fields map[string]string
+	var images []map[string]string
+	var err error
+	if delimiter == "+++" {
+		fields, images, err = parseTOML(block)
+	} else {
+		fields, images, err = parseYAML(block)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	meta, err := newMeta(fields, images)
+	if err != nil {
+		return nil, "", err
+	}
+	return meta, strings.TrimSpace(body), nil
+}
+
+// split finds the frontmatter block at the start of text. A block that's
+// never closed isn't frontmatter.
+func split(text string) (delimiter string, block []string, body string, ok bool) {
+	lines := strings.Split(text, "\n")
+	delimiter = strings.TrimSpace(lines[0])
+	if delimiter != "---" && delimiter != "+++" {
+		return "", nil, "", false
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == delimiter {
+			return delimiter, lines[1:i], strings.Join(lines[i+1:], "\n"), true
+		}
+	}
+	return "", nil, "", false
+}
+
+func parseYAML(lines []string) (map[string]string, []map[string]string, error) {
+	fields := make(map[string]string)
+	var images []map[string]string
+	inImages := false
+
+	for n, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// YAML allows list items at the same indentation as their key
+		indented := line[0] == ' ' || line[0] == '\t'
+		if !indented && !strings.HasPrefix(trimmed, "- ") {
+			inImages = false
+		}
+
+		switch {
+		case inImages && strings.HasPrefix(trimmed, "- "):
+			item := strings.TrimSpace(strings.TrimPrefix(trimmed, "- "))
+			image := make(map[string]string)
+			if key, value, ok := yamlPair(item); ok {
+				image[key] = value
+			} else {
+				image["path"] = unquote(item)
+			}
+			images = append(images, image)
+
+		case inImages && indented && len(images) > 0:
+			key, value, ok := yamlPair(trimmed)
+			if !ok {
+				return nil, nil, fmt.Errorf("frontmatter line %d: expected \"key: value\"", n+2)
+			}
+			images[len(images)-1][key] = value
+
+		case !indented:
+			key, value, ok := yamlPair(trimmed)
+			if !ok {
+				return nil, nil, fmt.Errorf("frontmatter line %d: expected \"key: value\"", n+2)
+			}
+			if key == "images" && value == "" {
+				inImages = true
+				continue
+			}
+			fields[key] = value
+
+		default:
+			return nil, nil, fmt.Errorf("frontmatter line %d: unexpected indentation", n+2)
+		}
+	}
+	return fields, images, nil
+}
+
+// yamlPair splits "key: value"
+func yamlPair(s string) (string, string, bool) {
+	key, value, ok := strings.Cut(s, ":")
+	if !ok || strings.ContainsAny(key, " \t\"'") {
+		return "", "", false
+	}
+	return strings.ToLower(key), unquote(strings.TrimSpace(value)), true
+}
+
+func parseTOML(lines []string) (map[string]string, []map[string]string, error) {
+	fields := make(map[string]string)
+	var images []map[string]string
+	current := fields
+
+	for n, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if trimmed == "[[images]]" {
+			images = append(images, make(map[string]string))
+			current = images[len(images)-1]
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("frontmatter line %d: expected \"key = value\"", n+2)
+		}
+		current[strings.ToLower(strings.TrimSpace(key))] = unquote(strings.TrimSpace(value))
+	}
+	return fields, images, nil
+}
+
+// unquote removes the quotes around a string value. Double-quoted strings
+// may use backslash escapes; in single-quoted ones, a quote is doubled.
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+		return s[1 : len(s)-1]
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+func newMeta(fields map[string]string, images []map[string]string) (*Meta, error) {
+	meta := &Meta{}
+
+	for key, value := range fields {
+		switch key {
+		case "visibility":
+			meta.Visibility = value
+		case "cw", "spoiler_text":
+			meta.SpoilerText = value
+		case "lang", "language":
+			meta.Language = value
+		case "sensitive":
+			sensitive, err := parseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sensitive %q: must be true or false", value)
+			}
+			meta.Sensitive = &sensitive
+		case "schedule", "scheduled_at":
+			at, err := parseSchedule(value)
+			if err != nil {
+				return nil, err
+			}
+			meta.Schedule = at
+		default:
+			return nil, fmt.Errorf("unknown frontmatter key %q", key)
+		}
+	}
+
+	for i, fields := range images {
+		var image Image
+		for key, value := range fields {
+			switch key {
+			case "path":
+				image.Path = value
+			case "alt":
+				image.Alt = value
+			default:
+				return nil, fmt.Errorf("unknown key %q for image %d", key, i+1)
+			}
+		}
+		if image.Path == "" {
+			return nil, fmt.Errorf("image %d has no path", i+1)
+		}
+		meta.Images = append(meta.Images, image)
+	}
+
+	return meta, nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+func parseSchedule(s string) (time.Time, error) {
+	for _, layout := range scheduleLayouts {
+		if at, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid schedule %q: use a time like 2006-01-02 15:04", s)
+}
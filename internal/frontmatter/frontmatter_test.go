@@ -0,0 +1,134 @@
+package frontmatter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseYAML(t *testing.T) {
+	text := `---
+visibility: unlisted
+cw: "Food: \"ramen\""
+lang: ja
+sensitive: yes
+schedule: 2026-10-16 09:30
+images:
+  - path: lunch.jpg
+    alt: A bowl of ramen
+  - 'dinner.png'
+---
+
+Lunch and dinner`
+
+	meta, body, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if body != "Lunch and dinner" {
+		t.Errorf("Unexpected body %q", body)
+	}
+	if meta.Visibility != "unlisted" || meta.SpoilerText != `Food: "ramen"` || meta.Language != "ja" {
+		t.Errorf("Unexpected meta %+v", meta)
+	}
+	if meta.Sensitive == nil || !*meta.Sensitive {
+		t.Error("Expected sensitive to be set")
+	}
+	if want := time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local); !meta.Schedule.Equal(want) {
+		t.Errorf("Expected schedule %v, got %v", want, meta.Schedule)
+	}
+
+	want := []Image{{Path: "lunch.jpg", Alt: "A bowl of ramen"}, {Path: "dinner.png"}}
+	if !reflect.DeepEqual(meta.Images, want) {
+		t.Errorf("Expected images %+v, got %+v", want, meta.Images)
+	}
+}
+
+func TestParseYAMLUnindentedList(t *testing.T) {
+	text := "---\nimages:\n- path: a.jpg\n  alt: A\n# a comment\nlang: en\n---\nHi"
+
+	meta, _, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(meta.Images) != 1 || meta.Images[0].Alt != "A" || meta.Language != "en" {
+		t.Errorf("Unexpected meta %+v", meta)
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	text := `+++
+visibility = "private"
+sensitive = false
+schedule = "2026-10-16T09:30:00Z"
+
+[[images]]
+path = "a.jpg"
+alt = 'It''s a cat'
+
+[[images]]
+path = "b.jpg"
++++
+Cats`
+
+	meta, body, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if body != "Cats" || meta.Visibility != "private" {
+		t.Errorf("Unexpected meta %+v and body %q", meta, body)
+	}
+	if meta.Sensitive == nil || *meta.Sensitive {
+		t.Error("Expected sensitive to be set to false")
+	}
+	if want := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC); !meta.Schedule.Equal(want) {
+		t.Errorf("Expected schedule %v, got %v", want, meta.Schedule)
+	}
+
+	want := []Image{{Path: "a.jpg", Alt: "It's a cat"}, {Path: "b.jpg"}}
+	if !reflect.DeepEqual(meta.Images, want) {
+		t.Errorf("Expected images %+v, got %+v", want, meta.Images)
+	}
+}
+
+func TestParseWithoutFrontmatter(t *testing.T) {
+	tests := []string{
+		"Just a post",
+		"--- not frontmatter\nvisibility: direct\n---",
+		"---\nNever closed",
+	}
+
+	for _, text := range tests {
+		meta, body, err := Parse(text)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", text, err)
+		}
+		if meta != nil || body != text {
+			t.Errorf("Parse(%q) = %+v, %q; want the text unchanged", text, meta, body)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"---\nvisibilty: direct\n---\nHi", `unknown frontmatter key "visibilty"`},
+		{"---\nsensitive: maybe\n---\nHi", "invalid sensitive"},
+		{"---\nschedule: tomorrow\n---\nHi", "invalid schedule"},
+		{"---\njust some words\n---\nHi", "line 2"},
+		{"---\nimages:\n  - alt: No path\n---\nHi", "image 1 has no path"},
+		{"+++\n[[images]]\npath = \"a.jpg\"\ncaption = \"x\"\n+++\nHi", `unknown key "caption"`},
+	}
+
+	for _, tt := range tests {
+		_, _, err := Parse(tt.text)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want one containing %q", tt.text, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Package hooks runs user scripts after tusk posts or deletes statuses, so
+// they can be passed on to other tools.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// Timeout is how long a hook may run before it's killed
+const Timeout = 30 * time.Second
+
+// Hook is a user's shell command, run after tusk changes something on their
+// account
+type Hook struct {
+	Command string
+
+	// Output receives what the command prints; defaults to stderr, so hooks
+	// can't get mixed up with tusk's own output
+	Output io.Writer
+}
+
+// Run runs the hook's command in the shell, with payload on its stdin and env
+// added to its environment
+func (h *Hook) Run(payload []byte, env map[string]string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, h.Command)
+	cmd.Stdin = bytes.NewReader(payload)
+
+	out := h.Output
+	if out == nil {
+		out = os.Stderr
+	}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	cmd.Env = os.Environ()
+	for _, key := range keys {
+		cmd.Env = append(cmd.Env, key+"="+env[key])
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", Timeout)
+		}
+		return err
+	}
+	return nil
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,42 @@
+package hooks
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunPassesPayloadAndEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	var out bytes.Buffer
+	hook := &Hook{Command: `printf '%s|' "$TUSK_EVENT"; cat`, Output: &out}
+
+	if err := hook.Run([]byte(`{"id":"1"}`), map[string]string{"TUSK_EVENT": "post"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if got := out.String(); got != `post|{"id":"1"}` {
+		t.Errorf("Expected the event and payload, got %q", got)
+	}
+}
+
+func TestRunReportsFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	var out bytes.Buffer
+	hook := &Hook{Command: "echo oops >&2; exit 3", Output: &out}
+
+	err := hook.Run(nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("Expected the exit status, got %v", err)
+	}
+	if out.String() != "oops\n" {
+		t.Errorf("Expected stderr to be passed through, got %q", out.String())
+	}
+}
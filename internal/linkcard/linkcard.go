@@ -0,0 +1,131 @@
+// Package linkcard reads the title, description, and image of a web page the
+// way an instance does when it builds the preview card for a link in a
+// status: from Open Graph and Twitter meta tags, falling back to the page's
+// <title> and description.
+package linkcard
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+// maxPageBytes is as much of a page as is read; the tags cards are made from
+// are in its head
+const maxPageBytes = 1 << 20
+
+var (
+	metaTag   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attribute = regexp.MustCompile(`(?is)([a-z:_-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	titleTag  = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	spaces    = regexp.MustCompile(`\s+`)
+)
+
+// Fetch gets the page at pageURL and returns the card an instance would
+// likely show for it. Only HTML pages have cards.
+func Fetch(client *http.Client, pageURL string) (*mastodon.Card, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", pageURL, resp.Status)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("%s is %s, not a web page", pageURL, mediaType)
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", pageURL, err)
+	}
+
+	// Redirects move the page, and relative image paths go with it
+	return Parse(string(page), resp.Request.URL.String()), nil
+}
+
+// Parse builds the card for a page from its HTML
+func Parse(page, pageURL string) *mastodon.Card {
+	meta := make(map[string]string)
+	for _, tag := range metaTag.FindAllString(page, -1) {
+		attrs := make(map[string]string)
+		for _, m := range attribute.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = m[2] + m[3] + m[4]
+		}
+		key := strings.ToLower(attrs["property"])
+		if key == "" {
+			key = strings.ToLower(attrs["name"])
+		}
+		// The first of a repeated tag wins, as it does for instances
+		if _, seen := meta[key]; key != "" && !seen {
+			meta[key] = clean(attrs["content"])
+		}
+	}
+
+	var title string
+	if m := titleTag.FindStringSubmatch(page); m != nil {
+		title = clean(m[1])
+	}
+
+	card := &mastodon.Card{
+		URL:          first(meta["og:url"], pageURL),
+		Title:        first(meta["og:title"], meta["twitter:title"], title),
+		Description:  first(meta["og:description"], meta["twitter:description"], meta["description"]),
+		Type:         "link",
+		ProviderName: meta["og:site_name"],
+		Image:        resolve(pageURL, first(meta["og:image"], meta["twitter:image"])),
+	}
+	if card.ProviderName == "" {
+		if u, err := url.Parse(pageURL); err == nil {
+			card.ProviderName = strings.TrimPrefix(u.Hostname(), "www.")
+		}
+	}
+	return card
+}
+
+// clean decodes entities and collapses the spacing in a tag's text
+func clean(s string) string {
+	return strings.TrimSpace(spaces.ReplaceAllString(html.UnescapeString(s), " "))
+}
+
+// first returns the first of values that isn't empty
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// resolve makes ref, such as an image path, absolute against the page's URL
+func resolve(pageURL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
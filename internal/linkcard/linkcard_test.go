@@ -0,0 +1,91 @@
+package linkcard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseOpenGraph(t *testing.T) {
+	page := `<!doctype html>
+<html><head>
+<title>Fallback title</title>
+<meta property="og:title" content="Tusk 2.0 &amp; beyond">
+<meta content='What&#39;s new in the release' property='og:description'>
+<meta property="og:site_name" content="The Tusk Blog">
+<meta property="og:image" content="/images/cover.png">
+<meta property="og:image" content="/images/second.png">
+</head><body></body></html>`
+
+	card := Parse(page, "https://blog.example/posts/tusk-2")
+
+	if card.Title != "Tusk 2.0 & beyond" {
+		t.Errorf("Expected the og:title, got %q", card.Title)
+	}
+	if card.Description != "What's new in the release" {
+		t.Errorf("Expected the og:description, got %q", card.Description)
+	}
+	if card.ProviderName != "The Tusk Blog" {
+		t.Errorf("Expected the site name, got %q", card.ProviderName)
+	}
+	if card.Image != "https://blog.example/images/cover.png" {
+		t.Errorf("Expected the first image, made absolute, got %q", card.Image)
+	}
+	if card.URL != "https://blog.example/posts/tusk-2" {
+		t.Errorf("Expected the page URL, got %q", card.URL)
+	}
+}
+
+func TestParseFallbacks(t *testing.T) {
+	page := `<html><head><TITLE>
+  A   plain page
+</TITLE><meta name="description" content="Just a description"></head></html>`
+
+	card := Parse(page, "https://www.plain.example/")
+
+	if card.Title != "A plain page" {
+		t.Errorf("Expected the <title>, got %q", card.Title)
+	}
+	if card.Description != "Just a description" {
+		t.Errorf("Expected the meta description, got %q", card.Description)
+	}
+	if card.ProviderName != "plain.example" {
+		t.Errorf("Expected the host as provider, got %q", card.ProviderName)
+	}
+	if card.Image != "" {
+		t.Errorf("Expected no image, got %q", card.Image)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/new":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<title>Moved</title><meta property="og:image" content="cover.png">`))
+		case "/file.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	card, err := Fetch(server.Client(), server.URL+"/old")
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if card.Title != "Moved" || card.Image != server.URL+"/cover.png" {
+		t.Errorf("Expected the redirected page's card, got %+v", card)
+	}
+
+	if _, err := Fetch(server.Client(), server.URL+"/file.pdf"); err == nil {
+		t.Error("Expected error for a PDF, got nil")
+	}
+	if _, err := Fetch(server.Client(), server.URL+"/missing"); err == nil {
+		t.Error("Expected error for a missing page, got nil")
+	}
+}
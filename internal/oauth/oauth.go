@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"html/template"
 	"math/big"
 	"net"
 	"net/http"
@@ -18,9 +19,14 @@ const (
 	maxPort = 65535
 )
 
+// bindAttempts is how many random ports are tried before giving up
+const bindAttempts = 10
+
 type CallbackServer struct {
 	port     int
+	listener net.Listener
 	server   *http.Server
+	page     *template.Template
 	codeChan chan string
 	errChan  chan error
 	once     sync.Once
@@ -35,14 +41,18 @@ func getRandomPort() (int, error) {
 	return minPort + int(n.Int64()), nil
 }
 
-func NewCallbackServer() (*CallbackServer, error) {
-	port, err := getRandomPort()
+// NewCallbackServer binds the callback server to port, or to a random free
+// port between minPort and maxPort if port is 0. The port is held from here
+// on, so the redirect URI stays valid until the server is shut down.
+func NewCallbackServer(port int) (*CallbackServer, error) {
+	listener, err := bind(port)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get random port: %w", err)
+		return nil, err
 	}
 
 	cs := &CallbackServer{
-		port:     port,
+		port:     listener.Addr().(*net.TCPAddr).Port,
+		listener: listener,
 		codeChan: make(chan string, 1),
 		errChan:  make(chan error, 1),
 	}
@@ -51,68 +61,106 @@ func NewCallbackServer() (*CallbackServer, error) {
 	mux.HandleFunc("/callback", cs.handleCallback)
 
 	cs.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", port),
 		Handler: mux,
 	}
 
 	return cs, nil
 }
 
+// bind listens on port, or on the first free random one if port is 0
+func bind(port int) (net.Listener, error) {
+	if port != 0 {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			return nil, fmt.Errorf("port %d is unavailable: %w", port, err)
+		}
+		return listener, nil
+	}
+
+	var lastErr error
+	for range bindAttempts {
+		port, err := getRandomPort()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get random port: %w", err)
+		}
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err == nil {
+			return listener, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no free port found after %d attempts: %w", bindAttempts, lastErr)
+}
+
+// AuthorizationError is what the instance redirects back with instead of a
+// code, such as when the user denies access (RFC 6749, section 4.1.2.1)
+type AuthorizationError struct {
+	Code        string
+	Description string
+}
+
+func (e *AuthorizationError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("authorization failed: %s (%s)", e.Description, e.Code)
+	}
+	return "authorization failed: " + e.Code
+}
+
+// Denied reports whether the user chose not to authorize
+func (e *AuthorizationError) Denied() bool {
+	return e.Code == "access_denied"
+}
+
+// Message explains the error to the user
+func (e *AuthorizationError) Message() string {
+	switch e.Code {
+	case "access_denied":
+		return "Access was denied, so tusk wasn't authorized."
+	case "invalid_scope":
+		return "The instance doesn't allow the permissions tusk asked for."
+	}
+	if e.Description != "" {
+		return e.Description
+	}
+	return fmt.Sprintf("The instance reported an error (%s).", e.Code)
+}
+
 func (cs *CallbackServer) handleCallback(w http.ResponseWriter, r *http.Request) {
-	code := r.URL.Query().Get("code")
+	query := r.URL.Query()
+	if code := query.Get("error"); code != "" {
+		authErr := &AuthorizationError{Code: code, Description: query.Get("error_description")}
+		cs.fail(authErr)
+		cs.writePage(w, r, http.StatusBadRequest, authErr.Message())
+		return
+	}
+
+	code := query.Get("code")
 	if code == "" {
-		cs.errChan <- fmt.Errorf("no authorization code received")
-		http.Error(w, "Authorization failed: no code received", http.StatusBadRequest)
+		cs.fail(fmt.Errorf("no authorization code received"))
+		cs.writePage(w, r, http.StatusBadRequest, "The instance didn't send an authorization code.")
 		return
 	}
 
-	cs.codeChan <- code
-
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	fmt.Fprintf(w, `
-<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="UTF-8">
-    <title>Tusk Authorization</title>
-    <style>
-        body {
-            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
-            display: flex;
-            justify-content: center;
-            align-items: center;
-            height: 100vh;
-            margin: 0;
-            background: #f5f5f5;
-        }
-        .container {
-            text-align: center;
-            background: white;
-            padding: 2rem;
-            border-radius: 8px;
-            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
-        }
-        h1 { color: #2ecc71; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h1>&#128640; Authorization Successful!</h1>
-        <p>You can close this window and return to your terminal.</p>
-    </div>
-</body>
-</html>
-`)
+	select {
+	case cs.codeChan <- code:
+	default:
+	}
+	cs.writePage(w, r, http.StatusOK, "")
 }
 
-func (cs *CallbackServer) Start() error {
-	listener, err := net.Listen("tcp", cs.server.Addr)
-	if err != nil {
-		return fmt.Errorf("failed to start server: %w", err)
+// fail reports err to WaitForCode. Only the first callback counts, so a
+// reload of the page can't block.
+func (cs *CallbackServer) fail(err error) {
+	select {
+	case cs.errChan <- err:
+	default:
 	}
+}
 
+// Start serves callbacks on the port bound by NewCallbackServer
+func (cs *CallbackServer) Start() error {
 	go func() {
-		if err := cs.server.Serve(listener); err != http.ErrServerClosed {
+		if err := cs.server.Serve(cs.listener); err != http.ErrServerClosed {
 			cs.errChan <- err
 		}
 	}()
@@ -120,7 +168,9 @@ func (cs *CallbackServer) Start() error {
 	return nil
 }
 
-func (cs *CallbackServer) WaitForCode(timeout time.Duration) (string, error) {
+// WaitForCode waits for the browser to deliver the authorization code, until
+// timeout or until ctx is cancelled
+func (cs *CallbackServer) WaitForCode(ctx context.Context, timeout time.Duration) (string, error) {
 	select {
 	case code := <-cs.codeChan:
 		cs.shutdown()
@@ -131,14 +181,25 @@ func (cs *CallbackServer) WaitForCode(timeout time.Duration) (string, error) {
 	case <-time.After(timeout):
 		cs.shutdown()
 		return "", fmt.Errorf("timeout waiting for authorization")
+	case <-ctx.Done():
+		cs.shutdown()
+		return "", ctx.Err()
 	}
 }
 
+// Close releases the port. It's safe to call after WaitForCode, which
+// closes the server itself.
+func (cs *CallbackServer) Close() {
+	cs.shutdown()
+}
+
 func (cs *CallbackServer) shutdown() {
 	cs.once.Do(func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		cs.server.Shutdown(ctx)
+		// Shutdown only closes the listener if Start was called
+		cs.listener.Close()
 	})
 }
 
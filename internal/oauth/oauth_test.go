@@ -1,8 +1,13 @@
 package oauth
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"html"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
@@ -19,7 +24,7 @@ func TestGetRandomPort(t *testing.T) {
 }
 
 func TestNewCallbackServer(t *testing.T) {
-	cs, err := NewCallbackServer()
+	cs, err := NewCallbackServer(0)
 	if err != nil {
 		t.Fatalf("Failed to create callback server: %v", err)
 	}
@@ -35,7 +40,7 @@ func TestNewCallbackServer(t *testing.T) {
 }
 
 func TestCallbackServerStart(t *testing.T) {
-	cs, err := NewCallbackServer()
+	cs, err := NewCallbackServer(0)
 	if err != nil {
 		t.Fatalf("Failed to create callback server: %v", err)
 	}
@@ -60,7 +65,7 @@ func TestCallbackServerStart(t *testing.T) {
 }
 
 func TestCallbackServerWaitForCode(t *testing.T) {
-	cs, err := NewCallbackServer()
+	cs, err := NewCallbackServer(0)
 	if err != nil {
 		t.Fatalf("Failed to create callback server: %v", err)
 	}
@@ -76,7 +81,7 @@ func TestCallbackServerWaitForCode(t *testing.T) {
 		http.Get(fmt.Sprintf("http://localhost:%d/callback?code=%s", cs.port, expectedCode))
 	}()
 
-	code, err := cs.WaitForCode(5 * time.Second)
+	code, err := cs.WaitForCode(context.Background(), 5*time.Second)
 	if err != nil {
 		t.Fatalf("Failed to wait for code: %v", err)
 	}
@@ -87,7 +92,7 @@ func TestCallbackServerWaitForCode(t *testing.T) {
 }
 
 func TestCallbackServerTimeout(t *testing.T) {
-	cs, err := NewCallbackServer()
+	cs, err := NewCallbackServer(0)
 	if err != nil {
 		t.Fatalf("Failed to create callback server: %v", err)
 	}
@@ -96,7 +101,7 @@ func TestCallbackServerTimeout(t *testing.T) {
 		t.Fatalf("Failed to start callback server: %v", err)
 	}
 
-	_, err = cs.WaitForCode(100 * time.Millisecond)
+	_, err = cs.WaitForCode(context.Background(), 100*time.Millisecond)
 	if err == nil {
 		t.Error("Expected timeout error, got nil")
 	}
@@ -106,8 +111,30 @@ func TestCallbackServerTimeout(t *testing.T) {
 	}
 }
 
+func TestCallbackServerCancelled(t *testing.T) {
+	cs, err := NewCallbackServer(0)
+	if err != nil {
+		t.Fatalf("Failed to create callback server: %v", err)
+	}
+
+	if err := cs.Start(); err != nil {
+		t.Fatalf("Failed to start callback server: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err = cs.WaitForCode(ctx, 5*time.Second)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the wait to be cancelled, got %v", err)
+	}
+}
+
 func TestCallbackServerMissingCode(t *testing.T) {
-	cs, err := NewCallbackServer()
+	cs, err := NewCallbackServer(0)
 	if err != nil {
 		t.Fatalf("Failed to create callback server: %v", err)
 	}
@@ -121,14 +148,14 @@ func TestCallbackServerMissingCode(t *testing.T) {
 		http.Get(fmt.Sprintf("http://localhost:%d/callback", cs.port))
 	}()
 
-	_, err = cs.WaitForCode(5 * time.Second)
+	_, err = cs.WaitForCode(context.Background(), 5*time.Second)
 	if err == nil {
 		t.Error("Expected error for missing code, got nil")
 	}
 }
 
 func TestCallbackServerPort(t *testing.T) {
-	cs, err := NewCallbackServer()
+	cs, err := NewCallbackServer(0)
 	if err != nil {
 		t.Fatalf("Failed to create callback server: %v", err)
 	}
@@ -140,7 +167,7 @@ func TestCallbackServerPort(t *testing.T) {
 }
 
 func TestCallbackServerRedirectURI(t *testing.T) {
-	cs, err := NewCallbackServer()
+	cs, err := NewCallbackServer(0)
 	if err != nil {
 		t.Fatalf("Failed to create callback server: %v", err)
 	}
@@ -152,3 +179,85 @@ func TestCallbackServerRedirectURI(t *testing.T) {
 		t.Errorf("Expected URI %q, got %q", expected, uri)
 	}
 }
+
+func TestCallbackServerFixedPort(t *testing.T) {
+	free, err := NewCallbackServer(0)
+	if err != nil {
+		t.Fatalf("Failed to create callback server: %v", err)
+	}
+	port := free.Port()
+	free.Close()
+
+	cs, err := NewCallbackServer(port)
+	if err != nil {
+		t.Fatalf("Failed to create callback server on port %d: %v", port, err)
+	}
+	defer cs.Close()
+
+	if cs.Port() != port {
+		t.Errorf("Expected port %d, got %d", port, cs.Port())
+	}
+	if expected := fmt.Sprintf("http://localhost:%d/callback", port); cs.RedirectURI() != expected {
+		t.Errorf("Expected URI %q, got %q", expected, cs.RedirectURI())
+	}
+
+	// The port is held from construction, before Start
+	if _, err := NewCallbackServer(port); err == nil {
+		t.Errorf("Expected port %d to be unavailable while held, got nil", port)
+	}
+}
+
+func TestCallbackServerAuthorizationError(t *testing.T) {
+	cs, err := NewCallbackServer(0)
+	if err != nil {
+		t.Fatalf("Failed to create callback server: %v", err)
+	}
+
+	if err := cs.Start(); err != nil {
+		t.Fatalf("Failed to start callback server: %v", err)
+	}
+
+	pageChan := make(chan string, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d/callback?error=access_denied&error_description=The+resource+owner+denied+the+request.", cs.port))
+		if err != nil {
+			pageChan <- ""
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		pageChan <- string(body)
+	}()
+
+	_, err = cs.WaitForCode(context.Background(), 5*time.Second)
+	var authErr *AuthorizationError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("Expected an AuthorizationError, got %v", err)
+	}
+	if !authErr.Denied() || authErr.Description != "The resource owner denied the request." {
+		t.Errorf("Unexpected error: %+v", authErr)
+	}
+
+	page := <-pageChan
+	if !strings.Contains(page, "Authorization Failed") || !strings.Contains(page, html.EscapeString(authErr.Message())) {
+		t.Errorf("Expected the page to explain the denial, got %q", page)
+	}
+}
+
+func TestAuthorizationErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  AuthorizationError
+		want string
+	}{
+		{AuthorizationError{Code: "invalid_scope", Description: "The requested scope is invalid."}, "The instance doesn't allow the permissions tusk asked for."},
+		{AuthorizationError{Code: "server_error", Description: "Something broke."}, "Something broke."},
+		{AuthorizationError{Code: "temporarily_unavailable"}, "The instance reported an error (temporarily_unavailable)."},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Message(); got != tt.want {
+			t.Errorf("Message() for %s = %q, want %q", tt.err.Code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package oauth
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// PageData is what the page the browser lands on after authorizing is
+// rendered with. A custom template set with SetPageTemplate gets the same.
+type PageData struct {
+	// Lang is the language the text is in, such as "en"
+	Lang    string
+	Success bool
+	Heading string
+	// Detail says why authorization failed; it's empty on success
+	Detail  string
+	Message string
+	// AutoClose is whether the page should try to close its tab. Browsers
+	// only allow it for tabs a script opened, so the message still says to.
+	AutoClose bool
+}
+
+// pageText is the page's wording in one language
+type pageText struct {
+	successHeading string
+	successMessage string
+	failureHeading string
+	failureMessage string
+}
+
+// pageTexts are the languages the page is available in, by primary language
+// subtag
+var pageTexts = map[string]pageText{
+	"en": {
+		successHeading: "Authorization Successful!",
+		successMessage: "You can close this window and return to your terminal.",
+		failureHeading: "Authorization Failed",
+		failureMessage: "Return to your terminal to try again.",
+	},
+	"de": {
+		successHeading: "Autorisierung erfolgreich!",
+		successMessage: "Du kannst dieses Fenster schließen und zum Terminal zurückkehren.",
+		failureHeading: "Autorisierung fehlgeschlagen",
+		failureMessage: "Kehre zum Terminal zurück, um es erneut zu versuchen.",
+	},
+	"es": {
+		successHeading: "¡Autorización completada!",
+		successMessage: "Puedes cerrar esta ventana y volver a la terminal.",
+		failureHeading: "La autorización ha fallado",
+		failureMessage: "Vuelve a la terminal para intentarlo de nuevo.",
+	},
+	"fr": {
+		successHeading: "Autorisation réussie !",
+		successMessage: "Vous pouvez fermer cette fenêtre et revenir à votre terminal.",
+		failureHeading: "Échec de l'autorisation",
+		failureMessage: "Revenez à votre terminal pour réessayer.",
+	},
+	"ja": {
+		successHeading: "認証に成功しました",
+		successMessage: "このウィンドウを閉じて、ターミナルに戻ってください。",
+		failureHeading: "認証に失敗しました",
+		failureMessage: "ターミナルに戻って、もう一度お試しください。",
+	},
+}
+
+const defaultPageTemplate = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+    <meta charset="UTF-8">
+    <title>Tusk Authorization</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
+            display: flex;
+            justify-content: center;
+            align-items: center;
+            height: 100vh;
+            margin: 0;
+            background: #f5f5f5;
+        }
+        .container {
+            text-align: center;
+            background: white;
+            padding: 2rem;
+            border-radius: 8px;
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+        }
+        h1 { color: {{if .Success}}#2ecc71{{else}}#e74c3c{{end}}; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>{{if .Success}}&#128640; {{end}}{{.Heading}}</h1>
+        {{if .Detail}}<p>{{.Detail}}</p>{{end}}
+        <p>{{.Message}}</p>
+    </div>
+    {{if .AutoClose}}<script>setTimeout(function () { window.close(); }, 3000);</script>{{end}}
+</body>
+</html>
+`
+
+var defaultPage = template.Must(template.New("page").Parse(defaultPageTemplate))
+
+// ParsePageTemplate parses a custom page, an html/template rendered with
+// PageData
+func ParsePageTemplate(text string) (*template.Template, error) {
+	page, err := template.New("page").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page template: %w", err)
+	}
+	return page, nil
+}
+
+// SetPageTemplate replaces the page the browser lands on with a template
+// from ParsePageTemplate
+func (cs *CallbackServer) SetPageTemplate(page *template.Template) {
+	cs.page = page
+}
+
+// pageLanguage picks the first language in an Accept-Language header that
+// the page is available in, or English
+func pageLanguage(acceptLanguage string) string {
+	for _, tag := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ = strings.Cut(tag, ";")
+		primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		primary = strings.ToLower(primary)
+		if _, ok := pageTexts[primary]; ok {
+			return primary
+		}
+	}
+	return "en"
+}
+
+// writePage renders the page for r, in the browser's language. detail says
+// why authorization failed, or is empty if it succeeded.
+func (cs *CallbackServer) writePage(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	lang := pageLanguage(r.Header.Get("Accept-Language"))
+	text := pageTexts[lang]
+
+	data := PageData{
+		Lang:      lang,
+		Success:   status == http.StatusOK,
+		Heading:   text.successHeading,
+		Detail:    detail,
+		Message:   text.successMessage,
+		AutoClose: status == http.StatusOK,
+	}
+	if !data.Success {
+		data.Heading = text.failureHeading
+		data.Message = text.failureMessage
+	}
+
+	page := cs.page
+	if page == nil {
+		page = defaultPage
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	page.Execute(w, data)
+}
@@ -0,0 +1,68 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPageLanguage(t *testing.T) {
+	tests := []struct {
+		header, want string
+	}{
+		{"", "en"},
+		{"de-DE,de;q=0.9,en;q=0.8", "de"},
+		{"pt-BR, fr;q=0.7", "fr"},
+		{"JA", "ja"},
+		{"zh-CN", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := pageLanguage(tt.header); got != tt.want {
+			t.Errorf("pageLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestWritePage(t *testing.T) {
+	cs := &CallbackServer{}
+
+	req := httptest.NewRequest("GET", "/callback", nil)
+	req.Header.Set("Accept-Language", "fr-CA,fr;q=0.9")
+	rec := httptest.NewRecorder()
+	cs.writePage(rec, req, http.StatusOK, "")
+
+	page := rec.Body.String()
+	if !strings.Contains(page, `lang="fr"`) || !strings.Contains(page, "Autorisation réussie") {
+		t.Errorf("Expected the page in French, got %q", page)
+	}
+	if !strings.Contains(page, "window.close()") {
+		t.Error("Expected the success page to close itself")
+	}
+
+	rec = httptest.NewRecorder()
+	cs.writePage(rec, httptest.NewRequest("GET", "/callback", nil), http.StatusBadRequest, "Access was denied")
+	if page := rec.Body.String(); strings.Contains(page, "window.close()") || !strings.Contains(page, "Access was denied") {
+		t.Errorf("Expected the failure page to stay open with the reason, got %q", page)
+	}
+}
+
+func TestCustomPageTemplate(t *testing.T) {
+	page, err := ParsePageTemplate(`<p lang="{{.Lang}}">{{if .Success}}ok{{else}}{{.Detail}}{{end}}</p>`)
+	if err != nil {
+		t.Fatalf("ParsePageTemplate failed: %v", err)
+	}
+	cs := &CallbackServer{}
+	cs.SetPageTemplate(page)
+
+	rec := httptest.NewRecorder()
+	cs.writePage(rec, httptest.NewRequest("GET", "/callback", nil), http.StatusOK, "")
+	if got := rec.Body.String(); got != `<p lang="en">ok</p>` {
+		t.Errorf("Unexpected page %q", got)
+	}
+
+	if _, err := ParsePageTemplate(`{{if .Success}}`); err == nil {
+		t.Error("Expected an unclosed action to fail, got nil")
+	}
+}
@@ -0,0 +1,153 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+// publicAudience is the ActivityPub collection meaning "everyone"
+const publicAudience = "https://www.w3.org/ns/activitystreams#Public"
+
+// Attachment is a media file referenced by a note. URL is relative to the
+// root of the account export.
+type Attachment struct {
+	MediaType string
+	URL       string
+	Name      string
+}
+
+// Note is a status recovered from an outbox
+type Note struct {
+	ID          string
+	Published   time.Time
+	Content     string
+	Summary     string
+	Sensitive   bool
+	Visibility  mastodon.Visibility
+	InReplyTo   string
+	Attachments []Attachment
+}
+
+type collection struct {
+	OrderedItems []activity `json:"orderedItems"`
+}
+
+type activity struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+type object struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Published  time.Time `json:"published"`
+	Content    string    `json:"content"`
+	Summary    *string   `json:"summary"`
+	Sensitive  bool      `json:"sensitive"`
+	InReplyTo  *string   `json:"inReplyTo"`
+	To         audience  `json:"to"`
+	Cc         audience  `json:"cc"`
+	Attachment []struct {
+		MediaType string  `json:"mediaType"`
+		URL       string  `json:"url"`
+		Name      *string `json:"name"`
+	} `json:"attachment"`
+}
+
+// audience accepts either a single IRI or a list of them
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Load reads the notes an account created from an outbox.json, oldest first.
+// Boosts and other activities are skipped.
+func Load(path string) ([]*Note, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes the notes in an outbox document
+func Parse(data []byte) ([]*Note, error) {
+	var outbox collection
+	if err := json.Unmarshal(data, &outbox); err != nil {
+		return nil, fmt.Errorf("failed to decode outbox: %w", err)
+	}
+
+	var notes []*Note
+	for _, item := range outbox.OrderedItems {
+		if item.Type != "Create" {
+			continue
+		}
+
+		// Objects of other activities can be bare IRIs; a Create's is embedded
+		var obj object
+		if err := json.Unmarshal(item.Object, &obj); err != nil || obj.Type != "Note" {
+			continue
+		}
+
+		note := &Note{
+			ID:         obj.ID,
+			Published:  obj.Published,
+			Content:    obj.Content,
+			Sensitive:  obj.Sensitive,
+			Visibility: visibilityOf(obj.To, obj.Cc),
+		}
+		if obj.Summary != nil {
+			note.Summary = *obj.Summary
+		}
+		if obj.InReplyTo != nil {
+			note.InReplyTo = *obj.InReplyTo
+		}
+		for _, a := range obj.Attachment {
+			attachment := Attachment{MediaType: a.MediaType, URL: a.URL}
+			if a.Name != nil {
+				attachment.Name = *a.Name
+			}
+			note.Attachments = append(note.Attachments, attachment)
+		}
+
+		notes = append(notes, note)
+	}
+
+	return notes, nil
+}
+
+// visibilityOf maps ActivityPub addressing back to a Mastodon visibility
+func visibilityOf(to, cc audience) mastodon.Visibility {
+	for _, iri := range to {
+		if iri == publicAudience {
+			return mastodon.VisibilityPublic
+		}
+	}
+	for _, iri := range cc {
+		if iri == publicAudience {
+			return mastodon.VisibilityUnlisted
+		}
+	}
+	for _, iri := range to {
+		if strings.HasSuffix(iri, "/followers") {
+			return mastodon.VisibilityPrivate
+		}
+	}
+	return mastodon.VisibilityDirect
+}
@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"testing"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+const sampleOutbox = `{
+  "@context": "https://www.w3.org/ns/activitystreams",
+  "type": "OrderedCollection",
+  "orderedItems": [
+    {
+      "type": "Create",
+      "object": {
+        "id": "https://example.com/users/me/statuses/1",
+        "type": "Note",
+        "published": "2024-01-01T12:00:00Z",
+        "content": "<p>Hello world</p>",
+        "summary": null,
+        "to": ["https://www.w3.org/ns/activitystreams#Public"],
+        "cc": ["https://example.com/users/me/followers"],
+        "attachment": [
+          {"type": "Document", "mediaType": "image/jpeg", "url": "/media_attachments/files/1.jpg", "name": "A cat"}
+        ]
+      }
+    },
+    {
+      "type": "Announce",
+      "object": "https://other.example/statuses/9"
+    },
+    {
+      "type": "Create",
+      "object": {
+        "id": "https://example.com/users/me/statuses/2",
+        "type": "Note",
+        "published": "2024-01-02T12:00:00Z",
+        "content": "<p>Quiet post</p>",
+        "summary": "spoilers",
+        "sensitive": true,
+        "inReplyTo": "https://example.com/users/me/statuses/1",
+        "to": ["https://example.com/users/me/followers"],
+        "cc": "https://www.w3.org/ns/activitystreams#Public"
+      }
+    },
+    {
+      "type": "Create",
+      "object": {
+        "id": "https://example.com/users/me/statuses/3",
+        "type": "Note",
+        "published": "2024-01-03T12:00:00Z",
+        "content": "<p>Followers only</p>",
+        "to": ["https://example.com/users/me/followers"],
+        "cc": []
+      }
+    },
+    {
+      "type": "Create",
+      "object": {
+        "id": "https://example.com/users/me/statuses/4",
+        "type": "Note",
+        "published": "2024-01-04T12:00:00Z",
+        "content": "<p>Psst</p>",
+        "to": ["https://other.example/users/friend"],
+        "cc": []
+      }
+    }
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	notes, err := Parse([]byte(sampleOutbox))
+	if err != nil {
+		t.Fatalf("Failed to parse outbox: %v", err)
+	}
+
+	if len(notes) != 4 {
+		t.Fatalf("Expected 4 notes (boost skipped), got %d", len(notes))
+	}
+
+	first := notes[0]
+	if first.Content != "<p>Hello world</p>" {
+		t.Errorf("Unexpected content %q", first.Content)
+	}
+	if len(first.Attachments) != 1 || first.Attachments[0].Name != "A cat" {
+		t.Errorf("Unexpected attachments %+v", first.Attachments)
+	}
+	if first.Published.Year() != 2024 {
+		t.Errorf("Expected published date to be parsed, got %v", first.Published)
+	}
+
+	second := notes[1]
+	if second.Summary != "spoilers" || !second.Sensitive {
+		t.Errorf("Expected CW and sensitive flag, got %+v", second)
+	}
+	if second.InReplyTo == "" {
+		t.Error("Expected inReplyTo to be set")
+	}
+
+	expected := []mastodon.Visibility{
+		mastodon.VisibilityPublic,
+		mastodon.VisibilityUnlisted,
+		mastodon.VisibilityPrivate,
+		mastodon.VisibilityDirect,
+	}
+	for i, note := range notes {
+		if note.Visibility != expected[i] {
+			t.Errorf("Note %d: expected visibility %q, got %q", i, expected[i], note.Visibility)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse([]byte("not json")); err == nil {
+		t.Error("Expected error for invalid outbox, got nil")
+	}
+}
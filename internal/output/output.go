@@ -2,38 +2,149 @@ package output
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/fatih/color"
 )
 
+// Level controls how much tusk says
+type Level int
+
+const (
+	// LevelQuiet drops informational and success messages, leaving results,
+	// warnings, errors, and prompts
+	LevelQuiet Level = iota
+	LevelNormal
+	// LevelVerbose adds debug messages, written to stderr
+	LevelVerbose
+)
+
+var level = LevelNormal
+
+// SetLevel changes how much is printed from here on
+func SetLevel(l Level) {
+	level = l
+}
+
+// Verbose reports whether debug messages are being printed
+func Verbose() bool {
+	return level >= LevelVerbose
+}
+
+// DisableColor turns off colors and other terminal escapes. Color is already
+// off when output isn't a terminal or the NO_COLOR environment variable is set.
+func DisableColor() {
+	color.NoColor = true
+}
+
 var (
 	successColor = color.New(color.FgGreen, color.Bold)
 	errorColor   = color.New(color.FgRed, color.Bold)
 	infoColor    = color.New(color.FgCyan)
 	urlColor     = color.New(color.FgBlue, color.Underline)
 	promptColor  = color.New(color.FgYellow)
+	warningColor = color.New(color.FgYellow, color.Bold)
+	addedColor   = color.New(color.FgGreen)
+	removedColor = color.New(color.FgRed)
+	debugColor   = color.New(color.FgHiBlack)
 )
 
+// Messages for people, such as progress, warnings, errors, and prompts, go
+// to stderr. Stdout is kept for what a command produces, such as statuses,
+// listings, and the URL of a new post, so that can be piped or captured on
+// its own.
+
+// messages is where messages for people are written
+func messages() io.Writer {
+	return color.Error
+}
+
+// results is where what a command produces is written: stdout, unless it's
+// kept for IDs or URLs alone
+func results() io.Writer {
+	if bare() {
+		return color.Error
+	}
+	return color.Output
+}
+
 func Success(format string, a ...interface{}) {
-	successColor.Printf("✓ "+format+"\n", a...)
+	if level < LevelNormal || structured() {
+		return
+	}
+	successColor.Fprintf(messages(), "✓ "+format+"\n", a...)
 }
 
 func Error(format string, a ...interface{}) {
-	errorColor.Printf("✗ "+format+"\n", a...)
+	errorColor.Fprintf(messages(), "✗ "+format+"\n", a...)
+}
+
+func Warning(format string, a ...interface{}) {
+	warningColor.Fprintf(messages(), "! "+format+"\n", a...)
 }
 
 func Info(format string, a ...interface{}) {
-	infoColor.Printf(format+"\n", a...)
+	if level < LevelNormal || structured() {
+		return
+	}
+	infoColor.Fprintf(messages(), format+"\n", a...)
 }
 
 func URL(url string) {
-	urlColor.Println(url)
+	// Keep any indentation out of the link
+	trimmed := strings.TrimLeft(url, " ")
+	fmt.Fprintln(results(), url[:len(url)-len(trimmed)]+Link(trimmed, urlColor.Sprint(trimmed)))
+}
+
+// Result prints what a command made or acted on, such as a new status: its
+// URL as a link, or with --output id or url, only its ID or URL, alone on
+// stdout where a script can capture it. Empty values are left out.
+func Result(id, url string) {
+	switch format {
+	case FormatID:
+		if id != "" {
+			fmt.Fprintln(color.Output, id)
+		}
+	case FormatURL:
+		if url != "" {
+			fmt.Fprintln(color.Output, url)
+		}
+	default:
+		if url != "" {
+			URL(url)
+		}
+	}
 }
 
 func Prompt(format string, a ...interface{}) {
-	promptColor.Printf(format, a...)
+	promptColor.Fprintf(messages(), format, a...)
 }
 
 func Plain(format string, a ...interface{}) {
-	fmt.Printf(format+"\n", a...)
+	fmt.Fprintf(results(), format+"\n", a...)
+}
+
+// Note prints plain text that goes with a message or question rather than
+// being a result, such as a post shown before asking whether to send it. Like
+// prompts, notes are printed even with --quiet.
+func Note(format string, a ...interface{}) {
+	fmt.Fprintf(messages(), format+"\n", a...)
+}
+
+func Added(format string, a ...interface{}) {
+	addedColor.Fprintf(results(), format+"\n", a...)
+}
+
+func Removed(format string, a ...interface{}) {
+	removedColor.Fprintf(results(), format+"\n", a...)
+}
+
+// Debug prints diagnostic details to stderr, only in verbose mode
+func Debug(format string, a ...interface{}) {
+	if level < LevelVerbose {
+		return
+	}
+	debugColor.Fprintf(os.Stderr, "debug: "+format+"\n", a...)
 }
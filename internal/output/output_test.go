@@ -0,0 +1,71 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestQuietDropsChatter(t *testing.T) {
+	withColor(t, false)
+
+	// Messages go to stderr
+	var buf bytes.Buffer
+	oldError := color.Error
+	color.Error = &buf
+	t.Cleanup(func() {
+		color.Error = oldError
+		SetLevel(LevelNormal)
+	})
+
+	SetLevel(LevelQuiet)
+	Info("fetching")
+	Success("done")
+	Warning("careful")
+	Error("broken")
+
+	if got := buf.String(); got != "! careful\n✗ broken\n" {
+		t.Errorf("Expected only the warning and error, got %q", got)
+	}
+	if Verbose() {
+		t.Error("Expected quiet mode not to be verbose")
+	}
+
+	buf.Reset()
+	SetLevel(LevelNormal)
+	Info("fetching")
+	Success("done")
+
+	if got := buf.String(); got != "fetching\n✓ done\n" {
+		t.Errorf("Expected info and success at the normal level, got %q", got)
+	}
+
+	SetLevel(LevelVerbose)
+	if !Verbose() {
+		t.Error("Expected verbose mode to report itself")
+	}
+}
+
+func TestResultsOnStdout(t *testing.T) {
+	withColor(t, false)
+
+	var stdout, stderr bytes.Buffer
+	oldOutput, oldError := color.Output, color.Error
+	color.Output, color.Error = &stdout, &stderr
+	t.Cleanup(func() { color.Output, color.Error = oldOutput, oldError })
+
+	Info("Posting status...")
+	Note("Status: hello")
+	Prompt("Post this? (y/N): ")
+	Success("Status posted!")
+	Result("1", "https://example.social/@me/1")
+	Plain("hello")
+
+	if want := "https://example.social/@me/1\nhello\n"; stdout.String() != want {
+		t.Errorf("Expected only results on stdout, got %q", stdout.String())
+	}
+	if want := "Posting status...\nStatus: hello\nPost this? (y/N): ✓ Status posted!\n"; stderr.String() != want {
+		t.Errorf("Expected the messages on stderr, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,384 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Format selects how listings are rendered
+type Format string
+
+const (
+	FormatPlain Format = "plain"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatTable Format = "table"
+
+	// FormatID and FormatURL print only the ID or URL of each thing a command
+	// makes or lists, one per line, for command substitution and xargs
+	FormatID  Format = "id"
+	FormatURL Format = "url"
+)
+
+// Renderer draws a listing in one format
+type Renderer interface {
+	Render(w io.Writer, listing *Listing) error
+}
+
+var renderers = map[Format]Renderer{
+	FormatPlain: plainRenderer{},
+	FormatJSON:  jsonRenderer{},
+	FormatYAML:  yamlRenderer{},
+	FormatTable: tableRenderer{},
+	FormatID:    fieldRenderer{key: "id"},
+	FormatURL:   fieldRenderer{key: "url"},
+}
+
+var format = FormatPlain
+
+// ParseFormat checks the name of an output format
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(s))
+	if _, ok := renderers[f]; !ok {
+		return "", fmt.Errorf("invalid output format %q: must be plain, json, yaml, table, id, or url", s)
+	}
+	return f, nil
+}
+
+// SetFormat changes the format listings are rendered in. JSON and YAML are
+// meant for other programs, so informational messages are dropped and
+// warnings and errors go to stderr. With IDs or URLs, every message goes to
+// stderr instead.
+func SetFormat(f Format) {
+	format = f
+}
+
+// structured reports whether stdout is reserved for machine-readable output
+func structured() bool {
+	return format == FormatJSON || format == FormatYAML
+}
+
+// bare reports whether stdout is reserved for IDs or URLs alone
+func bare() bool {
+	return format == FormatID || format == FormatURL
+}
+
+// Href is a URL in a listing. Plain output puts it on its own line, as a link.
+type Href string
+
+// Sparkline is a series of counts in a listing, drawn as a tiny bar chart in
+// plain and table output and kept as numbers in JSON and YAML
+type Sparkline []int
+
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// String draws the counts scaled between the smallest and largest, so the
+// shape shows the trend whatever its size
+func (s Sparkline) String() string {
+	if len(s) == 0 {
+		return ""
+	}
+
+	lo, hi := s[0], s[0]
+	for _, n := range s {
+		lo, hi = min(lo, n), max(hi, n)
+	}
+
+	bars := make([]rune, len(s))
+	for i, n := range s {
+		if hi == lo {
+			bars[i] = sparkBars[0]
+			continue
+		}
+		bars[i] = sparkBars[(n-lo)*(len(sparkBars)-1)/(hi-lo)]
+	}
+	return string(bars)
+}
+
+// Column is one field of a listing
+type Column struct {
+	Key    string // the field's name in JSON and YAML
+	Header string // its heading in tables
+
+	// For plain and table output: text is cut to Width if set, and Prefix
+	// and Suffix are added around it
+	Width  int
+	Prefix string
+	Suffix string
+
+	// Detail columns are left out of plain output
+	Detail bool
+}
+
+// Listing is rows of values under named columns, which can be rendered in
+// any format. Values are strings, numbers, bools, times, durations, Hrefs, or
+// Sparklines.
+// In JSON and YAML, times are RFC 3339 and durations are milliseconds.
+type Listing struct {
+	Columns []Column
+	Rows    [][]any
+
+	// Empty is shown in plain and table output when there are no rows
+	Empty string
+
+	// Tabular listings are aligned in columns in plain output too
+	Tabular bool
+
+	// Plain, if set, prints row i in plain output in place of the default of
+	// joining its values
+	Plain func(i int)
+}
+
+// Render prints a listing to stdout in the format chosen with SetFormat
+func Render(listing *Listing) error {
+	return renderers[format].Render(os.Stdout, listing)
+}
+
+// text is a value as shown in plain and table output
+func text(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case Href:
+		return string(v)
+	case Sparkline:
+		return v.String()
+	case bool:
+		if v {
+			return "yes"
+		}
+		return ""
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Local().Format("2006-01-02 15:04")
+	case time.Duration:
+		if v < time.Second {
+			return fmt.Sprintf("%dms", v.Milliseconds())
+		}
+		return fmt.Sprintf("%.1fs", v.Seconds())
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// cell is a value as shown in a plain or table column
+func cell(column Column, value any) string {
+	s := text(value)
+	if s == "" {
+		return ""
+	}
+	if column.Width > 3 && len(s) > column.Width {
+		s = s[:column.Width-3] + "..."
+	}
+	return column.Prefix + s + column.Suffix
+}
+
+// isZero reports whether plain output should leave a value out
+func isZero(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case int:
+		return v == 0
+	case int64:
+		return v == 0
+	default:
+		return text(value) == ""
+	}
+}
+
+// structuredValue is a value as written in JSON and YAML
+func structuredValue(value any) any {
+	switch v := value.(type) {
+	case Href:
+		return string(v)
+	case Sparkline:
+		return []int(v)
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339)
+	case time.Duration:
+		return v.Milliseconds()
+	default:
+		return v
+	}
+}
+
+type plainRenderer struct{}
+
+func (plainRenderer) Render(w io.Writer, listing *Listing) error {
+	if len(listing.Rows) == 0 {
+		if listing.Empty != "" {
+			Info("%s", listing.Empty)
+		}
+		return nil
+	}
+
+	if listing.Tabular {
+		return tableRenderer{}.Render(w, listing)
+	}
+
+	for i, row := range listing.Rows {
+		if listing.Plain != nil {
+			listing.Plain(i)
+			continue
+		}
+
+		var fields, links []string
+		for j, column := range listing.Columns {
+			if column.Detail {
+				continue
+			}
+			if href, ok := row[j].(Href); ok {
+				if href != "" {
+					links = append(links, Link(string(href), urlColor.Sprint(string(href))))
+				}
+				continue
+			}
+			if isZero(row[j]) {
+				continue
+			}
+			fields = append(fields, cell(column, row[j]))
+		}
+
+		fmt.Fprintln(w, strings.Join(fields, "  "))
+		for _, link := range links {
+			fmt.Fprintln(w, "    "+link)
+		}
+	}
+	return nil
+}
+
+type tableRenderer struct{}
+
+func (tableRenderer) Render(w io.Writer, listing *Listing) error {
+	if len(listing.Rows) == 0 {
+		if listing.Empty != "" {
+			Info("%s", listing.Empty)
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	headers := make([]string, len(listing.Columns))
+	for j, column := range listing.Columns {
+		headers[j] = column.Header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, row := range listing.Rows {
+		cells := make([]string, len(listing.Columns))
+		for j, column := range listing.Columns {
+			cells[j] = cell(column, row[j])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(w io.Writer, listing *Listing) error {
+	// Objects are written by hand to keep the columns in order
+	var b bytes.Buffer
+	b.WriteString("[")
+	for i, row := range listing.Rows {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  {")
+		for j, column := range listing.Columns {
+			if j > 0 {
+				b.WriteString(",")
+			}
+			key, _ := json.Marshal(column.Key)
+			value, err := json.Marshal(structuredValue(row[j]))
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", column.Key, err)
+			}
+			fmt.Fprintf(&b, "\n    %s: %s", key, value)
+		}
+		b.WriteString("\n  }")
+	}
+	if len(listing.Rows) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+type yamlRenderer struct{}
+
+func (yamlRenderer) Render(w io.Writer, listing *Listing) error {
+	if len(listing.Rows) == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+
+	var b bytes.Buffer
+	for _, row := range listing.Rows {
+		for j, column := range listing.Columns {
+			indent := "  "
+			if j == 0 {
+				indent = "- "
+			}
+			value, err := yamlScalar(structuredValue(row[j]))
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", column.Key, err)
+			}
+			fmt.Fprintf(&b, "%s%s: %s\n", indent, column.Key, value)
+		}
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// fieldRenderer writes one field of each row, alone on its line
+type fieldRenderer struct {
+	key string
+}
+
+func (r fieldRenderer) Render(w io.Writer, listing *Listing) error {
+	for j, column := range listing.Columns {
+		if column.Key != r.key {
+			continue
+		}
+		for _, row := range listing.Rows {
+			if s := text(row[j]); s != "" {
+				fmt.Fprintln(w, s)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("this listing has no %s field; use --output plain, json, yaml, or table", r.key)
+}
+
+// yamlScalar writes a value as a YAML scalar. Strings are double-quoted
+// using JSON's escapes, which YAML accepts as well.
+func yamlScalar(value any) (string, error) {
+	if value == nil {
+		return "null", nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,228 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func testListing() *Listing {
+	return &Listing{
+		Columns: []Column{
+			{Key: "name", Header: "NAME", Prefix: "#"},
+			{Key: "count", Header: "COUNT"},
+			{Key: "note", Header: "NOTE", Width: 8},
+			{Key: "pinned", Header: "PINNED", Detail: true},
+			{Key: "latency_ms", Header: "LATENCY"},
+			{Key: "at", Header: "AT"},
+			{Key: "url", Header: "URL"},
+		},
+		Rows: [][]any{
+			{"golang", 3, "a \"quoted\" note", true, 1500 * time.Millisecond,
+				time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), Href("https://example.com/tags/golang")},
+			{"rust", 0, "", false, 20 * time.Millisecond, time.Time{}, Href("")},
+		},
+	}
+}
+
+// withUTC makes local time UTC, since plain output shows times in local time
+func withUTC(t *testing.T) {
+	t.Helper()
+	old := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = old })
+}
+
+func render(t *testing.T, f Format, listing *Listing) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := renderers[f].Render(&buf, listing); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"plain", "json", "YAML", "table", "id", "url"} {
+		if _, err := ParseFormat(name); err != nil {
+			t.Errorf("Expected %q to be accepted, got %v", name, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+}
+
+func TestRenderPlain(t *testing.T) {
+	withColor(t, false)
+
+	want := "#golang  3  a \"qu...  1.5s  2026-03-01 12:00\n" +
+		"    https://example.com/tags/golang\n" +
+		"#rust  20ms\n"
+
+	withUTC(t)
+	if got := render(t, FormatPlain, testListing()); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderPlainHook(t *testing.T) {
+	var shown []int
+	listing := testListing()
+	listing.Plain = func(i int) { shown = append(shown, i) }
+
+	if got := render(t, FormatPlain, listing); got != "" {
+		t.Errorf("Expected the hook to do the printing, got %q", got)
+	}
+	if len(shown) != 2 || shown[0] != 0 || shown[1] != 1 {
+		t.Errorf("Expected the hook to be called for each row, got %v", shown)
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	withColor(t, false)
+	withUTC(t)
+
+	want := "NAME     COUNT  NOTE      PINNED  LATENCY  AT                URL\n" +
+		"#golang  3      a \"qu...  yes     1.5s     2026-03-01 12:00  https://example.com/tags/golang\n" +
+		"#rust    0                        20ms                       \n"
+	if got := render(t, FormatTable, testListing()); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	want := `[
+  {
+    "name": "golang",
+    "count": 3,
+    "note": "a \"quoted\" note",
+    "pinned": true,
+    "latency_ms": 1500,
+    "at": "2026-03-01T12:00:00Z",
+    "url": "https://example.com/tags/golang"
+  },
+  {
+    "name": "rust",
+    "count": 0,
+    "note": "",
+    "pinned": false,
+    "latency_ms": 20,
+    "at": null,
+    "url": ""
+  }
+]
+`
+	if got := render(t, FormatJSON, testListing()); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	if got := render(t, FormatJSON, &Listing{}); got != "[]\n" {
+		t.Errorf("Expected an empty array, got %q", got)
+	}
+}
+
+func TestRenderYAML(t *testing.T) {
+	want := `- name: "golang"
+  count: 3
+  note: "a \"quoted\" note"
+  pinned: true
+  latency_ms: 1500
+  at: "2026-03-01T12:00:00Z"
+  url: "https://example.com/tags/golang"
+- name: "rust"
+  count: 0
+  note: ""
+  pinned: false
+  latency_ms: 20
+  at: null
+  url: ""
+`
+	if got := render(t, FormatYAML, testListing()); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	if got := render(t, FormatYAML, &Listing{}); got != "[]\n" {
+		t.Errorf("Expected an empty list, got %q", got)
+	}
+}
+
+func TestStructuredFormats(t *testing.T) {
+	t.Cleanup(func() { SetFormat(FormatPlain) })
+
+	SetFormat(FormatJSON)
+	if !structured() {
+		t.Error("Expected JSON to be structured")
+	}
+	SetFormat(FormatTable)
+	if structured() {
+		t.Error("Expected tables not to be structured")
+	}
+}
+
+func TestRenderField(t *testing.T) {
+	if got := render(t, FormatURL, testListing()); got != "https://example.com/tags/golang\n" {
+		t.Errorf("Expected only the non-empty URL, got %q", got)
+	}
+
+	var buf bytes.Buffer
+	if err := renderers[FormatID].Render(&buf, testListing()); err == nil {
+		t.Error("Expected an error for a listing without IDs, got nil")
+	}
+}
+
+func TestBareMovesMessagesToStderr(t *testing.T) {
+	withColor(t, false)
+	t.Cleanup(func() { SetFormat(FormatPlain) })
+
+	var stdout, stderr bytes.Buffer
+	oldOutput, oldError := color.Output, color.Error
+	color.Output, color.Error = &stdout, &stderr
+	t.Cleanup(func() { color.Output, color.Error = oldOutput, oldError })
+
+	SetFormat(FormatID)
+	Info("Posting status...")
+	Success("Status posted!")
+	URL("https://example.social/@me/1")
+
+	Result("1", "https://example.social/@me/1")
+
+	if stdout.String() != "1\n" {
+		t.Errorf("Expected only the ID on stdout, got %q", stdout.String())
+	}
+	if want := "Posting status...\n✓ Status posted!\nhttps://example.social/@me/1\n"; stderr.String() != want {
+		t.Errorf("Expected the messages on stderr, got %q", stderr.String())
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		counts Sparkline
+		want   string
+	}{
+		{Sparkline{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{Sparkline{10, 100}, "▁█"},
+		{Sparkline{120, 118, 119}, "█▁▄"},
+		{Sparkline{0, 0, 0}, "▁▁▁"},
+		{Sparkline{}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.counts.String(); got != tt.want {
+			t.Errorf("Sparkline%v: expected %q, got %q", []int(tt.counts), tt.want, got)
+		}
+	}
+
+	listing := &Listing{
+		Columns: []Column{{Key: "week", Header: "WEEK"}},
+		Rows:    [][]any{{Sparkline{1, 2}}},
+	}
+	if got := render(t, FormatJSON, listing); got != "[\n  {\n    \"week\": [1,2]\n  }\n]\n" {
+		t.Errorf("Expected counts in JSON, got %q", got)
+	}
+	if got := render(t, FormatPlain, listing); got != "▁█\n" {
+		t.Errorf("Expected a sparkline in plain output, got %q", got)
+	}
+}
@@ -0,0 +1,117 @@
+package output
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Rich rendering of status text for terminals: clickable links, colored
+// mentions and hashtags, and content warning markers. Everything falls back
+// to plain text when output isn't a terminal (or NO_COLOR is set), since
+// color.NoColor covers both.
+
+var (
+	mentionColor = color.New(color.FgMagenta)
+	hashtagColor = color.New(color.FgCyan)
+	cwColor      = color.New(color.FgYellow, color.Bold)
+)
+
+var (
+	urlPattern     = regexp.MustCompile(`https?://[^\s<>"]+[^\s<>".,;:!?)\]]`)
+	mentionPattern = regexp.MustCompile(`(^|[^\w/])(@\w+(?:@[\w.-]+\w)?)`)
+	hashtagPattern = regexp.MustCompile(`(^|[^\w/&#])(#\w+)`)
+)
+
+// Link makes text a clickable link to url in terminals that support OSC 8
+// hyperlinks, and returns text unchanged elsewhere
+func Link(url, text string) string {
+	if !hyperlinksSupported() {
+		return text
+	}
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// hyperlinksSupported guesses whether the terminal understands OSC 8 links.
+// Terminals that don't would print the escape codes, so only ones known to
+// handle them get links. FORCE_HYPERLINK=1 or 0 overrides the guess.
+func hyperlinksSupported() bool {
+	if color.NoColor {
+		return false
+	}
+	if force := os.Getenv("FORCE_HYPERLINK"); force != "" {
+		enabled, err := strconv.ParseBool(force)
+		return err == nil && enabled
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	if vte, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+
+	term := os.Getenv("TERM")
+	return strings.Contains(term, "kitty") || strings.Contains(term, "alacritty") ||
+		strings.Contains(term, "foot") || strings.Contains(term, "ghostty")
+}
+
+// Highlight colors the @mentions and #hashtags in status text and makes its
+// URLs clickable
+func Highlight(text string) string {
+	if color.NoColor {
+		return text
+	}
+
+	// URLs first, so mentions and tags inside them are left alone
+	var b strings.Builder
+	last := 0
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		b.WriteString(highlightWords(text[last:loc[0]]))
+		url := text[loc[0]:loc[1]]
+		b.WriteString(Link(url, urlColor.Sprint(url)))
+		last = loc[1]
+	}
+	b.WriteString(highlightWords(text[last:]))
+
+	return b.String()
+}
+
+func highlightWords(text string) string {
+	text = mentionPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := mentionPattern.FindStringSubmatch(match)
+		return parts[1] + mentionColor.Sprint(parts[2])
+	})
+	return hashtagPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := hashtagPattern.FindStringSubmatch(match)
+		return parts[1] + hashtagColor.Sprint(parts[2])
+	})
+}
+
+// Fold renders a status body behind its content warning. Folded, only the
+// warning is shown; unfolded, the body follows it. Text without a content
+// warning is returned as is.
+func Fold(spoiler, body string, unfolded bool) string {
+	if spoiler == "" {
+		return body
+	}
+
+	if color.NoColor {
+		if !unfolded {
+			return "[CW: " + spoiler + "]"
+		}
+		return "[CW: " + spoiler + "]\n" + body
+	}
+
+	if !unfolded {
+		return cwColor.Sprint("▸ CW: "+spoiler) + " (hidden)"
+	}
+	return cwColor.Sprint("▾ CW: "+spoiler) + "\n" + body
+}
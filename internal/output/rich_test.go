@@ -0,0 +1,95 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// withColor forces color output on or off for the rest of the test
+func withColor(t *testing.T, enabled bool) {
+	t.Helper()
+	old := color.NoColor
+	color.NoColor = !enabled
+	t.Cleanup(func() { color.NoColor = old })
+}
+
+func TestHighlightPlainWhenNoColor(t *testing.T) {
+	withColor(t, false)
+	t.Setenv("FORCE_HYPERLINK", "1")
+
+	text := "hi @alice@example.com see https://example.com/#top #golang"
+	if got := Highlight(text); got != text {
+		t.Errorf("Expected text unchanged without color, got %q", got)
+	}
+	if got := Link("https://example.com", "example"); got != "example" {
+		t.Errorf("Expected plain link text, got %q", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	withColor(t, true)
+	t.Setenv("FORCE_HYPERLINK", "1")
+
+	got := Highlight("hi @alice@example.com, see https://example.com/@bob#top. #golang&go")
+
+	if !strings.Contains(got, mentionColor.Sprint("@alice@example.com")) {
+		t.Errorf("Expected mention to be colored: %q", got)
+	}
+	if !strings.Contains(got, hashtagColor.Sprint("#golang")) {
+		t.Errorf("Expected hashtag to be colored: %q", got)
+	}
+	if !strings.Contains(got, "\x1b]8;;https://example.com/@bob#top\x1b\\") {
+		t.Errorf("Expected a hyperlink without the trailing period: %q", got)
+	}
+	if strings.Contains(got, mentionColor.Sprint("@bob")) || strings.Contains(got, hashtagColor.Sprint("#top")) {
+		t.Errorf("Expected the URL's contents to be left alone: %q", got)
+	}
+}
+
+func TestHighlightIgnoresEmails(t *testing.T) {
+	withColor(t, true)
+
+	got := Highlight("mail me@example.com")
+	if strings.Contains(got, mentionColor.Sprint("@example.com")) {
+		t.Errorf("Expected an email address not to be a mention: %q", got)
+	}
+}
+
+func TestLinkRespectsForceHyperlink(t *testing.T) {
+	withColor(t, true)
+
+	t.Setenv("FORCE_HYPERLINK", "0")
+	t.Setenv("TERM_PROGRAM", "iTerm.app")
+	if got := Link("https://example.com", "x"); got != "x" {
+		t.Errorf("Expected FORCE_HYPERLINK=0 to disable links, got %q", got)
+	}
+
+	t.Setenv("FORCE_HYPERLINK", "")
+	if got := Link("https://example.com", "x"); !strings.HasPrefix(got, "\x1b]8;;") {
+		t.Errorf("Expected a link in iTerm, got %q", got)
+	}
+}
+
+func TestFold(t *testing.T) {
+	withColor(t, false)
+
+	if got := Fold("", "body", false); got != "body" {
+		t.Errorf("Expected body without a CW, got %q", got)
+	}
+	if got := Fold("spoilers", "body", false); got != "[CW: spoilers]" {
+		t.Errorf("Unexpected folded text: %q", got)
+	}
+	if got := Fold("spoilers", "body", true); got != "[CW: spoilers]\nbody" {
+		t.Errorf("Unexpected unfolded text: %q", got)
+	}
+
+	withColor(t, true)
+	if got := Fold("spoilers", "body", false); strings.Contains(got, "body") || !strings.Contains(got, "▸ CW: spoilers") {
+		t.Errorf("Unexpected folded text: %q", got)
+	}
+	if got := Fold("spoilers", "body", true); !strings.HasSuffix(got, "\nbody") || !strings.Contains(got, "▾ CW: spoilers") {
+		t.Errorf("Unexpected unfolded text: %q", got)
+	}
+}
@@ -0,0 +1,124 @@
+// Package prefetch runs speculative fetches in the background, so results are
+// often ready by the time they're needed.
+package prefetch
+
+import (
+	"context"
+	"sync"
+)
+
+// Fetcher runs fetches keyed by name on a bounded number of workers. Each key
+// is fetched at most once while it's running or after it succeeds; asking for
+// it again shares that result. A fetch that fails is forgotten once it
+// returns, so the next request for the key tries again.
+type Fetcher[T any] struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers chan struct{}
+
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+type call[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// New returns a Fetcher that runs at most workers fetches at once
+func New[T any](workers int) *Fetcher[T] {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Fetcher[T]{
+		ctx:     ctx,
+		cancel:  cancel,
+		workers: make(chan struct{}, workers),
+		calls:   make(map[string]*call[T]),
+	}
+}
+
+// Start schedules fn to fetch key in the background, unless key has already
+// been started. fn receives a context that's cancelled by Close.
+func (f *Fetcher[T]) Start(key string, fn func(ctx context.Context) (T, error)) {
+	f.start(key, fn)
+}
+
+// start is Start, returning the call for key, which a failed fetch may have
+// removed from calls by the time the caller looks
+func (f *Fetcher[T]) start(key string, fn func(ctx context.Context) (T, error)) *call[T] {
+	f.mu.Lock()
+	if c, ok := f.calls[key]; ok {
+		f.mu.Unlock()
+		return c
+	}
+	c := &call[T]{done: make(chan struct{})}
+	f.calls[key] = c
+	f.mu.Unlock()
+
+	go func() {
+		defer close(c.done)
+		defer func() {
+			if c.err != nil {
+				f.forget(key, c)
+			}
+		}()
+
+		select {
+		case f.workers <- struct{}{}:
+			defer func() { <-f.workers }()
+		case <-f.ctx.Done():
+			c.err = f.ctx.Err()
+			return
+		}
+
+		// Closed while waiting for a worker
+		if err := f.ctx.Err(); err != nil {
+			c.err = err
+			return
+		}
+
+		c.value, c.err = fn(f.ctx)
+	}()
+	return c
+}
+
+// forget drops a failed call for key, unless a newer one has replaced it
+func (f *Fetcher[T]) forget(key string, c *call[T]) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls[key] == c {
+		delete(f.calls, key)
+	}
+}
+
+// Get starts fetching key if needed and waits for the result
+func (f *Fetcher[T]) Get(key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	c := f.start(key, fn)
+	<-c.done
+	return c.value, c.err
+}
+
+// Peek returns the result for key if its fetch has finished. ok is false if
+// it hasn't been started, is still running, or failed.
+func (f *Fetcher[T]) Peek(key string) (value T, err error, ok bool) {
+	f.mu.Lock()
+	c, started := f.calls[key]
+	f.mu.Unlock()
+
+	if !started {
+		return value, nil, false
+	}
+
+	select {
+	case <-c.done:
+		return c.value, c.err, true
+	default:
+		return value, nil, false
+	}
+}
+
+// Close cancels fetches that haven't started yet and tells running ones to
+// stop. Results already fetched can still be read.
+func (f *Fetcher[T]) Close() {
+	f.cancel()
+}
@@ -0,0 +1,133 @@
+package prefetch
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetSharesResult(t *testing.T) {
+	f := New[int](2)
+	defer f.Close()
+
+	var calls atomic.Int32
+	fetch := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		return 42, nil
+	}
+
+	f.Start("a", fetch)
+	value, err := f.Get("a", fetch)
+	if err != nil || value != 42 {
+		t.Fatalf("Get() = %d, %v; want 42, nil", value, err)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected one fetch, got %d", calls.Load())
+	}
+
+	value, err, ok := f.Peek("a")
+	if !ok || err != nil || value != 42 {
+		t.Errorf("Peek() = %d, %v, %v; want 42, nil, true", value, err, ok)
+	}
+}
+
+func TestPeekNotStarted(t *testing.T) {
+	f := New[string](1)
+	defer f.Close()
+
+	if _, _, ok := f.Peek("missing"); ok {
+		t.Error("Expected Peek to report a key that was never started as not ready")
+	}
+}
+
+func TestWorkersBounded(t *testing.T) {
+	f := New[int](2)
+	defer f.Close()
+
+	var running, peak atomic.Int32
+	fetch := func(ctx context.Context) (int, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+		return 0, nil
+	}
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	for _, key := range keys {
+		f.Start(key, fetch)
+	}
+	for _, key := range keys {
+		f.Get(key, fetch)
+	}
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent fetches, saw %d", peak.Load())
+	}
+}
+
+func TestCloseCancelsPending(t *testing.T) {
+	f := New[int](1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	f.Start("busy", func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		return 1, nil
+	})
+
+	// The first fetch holds the only worker
+	<-started
+	f.Start("queued", func(ctx context.Context) (int, error) {
+		return 2, nil
+	})
+	f.Close()
+	close(release)
+
+	_, err := f.Get("queued", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected queued fetch to be cancelled, got %v", err)
+	}
+
+	value, err := f.Get("busy", nil)
+	if err != nil || value != 1 {
+		t.Errorf("Expected running fetch to finish, got %d, %v", value, err)
+	}
+}
+
+func TestFailedFetchRetried(t *testing.T) {
+	f := New[int](1)
+	defer f.Close()
+
+	var calls atomic.Int32
+	fetch := func(ctx context.Context) (int, error) {
+		if calls.Add(1) == 1 {
+			return 0, errors.New("temporary failure")
+		}
+		return 7, nil
+	}
+
+	if _, err := f.Get("a", fetch); err == nil {
+		t.Fatal("Expected the first fetch to fail")
+	}
+	if _, _, ok := f.Peek("a"); ok {
+		t.Error("Expected a failed fetch to be forgotten")
+	}
+
+	value, err := f.Get("a", fetch)
+	if err != nil || value != 7 {
+		t.Errorf("Get() = %d, %v; want 7, nil", value, err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected the key to be fetched again, got %d fetches", calls.Load())
+	}
+}
@@ -0,0 +1,131 @@
+// Package stats summarizes an account's posting habits from its statuses.
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+// TagCount is how many of the summarized statuses used a hashtag
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+// Summary is what a set of statuses says about when and how an account posts
+type Summary struct {
+	Posts int
+
+	// The oldest and newest of the statuses
+	First time.Time
+	Last  time.Time
+
+	// Posts by local day of the week (Sunday first) and hour of the day
+	ByWeekday [7]int
+	ByHour    [24]int
+
+	// Hashtags by how many posts used them, most used first
+	Tags []TagCount
+
+	Favourites int
+	Reblogs    int
+	Replies    int
+}
+
+// Summarize tallies statuses, counting days and hours in loc
+func Summarize(statuses []*mastodon.Status, loc *time.Location) *Summary {
+	summary := &Summary{}
+	tags := make(map[string]*TagCount)
+
+	for _, status := range statuses {
+		summary.Posts++
+
+		at := status.CreatedAt.In(loc)
+		if summary.First.IsZero() || at.Before(summary.First) {
+			summary.First = at
+		}
+		if at.After(summary.Last) {
+			summary.Last = at
+		}
+		summary.ByWeekday[at.Weekday()]++
+		summary.ByHour[at.Hour()]++
+
+		summary.Favourites += status.FavouritesCount
+		summary.Reblogs += status.ReblogsCount
+		summary.Replies += status.RepliesCount
+
+		// Tags differ only in case between posts, so count them together
+		// under the first spelling seen
+		seen := make(map[string]bool)
+		for _, tag := range status.Tags {
+			key := strings.ToLower(tag.Name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if tags[key] == nil {
+				tags[key] = &TagCount{Name: tag.Name}
+			}
+			tags[key].Count++
+		}
+	}
+
+	for _, tag := range tags {
+		summary.Tags = append(summary.Tags, *tag)
+	}
+	sort.Slice(summary.Tags, func(i, j int) bool {
+		if summary.Tags[i].Count != summary.Tags[j].Count {
+			return summary.Tags[i].Count > summary.Tags[j].Count
+		}
+		return summary.Tags[i].Name < summary.Tags[j].Name
+	})
+
+	return summary
+}
+
+// AverageFavourites is the mean number of favourites per post
+func (s *Summary) AverageFavourites() float64 {
+	return s.average(s.Favourites)
+}
+
+// AverageReblogs is the mean number of boosts per post
+func (s *Summary) AverageReblogs() float64 {
+	return s.average(s.Reblogs)
+}
+
+// AverageReplies is the mean number of replies per post
+func (s *Summary) AverageReplies() float64 {
+	return s.average(s.Replies)
+}
+
+func (s *Summary) average(total int) float64 {
+	if s.Posts == 0 {
+		return 0
+	}
+	return float64(total) / float64(s.Posts)
+}
+
+// PerWeek is how many posts a week the account made between its first and
+// last summarized status, counting at least a week
+func (s *Summary) PerWeek() float64 {
+	weeks := s.Last.Sub(s.First).Hours() / (24 * 7)
+	if weeks < 1 {
+		weeks = 1
+	}
+	return float64(s.Posts) / weeks
+}
+
+// BusiestHour is the hour of the day with the most posts, the earliest if
+// there's a tie
+func (s *Summary) BusiestHour() int {
+	busiest := 0
+	for hour, n := range s.ByHour {
+		if n > s.ByHour[busiest] {
+			busiest = hour
+		}
+	}
+	return busiest
+}
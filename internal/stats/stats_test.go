@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"biesnecker.com/tusk/pkg/mastodon"
+)
+
+func status(at string, favs, boosts int, tags ...string) *mastodon.Status {
+	createdAt, _ := time.Parse(time.RFC3339, at)
+	s := &mastodon.Status{CreatedAt: createdAt, FavouritesCount: favs, ReblogsCount: boosts}
+	for _, name := range tags {
+		s.Tags = append(s.Tags, &mastodon.Tag{Name: name})
+	}
+	return s
+}
+
+func TestSummarize(t *testing.T) {
+	statuses := []*mastodon.Status{
+		status("2026-03-16T09:30:00Z", 4, 1, "golang", "Caturday"), // Monday
+		status("2026-03-09T09:10:00Z", 2, 0, "GoLang", "golang"),   // Monday
+		status("2026-03-01T09:30:00Z", 0, 2),                       // Sunday
+	}
+
+	summary := Summarize(statuses, time.UTC)
+
+	if summary.Posts != 3 {
+		t.Errorf("Expected 3 posts, got %d", summary.Posts)
+	}
+	if summary.ByWeekday[time.Monday] != 2 || summary.ByWeekday[time.Sunday] != 1 {
+		t.Errorf("Unexpected weekdays %v", summary.ByWeekday)
+	}
+	if summary.ByHour[9] != 3 || summary.BusiestHour() != 9 {
+		t.Errorf("Unexpected hours %v", summary.ByHour)
+	}
+	if !summary.First.Equal(statuses[2].CreatedAt) || !summary.Last.Equal(statuses[0].CreatedAt) {
+		t.Errorf("Unexpected range %v to %v", summary.First, summary.Last)
+	}
+
+	// Case is ignored, and a tag counts once per post
+	if len(summary.Tags) != 2 || summary.Tags[0] != (TagCount{"golang", 2}) || summary.Tags[1] != (TagCount{"Caturday", 1}) {
+		t.Errorf("Unexpected tags %v", summary.Tags)
+	}
+
+	if summary.AverageFavourites() != 2 || summary.AverageReblogs() != 1 {
+		t.Errorf("Expected averages of 2 favourites and 1 boost, got %v and %v",
+			summary.AverageFavourites(), summary.AverageReblogs())
+	}
+
+	// 3 posts over 15 days
+	if got := summary.PerWeek(); math.Abs(got-1.4) > 0.01 {
+		t.Errorf("Expected 1.4 posts a week, got %v", got)
+	}
+}
+
+func TestSummarizeInLocation(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	summary := Summarize([]*mastodon.Status{status("2026-03-15T20:00:00Z", 0, 0)}, tokyo)
+
+	// 8pm Sunday in UTC is 5am Monday in Tokyo
+	if summary.ByWeekday[time.Monday] != 1 || summary.ByHour[5] != 1 {
+		t.Errorf("Expected Monday at 5am, got %v and %v", summary.ByWeekday, summary.ByHour)
+	}
+}
+
+func TestSummarizeNothing(t *testing.T) {
+	summary := Summarize(nil, time.UTC)
+	if summary.Posts != 0 || summary.AverageFavourites() != 0 || summary.PerWeek() != 0 {
+		t.Errorf("Expected an empty summary, got %+v", summary)
+	}
+}
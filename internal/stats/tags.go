@@ -0,0 +1,76 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// tagHalfLife is how long it takes a use of a tag to count half as much
+// toward suggestions, so tags from a past project fade out
+const tagHalfLife = 30 * 24 * time.Hour
+
+// TaggedPost is the hashtags one post used, and when
+type TaggedPost struct {
+	Tags []string
+	At   time.Time
+}
+
+// TagSuggestion is a hashtag ranked for suggesting in a new post
+type TagSuggestion struct {
+	Name     string
+	Count    int
+	LastUsed time.Time
+
+	// Score counts each use, weighted down by its age
+	Score float64
+}
+
+// RankTags ranks the hashtags used in posts by how often and how recently
+// they were used, best first. Tags differing only in case are counted
+// together under their most recent spelling.
+func RankTags(posts []TaggedPost, now time.Time) []TagSuggestion {
+	tags := make(map[string]*TagSuggestion)
+
+	for _, post := range posts {
+		age := now.Sub(post.At)
+		if age < 0 {
+			age = 0
+		}
+		weight := math.Pow(0.5, float64(age)/float64(tagHalfLife))
+
+		seen := make(map[string]bool)
+		for _, name := range post.Tags {
+			key := strings.ToLower(name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			tag := tags[key]
+			if tag == nil {
+				tag = &TagSuggestion{Name: name}
+				tags[key] = tag
+			}
+			tag.Count++
+			tag.Score += weight
+			if post.At.After(tag.LastUsed) {
+				tag.LastUsed = post.At
+				tag.Name = name
+			}
+		}
+	}
+
+	ranked := make([]TagSuggestion, 0, len(tags))
+	for _, tag := range tags {
+		ranked = append(ranked, *tag)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return strings.ToLower(ranked[i].Name) < strings.ToLower(ranked[j].Name)
+	})
+	return ranked
+}
@@ -0,0 +1,45 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRankTags(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	posts := []TaggedPost{
+		// Used often, but long ago
+		{Tags: []string{"inktober"}, At: daysAgo(360)},
+		{Tags: []string{"inktober"}, At: daysAgo(361)},
+		{Tags: []string{"inktober"}, At: daysAgo(362)},
+		// Used less, but lately
+		{Tags: []string{"golang", "caturday"}, At: daysAgo(2)},
+		{Tags: []string{"GoLang", "golang"}, At: daysAgo(1)},
+	}
+
+	ranked := RankTags(posts, now)
+	if len(ranked) != 3 {
+		t.Fatalf("Expected 3 tags, got %+v", ranked)
+	}
+
+	if ranked[0].Name != "GoLang" || ranked[0].Count != 2 {
+		t.Errorf("Expected GoLang used twice first, got %+v", ranked[0])
+	}
+	if !ranked[0].LastUsed.Equal(daysAgo(1)) {
+		t.Errorf("Expected GoLang last used a day ago, got %v", ranked[0].LastUsed)
+	}
+	if ranked[1].Name != "caturday" || ranked[2].Name != "inktober" || ranked[2].Count != 3 {
+		t.Errorf("Expected recent tags before old ones, got %+v", ranked)
+	}
+}
+
+func TestRankTagsTies(t *testing.T) {
+	at := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	ranked := RankTags([]TaggedPost{{Tags: []string{"zines", "Art"}, At: at}}, at)
+
+	if len(ranked) != 2 || ranked[0].Name != "Art" || ranked[1].Name != "zines" {
+		t.Errorf("Expected ties in name order, got %+v", ranked)
+	}
+}
@@ -0,0 +1,282 @@
+// Package update finds tusk's latest release on GitHub and replaces the
+// running binary with it.
+//
+// Releases are expected to carry one binary per platform, named like
+// tusk_linux_amd64 (with .exe on Windows), and a checksums.txt listing the
+// SHA-256 of each in the "HASH  NAME" form sha256sum prints.
+package update
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ChecksumsName is the release asset listing the other assets' checksums
+const ChecksumsName = "checksums.txt"
+
+// Release is a published GitHub release
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Asset returns the release's asset with the given name, or nil
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Checker looks up releases of a GitHub repository
+type Checker struct {
+	// APIURL is GitHub's API, or a stand-in for tests
+	APIURL string
+	// Repo is "owner/name"
+	Repo string
+
+	HTTPClient *http.Client
+}
+
+// Latest returns the newest release that isn't a draft or prerelease
+func (c *Checker) Latest() (*Release, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(c.APIURL, "/"), c.Repo)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("no releases of %s found", c.Repo)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to check for releases: %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+	return &release, nil
+}
+
+// Download fetches an asset's contents
+func (c *Checker) Download(asset *Asset) ([]byte, error) {
+	resp, err := c.HTTPClient.Get(asset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", asset.Name, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	return data, nil
+}
+
+// AssetName is the name of the release binary for a platform
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("tusk_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// VerifyChecksum checks data against its entry in a checksums file
+func VerifyChecksum(data, checksums []byte, name string) error {
+	var want string
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		// sha256sum marks binary-mode files with a leading *
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			want = strings.ToLower(fields[0])
+			break
+		}
+	}
+	if want == "" {
+		return fmt.Errorf("%s has no checksum for %s", ChecksumsName, name)
+	}
+
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, want, got)
+	}
+	return nil
+}
+
+// Replace swaps the binary at path for data, keeping its permissions. The
+// new file is written beside the old one first, so a failure leaves the old
+// binary in place.
+func Replace(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tusk-update-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := io.Copy(tmp, bytes.NewReader(data)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	// Windows won't replace a running executable, but it will rename one
+	old := path + ".old"
+	os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Rename(old, path)
+		return err
+	}
+	os.Remove(old)
+	return nil
+}
+
+// Version is a parsed semantic version
+type Version struct {
+	Major, Minor, Patch int
+	// Pre is the pre-release part, e.g. "rc.1", or "" for a release
+	Pre string
+}
+
+// pseudoVersion matches the versions Go gives builds from a commit that
+// isn't tagged, e.g. v0.0.0-20261015182649-528251f364ab
+var pseudoVersion = regexp.MustCompile(`-(?:\w+\.)?(?:0\.)?\d{14}-[0-9a-f]{12}(?:\+|$)`)
+
+// ParseVersion parses a version like "v1.2.3" or "1.2.3-rc.1". Build
+// metadata after a + is ignored. Go's pseudo-versions for untagged commits
+// aren't releases and are rejected.
+func ParseVersion(s string) (Version, error) {
+	if pseudoVersion.MatchString(s) {
+		return Version{}, fmt.Errorf("%q is a development build", s)
+	}
+
+	v := Version{}
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	rest, _, _ = strings.Cut(rest, "+")
+	rest, v.Pre, _ = strings.Cut(rest, "-")
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Compare returns -1, 0, or 1 as v is older than, the same as, or newer
+// than other. A pre-release comes before its release.
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			return compareInts(pair[0], pair[1])
+		}
+	}
+
+	switch {
+	case v.Pre == other.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case other.Pre == "":
+		return -1
+	}
+	return comparePre(v.Pre, other.Pre)
+}
+
+// comparePre orders pre-release parts as semver does: numeric identifiers
+// by value and below alphanumeric ones, which compare as text, and a shorter
+// list first when one is a prefix of the other
+func comparePre(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return compareInts(an, bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(as), len(bs))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
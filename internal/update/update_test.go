@@ -0,0 +1,145 @@
+package update
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"v1.2.3", Version{1, 2, 3, ""}},
+		{"0.10.0-rc.1", Version{0, 10, 0, "rc.1"}},
+		{"1.0.0+build.5", Version{1, 0, 0, ""}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if err != nil {
+			t.Fatalf("ParseVersion(%q) failed: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"dev", "1.2", "1.x.3", "", "v0.0.0-20261015182649-528251f364ab+dirty", "v1.2.4-0.20261015182649-528251f364ab"} {
+		if _, err := ParseVersion(bad); err == nil {
+			t.Errorf("Expected ParseVersion(%q) to fail", bad)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	// Each is older than the next
+	ordered := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.2.0",
+		"2.0.0",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := ParseVersion(ordered[i])
+		b, _ := ParseVersion(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("Expected %s < %s", a, b)
+		}
+		if a.Compare(a) != 0 {
+			t.Errorf("Expected %s to equal itself", a)
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/tusk/releases/latest" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"tag_name":"v1.4.0","html_url":"https://example.com/r","assets":[{"name":"tusk_linux_amd64","browser_download_url":"https://example.com/a"}]}`))
+	}))
+	defer server.Close()
+
+	checker := &Checker{APIURL: server.URL, Repo: "owner/tusk", HTTPClient: server.Client()}
+	release, err := checker.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if release.TagName != "v1.4.0" {
+		t.Errorf("Unexpected tag %q", release.TagName)
+	}
+	if asset := release.Asset("tusk_linux_amd64"); asset == nil || asset.URL != "https://example.com/a" {
+		t.Errorf("Unexpected asset %+v", asset)
+	}
+	if release.Asset("tusk_plan9_386") != nil {
+		t.Error("Expected no asset for an unknown platform")
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	if got := AssetName("linux", "arm64"); got != "tusk_linux_arm64" {
+		t.Errorf("Unexpected name %q", got)
+	}
+	if got := AssetName("windows", "amd64"); got != "tusk_windows_amd64.exe" {
+		t.Errorf("Unexpected name %q", got)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("new binary")
+	sum := sha256.Sum256(data)
+	checksums := []byte("0000  tusk_darwin_arm64\n" + hex.EncodeToString(sum[:]) + " *tusk_linux_amd64\n")
+
+	if err := VerifyChecksum(data, checksums, "tusk_linux_amd64"); err != nil {
+		t.Errorf("Expected the checksum to match: %v", err)
+	}
+
+	err := VerifyChecksum([]byte("tampered"), checksums, "tusk_linux_amd64")
+	if err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Errorf("Expected a mismatch, got %v", err)
+	}
+
+	err = VerifyChecksum(data, checksums, "tusk_windows_amd64.exe")
+	if err == nil || !strings.Contains(err.Error(), "no checksum") {
+		t.Errorf("Expected a missing checksum, got %v", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tusk")
+	if err := os.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(path, []byte("new")); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "new" {
+		t.Errorf("Expected the new binary, got %q", data)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("Expected permissions to be kept, got %v", info.Mode().Perm())
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected no leftover files, got %d entries", len(entries))
+	}
+}
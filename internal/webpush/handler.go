@@ -0,0 +1,51 @@
+package webpush
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// maxMessageBytes bounds what's read of a push; Web Push messages are at
+// most 4 KiB, plus the encoding's header
+const maxMessageBytes = 8 << 10
+
+// Handler receives pushes encrypted to keys, passing each one's plaintext to
+// deliver. Like a push service, it answers 201 Created once a message is
+// accepted.
+func Handler(keys *Keys, deliver func(message []byte)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageBytes+1))
+		if err != nil {
+			http.Error(w, "failed to read message", http.StatusBadRequest)
+			return
+		}
+		if len(body) > maxMessageBytes {
+			http.Error(w, "message too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		message, err := keys.Decrypt(Headers{
+			ContentEncoding: r.Header.Get("Content-Encoding"),
+			Encryption:      r.Header.Get("Encryption"),
+			CryptoKey:       r.Header.Get("Crypto-Key"),
+		}, body)
+		if errors.Is(err, ErrEncoding) {
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		deliver(message)
+		w.WriteHeader(http.StatusCreated)
+	})
+}
@@ -0,0 +1,255 @@
+// Package webpush receives Web Push messages: it makes the key pair and
+// authentication secret a push subscription is created with, and decrypts
+// what's pushed to it, in both the standard aes128gcm encoding (RFC 8291) and
+// the draft aesgcm one older Mastodon versions send.
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Keys are a receiver's secrets: the private half of its P-256 key pair and
+// the authentication secret shared with the sender
+type Keys struct {
+	private *ecdh.PrivateKey
+	auth    []byte
+}
+
+// GenerateKeys makes new keys for a subscription
+func GenerateKeys() (*Keys, error) {
+	private, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		return nil, fmt.Errorf("failed to generate auth secret: %w", err)
+	}
+	return &Keys{private: private, auth: auth}, nil
+}
+
+// ParseKeys reads keys saved with Private and Auth
+func ParseKeys(private, auth string) (*Keys, error) {
+	privateBytes, err := decode(private)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(privateBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	authBytes, err := decode(auth)
+	if err != nil || len(authBytes) != 16 {
+		return nil, fmt.Errorf("invalid auth secret")
+	}
+	return &Keys{private: key, auth: authBytes}, nil
+}
+
+// Private is the private key, base64url-encoded, to save
+func (k *Keys) Private() string {
+	return base64.RawURLEncoding.EncodeToString(k.private.Bytes())
+}
+
+// Public is the public key, base64url-encoded, as a subscription's p256dh
+func (k *Keys) Public() string {
+	return base64.RawURLEncoding.EncodeToString(k.private.PublicKey().Bytes())
+}
+
+// Auth is the authentication secret, base64url-encoded, as a subscription's
+// auth
+func (k *Keys) Auth() string {
+	return base64.RawURLEncoding.EncodeToString(k.auth)
+}
+
+// Headers are the request headers the draft aesgcm encoding keeps its
+// parameters in
+type Headers struct {
+	ContentEncoding string
+	Encryption      string
+	CryptoKey       string
+}
+
+// ErrEncoding is returned for a push in an encoding other than aes128gcm or
+// aesgcm
+var ErrEncoding = errors.New("unsupported content encoding")
+
+// Decrypt returns the plaintext of a pushed message
+func (k *Keys) Decrypt(headers Headers, body []byte) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(headers.ContentEncoding)) {
+	case "aes128gcm":
+		return k.decryptStandard(body)
+	case "aesgcm":
+		return k.decryptDraft(headers, body)
+	}
+	return nil, fmt.Errorf("%w %q", ErrEncoding, headers.ContentEncoding)
+}
+
+// decryptStandard decrypts the aes128gcm encoding of RFC 8291, whose header
+// carries the salt and the sender's public key
+func (k *Keys) decryptStandard(body []byte) ([]byte, error) {
+	// salt (16) | record size (4) | key ID length (1) | key ID
+	if len(body) < 21 {
+		return nil, errors.New("message too short")
+	}
+	salt := body[:16]
+	recordSize := binary.BigEndian.Uint32(body[16:20])
+	idLen := int(body[20])
+	if len(body) < 21+idLen {
+		return nil, errors.New("message too short")
+	}
+	senderKey := body[21 : 21+idLen]
+	ciphertext := body[21+idLen:]
+	// Mastodon sends a single record
+	if uint32(len(ciphertext)) > recordSize {
+		return nil, errors.New("messages of more than one record aren't supported")
+	}
+
+	secret, err := k.sharedSecret(senderKey)
+	if err != nil {
+		return nil, err
+	}
+
+	receiverKey := k.private.PublicKey().Bytes()
+	keyInfo := "WebPush: info\x00" + string(receiverKey) + string(senderKey)
+	ikm, err := derive(k.auth, secret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := derive(salt, ikm, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := derive(salt, ikm, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := open(cek, nonce, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	// The last record ends with a 2 delimiter, then any zero padding
+	plaintext = bytes.TrimRight(plaintext, "\x00")
+	if len(plaintext) == 0 || plaintext[len(plaintext)-1] != 2 {
+		return nil, errors.New("invalid padding")
+	}
+	return plaintext[:len(plaintext)-1], nil
+}
+
+// decryptDraft decrypts the aesgcm encoding of the drafts before RFC 8291,
+// which keeps the salt in the Encryption header and the sender's public key
+// in Crypto-Key
+func (k *Keys) decryptDraft(headers Headers, body []byte) ([]byte, error) {
+	salt, err := decode(headerParam(headers.Encryption, "salt"))
+	if err != nil || len(salt) != 16 {
+		return nil, errors.New("missing or invalid salt in the Encryption header")
+	}
+	senderKey, err := decode(headerParam(headers.CryptoKey, "dh"))
+	if err != nil || len(senderKey) == 0 {
+		return nil, errors.New("missing or invalid dh in the Crypto-Key header")
+	}
+
+	secret, err := k.sharedSecret(senderKey)
+	if err != nil {
+		return nil, err
+	}
+
+	receiverKey := k.private.PublicKey().Bytes()
+	ikm, err := derive(k.auth, secret, "Content-Encoding: auth\x00", 32)
+	if err != nil {
+		return nil, err
+	}
+	context := "P-256\x00" + lengthPrefixed(receiverKey) + lengthPrefixed(senderKey)
+	cek, err := derive(salt, ikm, "Content-Encoding: aesgcm\x00"+context, 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := derive(salt, ikm, "Content-Encoding: nonce\x00"+context, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := open(cek, nonce, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// A two-byte length of padding comes first
+	if len(plaintext) < 2 {
+		return nil, errors.New("invalid padding")
+	}
+	padding := int(binary.BigEndian.Uint16(plaintext))
+	if len(plaintext) < 2+padding {
+		return nil, errors.New("invalid padding")
+	}
+	return plaintext[2+padding:], nil
+}
+
+// sharedSecret is the ECDH secret between the receiver and the sender's key
+func (k *Keys) sharedSecret(senderKey []byte) ([]byte, error) {
+	public, err := ecdh.P256().NewPublicKey(senderKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender key: %w", err)
+	}
+	return k.private.ECDH(public)
+}
+
+// derive runs HKDF-SHA256 over secret with salt
+func derive(salt, secret []byte, info string, length int) ([]byte, error) {
+	prk, err := hkdf.Extract(sha256.New, secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	return hkdf.Expand(sha256.New, prk, info, length)
+}
+
+// open decrypts and authenticates a record with AES-128-GCM
+func open(key, nonce, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt message; it may be for an older subscription")
+	}
+	return plaintext, nil
+}
+
+// lengthPrefixed writes b after its length as two bytes
+func lengthPrefixed(b []byte) string {
+	return string(binary.BigEndian.AppendUint16(nil, uint16(len(b)))) + string(b)
+}
+
+// headerParam finds name=value in a header such as
+// "keyid=p256dh;dh=BNc...", where the value may be quoted
+func headerParam(header, name string) string {
+	for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ';' || r == ',' }) {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && strings.EqualFold(key, name) {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+// decode reads base64url with or without padding, as senders vary
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,137 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// The example in RFC 8291, appendix A
+func TestDecryptStandard(t *testing.T) {
+	keys, err := ParseKeys("q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94", "BTBZMqHH6r4Tts7J_aSIgg")
+	if err != nil {
+		t.Fatalf("ParseKeys failed: %v", err)
+	}
+	if want := "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"; keys.Public() != want {
+		t.Errorf("Expected public key %s, got %s", want, keys.Public())
+	}
+
+	body, _ := base64.RawURLEncoding.DecodeString("DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN")
+
+	plaintext, err := keys.Decrypt(Headers{ContentEncoding: "aes128gcm"}, body)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if got := string(plaintext); got != "When I grow up, I want to be a watermelon" {
+		t.Errorf("Unexpected plaintext %q", got)
+	}
+
+	body[len(body)-1] ^= 1
+	if _, err := keys.Decrypt(Headers{ContentEncoding: "aes128gcm"}, body); err == nil {
+		t.Error("Expected a tampered message to fail, got nil")
+	}
+}
+
+func TestDecryptDraft(t *testing.T) {
+	keys, err := GenerateKeys()
+	if err != nil {
+		t.Fatalf("GenerateKeys failed: %v", err)
+	}
+
+	headers, body := encryptDraft(t, keys, []byte(`{"title":"@alice mentioned you"}`))
+	plaintext, err := keys.Decrypt(headers, body)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if got := string(plaintext); got != `{"title":"@alice mentioned you"}` {
+		t.Errorf("Unexpected plaintext %q", got)
+	}
+
+	// Keys saved and read back decrypt the same
+	saved, err := ParseKeys(keys.Private(), keys.Auth())
+	if err != nil {
+		t.Fatalf("ParseKeys failed: %v", err)
+	}
+	if _, err := saved.Decrypt(headers, body); err != nil {
+		t.Errorf("Decrypt with saved keys failed: %v", err)
+	}
+
+	other, _ := GenerateKeys()
+	if _, err := other.Decrypt(headers, body); err == nil {
+		t.Error("Expected another subscription's keys to fail, got nil")
+	}
+	if _, err := keys.Decrypt(Headers{ContentEncoding: "gzip"}, body); err == nil {
+		t.Error("Expected an unknown encoding to fail, got nil")
+	}
+}
+
+// encryptDraft encrypts plaintext to keys as a sender using aesgcm would
+func encryptDraft(t *testing.T, keys *Keys, plaintext []byte) (Headers, []byte) {
+	t.Helper()
+
+	sender, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := sender.ECDH(keys.private.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt := make([]byte, 16)
+	rand.Read(salt)
+
+	receiverKey := keys.private.PublicKey().Bytes()
+	senderKey := sender.PublicKey().Bytes()
+	ikm, _ := derive(keys.auth, secret, "Content-Encoding: auth\x00", 32)
+	context := "P-256\x00" + lengthPrefixed(receiverKey) + lengthPrefixed(senderKey)
+	cek, _ := derive(salt, ikm, "Content-Encoding: aesgcm\x00"+context, 16)
+	nonce, _ := derive(salt, ikm, "Content-Encoding: nonce\x00"+context, 12)
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	// Three bytes of padding
+	padded := append([]byte{0, 3, 0, 0, 0}, plaintext...)
+	body := gcm.Seal(nil, nonce, padded, nil)
+
+	return Headers{
+		ContentEncoding: "aesgcm",
+		Encryption:      "salt=" + base64.RawURLEncoding.EncodeToString(salt),
+		CryptoKey:       "dh=" + base64.RawURLEncoding.EncodeToString(senderKey) + ";p256ecdsa=BDd3",
+	}, body
+}
+
+func TestHandler(t *testing.T) {
+	keys, _ := GenerateKeys()
+	var delivered []string
+	handler := Handler(keys, func(message []byte) { delivered = append(delivered, string(message)) })
+
+	headers, body := encryptDraft(t, keys, []byte("hello"))
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Encoding", headers.ContentEncoding)
+	req.Header.Set("Encryption", headers.Encryption)
+	req.Header.Set("Crypto-Key", headers.CryptoKey)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	if len(delivered) != 1 || delivered[0] != "hello" {
+		t.Errorf("Expected the message delivered, got %q", delivered)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/", bytes.NewReader([]byte("junk"))))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected 415 without an encoding, got %d", rec.Code)
+	}
+	if len(delivered) != 1 {
+		t.Errorf("Expected nothing more delivered, got %q", delivered)
+	}
+}
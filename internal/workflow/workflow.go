@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Step is one action in a workflow. Undo compensates for a completed Do; it
+// may be nil when there is nothing to roll back.
+type Step struct {
+	Name string
+	Do   func() error
+	Undo func() error
+}
+
+// Outcome describes what happened to a step
+type Outcome string
+
+const (
+	Done       Outcome = "done"
+	Failed     Outcome = "failed"
+	RolledBack Outcome = "rolled back"
+	UndoFailed Outcome = "undo failed"
+	Skipped    Outcome = "skipped"
+)
+
+// Event is a record of a step's outcome, in the order it happened
+type Event struct {
+	Step    string
+	Outcome Outcome
+	Err     error
+}
+
+// Workflow runs steps in order and, if one fails, undoes the completed ones
+// in reverse so a partial failure leaves things as they were
+type Workflow struct {
+	steps []Step
+	log   []Event
+}
+
+// New creates an empty workflow
+func New() *Workflow {
+	return &Workflow{}
+}
+
+// Add appends a step
+func (w *Workflow) Add(name string, do, undo func() error) {
+	w.steps = append(w.steps, Step{Name: name, Do: do, Undo: undo})
+}
+
+// Log returns every event recorded so far
+func (w *Workflow) Log() []Event {
+	return w.log
+}
+
+// Error is returned by Run when a step fails
+type Error struct {
+	Step        string
+	Err         error
+	RollbackErr error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s failed: %v", e.Step, e.Err)
+	if e.RollbackErr != nil {
+		msg += fmt.Sprintf(" (rollback incomplete: %v)", e.RollbackErr)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// RolledBack reports whether every completed step was undone cleanly
+func (e *Error) RolledBack() bool {
+	return e.RollbackErr == nil
+}
+
+// Run executes the steps. On failure it rolls back and returns an *Error.
+func (w *Workflow) Run() error {
+	var completed []Step
+
+	for i, step := range w.steps {
+		if err := step.Do(); err != nil {
+			w.log = append(w.log, Event{Step: step.Name, Outcome: Failed, Err: err})
+			for _, skipped := range w.steps[i+1:] {
+				w.log = append(w.log, Event{Step: skipped.Name, Outcome: Skipped})
+			}
+			return &Error{Step: step.Name, Err: err, RollbackErr: w.rollback(completed)}
+		}
+		w.log = append(w.log, Event{Step: step.Name, Outcome: Done})
+		completed = append(completed, step)
+	}
+
+	return nil
+}
+
+func (w *Workflow) rollback(completed []Step) error {
+	var errs []error
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.Undo == nil {
+			continue
+		}
+		if err := step.Undo(); err != nil {
+			w.log = append(w.log, Event{Step: step.Name, Outcome: UndoFailed, Err: err})
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, err))
+			continue
+		}
+		w.log = append(w.log, Event{Step: step.Name, Outcome: RolledBack})
+	}
+	return errors.Join(errs...)
+}
+
+// Summary renders the log as one line per event
+func (w *Workflow) Summary() string {
+	var b strings.Builder
+	for _, event := range w.log {
+		fmt.Fprintf(&b, "%s: %s", event.Step, event.Outcome)
+		if event.Err != nil {
+			fmt.Fprintf(&b, " (%v)", event.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package workflow
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRunSuccess(t *testing.T) {
+	var calls []string
+
+	w := New()
+	w.Add("first", func() error { calls = append(calls, "do first"); return nil }, func() error { calls = append(calls, "undo first"); return nil })
+	w.Add("second", func() error { calls = append(calls, "do second"); return nil }, nil)
+
+	if err := w.Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"do first", "do second"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+
+	for _, event := range w.Log() {
+		if event.Outcome != Done {
+			t.Errorf("Expected step %s to be done, got %s", event.Step, event.Outcome)
+		}
+	}
+}
+
+func TestRunRollsBackInReverse(t *testing.T) {
+	var calls []string
+	boom := errors.New("boom")
+
+	w := New()
+	w.Add("first", func() error { calls = append(calls, "do first"); return nil }, func() error { calls = append(calls, "undo first"); return nil })
+	w.Add("second", func() error { calls = append(calls, "do second"); return nil }, func() error { calls = append(calls, "undo second"); return nil })
+	w.Add("third", func() error { return boom }, func() error { calls = append(calls, "undo third"); return nil })
+	w.Add("fourth", func() error { calls = append(calls, "do fourth"); return nil }, nil)
+
+	err := w.Run()
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	var wfErr *Error
+	if !errors.As(err, &wfErr) {
+		t.Fatalf("Expected *Error, got %T", err)
+	}
+	if wfErr.Step != "third" {
+		t.Errorf("Expected failing step 'third', got %q", wfErr.Step)
+	}
+	if !errors.Is(err, boom) {
+		t.Error("Expected error to wrap the step's error")
+	}
+	if !wfErr.RolledBack() {
+		t.Errorf("Expected clean rollback, got %v", wfErr.RollbackErr)
+	}
+
+	expected := []string{"do first", "do second", "undo second", "undo first"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+
+	outcomes := map[string][]Outcome{}
+	for _, event := range w.Log() {
+		outcomes[event.Step] = append(outcomes[event.Step], event.Outcome)
+	}
+	if !reflect.DeepEqual(outcomes["fourth"], []Outcome{Skipped}) {
+		t.Errorf("Expected fourth to be skipped, got %v", outcomes["fourth"])
+	}
+	if !reflect.DeepEqual(outcomes["first"], []Outcome{Done, RolledBack}) {
+		t.Errorf("Expected first to be done then rolled back, got %v", outcomes["first"])
+	}
+}
+
+func TestRunReportsFailedUndo(t *testing.T) {
+	w := New()
+	w.Add("first", func() error { return nil }, func() error { return errors.New("cannot undo") })
+	w.Add("second", func() error { return errors.New("boom") }, nil)
+
+	err := w.Run()
+
+	var wfErr *Error
+	if !errors.As(err, &wfErr) {
+		t.Fatalf("Expected *Error, got %T", err)
+	}
+	if wfErr.RolledBack() {
+		t.Error("Expected rollback to be reported as incomplete")
+	}
+}
@@ -0,0 +1,55 @@
+package mastodon
+
+import "net/http"
+
+// AccountCache keeps the IDs of the accounts access tokens log in as between
+// runs, so they don't have to be looked up each time
+type AccountCache interface {
+	AccountID(baseURL, accessToken string) string
+	SetAccountID(baseURL, accessToken, accountID string)
+	ForgetAccountID(baseURL, accessToken string)
+}
+
+// CacheAccountID makes AccountID use cache. The cached ID is forgotten as
+// soon as the instance rejects the access token.
+func (c *Client) CacheAccountID(cache AccountCache) {
+	c.accountCache = cache
+	c.ObserveRequests(func(m RequestMetric) {
+		if m.Status == http.StatusUnauthorized {
+			c.accountID = ""
+			cache.ForgetAccountID(c.BaseURL, c.AccessToken)
+		}
+	})
+}
+
+// AccountID returns the ID of the account the client is logged in as. It's
+// looked up with verify_credentials only if it isn't already known.
+func (c *Client) AccountID() (string, error) {
+	if c.accountID != "" {
+		return c.accountID, nil
+	}
+	if c.accountCache != nil {
+		if id := c.accountCache.AccountID(c.BaseURL, c.AccessToken); id != "" {
+			c.accountID = id
+			return id, nil
+		}
+	}
+
+	account, err := c.VerifyCredentials()
+	if err != nil {
+		return "", err
+	}
+	return account.ID, nil
+}
+
+// rememberAccountID records the account the client is logged in as, once
+// verify_credentials has said
+func (c *Client) rememberAccountID(id string) {
+	if id == "" || id == c.accountID {
+		return
+	}
+	c.accountID = id
+	if c.accountCache != nil {
+		c.accountCache.SetAccountID(c.BaseURL, c.AccessToken, id)
+	}
+}
@@ -0,0 +1,65 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type mapAccountCache map[string]string
+
+func (m mapAccountCache) AccountID(baseURL, accessToken string) string {
+	return m[baseURL+" "+accessToken]
+}
+
+func (m mapAccountCache) SetAccountID(baseURL, accessToken, accountID string) {
+	m[baseURL+" "+accessToken] = accountID
+}
+
+func (m mapAccountCache) ForgetAccountID(baseURL, accessToken string) {
+	delete(m, baseURL+" "+accessToken)
+}
+
+func TestAccountIDIsCached(t *testing.T) {
+	verifications := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/accounts/verify_credentials":
+			verifications++
+			w.Write([]byte(`{"id":"42","acct":"me"}`))
+		case "/api/v1/accounts/42/statuses":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"The access token is invalid"}`))
+		}
+	}))
+	defer server.Close()
+
+	cache := mapAccountCache{}
+
+	client := NewClient(server.URL, "token")
+	client.CacheAccountID(cache)
+	if _, err := client.GetAccountStatuses(20); err != nil {
+		t.Fatalf("GetAccountStatuses failed: %v", err)
+	}
+	if verifications != 1 || cache.AccountID(server.URL, "token") != "42" {
+		t.Fatalf("Expected the ID to be looked up and cached, got %d lookups and %v", verifications, cache)
+	}
+
+	// A later run finds it in the cache
+	client = NewClient(server.URL, "token")
+	client.CacheAccountID(cache)
+	if _, err := client.GetAccountStatuses(20); err != nil {
+		t.Fatalf("GetAccountStatuses failed: %v", err)
+	}
+	if verifications != 1 {
+		t.Errorf("Expected the cached ID to be used, got %d lookups", verifications)
+	}
+
+	// and forgets it once the token is rejected
+	client.GetStatus("1")
+	if id := cache.AccountID(server.URL, "token"); id != "" {
+		t.Errorf("Expected the ID to be forgotten after a 401, got %q", id)
+	}
+}
@@ -0,0 +1,281 @@
+package mastodon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// redacted replaces secrets in recorded cassettes
+const redacted = "REDACTED"
+
+// sensitiveKeys are form, query, and JSON keys whose values never reach disk
+var sensitiveKeys = []string{"access_token", "client_secret", "token", "code"}
+
+// recordedHeaders are the response headers the client reads, which are kept
+// so pagination and rate limiting replay as they happened
+var recordedHeaders = []string{"Link", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	RequestBody  string `json:"request_body,omitempty"`
+	StatusCode   int    `json:"status_code"`
+	ContentType  string `json:"content_type,omitempty"`
+	ResponseBody string `json:"response_body"`
+
+	// Header holds the response's recordedHeaders
+	Header http.Header `json:"header,omitempty"`
+}
+
+// Cassette is an ordered list of interactions stored as JSON on disk
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+
+	path string
+	used []bool
+	mu   sync.Mutex
+}
+
+// LoadCassette reads a cassette previously written by a recording client
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette: %w", err)
+	}
+	cassette.path = path
+	cassette.used = make([]bool, len(cassette.Interactions))
+
+	return &cassette, nil
+}
+
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// recordingTransport performs real requests and appends each one to a cassette
+type recordingTransport struct {
+	next     http.RoundTripper
+	cassette *Cassette
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Method:       req.Method,
+		URL:          redactURL(req.URL),
+		RequestBody:  redactBody(req.Header.Get("Content-Type"), reqBody),
+		StatusCode:   resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ResponseBody: redactBody(resp.Header.Get("Content-Type"), respBody),
+		Header:       recordHeaders(resp.Header),
+	}
+
+	t.cassette.mu.Lock()
+	defer t.cassette.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	if err := t.cassette.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// replayingTransport answers requests from a cassette without touching the network
+type replayingTransport struct {
+	cassette *Cassette
+}
+
+func (t *replayingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	target := redactURL(req.URL)
+
+	t.cassette.mu.Lock()
+	defer t.cassette.mu.Unlock()
+	for i, interaction := range t.cassette.Interactions {
+		if t.cassette.used[i] || interaction.Method != req.Method || interaction.URL != target {
+			continue
+		}
+		t.cassette.used[i] = true
+
+		header := interaction.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		if interaction.ContentType != "" {
+			header.Set("Content-Type", interaction.ContentType)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+			StatusCode:    interaction.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.ResponseBody)),
+			ContentLength: int64(len(interaction.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, target)
+}
+
+// RecordTo makes the client write every API interaction to a cassette at path
+func (c *Client) RecordTo(path string) {
+	next := c.HTTPClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.HTTPClient.Transport = &recordingTransport{
+		next:     next,
+		cassette: &Cassette{path: path},
+	}
+}
+
+// ReplayFrom makes the client answer API calls from a recorded cassette
+func (c *Client) ReplayFrom(path string) error {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return err
+	}
+	c.HTTPClient.Transport = &replayingTransport{cassette: cassette}
+	return nil
+}
+
+// recordHeaders picks out the recordedHeaders that a response has, if any.
+// Link targets lose their secrets as request URLs do.
+func recordHeaders(header http.Header) http.Header {
+	var kept http.Header
+	for _, name := range recordedHeaders {
+		for _, value := range header.Values(name) {
+			if kept == nil {
+				kept = make(http.Header)
+			}
+			if name == "Link" {
+				value = redactLinks(value)
+			}
+			kept.Add(name, value)
+		}
+	}
+	return kept
+}
+
+// redactLinks removes secrets from the query strings of a Link header's
+// targets, keeping their hosts so the pages still resolve when replayed
+func redactLinks(header string) string {
+	links := strings.Split(header, ",")
+	for i, link := range links {
+		start, end := strings.Index(link, "<"), strings.Index(link, ">")
+		if start < 0 || end < start {
+			continue
+		}
+		target, err := url.Parse(link[start+1 : end])
+		if err != nil {
+			continue
+		}
+		query := target.Query()
+		redactValues(query)
+		target.RawQuery = query.Encode()
+		links[i] = link[:start+1] + target.String() + link[end:]
+	}
+	return strings.Join(links, ",")
+}
+
+// redactURL strips the host and any secrets from the query string, so
+// cassettes replay against any base URL
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	redactValues(query)
+	if len(query) == 0 {
+		return u.Path
+	}
+	return u.Path + "?" + query.Encode()
+}
+
+func redactValues(values url.Values) {
+	for _, key := range sensitiveKeys {
+		if values.Has(key) {
+			values.Set(key, redacted)
+		}
+	}
+}
+
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "multipart/"):
+		return "<multipart body omitted>"
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return string(body)
+		}
+		redactValues(values)
+		return values.Encode()
+	case strings.HasPrefix(contentType, "application/json"):
+		var object map[string]interface{}
+		if err := json.Unmarshal(body, &object); err != nil {
+			return string(body)
+		}
+		for _, key := range sensitiveKeys {
+			if _, ok := object[key]; ok {
+				object[key] = redacted
+			}
+		}
+		redactedBody, err := json.Marshal(object)
+		if err != nil {
+			return string(body)
+		}
+		return string(redactedBody)
+	}
+
+	return string(body)
+}
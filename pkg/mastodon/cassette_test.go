@@ -0,0 +1,189 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&Status{ID: "123456", Content: "Recorded status"})
+	}))
+
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder := NewClient(server.URL, "secret_token")
+	recorder.RecordTo(cassettePath)
+
+	if _, err := recorder.GetStatus("123456"); err != nil {
+		t.Fatalf("Failed to get status while recording: %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatalf("Failed to read cassette: %v", err)
+	}
+	if strings.Contains(string(data), "secret_token") {
+		t.Error("Cassette should not contain the access token")
+	}
+
+	player := NewClient("https://replay.invalid", "other_token")
+	if err := player.ReplayFrom(cassettePath); err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+
+	status, err := player.GetStatus("123456")
+	if err != nil {
+		t.Fatalf("Failed to get status while replaying: %v", err)
+	}
+
+	if status.Content != "Recorded status" {
+		t.Errorf("Expected content 'Recorded status', got %q", status.Content)
+	}
+
+	// Each interaction is only replayed once
+	if _, err := player.GetStatus("123456"); err == nil {
+		t.Error("Expected error once the cassette is exhausted, got nil")
+	}
+}
+
+func TestRecordRedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "issued_token"})
+	}))
+	defer server.Close()
+
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+
+	client := NewClient(server.URL, "")
+	client.RecordTo(cassettePath)
+
+	if _, err := client.GetAccessToken("client_id", "client_secret_value", "redirect_uri", "auth_code"); err != nil {
+		t.Fatalf("Failed to get access token: %v", err)
+	}
+
+	data, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatalf("Failed to read cassette: %v", err)
+	}
+
+	for _, secret := range []string{"issued_token", "client_secret_value", "auth_code"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette should not contain %q", secret)
+		}
+	}
+}
+
+func TestReplayMissingInteraction(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(cassettePath, []byte(`{"interactions": []}`), 0600); err != nil {
+		t.Fatalf("Failed to write cassette: %v", err)
+	}
+
+	client := NewClient("https://replay.invalid", "test_token")
+	if err := client.ReplayFrom(cassettePath); err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+
+	if _, err := client.GetStatus("1"); err == nil {
+		t.Error("Expected error for unrecorded request, got nil")
+	}
+}
+
+func TestReplayFixturePagination(t *testing.T) {
+	client := NewClient("https://replay.invalid", "test_token")
+	if err := client.ReplayFrom(filepath.Join("testdata", "favourites_pages.json")); err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+
+	var ids []string
+	next := ""
+	for page := 0; page < 5; page++ {
+		statuses, nextURL, err := client.GetFavourites(next, 2)
+		if err != nil {
+			t.Fatalf("Failed to get favourites: %v", err)
+		}
+		for _, status := range statuses {
+			ids = append(ids, status.ID)
+		}
+		if nextURL == "" {
+			break
+		}
+		next = nextURL
+	}
+
+	if strings.Join(ids, ",") != "30,20,10" {
+		t.Errorf("Expected 30,20,10 across both pages, got %v", ids)
+	}
+}
+
+func TestReplayFixtureRateLimit(t *testing.T) {
+	client := NewClient("https://replay.invalid", "test_token")
+	if err := client.ReplayFrom(filepath.Join("testdata", "rate_limited.json")); err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+
+	_, err := client.GetStatus("1")
+	apiErr, ok := AsAPIError(err)
+	if !ok || !apiErr.RateLimited() {
+		t.Fatalf("Expected a rate limit error, got %v", err)
+	}
+	if apiErr.RetryAfter.Seconds() != 30 {
+		t.Errorf("Expected to retry after 30s, got %s", apiErr.RetryAfter)
+	}
+}
+
+func TestRecordKeepsLinkHeader(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("max_id") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/favourites?max_id=5&access_token=leaked>; rel="next"`, server.URL))
+			w.Write([]byte(`[{"id":"9"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id":"4"}]`))
+	}))
+
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := NewClient(server.URL, "test_token")
+	recorder.RecordTo(cassettePath)
+
+	_, next, err := recorder.GetFavourites("", 1)
+	if err != nil {
+		t.Fatalf("Failed to get favourites while recording: %v", err)
+	}
+	if _, _, err := recorder.GetFavourites(next, 1); err != nil {
+		t.Fatalf("Failed to get the next page while recording: %v", err)
+	}
+	server.Close()
+
+	data, _ := os.ReadFile(cassettePath)
+	if strings.Contains(string(data), "leaked") {
+		t.Error("Cassette should not contain secrets from the Link header")
+	}
+
+	player := NewClient("https://replay.invalid", "test_token")
+	if err := player.ReplayFrom(cassettePath); err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+	_, next, err = player.GetFavourites("", 1)
+	if err != nil || next == "" {
+		t.Fatalf("Expected a next page when replaying, got %q, %v", next, err)
+	}
+	statuses, _, err := player.GetFavourites(next, 1)
+	if err != nil || len(statuses) != 1 || statuses[0].ID != "4" {
+		t.Errorf("Expected status 4 on the replayed next page, got %v, %v", statuses, err)
+	}
+}
@@ -0,0 +1,127 @@
+package mastodon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Chats are a Pleroma/Akkoma extension: one-to-one conversations kept apart
+// from statuses. Mastodon doesn't have them.
+
+// SupportsChats reports whether the instance runs software with the chats
+// API. Pleroma and Akkoma both put their name in the version string, e.g.
+// "2.7.2 (compatible; Pleroma 2.5.0)".
+func (i *Instance) SupportsChats() bool {
+	return flavorFromVersion(i.Version).SupportsChats()
+}
+
+type Chat struct {
+	ID          string       `json:"id"`
+	Account     *Account     `json:"account"`
+	Unread      int          `json:"unread"`
+	LastMessage *ChatMessage `json:"last_message"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type ChatMessage struct {
+	ID        string    `json:"id"`
+	ChatID    string    `json:"chat_id"`
+	AccountID string    `json:"account_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *Client) GetChats() ([]*Chat, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/pleroma/chats", c.BaseURL)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get chats", resp)
+	}
+
+	var chats []*Chat
+	if err := json.NewDecoder(resp.Body).Decode(&chats); err != nil {
+		return nil, fmt.Errorf("failed to decode chats response: %w", err)
+	}
+
+	return chats, nil
+}
+
+// GetOrCreateChat returns the chat with an account, starting one if needed
+func (c *Client) GetOrCreateChat(accountID string) (*Chat, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/pleroma/chats/by-account-id/%s", c.BaseURL, url.PathEscape(accountID))
+
+	req, err := http.NewRequest("POST", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chat: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("open chat", resp)
+	}
+
+	var chat Chat
+	if err := json.NewDecoder(resp.Body).Decode(&chat); err != nil {
+		return nil, fmt.Errorf("failed to decode chat response: %w", err)
+	}
+
+	return &chat, nil
+}
+
+func (c *Client) SendChatMessage(chatID, content string) (*ChatMessage, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/pleroma/chats/%s/messages", c.BaseURL, url.PathEscape(chatID))
+
+	jsonData, err := json.Marshal(map[string]string{"content": content})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send chat message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("send chat message", resp)
+	}
+
+	var message ChatMessage
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		return nil, fmt.Errorf("failed to decode chat message response: %w", err)
+	}
+
+	return &message, nil
+}
@@ -0,0 +1,83 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInstanceSupportsChats(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"4.2.1", false},
+		{"2.7.2 (compatible; Pleroma 2.5.0)", true},
+		{"2.7.2 (compatible; Akkoma 3.10.4)", true},
+	}
+
+	for _, tt := range tests {
+		instance := &Instance{Version: tt.version}
+		if got := instance.SupportsChats(); got != tt.want {
+			t.Errorf("SupportsChats() for %q = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestGetChats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/pleroma/chats" {
+			t.Errorf("Expected path /api/v1/pleroma/chats, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]*Chat{{ID: "1", Account: &Account{Acct: "alice"}, Unread: 2}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	chats, err := client.GetChats()
+	if err != nil {
+		t.Fatalf("Failed to get chats: %v", err)
+	}
+
+	if len(chats) != 1 || chats[0].Unread != 2 || chats[0].Account.Acct != "alice" {
+		t.Errorf("Unexpected chats: %+v", chats)
+	}
+}
+
+func TestSendChatMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/pleroma/chats/by-account-id/42":
+			if r.Method != "POST" {
+				t.Errorf("Expected POST method, got %s", r.Method)
+			}
+			json.NewEncoder(w).Encode(&Chat{ID: "7"})
+		case "/api/v1/pleroma/chats/7/messages":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["content"] != "Hello" {
+				t.Errorf("Expected content 'Hello', got %q", body["content"])
+			}
+			json.NewEncoder(w).Encode(&ChatMessage{ID: "100", ChatID: "7", Content: "Hello"})
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	chat, err := client.GetOrCreateChat("42")
+	if err != nil {
+		t.Fatalf("Failed to open chat: %v", err)
+	}
+
+	message, err := client.SendChatMessage(chat.ID, "Hello")
+	if err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+
+	if message.ID != "100" || message.ChatID != "7" {
+		t.Errorf("Unexpected message: %+v", message)
+	}
+}
@@ -0,0 +1,961 @@
+// Package mastodon is a client for the Mastodon API and the servers that
+// implement it, such as Pleroma, Akkoma, and glitch-soc. It's what tusk uses
+// to talk to instances, and can be used by other programs as it is:
+//
+//	client := mastodon.NewClient("https://mastodon.social", token)
+//	status, err := client.PostStatusContext(ctx, mastodon.StatusParams{
+//		Status:     "Hello!",
+//		Visibility: mastodon.VisibilityUnlisted,
+//	})
+//
+// Methods whose names end in Context stop when their ctx is done. The others
+// run until the client's timeout; WithContext gives any of them a context.
+//
+// Failed requests return an *APIError when the instance answered; see
+// AsAPIError.
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Client struct {
+	BaseURL     string
+	AccessToken string
+	HTTPClient  *http.Client
+
+	// Flavor is the instance's server software, if known, so requests can
+	// allow for its differences from Mastodon
+	Flavor Flavor
+
+	// ContentType is the format statuses are written in, e.g. text/markdown,
+	// on servers that support it. Empty leaves it to the server.
+	ContentType string
+
+	// accountID is the logged-in account's, once known; see AccountID
+	accountID    string
+	accountCache AccountCache
+}
+
+type App struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+type Status struct {
+	ID               string             `json:"id"`
+	URI              string             `json:"uri"`
+	URL              string             `json:"url"`
+	CreatedAt        time.Time          `json:"created_at"`
+	Content          string             `json:"content"`
+	InReplyTo        string             `json:"in_reply_to_id"`
+	InReplyToAccount string             `json:"in_reply_to_account_id"`
+	Account          *Account           `json:"account"`
+	Visibility       Visibility         `json:"visibility"`
+	SpoilerText      string             `json:"spoiler_text"`
+	Sensitive        bool               `json:"sensitive"`
+	Language         string             `json:"language"`
+	MediaAttachments []*MediaAttachment `json:"media_attachments"`
+	Mentions         []*Mention         `json:"mentions"`
+	Tags             []*Tag             `json:"tags"`
+	RepliesCount     int                `json:"replies_count"`
+	ReblogsCount     int                `json:"reblogs_count"`
+	FavouritesCount  int                `json:"favourites_count"`
+
+	// Poll, Card, and Application are nil when the status has no poll, no
+	// link preview, or doesn't say what posted it
+	Poll        *Poll        `json:"poll"`
+	Card        *Card        `json:"card"`
+	Application *Application `json:"application"`
+
+	// Reactions are only set by servers with glitch-soc's emoji reactions
+	Reactions []*Reaction `json:"reactions"`
+}
+
+// Poll is a poll attached to a status
+type Poll struct {
+	ID          string        `json:"id"`
+	ExpiresAt   *time.Time    `json:"expires_at"`
+	Expired     bool          `json:"expired"`
+	Multiple    bool          `json:"multiple"`
+	VotesCount  int           `json:"votes_count"`
+	VotersCount int           `json:"voters_count"`
+	Options     []*PollOption `json:"options"`
+	Voted       bool          `json:"voted"`
+	OwnVotes    []int         `json:"own_votes"`
+}
+
+// PollOption is one of a poll's choices. VotesCount is null while the
+// results are hidden, which decodes as 0.
+type PollOption struct {
+	Title      string `json:"title"`
+	VotesCount int    `json:"votes_count"`
+}
+
+// Card is the preview of the first link in a status
+type Card struct {
+	URL          string `json:"url"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Type         string `json:"type"`
+	ProviderName string `json:"provider_name"`
+	Image        string `json:"image"`
+}
+
+// Application is the app a status was posted from
+type Application struct {
+	Name    string `json:"name"`
+	Website string `json:"website"`
+}
+
+// Mention is an account mentioned in a status
+type Mention struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Acct     string `json:"acct"`
+	URL      string `json:"url"`
+}
+
+type Account struct {
+	ID             string    `json:"id"`
+	Username       string    `json:"username"`
+	Acct           string    `json:"acct"`
+	DisplayName    string    `json:"display_name"`
+	URL            string    `json:"url"`
+	Note           string    `json:"note"`
+	Bot            bool      `json:"bot"`
+	CreatedAt      time.Time `json:"created_at"`
+	FollowersCount int       `json:"followers_count"`
+	FollowingCount int       `json:"following_count"`
+	StatusesCount  int       `json:"statuses_count"`
+}
+
+type MediaAttachment struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	PreviewURL  string `json:"preview_url"`
+	Description string `json:"description"`
+}
+
+type StatusSource struct {
+	ID          string `json:"id"`
+	Text        string `json:"text"`
+	SpoilerText string `json:"spoiler_text"`
+}
+
+type Notification struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Account   *Account  `json:"account"`
+	Status    *Status   `json:"status"`
+}
+
+type Announcement struct {
+	ID          string    `json:"id"`
+	Content     string    `json:"content"`
+	PublishedAt time.Time `json:"published_at"`
+	Read        bool      `json:"read"`
+}
+
+type StatusContext struct {
+	Ancestors   []*Status `json:"ancestors"`
+	Descendants []*Status `json:"descendants"`
+}
+
+// ScheduledStatus is a status the server will post later
+type ScheduledStatus struct {
+	ID          string    `json:"id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+type StatusParams struct {
+	Status      string
+	InReplyToID string
+	Visibility  Visibility
+	SpoilerText string
+	MediaIDs    []string
+	Language    string
+	Sensitive   bool
+
+	// LocalOnly keeps the status off other instances, on servers that
+	// support it (see Instance.SupportsLocalOnly)
+	LocalOnly bool
+
+	// IdempotencyKey identifies one attempt at posting, e.g. a compose
+	// session. The instance answers a repeat of it with the status it has
+	// already created, so with a key set, requests that time out or hit a
+	// server error are retried. See NewIdempotencyKey.
+	IdempotencyKey string
+}
+
+// NewIdempotencyKey returns a random key for StatusParams.IdempotencyKey
+func NewIdempotencyKey() string {
+	return rand.Text()
+}
+
+func NewClient(baseURL, accessToken string) *Client {
+	return &Client{
+		BaseURL:     baseURL,
+		AccessToken: accessToken,
+		HTTPClient:  &http.Client{},
+	}
+}
+
+func (c *Client) RegisterApp(appName, redirectURI, scopes string) (*App, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/apps", c.BaseURL)
+
+	data := url.Values{}
+	data.Set("client_name", appName)
+	data.Set("redirect_uris", redirectURI)
+	data.Set("scopes", scopes)
+
+	resp, err := c.HTTPClient.PostForm(endpoint, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register app: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("register app", resp)
+	}
+
+	var app App
+	if err := json.NewDecoder(resp.Body).Decode(&app); err != nil {
+		return nil, fmt.Errorf("failed to decode app response: %w", err)
+	}
+
+	return &app, nil
+}
+
+func (c *Client) GetAuthorizationURL(clientID, redirectURI, scopes string) string {
+	params := url.Values{}
+	params.Set("client_id", clientID)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("response_type", "code")
+	params.Set("scope", scopes)
+
+	return fmt.Sprintf("%s/oauth/authorize?%s", c.BaseURL, params.Encode())
+}
+
+// GetAccessToken trades an authorization code for an access token; see
+// ExchangeCode for its scopes too
+func (c *Client) GetAccessToken(clientID, clientSecret, redirectURI, code string) (string, error) {
+	token, err := c.ExchangeCode(clientID, clientSecret, redirectURI, code)
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+func (c *Client) PostStatus(params StatusParams) (*Status, error) {
+	return c.PostStatusContext(context.Background(), params)
+}
+
+// PostStatusContext is PostStatus, stopping when ctx is done
+func (c *Client) PostStatusContext(ctx context.Context, params StatusParams) (*Status, error) {
+	var status Status
+	if err := c.createStatus(ctx, c.statusPayload(params), params.IdempotencyKey, "post status", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ScheduleStatus asks the server to post a status at the given time, which
+// must be at least five minutes away
+func (c *Client) ScheduleStatus(params StatusParams, at time.Time) (*ScheduledStatus, error) {
+	return c.ScheduleStatusContext(context.Background(), params, at)
+}
+
+// ScheduleStatusContext is ScheduleStatus, stopping when ctx is done
+func (c *Client) ScheduleStatusContext(ctx context.Context, params StatusParams, at time.Time) (*ScheduledStatus, error) {
+	payload := c.statusPayload(params)
+	payload["scheduled_at"] = at.UTC().Format(time.RFC3339)
+
+	var scheduled ScheduledStatus
+	if err := c.createStatus(ctx, payload, params.IdempotencyKey, "schedule status", &scheduled); err != nil {
+		return nil, err
+	}
+	return &scheduled, nil
+}
+
+// statusPayload is the request body for creating a status with params
+func (c *Client) statusPayload(params StatusParams) map[string]interface{} {
+	payload := map[string]interface{}{
+		"status": params.Status,
+	}
+
+	if params.InReplyToID != "" {
+		payload["in_reply_to_id"] = params.InReplyToID
+	}
+
+	if params.Visibility != "" {
+		payload["visibility"] = params.Visibility
+	}
+
+	if params.SpoilerText != "" {
+		payload["spoiler_text"] = params.SpoilerText
+	}
+
+	if len(params.MediaIDs) > 0 {
+		payload["media_ids"] = params.MediaIDs
+	}
+
+	if params.Language != "" {
+		payload["language"] = params.Language
+	}
+
+	if params.Sensitive {
+		payload["sensitive"] = true
+	}
+
+	if params.LocalOnly {
+		payload["local_only"] = true
+	}
+
+	if c.ContentType != "" && c.Flavor.SupportsContentType() {
+		payload["content_type"] = c.ContentType
+	}
+
+	return payload
+}
+
+// statusAttempts is how many times a status with an idempotency key is sent
+// before giving up, and statusRetryDelay how long to wait before the first
+// retry. The delay doubles each time.
+const statusAttempts = 3
+
+var statusRetryDelay = time.Second
+
+// createStatus sends a new status and decodes the server's response into v.
+// With an idempotency key, timeouts and server errors are retried, since the
+// server won't create the status twice.
+func (c *Client) createStatus(ctx context.Context, payload map[string]interface{}, idempotencyKey, op string, v interface{}) error {
+	endpoint := fmt.Sprintf("%s/api/v1/statuses", c.BaseURL)
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %w", err)
+	}
+
+	attempts := 1
+	if idempotencyKey != "" {
+		attempts = statusAttempts
+	}
+
+	delay := statusRetryDelay
+	for attempt := 1; ; attempt++ {
+		reqCtx := ctx
+		if attempt > 1 {
+			reqCtx = asRetry(ctx)
+		}
+		req, err := http.NewRequestWithContext(reqCtx, "POST", endpoint, bytes.NewReader(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			// Any failure to get a response is tried again, be it a
+			// timeout, a refused or reset connection, or a TLS error:
+			// the Idempotency-Key keeps a status that did arrive from
+			// being posted twice. The caller's deadline or cancellation
+			// is final.
+			if attempt < attempts && ctx.Err() == nil && !errors.Is(err, context.Canceled) {
+				if err := waitToRetry(ctx, delay); err != nil {
+					return fmt.Errorf("failed to %s: %w", op, err)
+				}
+				delay *= 2
+				continue
+			}
+			return fmt.Errorf("failed to %s: %w", op, err)
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError && attempt < attempts {
+			resp.Body.Close()
+			if err := waitToRetry(ctx, delay); err != nil {
+				return fmt.Errorf("failed to %s: %w", op, err)
+			}
+			delay *= 2
+			continue
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return newAPIError(op, resp)
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return fmt.Errorf("failed to decode status response: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// waitToRetry waits delay before another attempt, returning ctx's error if
+// ctx is done first
+func waitToRetry(ctx context.Context, delay time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+func (c *Client) GetStatus(id string) (*Status, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/statuses/%s", c.BaseURL, id)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get status", resp)
+	}
+
+	var status Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode status response: %w", err)
+	}
+
+	return &status, nil
+}
+
+func (c *Client) GetStatusSource(id string) (*StatusSource, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/statuses/%s/source", c.BaseURL, id)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status source: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get status source", resp)
+	}
+
+	var source StatusSource
+	if err := json.NewDecoder(resp.Body).Decode(&source); err != nil {
+		return nil, fmt.Errorf("failed to decode status source response: %w", err)
+	}
+
+	return &source, nil
+}
+
+func (c *Client) VerifyCredentials() (*Account, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/verify_credentials", c.BaseURL)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify credentials: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("verify credentials", resp)
+	}
+
+	var account Account
+	if err := json.NewDecoder(resp.Body).Decode(&account); err != nil {
+		return nil, fmt.Errorf("failed to decode account response: %w", err)
+	}
+
+	c.rememberAccountID(account.ID)
+	return &account, nil
+}
+
+// GetAccountStatuses fetches up to limit of the logged-in account's newest
+// statuses
+func (c *Client) GetAccountStatuses(limit int) ([]*Status, error) {
+	accountID, err := c.AccountID()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetAccountStatusesPage(accountID, "", limit)
+}
+
+// GetAccountStatusesPage fetches up to limit of an account's statuses older
+// than maxID, newest first. An empty maxID starts from the newest status.
+func (c *Client) GetAccountStatusesPage(accountID, maxID string, limit int) ([]*Status, error) {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	params.Set("exclude_replies", "false")
+	params.Set("exclude_reblogs", "true")
+	if maxID != "" {
+		params.Set("max_id", maxID)
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%s/statuses?%s", c.BaseURL, accountID, params.Encode())
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorizeIfLoggedIn(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get statuses", resp)
+	}
+
+	var statuses []*Status
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, fmt.Errorf("failed to decode statuses response: %w", err)
+	}
+
+	return statuses, nil
+}
+
+func (c *Client) EditStatus(id string, params StatusParams) (*Status, error) {
+	return c.EditStatusContext(context.Background(), id, params)
+}
+
+// EditStatusContext is EditStatus, stopping when ctx is done
+func (c *Client) EditStatusContext(ctx context.Context, id string, params StatusParams) (*Status, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/statuses/%s", c.BaseURL, id)
+
+	payload := map[string]interface{}{
+		"status": params.Status,
+	}
+
+	if params.Visibility != "" {
+		payload["visibility"] = params.Visibility
+	}
+
+	if params.SpoilerText != "" {
+		payload["spoiler_text"] = params.SpoilerText
+	}
+
+	if len(params.MediaIDs) > 0 {
+		payload["media_ids"] = params.MediaIDs
+	}
+
+	if params.Language != "" {
+		payload["language"] = params.Language
+	}
+
+	if params.Sensitive {
+		payload["sensitive"] = true
+	}
+
+	if c.ContentType != "" && c.Flavor.SupportsContentType() {
+		payload["content_type"] = c.ContentType
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal status: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("edit status", resp)
+	}
+
+	var status Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode status response: %w", err)
+	}
+
+	return &status, nil
+}
+
+// DeleteStatus deletes a status and returns it as it was. Servers that don't
+// send it back get a status with just the ID.
+func (c *Client) DeleteStatus(id string) (*Status, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/statuses/%s", c.BaseURL, id)
+
+	req, err := http.NewRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("delete status", resp)
+	}
+
+	var status Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil || status.ID == "" {
+		return &Status{ID: id}, nil
+	}
+
+	return &status, nil
+}
+
+func (c *Client) UploadMedia(fileData []byte, filename, mimeType, description string) (*MediaAttachment, error) {
+	endpoint := fmt.Sprintf("%s/api/v2/media", c.BaseURL)
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	// Add the file
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form file: %w", err)
+	}
+
+	if _, err := part.Write(fileData); err != nil {
+		return nil, fmt.Errorf("failed to write file data: %w", err)
+	}
+
+	// Add description if provided
+	if description != "" {
+		if err := writer.WriteField("description", description); err != nil {
+			return nil, fmt.Errorf("failed to write description field: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", endpoint, &buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload media: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return nil, newAPIError("upload media", resp)
+	}
+
+	var media MediaAttachment
+	if err := json.NewDecoder(resp.Body).Decode(&media); err != nil {
+		return nil, fmt.Errorf("failed to decode media response: %w", err)
+	}
+
+	return &media, nil
+}
+
+func (c *Client) RevokeToken(clientID, clientSecret string) error {
+	endpoint := fmt.Sprintf("%s/oauth/revoke", c.BaseURL)
+
+	data := url.Values{}
+	data.Set("client_id", clientID)
+	data.Set("client_secret", clientSecret)
+	data.Set("token", c.AccessToken)
+
+	resp, err := c.HTTPClient.PostForm(endpoint, data)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError("revoke token", resp)
+	}
+
+	return nil
+}
+
+func (c *Client) LookupAccount(acct string) (*Account, error) {
+	params := url.Values{}
+	params.Set("acct", acct)
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/lookup?%s", c.BaseURL, params.Encode())
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorizeIfLoggedIn(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up account: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("look up account", resp)
+	}
+
+	var account Account
+	if err := json.NewDecoder(resp.Body).Decode(&account); err != nil {
+		return nil, fmt.Errorf("failed to decode account response: %w", err)
+	}
+
+	return &account, nil
+}
+
+func (c *Client) GetStatusContext(id string) (*StatusContext, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/statuses/%s/context", c.BaseURL, id)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status context: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get status context", resp)
+	}
+
+	var context StatusContext
+	if err := json.NewDecoder(resp.Body).Decode(&context); err != nil {
+		return nil, fmt.Errorf("failed to decode status context response: %w", err)
+	}
+
+	return &context, nil
+}
+
+// GetFavourites fetches a page of favourited statuses. Pass "" for pageURL to
+// get the first page, then the returned next URL for each following page; next
+// is "" once there are no more.
+func (c *Client) GetFavourites(pageURL string, limit int) ([]*Status, string, error) {
+	endpoint := pageURL
+	if endpoint == "" {
+		params := url.Values{}
+		params.Set("limit", fmt.Sprintf("%d", limit))
+		endpoint = fmt.Sprintf("%s/api/v1/favourites?%s", c.BaseURL, params.Encode())
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get favourites: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", newAPIError("get favourites", resp)
+	}
+
+	var statuses []*Status
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, "", fmt.Errorf("failed to decode favourites response: %w", err)
+	}
+
+	return statuses, nextPageURL(resp), nil
+}
+
+func (c *Client) UnfavouriteStatus(id string) error {
+	endpoint := fmt.Sprintf("%s/api/v1/statuses/%s/unfavourite", c.BaseURL, id)
+
+	req, err := http.NewRequest("POST", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to unfavourite status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError("unfavourite status", resp)
+	}
+
+	return nil
+}
+
+func (c *Client) GetNotification(id string) (*Notification, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/notifications/%s", c.BaseURL, id)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get notification", resp)
+	}
+
+	var notification Notification
+	if err := json.NewDecoder(resp.Body).Decode(&notification); err != nil {
+		return nil, fmt.Errorf("failed to decode notification response: %w", err)
+	}
+
+	return &notification, nil
+}
+
+// TokenValid reports whether the access token is still accepted. Unlike
+// VerifyCredentials, a rejected token is not an error.
+func (c *Client) TokenValid() (bool, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/verify_credentials", c.BaseURL)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify credentials: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, nil
+	default:
+		return false, newAPIError("verify credentials", resp)
+	}
+}
+
+func (c *Client) GetAnnouncements() ([]*Announcement, error) {
+	if !c.Flavor.SupportsAnnouncements() {
+		return nil, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/announcements", c.BaseURL)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get announcements: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get announcements", resp)
+	}
+
+	var announcements []*Announcement
+	if err := json.NewDecoder(resp.Body).Decode(&announcements); err != nil {
+		return nil, fmt.Errorf("failed to decode announcements response: %w", err)
+	}
+
+	return announcements, nil
+}
+
+func (c *Client) GetFollowRequests(limit int) ([]*Account, error) {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	endpoint := fmt.Sprintf("%s/api/v1/follow_requests?%s", c.BaseURL, params.Encode())
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get follow requests: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get follow requests", resp)
+	}
+
+	var accounts []*Account
+	if err := json.NewDecoder(resp.Body).Decode(&accounts); err != nil {
+		return nil, fmt.Errorf("failed to decode follow requests response: %w", err)
+	}
+
+	return accounts, nil
+}
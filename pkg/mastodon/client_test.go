@@ -0,0 +1,952 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewClient(t *testing.T) {
+	baseURL := "https://mastodon.social"
+	token := "test_token"
+
+	client := NewClient(baseURL, token)
+
+	if client.BaseURL != baseURL {
+		t.Errorf("Expected BaseURL %q, got %q", baseURL, client.BaseURL)
+	}
+
+	if client.AccessToken != token {
+		t.Errorf("Expected AccessToken %q, got %q", token, client.AccessToken)
+	}
+
+	if client.HTTPClient == nil {
+		t.Error("Expected HTTPClient to be initialized")
+	}
+}
+
+func TestRegisterApp(t *testing.T) {
+	expectedApp := &App{
+		ClientID:     "test_client_id",
+		ClientSecret: "test_client_secret",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/apps" {
+			t.Errorf("Expected path /api/v1/apps, got %s", r.URL.Path)
+		}
+
+		if r.Method != "POST" {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form: %v", err)
+		}
+
+		if r.FormValue("client_name") != "TestApp" {
+			t.Errorf("Expected client_name TestApp, got %s", r.FormValue("client_name"))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(expectedApp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	app, err := client.RegisterApp("TestApp", "http://localhost:8080/callback", "read write")
+
+	if err != nil {
+		t.Fatalf("Failed to register app: %v", err)
+	}
+
+	if app.ClientID != expectedApp.ClientID {
+		t.Errorf("Expected ClientID %q, got %q", expectedApp.ClientID, app.ClientID)
+	}
+
+	if app.ClientSecret != expectedApp.ClientSecret {
+		t.Errorf("Expected ClientSecret %q, got %q", expectedApp.ClientSecret, app.ClientSecret)
+	}
+}
+
+func TestGetAuthorizationURL(t *testing.T) {
+	client := NewClient("https://mastodon.social", "")
+	clientID := "test_client_id"
+	redirectURI := "http://localhost:8080/callback"
+	scopes := "read write"
+
+	authURL := client.GetAuthorizationURL(clientID, redirectURI, scopes)
+
+	parsedURL, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("Failed to parse auth URL: %v", err)
+	}
+
+	if parsedURL.Scheme != "https" {
+		t.Errorf("Expected scheme https, got %s", parsedURL.Scheme)
+	}
+
+	if parsedURL.Host != "mastodon.social" {
+		t.Errorf("Expected host mastodon.social, got %s", parsedURL.Host)
+	}
+
+	query := parsedURL.Query()
+	if query.Get("client_id") != clientID {
+		t.Errorf("Expected client_id %q, got %q", clientID, query.Get("client_id"))
+	}
+
+	if query.Get("redirect_uri") != redirectURI {
+		t.Errorf("Expected redirect_uri %q, got %q", redirectURI, query.Get("redirect_uri"))
+	}
+
+	if query.Get("response_type") != "code" {
+		t.Errorf("Expected response_type code, got %s", query.Get("response_type"))
+	}
+}
+
+func TestGetAccessToken(t *testing.T) {
+	expectedToken := "test_access_token_xyz"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" {
+			t.Errorf("Expected path /oauth/token, got %s", r.URL.Path)
+		}
+
+		if r.Method != "POST" {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+
+		response := map[string]string{
+			"access_token": expectedToken,
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	token, err := client.GetAccessToken("client_id", "client_secret", "redirect_uri", "auth_code")
+
+	if err != nil {
+		t.Fatalf("Failed to get access token: %v", err)
+	}
+
+	if token != expectedToken {
+		t.Errorf("Expected token %q, got %q", expectedToken, token)
+	}
+}
+
+func TestPostStatus(t *testing.T) {
+	expectedStatus := &Status{
+		ID:      "123456",
+		URL:     "https://mastodon.social/@user/123456",
+		Content: "Test status",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses" {
+			t.Errorf("Expected path /api/v1/statuses, got %s", r.URL.Path)
+		}
+
+		if r.Method != "POST" {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+
+		authHeader := r.Header.Get("Authorization")
+		if authHeader != "Bearer test_token" {
+			t.Errorf("Expected Authorization header 'Bearer test_token', got %q", authHeader)
+		}
+
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		if payload["status"] != "Test status" {
+			t.Errorf("Expected status 'Test status', got %v", payload["status"])
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(expectedStatus)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	status, err := client.PostStatus(StatusParams{
+		Status: "Test status",
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to post status: %v", err)
+	}
+
+	if status.ID != expectedStatus.ID {
+		t.Errorf("Expected ID %q, got %q", expectedStatus.ID, status.ID)
+	}
+
+	if status.URL != expectedStatus.URL {
+		t.Errorf("Expected URL %q, got %q", expectedStatus.URL, status.URL)
+	}
+}
+
+func TestPostStatusWithParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		if payload["in_reply_to_id"] != "999" {
+			t.Errorf("Expected in_reply_to_id '999', got %v", payload["in_reply_to_id"])
+		}
+
+		if payload["visibility"] != "unlisted" {
+			t.Errorf("Expected visibility 'unlisted', got %v", payload["visibility"])
+		}
+
+		if payload["spoiler_text"] != "CW: test" {
+			t.Errorf("Expected spoiler_text 'CW: test', got %v", payload["spoiler_text"])
+		}
+
+		if payload["sensitive"] != true {
+			t.Errorf("Expected sensitive true, got %v", payload["sensitive"])
+		}
+
+		if payload["local_only"] != true {
+			t.Errorf("Expected local_only true, got %v", payload["local_only"])
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&Status{ID: "123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	_, err := client.PostStatus(StatusParams{
+		Status:      "Test",
+		InReplyToID: "999",
+		Visibility:  "unlisted",
+		SpoilerText: "CW: test",
+		Sensitive:   true,
+		LocalOnly:   true,
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to post status: %v", err)
+	}
+}
+
+func TestPostStatusRetriesWithIdempotencyKey(t *testing.T) {
+	saved := statusRetryDelay
+	statusRetryDelay = 0
+	defer func() { statusRetryDelay = saved }()
+
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(&Status{ID: "123"})
+	}))
+	defer server.Close()
+
+	var retries []bool
+	client := NewClient(server.URL, "test_token")
+	client.ObserveRequests(func(m RequestMetric) {
+		retries = append(retries, m.Retry)
+	})
+	status, err := client.PostStatus(StatusParams{Status: "Hello", IdempotencyKey: "abc"})
+	if err != nil {
+		t.Fatalf("Failed to post status: %v", err)
+	}
+	if status.ID != "123" {
+		t.Errorf("Expected ID 123, got %q", status.ID)
+	}
+	if len(keys) != 3 || keys[0] != "abc" || keys[2] != "abc" {
+		t.Errorf("Expected three requests with key abc, got %q", keys)
+	}
+	if want := []bool{false, true, true}; !slices.Equal(retries, want) {
+		t.Errorf("Expected the repeats to be counted as retries, got %v", retries)
+	}
+}
+
+func TestPostStatusRetriesDroppedConnection(t *testing.T) {
+	saved := statusRetryDelay
+	statusRetryDelay = 0
+	defer func() { statusRetryDelay = saved }()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			// Hang up without answering, as a restarting instance might
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Failed to hijack connection: %v", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		json.NewEncoder(w).Encode(&Status{ID: "123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	status, err := client.PostStatus(StatusParams{Status: "Hello", IdempotencyKey: "abc"})
+	if err != nil {
+		t.Fatalf("Failed to post status: %v", err)
+	}
+	if status.ID != "123" || requests != 2 {
+		t.Errorf("Expected status 123 after 2 requests, got %q after %d", status.ID, requests)
+	}
+}
+
+func TestPostStatusWithoutIdempotencyKeyDoesNotRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Idempotency-Key") != "" {
+			t.Errorf("Expected no Idempotency-Key, got %q", r.Header.Get("Idempotency-Key"))
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	_, err := client.PostStatus(StatusParams{Status: "Hello"})
+	if apiErr, ok := AsAPIError(err); !ok || apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected a 502 error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestPostStatusContextCancelled(t *testing.T) {
+	requests := 0
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewClient(server.URL, "test_token")
+	_, err := client.PostStatusContext(ctx, StatusParams{Status: "Hello", IdempotencyKey: "abc"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to stop the post, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the post not to be retried once ctx is done, got %d requests", requests)
+	}
+}
+
+func TestPostStatusRetryStopsWithContext(t *testing.T) {
+	saved := statusRetryDelay
+	statusRetryDelay = time.Hour
+	defer func() { statusRetryDelay = saved }()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	client := NewClient(server.URL, "test_token")
+	_, err := client.PostStatusContext(ctx, StatusParams{Status: "Hello", IdempotencyKey: "abc"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to end the wait for a retry, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected the wait to stop with ctx, took %s", elapsed)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestEditStatusContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/v1/statuses/42" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&Status{ID: "42", Content: "<p>Edited</p>"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	status, err := client.EditStatusContext(context.Background(), "42", StatusParams{Status: "Edited"})
+	if err != nil || status.ID != "42" {
+		t.Fatalf("EditStatusContext() = %+v, %v", status, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.EditStatusContext(ctx, "42", StatusParams{Status: "Edited"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled ctx to stop the edit, got %v", err)
+	}
+}
+
+func TestScheduleStatus(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		if payload["scheduled_at"] != "2026-10-16T09:00:00Z" {
+			t.Errorf("Expected scheduled_at 2026-10-16T09:00:00Z, got %v", payload["scheduled_at"])
+		}
+		if payload["visibility"] != "unlisted" {
+			t.Errorf("Expected visibility unlisted, got %v", payload["visibility"])
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "7", "scheduled_at": "2026-10-16T09:00:00.000Z", "params": {"text": "Later"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	scheduled, err := client.ScheduleStatus(StatusParams{Status: "Later", Visibility: VisibilityUnlisted}, at)
+	if err != nil {
+		t.Fatalf("Failed to schedule status: %v", err)
+	}
+
+	if scheduled.ID != "7" || !scheduled.ScheduledAt.Equal(at) {
+		t.Errorf("Unexpected scheduled status %+v", scheduled)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	expectedStatus := &Status{
+		ID:      "123456",
+		URL:     "https://mastodon.social/@user/123456",
+		Content: "Test status",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses/123456" {
+			t.Errorf("Expected path /api/v1/statuses/123456, got %s", r.URL.Path)
+		}
+
+		if r.Method != "GET" {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+
+		authHeader := r.Header.Get("Authorization")
+		if authHeader != "Bearer test_token" {
+			t.Errorf("Expected Authorization header 'Bearer test_token', got %q", authHeader)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(expectedStatus)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	status, err := client.GetStatus("123456")
+
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+
+	if status.ID != expectedStatus.ID {
+		t.Errorf("Expected ID %q, got %q", expectedStatus.ID, status.ID)
+	}
+}
+
+func TestStatusDecodesPollCardAndApplication(t *testing.T) {
+	body := `{
+		"id": "1",
+		"visibility": "unlisted",
+		"poll": {"id": "9", "expires_at": null, "multiple": false, "votes_count": 3,
+			"options": [{"title": "Yes", "votes_count": 2}, {"title": "No", "votes_count": null}]},
+		"card": {"url": "https://example.com/", "title": "Example", "type": "link"},
+		"application": {"name": "tusk", "website": null}
+	}`
+
+	var status Status
+	if err := json.Unmarshal([]byte(body), &status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+
+	if status.Poll == nil || len(status.Poll.Options) != 2 || status.Poll.Options[0].VotesCount != 2 {
+		t.Errorf("Expected a poll with two options, got %+v", status.Poll)
+	}
+	if status.Poll != nil && status.Poll.ExpiresAt != nil {
+		t.Errorf("Expected no expiry, got %v", status.Poll.ExpiresAt)
+	}
+	if status.Card == nil || status.Card.Title != "Example" {
+		t.Errorf("Expected card Example, got %+v", status.Card)
+	}
+	if status.Application == nil || status.Application.Name != "tusk" {
+		t.Errorf("Expected application tusk, got %+v", status.Application)
+	}
+}
+
+func TestGetStatusSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses/123456/source" {
+			t.Errorf("Expected path /api/v1/statuses/123456/source, got %s", r.URL.Path)
+		}
+
+		if r.Method != "GET" {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&StatusSource{ID: "123456", Text: "Plain *source*", SpoilerText: "cw"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	source, err := client.GetStatusSource("123456")
+
+	if err != nil {
+		t.Fatalf("Failed to get status source: %v", err)
+	}
+
+	if source.Text != "Plain *source*" {
+		t.Errorf("Expected text 'Plain *source*', got %q", source.Text)
+	}
+}
+
+func TestUploadMedia(t *testing.T) {
+	expectedMedia := &MediaAttachment{
+		ID:          "media123",
+		Type:        "image",
+		URL:         "https://example.com/media/image.jpg",
+		PreviewURL:  "https://example.com/media/preview.jpg",
+		Description: "Test alt text",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/media" {
+			t.Errorf("Expected path /api/v2/media, got %s", r.URL.Path)
+		}
+
+		if r.Method != "POST" {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+
+		authHeader := r.Header.Get("Authorization")
+		if authHeader != "Bearer test_token" {
+			t.Errorf("Expected Authorization header 'Bearer test_token', got %q", authHeader)
+		}
+
+		// Parse multipart form
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			t.Fatalf("Failed to parse multipart form: %v", err)
+		}
+
+		description := r.FormValue("description")
+		if description != "Test alt text" {
+			t.Errorf("Expected description 'Test alt text', got %q", description)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(expectedMedia)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	testData := []byte("test image data")
+	media, err := client.UploadMedia(testData, "test.jpg", "image/jpeg", "Test alt text")
+
+	if err != nil {
+		t.Fatalf("Failed to upload media: %v", err)
+	}
+
+	if media.ID != expectedMedia.ID {
+		t.Errorf("Expected ID %q, got %q", expectedMedia.ID, media.ID)
+	}
+
+	if media.Description != expectedMedia.Description {
+		t.Errorf("Expected description %q, got %q", expectedMedia.Description, media.Description)
+	}
+}
+
+func TestDeleteStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses/123456" {
+			t.Errorf("Expected path /api/v1/statuses/123456, got %s", r.URL.Path)
+		}
+
+		if r.Method != "DELETE" {
+			t.Errorf("Expected DELETE method, got %s", r.Method)
+		}
+
+		authHeader := r.Header.Get("Authorization")
+		if authHeader != "Bearer test_token" {
+			t.Errorf("Expected Authorization header 'Bearer test_token', got %q", authHeader)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	status, err := client.DeleteStatus("123456")
+
+	if err != nil {
+		t.Fatalf("Failed to delete status: %v", err)
+	}
+
+	if status.ID != "123456" {
+		t.Errorf("Expected the deleted status's ID, got %q", status.ID)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/revoke" {
+			t.Errorf("Expected path /oauth/revoke, got %s", r.URL.Path)
+		}
+
+		if r.Method != "POST" {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form: %v", err)
+		}
+
+		if r.FormValue("token") != "test_token" {
+			t.Errorf("Expected token 'test_token', got %s", r.FormValue("token"))
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	err := client.RevokeToken("client_id", "client_secret")
+
+	if err != nil {
+		t.Fatalf("Failed to revoke token: %v", err)
+	}
+}
+
+func TestLookupAccount(t *testing.T) {
+	expectedAccount := &Account{
+		ID:       "42",
+		Username: "alice",
+		Acct:     "alice@example.com",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/lookup" {
+			t.Errorf("Expected path /api/v1/accounts/lookup, got %s", r.URL.Path)
+		}
+
+		if r.Method != "GET" {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+
+		if r.URL.Query().Get("acct") != "alice@example.com" {
+			t.Errorf("Expected acct 'alice@example.com', got %q", r.URL.Query().Get("acct"))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(expectedAccount)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	account, err := client.LookupAccount("alice@example.com")
+
+	if err != nil {
+		t.Fatalf("Failed to look up account: %v", err)
+	}
+
+	if account.ID != expectedAccount.ID {
+		t.Errorf("Expected ID %q, got %q", expectedAccount.ID, account.ID)
+	}
+}
+
+func TestLookupAccountNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"Record not found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	if _, err := client.LookupAccount("nobody@example.com"); err == nil {
+		t.Error("Expected error for unknown account, got nil")
+	}
+}
+
+func TestGetAccountStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/accounts/verify_credentials":
+			json.NewEncoder(w).Encode(&Account{ID: "42", Acct: "me"})
+		case "/api/v1/accounts/42/statuses":
+			if r.URL.Query().Get("limit") != "20" {
+				t.Errorf("Expected limit 20, got %s", r.URL.Query().Get("limit"))
+			}
+			if r.URL.Query().Has("max_id") {
+				t.Errorf("Expected no max_id, got %s", r.URL.Query().Get("max_id"))
+			}
+			json.NewEncoder(w).Encode([]*Status{{ID: "2"}, {ID: "1"}})
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	statuses, err := client.GetAccountStatuses(20)
+
+	if err != nil {
+		t.Fatalf("Failed to get statuses: %v", err)
+	}
+
+	if len(statuses) != 2 || statuses[0].ID != "2" {
+		t.Errorf("Unexpected statuses: %v", statuses)
+	}
+}
+
+func TestGetAccountStatusesPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/42/statuses" {
+			t.Errorf("Expected path /api/v1/accounts/42/statuses, got %s", r.URL.Path)
+		}
+
+		if r.URL.Query().Get("max_id") != "100" {
+			t.Errorf("Expected max_id 100, got %q", r.URL.Query().Get("max_id"))
+		}
+
+		json.NewEncoder(w).Encode([]*Status{{ID: "99"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	statuses, err := client.GetAccountStatusesPage("42", "100", 40)
+
+	if err != nil {
+		t.Fatalf("Failed to get statuses: %v", err)
+	}
+
+	if len(statuses) != 1 || statuses[0].ID != "99" {
+		t.Errorf("Unexpected statuses: %v", statuses)
+	}
+}
+
+func TestGetStatusContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses/123/context" {
+			t.Errorf("Expected path /api/v1/statuses/123/context, got %s", r.URL.Path)
+		}
+
+		json.NewEncoder(w).Encode(&StatusContext{
+			Ancestors:   []*Status{{ID: "100"}},
+			Descendants: []*Status{{ID: "124"}, {ID: "125"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	context, err := client.GetStatusContext("123")
+
+	if err != nil {
+		t.Fatalf("Failed to get status context: %v", err)
+	}
+
+	if len(context.Ancestors) != 1 || len(context.Descendants) != 2 {
+		t.Errorf("Unexpected context: %+v", context)
+	}
+}
+
+func TestGetFavourites(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/favourites" {
+			t.Errorf("Expected path /api/v1/favourites, got %s", r.URL.Path)
+		}
+
+		if r.URL.Query().Get("max_id") == "" {
+			if r.URL.Query().Get("limit") != "2" {
+				t.Errorf("Expected limit 2, got %s", r.URL.Query().Get("limit"))
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/favourites?max_id=abc>; rel="next"`, server.URL))
+			json.NewEncoder(w).Encode([]*Status{{ID: "5"}, {ID: "3"}})
+			return
+		}
+
+		json.NewEncoder(w).Encode([]*Status{{ID: "1"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	statuses, next, err := client.GetFavourites("", 2)
+	if err != nil {
+		t.Fatalf("Failed to get favourites: %v", err)
+	}
+	if len(statuses) != 2 || next == "" {
+		t.Fatalf("Unexpected first page: %v, next %q", statuses, next)
+	}
+
+	statuses, next, err = client.GetFavourites(next, 2)
+	if err != nil {
+		t.Fatalf("Failed to get second page: %v", err)
+	}
+	if len(statuses) != 1 || next != "" {
+		t.Errorf("Unexpected second page: %v, next %q", statuses, next)
+	}
+}
+
+func TestUnfavouriteStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses/123/unfavourite" {
+			t.Errorf("Expected path /api/v1/statuses/123/unfavourite, got %s", r.URL.Path)
+		}
+
+		if r.Method != "POST" {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+
+		json.NewEncoder(w).Encode(&Status{ID: "123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	if err := client.UnfavouriteStatus("123"); err != nil {
+		t.Errorf("Failed to unfavourite status: %v", err)
+	}
+}
+
+func TestGetNotification(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/notifications/55" {
+			t.Errorf("Expected path /api/v1/notifications/55, got %s", r.URL.Path)
+		}
+
+		json.NewEncoder(w).Encode(&Notification{
+			ID:      "55",
+			Type:    "mention",
+			Account: &Account{Acct: "alice"},
+			Status:  &Status{ID: "123"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	notification, err := client.GetNotification("55")
+
+	if err != nil {
+		t.Fatalf("Failed to get notification: %v", err)
+	}
+
+	if notification.Type != "mention" || notification.Status == nil || notification.Status.ID != "123" {
+		t.Errorf("Unexpected notification: %+v", notification)
+	}
+}
+
+func TestTokenValid(t *testing.T) {
+	valid := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"The access token is invalid"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(&Account{ID: "42"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	ok, err := client.TokenValid()
+	if err != nil || !ok {
+		t.Errorf("Expected valid token, got %v, %v", ok, err)
+	}
+
+	valid = false
+	ok, err = client.TokenValid()
+	if err != nil || ok {
+		t.Errorf("Expected invalid token without error, got %v, %v", ok, err)
+	}
+}
+
+func TestGetAnnouncements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/announcements" {
+			t.Errorf("Expected path /api/v1/announcements, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]*Announcement{{ID: "1", Read: true}, {ID: "2"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	announcements, err := client.GetAnnouncements()
+	if err != nil {
+		t.Fatalf("Failed to get announcements: %v", err)
+	}
+
+	if len(announcements) != 2 || !announcements[0].Read || announcements[1].Read {
+		t.Errorf("Unexpected announcements: %+v", announcements)
+	}
+}
+
+func TestGetFollowRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/follow_requests" {
+			t.Errorf("Expected path /api/v1/follow_requests, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("limit") != "40" {
+			t.Errorf("Expected limit 40, got %s", r.URL.Query().Get("limit"))
+		}
+		json.NewEncoder(w).Encode([]*Account{{ID: "7", Acct: "bob"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	accounts, err := client.GetFollowRequests(40)
+	if err != nil {
+		t.Fatalf("Failed to get follow requests: %v", err)
+	}
+
+	if len(accounts) != 1 || accounts[0].Acct != "bob" {
+		t.Errorf("Unexpected follow requests: %+v", accounts)
+	}
+}
+
+func TestDeleteStatusReturnsDeletedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"123456","text":"old words","url":"https://example.com/@me/123456"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	status, err := client.DeleteStatus("123456")
+	if err != nil {
+		t.Fatalf("Failed to delete status: %v", err)
+	}
+
+	if status.URL != "https://example.com/@me/123456" {
+		t.Errorf("Expected the deleted status, got %+v", status)
+	}
+}
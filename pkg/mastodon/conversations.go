@@ -0,0 +1,90 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Conversation is a thread of direct statuses, as shown in the web client's
+// private mentions column
+type Conversation struct {
+	ID         string     `json:"id"`
+	Unread     bool       `json:"unread"`
+	Accounts   []*Account `json:"accounts"`
+	LastStatus *Status    `json:"last_status"`
+}
+
+// GetConversations returns your most recently active conversations
+func (c *Client) GetConversations(limit int) ([]*Conversation, error) {
+	params := url.Values{}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/conversations?%s", c.BaseURL, params.Encode())
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get conversations", resp)
+	}
+
+	var conversations []*Conversation
+	if err := json.NewDecoder(resp.Body).Decode(&conversations); err != nil {
+		return nil, fmt.Errorf("failed to decode conversations response: %w", err)
+	}
+
+	return conversations, nil
+}
+
+// MarkConversationRead clears a conversation's unread marker
+func (c *Client) MarkConversationRead(id string) (*Conversation, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/conversations/%s/read", c.BaseURL, url.PathEscape(id))
+
+	req, err := http.NewRequest("POST", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark conversation read: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("mark conversation read", resp)
+	}
+
+	var conversation Conversation
+	if err := json.NewDecoder(resp.Body).Decode(&conversation); err != nil {
+		return nil, fmt.Errorf("failed to decode conversation response: %w", err)
+	}
+
+	return &conversation, nil
+}
+
+// FindConversation returns the conversation with the given ID, or nil
+func FindConversation(conversations []*Conversation, id string) *Conversation {
+	for _, conversation := range conversations {
+		if conversation.ID == id {
+			return conversation
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetConversations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/conversations" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("limit") != "10" {
+			t.Errorf("Expected limit=10, got %q", r.URL.Query().Get("limit"))
+		}
+		w.Write([]byte(`[{"id":"7","unread":true,"accounts":[{"id":"2","acct":"alice@example.com"}],"last_status":{"id":"99","visibility":"direct"}}]`))
+	}))
+	defer server.Close()
+
+	conversations, err := NewClient(server.URL, "test_token").GetConversations(10)
+	if err != nil {
+		t.Fatalf("GetConversations failed: %v", err)
+	}
+	if len(conversations) != 1 {
+		t.Fatalf("Expected 1 conversation, got %d", len(conversations))
+	}
+
+	c := conversations[0]
+	if !c.Unread || c.LastStatus == nil || c.LastStatus.ID != "99" || c.Accounts[0].Acct != "alice@example.com" {
+		t.Errorf("Unexpected conversation %+v", c)
+	}
+}
+
+func TestMarkConversationRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/conversations/7/read" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"id":"7","unread":false}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "test_token").MarkConversationRead("7")
+	if err != nil {
+		t.Fatalf("MarkConversationRead failed: %v", err)
+	}
+	if c.Unread {
+		t.Error("Expected the conversation to be read")
+	}
+}
+
+func TestFindConversation(t *testing.T) {
+	conversations := []*Conversation{{ID: "1"}, {ID: "2"}}
+
+	if c := FindConversation(conversations, "2"); c == nil || c.ID != "2" {
+		t.Errorf("Expected conversation 2, got %v", c)
+	}
+	if c := FindConversation(conversations, "3"); c != nil {
+		t.Errorf("Expected nil, got %v", c)
+	}
+}
@@ -0,0 +1,54 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GetFavouritedBy fetches a page of the accounts that favourited a status.
+// Pass "" for pageURL to get the first page, then the returned next URL for
+// each following page; next is "" once there are no more.
+func (c *Client) GetFavouritedBy(statusID, pageURL string, limit int) ([]*Account, string, error) {
+	return c.getStatusAccounts(statusID, "favourited_by", pageURL, limit)
+}
+
+// GetRebloggedBy fetches a page of the accounts that boosted a status, paged
+// like GetFavouritedBy
+func (c *Client) GetRebloggedBy(statusID, pageURL string, limit int) ([]*Account, string, error) {
+	return c.getStatusAccounts(statusID, "reblogged_by", pageURL, limit)
+}
+
+func (c *Client) getStatusAccounts(statusID, kind, pageURL string, limit int) ([]*Account, string, error) {
+	endpoint := pageURL
+	if endpoint == "" {
+		params := url.Values{}
+		params.Set("limit", fmt.Sprintf("%d", limit))
+		endpoint = fmt.Sprintf("%s/api/v1/statuses/%s/%s?%s", c.BaseURL, url.PathEscape(statusID), kind, params.Encode())
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorizeIfLoggedIn(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get %s: %w", kind, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", newAPIError("get "+kind, resp)
+	}
+
+	var accounts []*Account
+	if err := json.NewDecoder(resp.Body).Decode(&accounts); err != nil {
+		return nil, "", fmt.Errorf("failed to decode accounts response: %w", err)
+	}
+
+	return accounts, nextPageURL(resp), nil
+}
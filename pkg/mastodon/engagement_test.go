@@ -0,0 +1,65 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetFavouritedByPages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses/123/favourited_by" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("max_id") == "" {
+			if r.URL.Query().Get("limit") != "2" {
+				t.Errorf("Expected limit=2, got %q", r.URL.Query().Get("limit"))
+			}
+			w.Header().Set("Link", `<`+server.URL+`/api/v1/statuses/123/favourited_by?limit=2&max_id=50>; rel="next", <`+server.URL+`/api/v1/statuses/123/favourited_by?min_id=60>; rel="prev"`)
+			w.Write([]byte(`[{"id":"1","acct":"alice"},{"id":"2","acct":"bob"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id":"3","acct":"carol@example.com"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+
+	accounts, next, err := client.GetFavouritedBy("123", "", 2)
+	if err != nil {
+		t.Fatalf("GetFavouritedBy failed: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].Acct != "alice" {
+		t.Errorf("Unexpected first page %+v", accounts)
+	}
+	if next == "" {
+		t.Fatal("Expected a next page")
+	}
+
+	accounts, next, err = client.GetFavouritedBy("123", next, 2)
+	if err != nil {
+		t.Fatalf("GetFavouritedBy failed on the second page: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Acct != "carol@example.com" || next != "" {
+		t.Errorf("Unexpected last page %+v (next %q)", accounts, next)
+	}
+}
+
+func TestGetRebloggedBy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses/123/reblogged_by" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	accounts, next, err := NewClient(server.URL, "test_token").GetRebloggedBy("123", "", 40)
+	if err != nil {
+		t.Fatalf("GetRebloggedBy failed: %v", err)
+	}
+	if len(accounts) != 0 || next != "" {
+		t.Errorf("Expected nothing, got %+v (next %q)", accounts, next)
+	}
+}
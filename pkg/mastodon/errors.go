@@ -0,0 +1,116 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is an error response from the instance. Mastodon explains most
+// failures in the body's error field, and OAuth endpoints add
+// error_description.
+type APIError struct {
+	Op          string // what tusk was trying to do, e.g. "post status"
+	StatusCode  int
+	Message     string // the body's error field, or the raw body
+	Description string // the body's error_description field
+
+	// RetryAfter is how long the instance asked us to wait, for 429 and 503
+	// responses that say so
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if e.Description != "" {
+		if message != "" {
+			message += ": "
+		}
+		message += e.Description
+	}
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("failed to %s: %s (status %d)", e.Op, message, e.StatusCode)
+}
+
+// Unauthorized reports whether the access token was missing, expired, or revoked
+func (e *APIError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+// NotFound reports whether the status, account, or endpoint doesn't exist
+func (e *APIError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// Invalid reports whether the instance rejected the request's content, such
+// as a status that's too long
+func (e *APIError) Invalid() bool {
+	return e.StatusCode == http.StatusUnprocessableEntity
+}
+
+// RateLimited reports whether the instance turned the request away for
+// exceeding its rate limit
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// AsAPIError finds an APIError in err's chain
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// newAPIError reads an error response. op says what failed, e.g. "get status".
+func newAPIError(op string, resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+
+	apiErr := &APIError{
+		Op:         op,
+		StatusCode: resp.StatusCode,
+		RetryAfter: retryAfter(resp.Header, time.Now()),
+	}
+
+	var payload struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && (payload.Error != "" || payload.ErrorDescription != "") {
+		apiErr.Message = payload.Error
+		apiErr.Description = payload.ErrorDescription
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	return apiErr
+}
+
+// retryAfter reads how long to wait from Retry-After, which is either seconds
+// or a date, falling back to Mastodon's X-RateLimit-Reset
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(value); err == nil && at.After(now) {
+			return at.Sub(now)
+		}
+	}
+
+	if value := header.Get("X-RateLimit-Reset"); value != "" {
+		if at, err := time.Parse(time.RFC3339, value); err == nil && at.After(now) {
+			return at.Sub(now)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,86 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIErrorFromMastodonBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":"Validation failed: Text character limit of 500 exceeded"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	_, err := client.PostStatus(StatusParams{Status: "long"})
+
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if !apiErr.Invalid() || apiErr.Unauthorized() || apiErr.NotFound() || apiErr.RateLimited() {
+		t.Errorf("Expected only Invalid to be true for %+v", apiErr)
+	}
+	want := "failed to post status: Validation failed: Text character limit of 500 exceeded (status 422)"
+	if err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestAPIErrorMessages(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"error":"invalid_grant","error_description":"The provided authorization grant is invalid"}`,
+			"failed to get access token: invalid_grant: The provided authorization grant is invalid (status 401)"},
+		{`<html>Bad Gateway</html>`, "failed to get access token: <html>Bad Gateway</html> (status 401)"},
+		{``, "failed to get access token: Unauthorized (status 401)"},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(tt.body))
+		}))
+
+		client := NewClient(server.URL, "")
+		_, err := client.GetAccessToken("id", "secret", "urn:ietf:wg:oauth:2.0:oob", "code")
+		server.Close()
+
+		apiErr, ok := AsAPIError(err)
+		if !ok || !apiErr.Unauthorized() {
+			t.Errorf("Expected an unauthorized APIError, got %v", err)
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, err.Error())
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"seconds", http.Header{"Retry-After": {"120"}}, 2 * time.Minute},
+		{"date", http.Header{"Retry-After": {"Sun, 01 Mar 2026 12:00:30 GMT"}}, 30 * time.Second},
+		{"rate limit reset", http.Header{"X-Ratelimit-Reset": {"2026-03-01T12:05:00.000Z"}}, 5 * time.Minute},
+		{"reset in the past", http.Header{"X-Ratelimit-Reset": {"2026-03-01T11:00:00Z"}}, 0},
+		{"none", http.Header{}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfter(tt.header, now); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package mastodon
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestMetric describes one API request, for performance diagnostics
+type RequestMetric struct {
+	Method   string
+	Endpoint string
+	Status   int // 0 if no response was received
+	Duration time.Duration
+	// Retry is whether the request repeated one that failed
+	Retry bool
+}
+
+// RateLimited reports whether the server turned the request away for
+// exceeding its rate limit
+func (m RequestMetric) RateLimited() bool {
+	return m.Status == http.StatusTooManyRequests
+}
+
+// ObserveRequests calls observe with the timing of every request the client
+// makes. It composes with RecordTo and ReplayFrom, but should be set up after
+// them so that the timing includes the whole round trip.
+func (c *Client) ObserveRequests(observe func(RequestMetric)) {
+	next := c.HTTPClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.HTTPClient.Transport = &timingTransport{next: next, observe: observe}
+}
+
+// retryKey marks a request's context as a retry, for its metric
+type retryKey struct{}
+
+// asRetry returns ctx marked so that requests made with it are counted as
+// retries
+func asRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryKey{}, true)
+}
+
+type timingTransport struct {
+	next    http.RoundTripper
+	observe func(RequestMetric)
+}
+
+func (t *timingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	metric := RequestMetric{
+		Method:   req.Method,
+		Endpoint: endpointPattern(req.URL.Path),
+		Duration: time.Since(start),
+		Retry:    req.Context().Value(retryKey{}) != nil,
+	}
+	if err == nil {
+		metric.Status = resp.StatusCode
+	}
+	t.observe(metric)
+
+	return resp, err
+}
+
+// endpointPattern replaces the IDs in an API path with ":id", so requests
+// for different statuses or accounts are grouped together
+func endpointPattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if looksLikeID(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// looksLikeID matches Mastodon's numeric IDs as well as the longer
+// alphanumeric ones other servers (e.g. Pleroma) use
+func looksLikeID(segment string) bool {
+	if segment == "" || (len(segment) == 2 && segment[0] == 'v') {
+		return false
+	}
+
+	digits := 0
+	for _, r := range segment {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		default:
+			return false
+		}
+	}
+
+	return digits == len(segment) || (digits > 0 && len(segment) >= 16)
+}
@@ -0,0 +1,61 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEndpointPattern(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/statuses/109876543210987654", "/api/v1/statuses/:id"},
+		{"/api/v1/statuses/123/context", "/api/v1/statuses/:id/context"},
+		{"/api/v1/accounts/verify_credentials", "/api/v1/accounts/verify_credentials"},
+		{"/api/v2/media", "/api/v2/media"},
+		{"/api/v1/statuses/AbCdEf1234567890Xy", "/api/v1/statuses/:id"},
+		{"/api/v1/favourites", "/api/v1/favourites"},
+	}
+
+	for _, tt := range tests {
+		if got := endpointPattern(tt.path); got != tt.want {
+			t.Errorf("endpointPattern(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestObserveRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/statuses/1" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"id":"2"}`))
+	}))
+	defer server.Close()
+
+	var metrics []RequestMetric
+	client := NewClient(server.URL, "test_token")
+	client.ObserveRequests(func(m RequestMetric) {
+		metrics = append(metrics, m)
+	})
+
+	client.GetStatus("1")
+	client.GetStatus("2")
+
+	if len(metrics) != 2 {
+		t.Fatalf("Expected 2 metrics, got %d", len(metrics))
+	}
+
+	if metrics[0].Endpoint != "/api/v1/statuses/:id" || metrics[0].Method != "GET" {
+		t.Errorf("Unexpected metric %+v", metrics[0])
+	}
+	if !metrics[0].RateLimited() {
+		t.Error("Expected first request to be rate limited")
+	}
+	if metrics[1].Status != http.StatusOK || metrics[1].RateLimited() {
+		t.Errorf("Unexpected metric %+v", metrics[1])
+	}
+}
@@ -0,0 +1,70 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Notification types, as used in Notification.Type and to filter
+// GetNotifications
+const (
+	NotificationMention       = "mention"
+	NotificationStatus        = "status"
+	NotificationReblog        = "reblog"
+	NotificationFollow        = "follow"
+	NotificationFollowRequest = "follow_request"
+	NotificationFavourite     = "favourite"
+	NotificationPoll          = "poll"
+	NotificationUpdate        = "update"
+)
+
+// NotificationTypes are the notification types every Mastodon version since
+// 3.5 sends
+var NotificationTypes = []string{
+	NotificationMention, NotificationStatus, NotificationReblog, NotificationFollow,
+	NotificationFollowRequest, NotificationFavourite, NotificationPoll, NotificationUpdate,
+}
+
+// GetNotifications returns up to limit notifications newer than sinceID,
+// newest first. An empty sinceID returns the newest, and types, if given,
+// limits them to those types.
+func (c *Client) GetNotifications(sinceID string, types []string, limit int) ([]*Notification, error) {
+	params := url.Values{}
+	if sinceID != "" {
+		params.Set("since_id", sinceID)
+	}
+	for _, t := range types {
+		params.Add("types[]", t)
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/notifications?%s", c.BaseURL, params.Encode())
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get notifications", resp)
+	}
+
+	var notifications []*Notification
+	if err := json.NewDecoder(resp.Body).Decode(&notifications); err != nil {
+		return nil, fmt.Errorf("failed to decode notifications response: %w", err)
+	}
+
+	return notifications, nil
+}
@@ -0,0 +1,40 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetNotifications(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/notifications" {
+			t.Errorf("Expected path /api/v1/notifications, got %s", r.URL.Path)
+		}
+
+		query := r.URL.Query()
+		if query.Get("since_id") != "10" {
+			t.Errorf("Expected since_id 10, got %q", query.Get("since_id"))
+		}
+		if types := strings.Join(query["types[]"], ","); types != "mention,follow" {
+			t.Errorf("Expected types mention,follow, got %q", types)
+		}
+		if query.Get("limit") != "30" {
+			t.Errorf("Expected limit 30, got %q", query.Get("limit"))
+		}
+
+		w.Write([]byte(`[{"id":"12","type":"follow","account":{"acct":"alice"}},{"id":"11","type":"mention","status":{"id":"5"}}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	notifications, err := client.GetNotifications("10", []string{NotificationMention, NotificationFollow}, 30)
+	if err != nil {
+		t.Fatalf("Failed to get notifications: %v", err)
+	}
+
+	if len(notifications) != 2 || notifications[0].ID != "12" || notifications[1].Status.ID != "5" {
+		t.Errorf("Expected notifications 12 and 11, got %+v", notifications)
+	}
+}
@@ -0,0 +1,33 @@
+package mastodon
+
+import (
+	"net/http"
+	"strings"
+)
+
+// nextPageURL extracts the rel="next" target from a response's Link header,
+// which is how Mastodon paginates endpoints whose cursors aren't status IDs.
+// It returns "" on the last page.
+func nextPageURL(resp *http.Response) string {
+	for _, header := range resp.Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 {
+				continue
+			}
+
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				param = strings.TrimSpace(param)
+				if param == `rel="next"` || param == "rel=next" {
+					return strings.Trim(target, "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
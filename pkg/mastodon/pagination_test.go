@@ -0,0 +1,47 @@
+package mastodon
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "next and prev",
+			header: `<https://example.com/api/v1/favourites?max_id=9>; rel="next", <https://example.com/api/v1/favourites?min_id=20>; rel="prev"`,
+			want:   "https://example.com/api/v1/favourites?max_id=9",
+		},
+		{
+			name:   "prev only",
+			header: `<https://example.com/api/v1/favourites?min_id=20>; rel="prev"`,
+			want:   "",
+		},
+		{
+			name:   "no header",
+			header: "",
+			want:   "",
+		},
+		{
+			name:   "malformed",
+			header: `https://example.com; rel="next"`,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Link", tt.header)
+			}
+			if got := nextPageURL(resp); got != tt.want {
+				t.Errorf("nextPageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// PinStatus pins one of your statuses to your profile
+func (c *Client) PinStatus(id string) (*Status, error) {
+	return c.postStatusAction(id, "pin", "pin status")
+}
+
+// UnpinStatus takes a status off your profile
+func (c *Client) UnpinStatus(id string) (*Status, error) {
+	return c.postStatusAction(id, "unpin", "unpin status")
+}
+
+// GetPinnedStatuses returns the statuses an account has pinned to its profile
+func (c *Client) GetPinnedStatuses(accountID string) ([]*Status, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%s/statuses?pinned=true", c.BaseURL, accountID)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorizeIfLoggedIn(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned statuses: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get pinned statuses", resp)
+	}
+
+	var statuses []*Status
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, fmt.Errorf("failed to decode statuses response: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// postStatusAction POSTs to one of the /api/v1/statuses/:id/ACTION endpoints,
+// which all return the updated status
+func (c *Client) postStatusAction(id, action, op string) (*Status, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/statuses/%s/%s", c.BaseURL, id, action)
+
+	req, err := http.NewRequest("POST", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(op, resp)
+	}
+
+	var status Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode status response: %w", err)
+	}
+
+	return &status, nil
+}
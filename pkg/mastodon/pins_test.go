@@ -0,0 +1,72 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPinAndUnpinStatus(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if r.Header.Get("Authorization") != "Bearer test_token" {
+			t.Errorf("Unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"id":"123","pinned":true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+
+	status, err := client.PinStatus("123")
+	if err != nil {
+		t.Fatalf("PinStatus failed: %v", err)
+	}
+	if status.ID != "123" {
+		t.Errorf("Expected status 123, got %q", status.ID)
+	}
+
+	if _, err := client.UnpinStatus("123"); err != nil {
+		t.Fatalf("UnpinStatus failed: %v", err)
+	}
+
+	if len(paths) != 2 || paths[0] != "/api/v1/statuses/123/pin" || paths[1] != "/api/v1/statuses/123/unpin" {
+		t.Errorf("Unexpected paths %v", paths)
+	}
+}
+
+func TestPinStatusNotYours(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":"Validation failed: Status can't be pinned"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test_token").PinStatus("123")
+	apiErr, ok := AsAPIError(err)
+	if !ok || !apiErr.Invalid() {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestGetPinnedStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/42/statuses" || r.URL.Query().Get("pinned") != "true" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[{"id":"1"},{"id":"2"}]`))
+	}))
+	defer server.Close()
+
+	statuses, err := NewClient(server.URL, "test_token").GetPinnedStatuses("42")
+	if err != nil {
+		t.Fatalf("GetPinnedStatuses failed: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Errorf("Expected 2 statuses, got %d", len(statuses))
+	}
+}
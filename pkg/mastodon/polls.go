@@ -0,0 +1,74 @@
+package mastodon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// GetPoll fetches a poll with its latest results. Polls are found by their
+// own ID, which is the Poll.ID of the status they're attached to.
+func (c *Client) GetPoll(id string) (*Poll, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/polls/%s", c.BaseURL, id)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorizeIfLoggedIn(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get poll", resp)
+	}
+
+	var poll Poll
+	if err := json.NewDecoder(resp.Body).Decode(&poll); err != nil {
+		return nil, fmt.Errorf("failed to decode poll response: %w", err)
+	}
+
+	return &poll, nil
+}
+
+// VotePoll votes in a poll for the options at the given indexes, counting
+// from 0. Only a poll that allows Multiple takes more than one; the instance
+// rejects a second vote, or one in a poll that has closed.
+func (c *Client) VotePoll(id string, choices []int) (*Poll, error) {
+	jsonData, err := json.Marshal(map[string][]int{"choices": choices})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/polls/%s/votes", c.BaseURL, id)
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to vote: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("vote", resp)
+	}
+
+	var poll Poll
+	if err := json.NewDecoder(resp.Body).Decode(&poll); err != nil {
+		return nil, fmt.Errorf("failed to decode poll response: %w", err)
+	}
+
+	return &poll, nil
+}
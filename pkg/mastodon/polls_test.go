@@ -0,0 +1,52 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetPoll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/v1/polls/9" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"id":"9","votes_count":5,"voters_count":4,"multiple":true,
+			"options":[{"title":"Tea","votes_count":3},{"title":"Coffee","votes_count":2}],
+			"voted":true,"own_votes":[0]}`))
+	}))
+	defer server.Close()
+
+	poll, err := NewClient(server.URL, "test_token").GetPoll("9")
+	if err != nil {
+		t.Fatalf("GetPoll failed: %v", err)
+	}
+	if poll.VotersCount != 4 || len(poll.Options) != 2 || poll.Options[1].Title != "Coffee" || !reflect.DeepEqual(poll.OwnVotes, []int{0}) {
+		t.Errorf("Unexpected poll %+v", poll)
+	}
+}
+
+func TestVotePoll(t *testing.T) {
+	var body map[string][]int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/polls/9/votes" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"id":"9","voted":true,"own_votes":[0,2]}`))
+	}))
+	defer server.Close()
+
+	poll, err := NewClient(server.URL, "test_token").VotePoll("9", []int{0, 2})
+	if err != nil {
+		t.Fatalf("VotePoll failed: %v", err)
+	}
+	if !reflect.DeepEqual(body["choices"], []int{0, 2}) {
+		t.Errorf("Expected choices [0 2], got %v", body["choices"])
+	}
+	if !poll.Voted {
+		t.Errorf("Expected the poll to be voted in, got %+v", poll)
+	}
+}
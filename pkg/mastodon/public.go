@@ -0,0 +1,150 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Endpoints that most instances serve without a login, for browsing before
+// (or without) running 'tusk auth'. Each sends the access token only when the
+// client has one.
+
+// authorizeIfLoggedIn adds the access token to a request for a public
+// endpoint, if there is one; some instances reject an empty bearer token
+func (c *Client) authorizeIfLoggedIn(req *http.Request) {
+	if c.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	}
+}
+
+// TimelineParams picks which statuses a timeline request returns. Limit 0
+// leaves it to the server, which usually sends 20.
+type TimelineParams struct {
+	Limit int
+
+	// MaxID returns only statuses older than this one, and SinceID only
+	// newer ones, for paging
+	MaxID   string
+	SinceID string
+
+	// Local keeps only statuses from this instance, for the public timeline
+	Local bool
+	// OnlyMedia keeps only statuses with attachments
+	OnlyMedia bool
+}
+
+// query is the timeline request's query string
+func (p TimelineParams) query() url.Values {
+	query := url.Values{}
+	if p.Limit > 0 {
+		query.Set("limit", fmt.Sprintf("%d", p.Limit))
+	}
+	if p.MaxID != "" {
+		query.Set("max_id", p.MaxID)
+	}
+	if p.SinceID != "" {
+		query.Set("since_id", p.SinceID)
+	}
+	if p.Local {
+		query.Set("local", "true")
+	}
+	if p.OnlyMedia {
+		query.Set("only_media", "true")
+	}
+	return query
+}
+
+// GetPublicTimeline fetches the newest public statuses, from this instance
+// only when local is set
+func (c *Client) GetPublicTimeline(local bool, limit int) ([]*Status, error) {
+	return c.GetPublicTimelineContext(context.Background(), TimelineParams{Limit: limit, Local: local})
+}
+
+// GetPublicTimelineContext fetches public statuses, newest first, stopping
+// when ctx is done
+func (c *Client) GetPublicTimelineContext(ctx context.Context, params TimelineParams) ([]*Status, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/timelines/public?%s", c.BaseURL, params.query().Encode())
+	return c.getTimeline(ctx, endpoint)
+}
+
+// GetTagTimeline fetches the newest public statuses using a hashtag
+func (c *Client) GetTagTimeline(tag string, limit int) ([]*Status, error) {
+	return c.GetTagTimelineContext(context.Background(), tag, TimelineParams{Limit: limit})
+}
+
+// GetTagTimelineContext fetches public statuses using a hashtag, newest
+// first, stopping when ctx is done
+func (c *Client) GetTagTimelineContext(ctx context.Context, tag string, params TimelineParams) ([]*Status, error) {
+	tag = strings.TrimPrefix(tag, "#")
+	endpoint := fmt.Sprintf("%s/api/v1/timelines/tag/%s?%s", c.BaseURL, url.PathEscape(tag), params.query().Encode())
+	return c.getTimeline(ctx, endpoint)
+}
+
+// GetHomeTimelineContext fetches the statuses of the accounts the logged-in
+// account follows, newest first, stopping when ctx is done. It needs a login.
+func (c *Client) GetHomeTimelineContext(ctx context.Context, params TimelineParams) ([]*Status, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/timelines/home?%s", c.BaseURL, params.query().Encode())
+	return c.getTimeline(ctx, endpoint)
+}
+
+// AccountTimelineParams picks which of an account's statuses
+// GetAccountTimeline returns
+type AccountTimelineParams struct {
+	Limit int
+	// ExcludeReplies leaves out replies to other accounts, as a profile's
+	// Posts tab does; replies in the account's own threads stay
+	ExcludeReplies bool
+	// OnlyMedia keeps only statuses with attachments
+	OnlyMedia bool
+
+	// MaxID returns only statuses older than this one, for paging
+	MaxID string
+}
+
+// GetAccountTimeline fetches the newest statuses an account has written,
+// leaving out its boosts
+func (c *Client) GetAccountTimeline(accountID string, params AccountTimelineParams) ([]*Status, error) {
+	return c.GetAccountTimelineContext(context.Background(), accountID, params)
+}
+
+// GetAccountTimelineContext is GetAccountTimeline, stopping when ctx is done
+func (c *Client) GetAccountTimelineContext(ctx context.Context, accountID string, params AccountTimelineParams) ([]*Status, error) {
+	query := TimelineParams{Limit: params.Limit, MaxID: params.MaxID, OnlyMedia: params.OnlyMedia}.query()
+	query.Set("exclude_reblogs", "true")
+	if params.ExcludeReplies {
+		query.Set("exclude_replies", "true")
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%s/statuses?%s", c.BaseURL, url.PathEscape(accountID), query.Encode())
+
+	return c.getTimeline(ctx, endpoint)
+}
+
+func (c *Client) getTimeline(ctx context.Context, endpoint string) ([]*Status, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorizeIfLoggedIn(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timeline: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get timeline", resp)
+	}
+
+	var statuses []*Status
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, fmt.Errorf("failed to decode timeline response: %w", err)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,131 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetPublicTimelineWithoutLogin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/timelines/public" {
+			t.Errorf("Expected path /api/v1/timelines/public, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Expected no Authorization header, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("local") != "true" {
+			t.Errorf("Expected local=true, got %q", r.URL.Query().Get("local"))
+		}
+		if r.URL.Query().Get("limit") != "5" {
+			t.Errorf("Expected limit 5, got %q", r.URL.Query().Get("limit"))
+		}
+
+		json.NewEncoder(w).Encode([]*Status{{ID: "1"}, {ID: "2"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	statuses, err := client.GetPublicTimeline(true, 5)
+	if err != nil {
+		t.Fatalf("Failed to get timeline: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].ID != "1" {
+		t.Errorf("Unexpected statuses: %+v", statuses)
+	}
+}
+
+func TestGetTagTimeline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/timelines/tag/golang" {
+			t.Errorf("Expected path /api/v1/timelines/tag/golang, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test_token" {
+			t.Errorf("Expected the token to be sent when logged in, got %q", r.Header.Get("Authorization"))
+		}
+
+		json.NewEncoder(w).Encode([]*Status{{ID: "7"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	statuses, err := client.GetTagTimeline("#golang", 20)
+	if err != nil {
+		t.Fatalf("Failed to get tag timeline: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].ID != "7" {
+		t.Errorf("Unexpected statuses: %+v", statuses)
+	}
+}
+
+func TestGetTimelineError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":"This method requires an authenticated user"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	if _, err := client.GetPublicTimeline(false, 20); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestGetAccountTimeline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/42/statuses" {
+			t.Errorf("Expected path /api/v1/accounts/42/statuses, got %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("exclude_replies") != "true" || query.Get("only_media") != "true" || query.Get("exclude_reblogs") != "true" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		if query.Get("limit") != "10" {
+			t.Errorf("Expected limit 10, got %q", query.Get("limit"))
+		}
+
+		json.NewEncoder(w).Encode([]*Status{{ID: "9"}})
+	}))
+	defer server.Close()
+
+	statuses, err := NewClient(server.URL, "").GetAccountTimeline("42", AccountTimelineParams{
+		Limit:          10,
+		ExcludeReplies: true,
+		OnlyMedia:      true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to get account timeline: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].ID != "9" {
+		t.Errorf("Unexpected statuses: %+v", statuses)
+	}
+}
+
+func TestGetHomeTimelineContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/timelines/home" {
+			t.Errorf("Expected path /api/v1/timelines/home, got %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("limit") != "10" || query.Get("max_id") != "500" || query.Get("only_media") != "true" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		if query.Has("local") || query.Has("since_id") {
+			t.Errorf("Expected unset params to be left out, got %s", r.URL.RawQuery)
+		}
+
+		json.NewEncoder(w).Encode([]*Status{{ID: "499"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	statuses, err := client.GetHomeTimelineContext(context.Background(), TimelineParams{Limit: 10, MaxID: "500", OnlyMedia: true})
+	if err != nil {
+		t.Fatalf("GetHomeTimelineContext failed: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].ID != "499" {
+		t.Errorf("Unexpected statuses %v", statuses)
+	}
+}
@@ -0,0 +1,156 @@
+package mastodon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Which notifications a push subscription delivers, by the accounts they're
+// from
+const (
+	PushPolicyAll      = "all"
+	PushPolicyFollowed = "followed"
+	PushPolicyFollower = "follower"
+	PushPolicyNone     = "none"
+)
+
+// PushSubscription is the instance's record of where to push notifications
+// for the access token, one subscription per token
+type PushSubscription struct {
+	ID       json.Number     `json:"id"`
+	Endpoint string          `json:"endpoint"`
+	Alerts   map[string]bool `json:"alerts"`
+	Policy   string          `json:"policy"`
+	// ServerKey is the instance's VAPID public key, which it signs pushes with
+	ServerKey string `json:"server_key"`
+	// Standard reports whether pushes are encrypted as RFC 8291 describes
+	// (aes128gcm), rather than with the draft scheme (aesgcm) instances used
+	// before it
+	Standard bool `json:"standard"`
+}
+
+// PushSubscriptionParams sets up a push subscription. P256DH and Auth are the
+// receiver's public key and authentication secret, base64url-encoded.
+type PushSubscriptionParams struct {
+	Endpoint string
+	P256DH   string
+	Auth     string
+	// Alerts are the notification types to push, such as mention
+	Alerts []string
+	Policy string
+}
+
+// PushPayload is a decrypted push from Mastodon
+type PushPayload struct {
+	NotificationID   json.Number `json:"notification_id"`
+	NotificationType string      `json:"notification_type"`
+	Title            string      `json:"title"`
+	Body             string      `json:"body"`
+	Icon             string      `json:"icon"`
+	PreferredLocale  string      `json:"preferred_locale"`
+}
+
+// SubscribePush points the access token's push subscription at an endpoint,
+// replacing any it had. Instances that support standard Web Push encrypt
+// with it; older ones ignore the request and use the draft scheme.
+func (c *Client) SubscribePush(params PushSubscriptionParams) (*PushSubscription, error) {
+	alerts := make(map[string]bool, len(params.Alerts))
+	for _, alert := range params.Alerts {
+		alerts[alert] = true
+	}
+
+	body := map[string]any{
+		"subscription": map[string]any{
+			"endpoint": params.Endpoint,
+			"keys": map[string]string{
+				"p256dh": params.P256DH,
+				"auth":   params.Auth,
+			},
+			"standard": true,
+		},
+		"data": map[string]any{
+			"alerts": alerts,
+			"policy": params.Policy,
+		},
+	}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.BaseURL+"/api/v1/push/subscription", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to push notifications: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("subscribe to push notifications", resp)
+	}
+
+	var subscription PushSubscription
+	if err := json.NewDecoder(resp.Body).Decode(&subscription); err != nil {
+		return nil, fmt.Errorf("failed to decode push subscription response: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+// GetPushSubscription returns the access token's push subscription. An
+// instance without one responds with a 404 APIError.
+func (c *Client) GetPushSubscription() (*PushSubscription, error) {
+	req, err := http.NewRequest("GET", c.BaseURL+"/api/v1/push/subscription", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push subscription: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get push subscription", resp)
+	}
+
+	var subscription PushSubscription
+	if err := json.NewDecoder(resp.Body).Decode(&subscription); err != nil {
+		return nil, fmt.Errorf("failed to decode push subscription response: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+// UnsubscribePush removes the access token's push subscription
+func (c *Client) UnsubscribePush() error {
+	req, err := http.NewRequest("DELETE", c.BaseURL+"/api/v1/push/subscription", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe from push notifications: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError("unsubscribe from push notifications", resp)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSubscribePush(t *testing.T) {
+	var body struct {
+		Subscription struct {
+			Endpoint string            `json:"endpoint"`
+			Keys     map[string]string `json:"keys"`
+			Standard bool              `json:"standard"`
+		} `json:"subscription"`
+		Data struct {
+			Alerts map[string]bool `json:"alerts"`
+			Policy string          `json:"policy"`
+		} `json:"data"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/push/subscription" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"id":7,"endpoint":"https://push.example.com/tusk","alerts":{"mention":true},"policy":"followed","server_key":"BKey","standard":true}`)
+	}))
+	defer server.Close()
+
+	subscription, err := NewClient(server.URL, "token").SubscribePush(PushSubscriptionParams{
+		Endpoint: "https://push.example.com/tusk",
+		P256DH:   "BPublic",
+		Auth:     "secret",
+		Alerts:   []string{NotificationMention},
+		Policy:   PushPolicyFollowed,
+	})
+	if err != nil {
+		t.Fatalf("SubscribePush failed: %v", err)
+	}
+
+	if body.Subscription.Endpoint != "https://push.example.com/tusk" || body.Subscription.Keys["p256dh"] != "BPublic" || body.Subscription.Keys["auth"] != "secret" || !body.Subscription.Standard {
+		t.Errorf("Unexpected subscription sent: %+v", body.Subscription)
+	}
+	if !body.Data.Alerts[NotificationMention] || len(body.Data.Alerts) != 1 || body.Data.Policy != PushPolicyFollowed {
+		t.Errorf("Unexpected data sent: %+v", body.Data)
+	}
+	if subscription.ID.String() != "7" || subscription.ServerKey != "BKey" || !subscription.Standard {
+		t.Errorf("Unexpected subscription: %+v", subscription)
+	}
+}
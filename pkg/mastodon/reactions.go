@@ -0,0 +1,149 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Emoji reactions aren't part of Mastodon's API. Pleroma and Akkoma have
+// their own endpoints for them, and glitch-soc and its forks add theirs
+// under /api/v1/statuses.
+
+// ReactionAPI is the flavor of emoji reactions an instance supports
+type ReactionAPI int
+
+const (
+	ReactionsUnsupported ReactionAPI = iota
+	ReactionsPleroma
+	ReactionsGlitch
+)
+
+// Reaction is an emoji reaction on a status
+type Reaction struct {
+	// Name is the emoji itself, or a custom emoji's shortcode
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Me    bool   `json:"me"`
+
+	// URL is the image of a custom emoji
+	URL string `json:"url"`
+
+	// Accounts is who reacted, which only Pleroma and Akkoma list
+	Accounts []*Account `json:"accounts"`
+}
+
+// DetectReactions works out which emoji reaction API the instance has, if
+// any. Glitch-soc says so in its instance configuration.
+func (c *Client) DetectReactions() (ReactionAPI, error) {
+	if c.Flavor.SupportsReactions() {
+		return ReactionsPleroma, nil
+	}
+
+	instance, err := c.GetInstance()
+	if err != nil {
+		return ReactionsUnsupported, err
+	}
+	switch {
+	case flavorFromVersion(instance.Version).SupportsReactions():
+		return ReactionsPleroma, nil
+	case instance.Configuration.Reactions.MaxReactions > 0:
+		return ReactionsGlitch, nil
+	default:
+		return ReactionsUnsupported, nil
+	}
+}
+
+// React adds an emoji reaction to a status. A custom emoji may be given with
+// or without its colons.
+func (c *Client) React(api ReactionAPI, id, emoji string) (*Status, error) {
+	if api == ReactionsPleroma {
+		return c.pleromaReaction("PUT", id, emoji, "react to status")
+	}
+	return c.postStatusAction(id, "react/"+reactionName(emoji), "react to status")
+}
+
+// Unreact removes your emoji reaction from a status
+func (c *Client) Unreact(api ReactionAPI, id, emoji string) (*Status, error) {
+	if api == ReactionsPleroma {
+		return c.pleromaReaction("DELETE", id, emoji, "remove reaction")
+	}
+	return c.postStatusAction(id, "unreact/"+reactionName(emoji), "remove reaction")
+}
+
+// GetReactions returns the emoji reactions on a status
+func (c *Client) GetReactions(api ReactionAPI, id string) ([]*Reaction, error) {
+	if api != ReactionsPleroma {
+		status, err := c.GetStatus(id)
+		if err != nil {
+			return nil, err
+		}
+		return status.Reactions, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/pleroma/statuses/%s/reactions", c.BaseURL, id)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorizeIfLoggedIn(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get reactions", resp)
+	}
+
+	var reactions []*Reaction
+	if err := json.NewDecoder(resp.Body).Decode(&reactions); err != nil {
+		return nil, fmt.Errorf("failed to decode reactions response: %w", err)
+	}
+
+	return reactions, nil
+}
+
+func (c *Client) pleromaReaction(method, id, emoji, op string) (*Status, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/pleroma/statuses/%s/reactions/%s", c.BaseURL, id, reactionName(emoji))
+
+	req, err := http.NewRequest(method, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(op, resp)
+	}
+
+	var status Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode status response: %w", err)
+	}
+
+	return &status, nil
+}
+
+// reactionName is emoji as it goes in a reaction URL: a custom emoji's
+// shortcode without colons, escaped for the path
+func reactionName(emoji string) string {
+	name := strings.TrimSpace(emoji)
+	if len(name) > 2 && strings.HasPrefix(name, ":") && strings.HasSuffix(name, ":") {
+		name = name[1 : len(name)-1]
+	}
+	return url.PathEscape(name)
+}
@@ -0,0 +1,117 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectReactions(t *testing.T) {
+	tests := []struct {
+		flavor   Flavor
+		instance string
+		want     ReactionAPI
+	}{
+		{FlavorAkkoma, `{}`, ReactionsPleroma},
+		{FlavorUnknown, `{"version":"2.7.2 (compatible; Pleroma 2.5.0)"}`, ReactionsPleroma},
+		{FlavorMastodon, `{"version":"4.2.1+glitch","configuration":{"reactions":{"max_reactions":1}}}`, ReactionsGlitch},
+		{FlavorMastodon, `{"version":"4.2.1"}`, ReactionsUnsupported},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tt.instance))
+		}))
+
+		client := NewClient(server.URL, "test_token")
+		client.Flavor = tt.flavor
+		got, err := client.DetectReactions()
+		server.Close()
+
+		if err != nil {
+			t.Fatalf("DetectReactions failed: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("DetectReactions() for %s %s = %d, want %d", tt.flavor, tt.instance, got, tt.want)
+		}
+	}
+}
+
+func TestReactPleroma(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		w.Write([]byte(`{"id":"123"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	if _, err := client.React(ReactionsPleroma, "123", ":blobcat:"); err != nil {
+		t.Fatalf("React failed: %v", err)
+	}
+	if _, err := client.Unreact(ReactionsPleroma, "123", "👍"); err != nil {
+		t.Fatalf("Unreact failed: %v", err)
+	}
+
+	want := []string{
+		"PUT /api/v1/pleroma/statuses/123/reactions/blobcat",
+		"DELETE /api/v1/pleroma/statuses/123/reactions/%F0%9F%91%8D",
+	}
+	if len(requests) != 2 || requests[0] != want[0] || requests[1] != want[1] {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+}
+
+func TestReactGlitch(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"id":"123"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	client.React(ReactionsGlitch, "123", ":blobcat:")
+	client.Unreact(ReactionsGlitch, "123", ":blobcat:")
+
+	want := []string{
+		"POST /api/v1/statuses/123/react/blobcat",
+		"POST /api/v1/statuses/123/unreact/blobcat",
+	}
+	if len(requests) != 2 || requests[0] != want[0] || requests[1] != want[1] {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+}
+
+func TestGetReactions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/pleroma/statuses/123/reactions":
+			json.NewEncoder(w).Encode([]*Reaction{{Name: "👍", Count: 2, Accounts: []*Account{{Acct: "alice"}, {Acct: "bob"}}}})
+		case "/api/v1/statuses/123":
+			w.Write([]byte(`{"id":"123","reactions":[{"name":"blobcat","count":1,"me":true,"url":"https://example.com/blobcat.png"}]}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+
+	reactions, err := client.GetReactions(ReactionsPleroma, "123")
+	if err != nil {
+		t.Fatalf("GetReactions failed: %v", err)
+	}
+	if len(reactions) != 1 || reactions[0].Count != 2 || len(reactions[0].Accounts) != 2 {
+		t.Errorf("Unexpected Pleroma reactions %+v", reactions)
+	}
+
+	reactions, err = client.GetReactions(ReactionsGlitch, "123")
+	if err != nil {
+		t.Fatalf("GetReactions failed: %v", err)
+	}
+	if len(reactions) != 1 || reactions[0].Name != "blobcat" || !reactions[0].Me {
+		t.Errorf("Unexpected glitch reactions %+v", reactions)
+	}
+}
@@ -0,0 +1,12 @@
+package mastodon
+
+// ReblogStatus boosts a status. The status returned is the boost itself.
+func (c *Client) ReblogStatus(id string) (*Status, error) {
+	return c.postStatusAction(id, "reblog", "boost status")
+}
+
+// UnreblogStatus undoes a boost of a status. Undoing one that isn't boosted
+// succeeds without changing anything.
+func (c *Client) UnreblogStatus(id string) (*Status, error) {
+	return c.postStatusAction(id, "unreblog", "unboost status")
+}
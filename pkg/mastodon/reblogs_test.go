@@ -0,0 +1,36 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReblogAndUnreblogStatus(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"id":"456"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+
+	if _, err := client.UnreblogStatus("123"); err != nil {
+		t.Fatalf("UnreblogStatus failed: %v", err)
+	}
+	boost, err := client.ReblogStatus("123")
+	if err != nil {
+		t.Fatalf("ReblogStatus failed: %v", err)
+	}
+	if boost.ID != "456" {
+		t.Errorf("Expected the boost, got %q", boost.ID)
+	}
+
+	if len(paths) != 2 || paths[0] != "/api/v1/statuses/123/unreblog" || paths[1] != "/api/v1/statuses/123/reblog" {
+		t.Errorf("Unexpected paths %v", paths)
+	}
+}
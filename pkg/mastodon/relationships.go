@@ -0,0 +1,162 @@
+package mastodon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Relationship is how the logged-in account and another account stand
+// towards each other
+type Relationship struct {
+	ID string `json:"id"`
+	// Following is true once a follow is accepted; until then, Requested is
+	Following bool `json:"following"`
+	// ShowingReblogs and Notifying say how a followed account's posts are
+	// shown: with its boosts, and with a notification for each
+	ShowingReblogs bool `json:"showing_reblogs"`
+	Notifying      bool `json:"notifying"`
+	// Languages limits a followed account's posts in the home timeline to
+	// these, or shows all of them when empty
+	Languages  []string `json:"languages"`
+	FollowedBy bool     `json:"followed_by"`
+	// RequestedBy is true while the other account waits for the logged-in
+	// one to approve its follow
+	RequestedBy bool `json:"requested_by"`
+	Blocking    bool `json:"blocking"`
+	BlockedBy   bool `json:"blocked_by"`
+	// DomainBlocking is true when the other account's whole instance is
+	// blocked
+	DomainBlocking bool `json:"domain_blocking"`
+	Muting         bool `json:"muting"`
+	// MutingNotifications is true when a mute hides notifications too
+	MutingNotifications bool `json:"muting_notifications"`
+	Requested           bool `json:"requested"`
+	// Note is the logged-in account's private note about the other one
+	Note string `json:"note"`
+}
+
+// FollowParams are the options a follow is made with
+type FollowParams struct {
+	// Reblogs shows the account's boosts in the home timeline
+	Reblogs bool
+	// Notify sends a notification for each of the account's posts
+	Notify bool
+	// Languages limits which of the account's posts are shown; empty shows
+	// them all
+	Languages []string
+}
+
+// FollowAccount follows an account, or updates the options of a follow that
+// already exists. An account that approves followers leaves the follow
+// Requested until it does.
+func (c *Client) FollowAccount(id string, params FollowParams) (*Relationship, error) {
+	body := map[string]any{
+		"reblogs": params.Reblogs,
+		"notify":  params.Notify,
+	}
+	if len(params.Languages) > 0 {
+		body["languages"] = params.Languages
+	}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%s/follow", c.BaseURL, id)
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow account: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("follow account", resp)
+	}
+
+	var relationship Relationship
+	if err := json.NewDecoder(resp.Body).Decode(&relationship); err != nil {
+		return nil, fmt.Errorf("failed to decode relationship response: %w", err)
+	}
+
+	return &relationship, nil
+}
+
+// GetRelationships returns how the logged-in account stands towards each of
+// the accounts with the given IDs
+func (c *Client) GetRelationships(ids []string) ([]*Relationship, error) {
+	params := url.Values{}
+	for _, id := range ids {
+		params.Add("id[]", id)
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/relationships?%s", c.BaseURL, params.Encode())
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relationships: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get relationships", resp)
+	}
+
+	var relationships []*Relationship
+	if err := json.NewDecoder(resp.Body).Decode(&relationships); err != nil {
+		return nil, fmt.Errorf("failed to decode relationships response: %w", err)
+	}
+
+	return relationships, nil
+}
+
+// SetAccountNote sets the logged-in account's private note about another
+// account. An empty comment removes it.
+func (c *Client) SetAccountNote(id, comment string) (*Relationship, error) {
+	jsonData, err := json.Marshal(map[string]string{"comment": comment})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%s/note", c.BaseURL, id)
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set account note: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("set account note", resp)
+	}
+
+	var relationship Relationship
+	if err := json.NewDecoder(resp.Body).Decode(&relationship); err != nil {
+		return nil, fmt.Errorf("failed to decode relationship response: %w", err)
+	}
+
+	return &relationship, nil
+}
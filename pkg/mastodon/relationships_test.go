@@ -0,0 +1,80 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFollowAccount(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/accounts/42/follow" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode body: %v", err)
+		}
+		w.Write([]byte(`{"id":"42","following":false,"requested":true,"showing_reblogs":true}`))
+	}))
+	defer server.Close()
+
+	relationship, err := NewClient(server.URL, "test_token").FollowAccount("42", FollowParams{
+		Reblogs:   true,
+		Languages: []string{"en", "de"},
+	})
+	if err != nil {
+		t.Fatalf("FollowAccount failed: %v", err)
+	}
+	if !relationship.Requested || relationship.Following {
+		t.Errorf("Expected a pending follow, got %+v", relationship)
+	}
+
+	want := map[string]any{"reblogs": true, "notify": false, "languages": []any{"en", "de"}}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("Expected body %v, got %v", want, body)
+	}
+}
+
+func TestGetRelationships(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/relationships" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if ids := r.URL.Query()["id[]"]; !reflect.DeepEqual(ids, []string{"42", "43"}) {
+			t.Errorf("Expected ids 42 and 43, got %q", ids)
+		}
+		w.Write([]byte(`[{"id":"42","following":true,"followed_by":true,"note":"met at a conference"},{"id":"43","muting":true}]`))
+	}))
+	defer server.Close()
+
+	relationships, err := NewClient(server.URL, "test_token").GetRelationships([]string{"42", "43"})
+	if err != nil {
+		t.Fatalf("GetRelationships failed: %v", err)
+	}
+	if len(relationships) != 2 || !relationships[0].FollowedBy || relationships[0].Note != "met at a conference" || !relationships[1].Muting {
+		t.Errorf("Unexpected relationships %+v", relationships)
+	}
+}
+
+func TestSetAccountNote(t *testing.T) {
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/accounts/42/note" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"id":"42","note":"met at a conference"}`))
+	}))
+	defer server.Close()
+
+	relationship, err := NewClient(server.URL, "test_token").SetAccountNote("42", "met at a conference")
+	if err != nil {
+		t.Fatalf("SetAccountNote failed: %v", err)
+	}
+	if body["comment"] != "met at a conference" || relationship.Note != "met at a conference" {
+		t.Errorf("Unexpected body %v and relationship %+v", body, relationship)
+	}
+}
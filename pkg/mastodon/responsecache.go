@@ -0,0 +1,123 @@
+package mastodon
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CachedResponse is a response body kept for reuse, with the ETag to check
+// that it's still current
+type CachedResponse struct {
+	Body        []byte
+	ContentType string
+	ETag        string
+	StoredAt    time.Time
+}
+
+// ResponseCache keeps responses from slow-changing GET endpoints between
+// runs. Keys are request URLs, without secrets.
+type ResponseCache interface {
+	CachedResponse(key string) *CachedResponse
+	StoreResponse(key string, resp *CachedResponse)
+}
+
+// cacheTTLs are the endpoints whose responses are cached, and how long they're
+// used without asking the instance. After that they're revalidated with
+// If-None-Match, so an unchanged response costs a 304 and no body.
+var cacheTTLs = map[string]time.Duration{
+	"/api/v1/instance":        24 * time.Hour,
+	"/api/v2/instance":        24 * time.Hour,
+	"/api/v1/custom_emojis":   24 * time.Hour,
+	"/.well-known/nodeinfo":   24 * time.Hour,
+	"/api/v1/accounts/lookup": time.Hour,
+}
+
+// CacheResponses makes the client reuse responses from cache for instance
+// information, custom emoji, and account lookups. It wraps the transport, so
+// requests it answers from the cache aren't seen by RecordTo, ObserveRequests,
+// or TraceTo when they're set up before it.
+func (c *Client) CacheResponses(cache ResponseCache) {
+	next := c.HTTPClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.HTTPClient.Transport = &cachingTransport{next: next, cache: cache, now: time.Now}
+}
+
+type cachingTransport struct {
+	next  http.RoundTripper
+	cache ResponseCache
+	now   func() time.Time
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ttl, ok := cacheTTLs[req.URL.Path]
+	if !ok || req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+
+	key := req.URL.Scheme + "://" + req.URL.Host + redactURL(req.URL)
+	cached := t.cache.CachedResponse(key)
+	if cached != nil && t.now().Sub(cached.StoredAt) < ttl {
+		return cachedHTTPResponse(req, cached), nil
+	}
+
+	if cached != nil && cached.ETag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		cached.StoredAt = t.now()
+		t.cache.StoreResponse(key, cached)
+		return cachedHTTPResponse(req, cached), nil
+
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+			t.cache.StoreResponse(key, &CachedResponse{
+				Body:        body,
+				ContentType: resp.Header.Get("Content-Type"),
+				ETag:        resp.Header.Get("ETag"),
+				StoredAt:    t.now(),
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+// cachedHTTPResponse answers req with a cached body
+func cachedHTTPResponse(req *http.Request, cached *CachedResponse) *http.Response {
+	header := make(http.Header)
+	if cached.ContentType != "" {
+		header.Set("Content-Type", cached.ContentType)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,88 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// memoryCache is a ResponseCache in a map
+type memoryCache map[string]*CachedResponse
+
+func (m memoryCache) CachedResponse(key string) *CachedResponse {
+	if cached, ok := m[key]; ok {
+		copied := *cached
+		return &copied
+	}
+	return nil
+}
+
+func (m memoryCache) StoreResponse(key string, resp *CachedResponse) {
+	m[key] = resp
+}
+
+func TestCacheResponsesRevalidatesWithETag(t *testing.T) {
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"uri":"example.social","version":"4.3.0"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	client := NewClient(server.URL, "token")
+	client.CacheResponses(memoryCache{})
+	client.HTTPClient.Transport.(*cachingTransport).now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetInstance(); err != nil {
+			t.Fatalf("GetInstance failed: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the second call to be answered from the cache, got %d requests", requests)
+	}
+
+	// Once the TTL is up, the instance is asked whether it changed
+	now = now.Add(25 * time.Hour)
+	instance, err := client.GetInstance()
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Expected one revalidation, got %d requests and %d 304s", requests, notModified)
+	}
+	if instance.Version != "4.3.0" {
+		t.Errorf("Expected the cached instance, got version %q", instance.Version)
+	}
+}
+
+func TestCacheResponsesSkipsOtherEndpoints(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	cache := memoryCache{}
+	client := NewClient(server.URL, "token")
+	client.CacheResponses(cache)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetStatus("1"); err != nil {
+			t.Fatalf("GetStatus failed: %v", err)
+		}
+	}
+	if requests != 2 || len(cache) != 0 {
+		t.Errorf("Expected statuses not to be cached, got %d requests and %d cached", requests, len(cache))
+	}
+}
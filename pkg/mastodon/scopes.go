@@ -0,0 +1,110 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The top-level OAuth scopes. Each of read, write, and admin also has
+// narrower scopes under it, such as read:statuses and write:media.
+const (
+	ScopeRead    = "read"
+	ScopeWrite   = "write"
+	ScopeFollow  = "follow"
+	ScopePush    = "push"
+	ScopeProfile = "profile"
+	ScopeAdmin   = "admin"
+)
+
+// followScopes are what the deprecated follow scope grants
+var followScopes = []string{"read:follows", "write:follows", "read:blocks", "write:blocks", "read:mutes", "write:mutes"}
+
+// Token is an access token and what it's allowed to do
+type Token struct {
+	AccessToken string `json:"access_token"`
+	// Scope is the space-separated scopes the token was granted
+	Scope string `json:"scope"`
+}
+
+// ParseScopes splits a list of scopes, separated by spaces as OAuth writes
+// them or by commas
+func ParseScopes(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+}
+
+// ValidScope reports whether scope is one instances know, going by its
+// top-level part
+func ValidScope(scope string) bool {
+	top, sub, narrow := strings.Cut(scope, ":")
+	switch top {
+	case ScopeRead, ScopeWrite:
+		return !narrow || sub != ""
+	case ScopeAdmin:
+		return narrow && (strings.HasPrefix(sub, "read") || strings.HasPrefix(sub, "write"))
+	case ScopeFollow, ScopePush, ScopeProfile:
+		return !narrow
+	}
+	return false
+}
+
+// HasScope reports whether granted includes scope, directly or through a
+// broader scope: read includes read:statuses, and follow includes the
+// follow, block, and mute scopes
+func HasScope(granted []string, scope string) bool {
+	for _, g := range granted {
+		if g == scope || strings.HasPrefix(scope, g+":") {
+			return true
+		}
+		if g == ScopeFollow {
+			for _, f := range followScopes {
+				if f == scope {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// MissingScopes returns the scopes in needed that granted doesn't include
+func MissingScopes(granted, needed []string) []string {
+	var missing []string
+	for _, scope := range needed {
+		if !HasScope(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// ExchangeCode trades an authorization code for an access token, returning
+// the scopes it was granted along with it
+func (c *Client) ExchangeCode(clientID, clientSecret, redirectURI, code string) (*Token, error) {
+	endpoint := fmt.Sprintf("%s/oauth/token", c.BaseURL)
+
+	data := url.Values{}
+	data.Set("client_id", clientID)
+	data.Set("client_secret", clientSecret)
+	data.Set("redirect_uri", redirectURI)
+	data.Set("code", code)
+	data.Set("grant_type", "authorization_code")
+
+	resp, err := c.HTTPClient.PostForm(endpoint, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get access token", resp)
+	}
+
+	var token Token
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	return &token, nil
+}
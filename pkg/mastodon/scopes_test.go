@@ -0,0 +1,65 @@
+package mastodon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseScopes(t *testing.T) {
+	got := ParseScopes("read  write,push")
+	if want := []string{"read", "write", "push"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseScopes = %q, want %q", got, want)
+	}
+}
+
+func TestValidScope(t *testing.T) {
+	for _, scope := range []string{"read", "write:media", "follow", "push", "profile", "admin:read:accounts"} {
+		if !ValidScope(scope) {
+			t.Errorf("Expected %q to be valid", scope)
+		}
+	}
+	for _, scope := range []string{"", "reed", "read:", "push:all", "admin"} {
+		if ValidScope(scope) {
+			t.Errorf("Expected %q to be invalid", scope)
+		}
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		granted, needed, want []string
+	}{
+		{[]string{"read", "write"}, []string{"read:statuses", "write"}, nil},
+		{[]string{"read", "write"}, []string{"push"}, []string{"push"}},
+		{[]string{"read:statuses"}, []string{"read"}, []string{"read"}},
+		{[]string{"follow"}, []string{"write:follows", "write:lists"}, []string{"write:lists"}},
+		{nil, []string{"read"}, []string{"read"}},
+	}
+
+	for _, tt := range tests {
+		if got := MissingScopes(tt.granted, tt.needed); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MissingScopes(%q, %q) = %q, want %q", tt.granted, tt.needed, got, tt.want)
+		}
+	}
+}
+
+func TestExchangeCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" {
+			t.Errorf("Expected path /oauth/token, got %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","scope":"read write push"}`)
+	}))
+	defer server.Close()
+
+	token, err := NewClient(server.URL, "").ExchangeCode("id", "secret", "redirect", "code")
+	if err != nil {
+		t.Fatalf("ExchangeCode failed: %v", err)
+	}
+	if token.AccessToken != "token" || token.Scope != "read write push" {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+}
@@ -0,0 +1,186 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Search result types, for Search's kind
+const (
+	SearchAccounts = "accounts"
+	SearchStatuses = "statuses"
+	SearchHashtags = "hashtags"
+)
+
+// SearchResults are the accounts, statuses, and hashtags matching a search
+type SearchResults struct {
+	Accounts []*Account `json:"accounts"`
+	Statuses []*Status  `json:"statuses"`
+	Hashtags []*Tag     `json:"hashtags"`
+}
+
+// Search looks for accounts, statuses, and hashtags matching query, or only
+// those of kind if it isn't "". With resolve, a query that's a link to a
+// status or account on another instance is fetched from there if this
+// instance hasn't seen it yet.
+func (c *Client) Search(query, kind string, resolve bool, limit int) (*SearchResults, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if kind != "" {
+		params.Set("type", kind)
+	}
+	if resolve {
+		params.Set("resolve", "true")
+	}
+	if limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	endpoint := fmt.Sprintf("%s/api/v2/search?%s", c.BaseURL, params.Encode())
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorizeIfLoggedIn(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("search", resp)
+	}
+
+	var results SearchResults
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	return &results, nil
+}
+
+// ResolveStatusID returns the ID this instance knows a status by, given its
+// ID or a link to it, such as one copied from a browser. Links to statuses
+// on other instances are looked up with Search, which fetches them if need
+// be.
+func (c *Client) ResolveStatusID(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if !strings.Contains(ref, "://") {
+		return ref, nil
+	}
+
+	link, err := url.Parse(ref)
+	if err != nil || link.Host == "" {
+		return "", fmt.Errorf("%q isn't a status ID or link", ref)
+	}
+
+	if base, err := url.Parse(c.BaseURL); err == nil && strings.EqualFold(link.Host, base.Host) {
+		if id := StatusIDFromPath(link.Path); id != "" {
+			return id, nil
+		}
+	}
+
+	results, err := c.Search(ref, SearchStatuses, true, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(results.Statuses) == 0 {
+		return "", fmt.Errorf("no status found at %s", ref)
+	}
+	return results.Statuses[0].ID, nil
+}
+
+// StatusIDFromPath returns the status ID in the path of a link to a status on
+// the instance it's from, or "" if the path isn't one. It knows the forms
+// Mastodon, Pleroma, Akkoma, and GoToSocial use:
+//
+//	/@alice/110123
+//	/@alice@example.com/110123
+//	/@alice/statuses/01H8...
+//	/users/alice/statuses/110123
+//	/notice/AZ3k...
+//
+// A status ID always has a digit in it in practice, which tells it from a
+// profile page such as /@alice/media.
+func StatusIDFromPath(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var id string
+	switch {
+	case len(parts) == 2 && strings.HasPrefix(parts[0], "@"):
+		id = parts[1]
+	case len(parts) == 3 && strings.HasPrefix(parts[0], "@") && parts[1] == "statuses":
+		id = parts[2]
+	case len(parts) == 4 && parts[0] == "users" && parts[2] == "statuses":
+		id = parts[3]
+	case len(parts) == 2 && parts[0] == "notice":
+		id = parts[1]
+	}
+	if !strings.ContainsAny(id, "0123456789") {
+		return ""
+	}
+	return id
+}
+
+// ResolveAccount finds an account given any of the ways people write one:
+// @alice, alice, @alice@example.com, acct:alice@example.com, or a link to
+// the profile. Accounts on other instances that this one hasn't seen yet are
+// fetched with Search, which needs a login.
+func (c *Client) ResolveAccount(ref string) (*Account, error) {
+	ref = strings.TrimSpace(ref)
+	if !strings.Contains(ref, "://") {
+		acct := strings.TrimPrefix(strings.TrimPrefix(ref, "acct:"), "@")
+		if acct == "" {
+			return nil, fmt.Errorf("missing account")
+		}
+		account, err := c.LookupAccount(acct)
+		if apiErr, ok := AsAPIError(err); ok && apiErr.StatusCode == http.StatusNotFound && strings.Contains(acct, "@") {
+			return c.searchAccount("@"+acct, err)
+		}
+		return account, err
+	}
+
+	link, err := url.Parse(ref)
+	if err != nil || link.Host == "" {
+		return nil, fmt.Errorf("%q isn't an account or a link to one", ref)
+	}
+
+	if base, err := url.Parse(c.BaseURL); err == nil && strings.EqualFold(link.Host, base.Host) {
+		if acct := AcctFromPath(link.Path); acct != "" {
+			return c.LookupAccount(acct)
+		}
+	}
+	return c.searchAccount(ref, fmt.Errorf("no account found at %s", ref))
+}
+
+// searchAccount resolves query with Search, returning notFound if nothing
+// turns up
+func (c *Client) searchAccount(query string, notFound error) (*Account, error) {
+	results, err := c.Search(query, SearchAccounts, true, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Accounts) == 0 {
+		return nil, notFound
+	}
+	return results.Accounts[0], nil
+}
+
+// AcctFromPath returns the account in the path of a link to a profile on
+// the instance it's from, such as /@alice, /@alice@example.com, or
+// /users/alice, or "" if the path isn't one
+func AcctFromPath(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) == 1 && strings.HasPrefix(parts[0], "@"):
+		return strings.TrimPrefix(parts[0], "@")
+	case len(parts) == 2 && parts[0] == "users":
+		return parts[1]
+	}
+	return ""
+}
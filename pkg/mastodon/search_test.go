@@ -0,0 +1,143 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/search" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("q") != "tusk" || query.Get("type") != "accounts" || query.Get("resolve") != "true" || query.Get("limit") != "5" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"accounts":[{"id":"7","acct":"tusk@example.com"}],"statuses":[],"hashtags":[]}`))
+	}))
+	defer server.Close()
+
+	results, err := NewClient(server.URL, "test_token").Search("tusk", SearchAccounts, true, 5)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results.Accounts) != 1 || results.Accounts[0].Acct != "tusk@example.com" {
+		t.Errorf("Unexpected accounts %v", results.Accounts)
+	}
+}
+
+func TestResolveStatusID(t *testing.T) {
+	var searched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searched = append(searched, r.URL.Query().Get("q"))
+		if r.URL.Query().Get("q") == "https://elsewhere.example/@bob/404" {
+			w.Write([]byte(`{"accounts":[],"statuses":[],"hashtags":[]}`))
+			return
+		}
+		w.Write([]byte(`{"accounts":[],"statuses":[{"id":"555"}],"hashtags":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+
+	for ref, want := range map[string]string{
+		"123":                                   "123",
+		" 123\n":                                "123",
+		server.URL + "/@alice/110123":           "110123",
+		server.URL + "/@bob@other.example/9":    "9",
+		server.URL + "/users/alice/statuses/42": "42",
+		"https://elsewhere.example/@bob/77":     "555",
+	} {
+		got, err := client.ResolveStatusID(ref)
+		if err != nil {
+			t.Errorf("ResolveStatusID(%q) failed: %v", ref, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ResolveStatusID(%q) = %q, expected %q", ref, got, want)
+		}
+	}
+
+	if len(searched) != 1 {
+		t.Errorf("Expected only the remote link to be searched, searched %v", searched)
+	}
+
+	if _, err := client.ResolveStatusID("https://elsewhere.example/@bob/404"); err == nil {
+		t.Error("Expected error for a link search doesn't find, got nil")
+	}
+}
+
+func TestStatusIDFromPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"/@alice/110123":                     "110123",
+		"/@alice/statuses/01H8XGZ3T2":        "01H8XGZ3T2",
+		"/users/alice/statuses/110123":       "110123",
+		"/notice/AZ3k9xQ":                    "AZ3k9xQ",
+		"/@alice":                            "",
+		"/@alice/media":                      "",
+		"/@alice/with_replies":               "",
+		"/tags/go":                           "",
+		"/users/alice/statuses/110/activity": "",
+	} {
+		if got := StatusIDFromPath(path); got != want {
+			t.Errorf("StatusIDFromPath(%q) = %q, expected %q", path, got, want)
+		}
+	}
+}
+
+func TestResolveAccount(t *testing.T) {
+	var lookups, searches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/accounts/lookup":
+			acct := r.URL.Query().Get("acct")
+			lookups = append(lookups, acct)
+			if acct == "carol@unseen.example" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"Record not found"}`))
+				return
+			}
+			w.Write([]byte(`{"id":"1","acct":"` + acct + `"}`))
+		case "/api/v2/search":
+			searches = append(searches, r.URL.Query().Get("q"))
+			w.Write([]byte(`{"accounts":[{"id":"2","acct":"found@remote.example"}],"statuses":[],"hashtags":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+
+	for ref, want := range map[string]string{
+		"@alice":                        "alice",
+		"bob@other.example":             "bob@other.example",
+		"acct:bob@other.example":        "bob@other.example",
+		server.URL + "/@alice":          "alice",
+		server.URL + "/users/alice":     "alice",
+		"@carol@unseen.example":         "found@remote.example",
+		"https://remote.example/@found": "found@remote.example",
+	} {
+		account, err := client.ResolveAccount(ref)
+		if err != nil {
+			t.Errorf("ResolveAccount(%q) failed: %v", ref, err)
+			continue
+		}
+		if account.Acct != want {
+			t.Errorf("ResolveAccount(%q) = %q, expected %q", ref, account.Acct, want)
+		}
+	}
+
+	if len(searches) != 2 || searches[0] == searches[1] {
+		t.Errorf("Expected a search for the unseen handle and the remote link, got %v", searches)
+	}
+	for _, acct := range lookups {
+		if acct == "" || acct[0] == '@' {
+			t.Errorf("Looked up a malformed acct %q", acct)
+		}
+	}
+
+	if _, err := client.ResolveAccount("@"); err == nil {
+		t.Error("Expected error for an empty account, got nil")
+	}
+}
@@ -0,0 +1,241 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Flavor is the server software behind an instance. Pleroma, Akkoma, and
+// GoToSocial implement most of Mastodon's API, but not all of it, and add a
+// few things of their own. Other software is assumed to behave like Mastodon.
+type Flavor string
+
+const (
+	FlavorUnknown    Flavor = ""
+	FlavorMastodon   Flavor = "mastodon"
+	FlavorPleroma    Flavor = "pleroma"
+	FlavorAkkoma     Flavor = "akkoma"
+	FlavorGoToSocial Flavor = "gotosocial"
+)
+
+func (f Flavor) String() string {
+	switch f {
+	case FlavorUnknown:
+		return "unknown"
+	case FlavorMastodon:
+		return "Mastodon"
+	case FlavorPleroma:
+		return "Pleroma"
+	case FlavorAkkoma:
+		return "Akkoma"
+	case FlavorGoToSocial:
+		return "GoToSocial"
+	default:
+		return string(f)
+	}
+}
+
+// SupportsChats reports whether the server has the Pleroma chats API
+func (f Flavor) SupportsChats() bool {
+	return f == FlavorPleroma || f == FlavorAkkoma
+}
+
+// SupportsReactions reports whether the server has the Pleroma emoji
+// reactions API
+func (f Flavor) SupportsReactions() bool {
+	return f == FlavorPleroma || f == FlavorAkkoma
+}
+
+// SupportsContentType reports whether statuses can be posted as Markdown or
+// HTML with content_type
+func (f Flavor) SupportsContentType() bool {
+	return f == FlavorPleroma || f == FlavorAkkoma || f == FlavorGoToSocial
+}
+
+// SupportsAnnouncements reports whether the server has instance announcements
+func (f Flavor) SupportsAnnouncements() bool {
+	return f != FlavorGoToSocial
+}
+
+// flavorOf maps nodeinfo's software name to a flavor
+func flavorOf(software string) Flavor {
+	return Flavor(strings.ToLower(strings.TrimSpace(software)))
+}
+
+// flavorFromVersion guesses the flavor from /api/v1/instance's version, which
+// Pleroma and Akkoma give as e.g. "2.7.2 (compatible; Pleroma 2.5.0)"
+func flavorFromVersion(version string) Flavor {
+	version = strings.ToLower(version)
+	switch {
+	case strings.Contains(version, "akkoma"):
+		return FlavorAkkoma
+	case strings.Contains(version, "pleroma"):
+		return FlavorPleroma
+	case strings.Contains(version, "gotosocial"):
+		return FlavorGoToSocial
+	default:
+		return FlavorMastodon
+	}
+}
+
+// Instance is what an instance says about itself at /api/v1/instance
+type Instance struct {
+	URI     string `json:"uri"`
+	Title   string `json:"title"`
+	Version string `json:"version"`
+
+	// Mastodon and GoToSocial report limits here
+	Configuration struct {
+		Statuses struct {
+			MaxCharacters int `json:"max_characters"`
+		} `json:"statuses"`
+		// Only glitch-soc and its forks report this
+		Reactions struct {
+			MaxReactions int `json:"max_reactions"`
+		} `json:"reactions"`
+		MediaAttachments struct {
+			ImageSizeLimit int64 `json:"image_size_limit"`
+		} `json:"media_attachments"`
+	} `json:"configuration"`
+
+	// Pleroma and Akkoma report them at the top level instead
+	MaxTootChars int   `json:"max_toot_chars"`
+	UploadLimit  int64 `json:"upload_limit"`
+}
+
+// ImageSizeLimit is the largest image upload the instance accepts, in bytes,
+// or 0 if it doesn't say
+func (i *Instance) ImageSizeLimit() int64 {
+	if limit := i.Configuration.MediaAttachments.ImageSizeLimit; limit > 0 {
+		return limit
+	}
+	return i.UploadLimit
+}
+
+// SupportsLocalOnly reports whether statuses can be kept to the instance
+// with local_only. Glitch-soc and Hometown, both Mastodon forks, put their
+// name in the version string, e.g. "4.2.1+glitch".
+func (i *Instance) SupportsLocalOnly() bool {
+	version := strings.ToLower(i.Version)
+	return strings.Contains(version, "glitch") || strings.Contains(version, "hometown")
+}
+
+// MaxCharacters is the longest status the instance accepts, or 0 if it
+// doesn't say
+func (i *Instance) MaxCharacters() int {
+	if limit := i.Configuration.Statuses.MaxCharacters; limit > 0 {
+		return limit
+	}
+	return i.MaxTootChars
+}
+
+func (c *Client) GetInstance() (*Instance, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/instance", c.BaseURL)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get instance", resp)
+	}
+
+	var instance Instance
+	if err := json.NewDecoder(resp.Body).Decode(&instance); err != nil {
+		return nil, fmt.Errorf("failed to decode instance response: %w", err)
+	}
+
+	return &instance, nil
+}
+
+// NodeInfo is the part of an instance's nodeinfo document that says what
+// software it runs
+type NodeInfo struct {
+	Software struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"software"`
+}
+
+// GetNodeInfo fetches the instance's nodeinfo, which every ActivityPub server
+// publishes, via the links in /.well-known/nodeinfo
+func (c *Client) GetNodeInfo() (*NodeInfo, error) {
+	var wellKnown struct {
+		Links []struct {
+			Rel  string `json:"rel"`
+			Href string `json:"href"`
+		} `json:"links"`
+	}
+	if err := c.getPublicJSON(c.BaseURL+"/.well-known/nodeinfo", "get nodeinfo", &wellKnown); err != nil {
+		return nil, err
+	}
+
+	// Prefer the newest 2.x schema
+	href := ""
+	best := ""
+	for _, link := range wellKnown.Links {
+		const prefix = "http://nodeinfo.diaspora.software/ns/schema/"
+		if !strings.HasPrefix(link.Rel, prefix) {
+			continue
+		}
+		if version := strings.TrimPrefix(link.Rel, prefix); version > best {
+			best, href = version, link.Href
+		}
+	}
+	if href == "" {
+		return nil, fmt.Errorf("failed to get nodeinfo: no supported schema")
+	}
+
+	var info NodeInfo
+	if err := c.getPublicJSON(href, "get nodeinfo", &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// DetectFlavor works out what software the instance runs, from nodeinfo or,
+// failing that, the instance's version string
+func (c *Client) DetectFlavor() (Flavor, error) {
+	if info, err := c.GetNodeInfo(); err == nil && info.Software.Name != "" {
+		return flavorOf(info.Software.Name), nil
+	}
+
+	instance, err := c.GetInstance()
+	if err != nil {
+		return FlavorUnknown, err
+	}
+	return flavorFromVersion(instance.Version), nil
+}
+
+// getPublicJSON decodes a public document that needs no login
+func (c *Client) getPublicJSON(endpoint, op string, v any) error {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(op, resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", strings.TrimPrefix(op, "get "), err)
+	}
+	return nil
+}
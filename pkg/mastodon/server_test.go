@@ -0,0 +1,188 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func nodeInfoServer(t *testing.T, software string) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/nodeinfo":
+			w.Write([]byte(`{"links":[
+				{"rel":"http://nodeinfo.diaspora.software/ns/schema/2.0","href":"` + server.URL + `/nodeinfo/2.0"},
+				{"rel":"http://nodeinfo.diaspora.software/ns/schema/2.1","href":"` + server.URL + `/nodeinfo/2.1"}
+			]}`))
+		case "/nodeinfo/2.1":
+			w.Write([]byte(`{"software":{"name":"` + software + `","version":"1.0"}}`))
+		case "/nodeinfo/2.0":
+			t.Error("Expected the newest schema to be used")
+			w.Write([]byte(`{"software":{"name":"mastodon","version":"1.0"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDetectFlavorFromNodeInfo(t *testing.T) {
+	tests := map[string]Flavor{
+		"mastodon":   FlavorMastodon,
+		"Akkoma":     FlavorAkkoma,
+		"pleroma":    FlavorPleroma,
+		"gotosocial": FlavorGoToSocial,
+		"misskey":    Flavor("misskey"),
+	}
+
+	for software, want := range tests {
+		client := NewClient(nodeInfoServer(t, software).URL, "")
+		got, err := client.DetectFlavor()
+		if err != nil {
+			t.Errorf("%s: DetectFlavor failed: %v", software, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected %q, got %q", software, want, got)
+		}
+	}
+}
+
+func TestDetectFlavorFallsBackToVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/instance" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"version":"2.7.2 (compatible; Pleroma 2.5.0)"}`))
+	}))
+	defer server.Close()
+
+	flavor, err := NewClient(server.URL, "").DetectFlavor()
+	if err != nil {
+		t.Fatalf("DetectFlavor failed: %v", err)
+	}
+	if flavor != FlavorPleroma {
+		t.Errorf("Expected Pleroma, got %q", flavor)
+	}
+}
+
+func TestContentTypeOnlySentWhereSupported(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	client.ContentType = "text/markdown"
+
+	client.Flavor = FlavorMastodon
+	client.PostStatus(StatusParams{Status: "*hi*"})
+	if _, ok := payload["content_type"]; ok {
+		t.Error("Expected no content_type for Mastodon")
+	}
+
+	client.Flavor = FlavorAkkoma
+	client.EditStatus("1", StatusParams{Status: "*hi*"})
+	if payload["content_type"] != "text/markdown" {
+		t.Errorf("Expected content_type for Akkoma, got %v", payload["content_type"])
+	}
+}
+
+func TestGetAnnouncementsOnGoToSocial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	client.Flavor = FlavorGoToSocial
+
+	announcements, err := client.GetAnnouncements()
+	if err != nil || len(announcements) != 0 {
+		t.Errorf("Expected no announcements and no error, got %v, %v", announcements, err)
+	}
+}
+
+func TestInstanceImageSizeLimit(t *testing.T) {
+	var mastodon, pleroma Instance
+	json.Unmarshal([]byte(`{"configuration":{"media_attachments":{"image_size_limit":16777216}}}`), &mastodon)
+	json.Unmarshal([]byte(`{"upload_limit":8000000}`), &pleroma)
+
+	if got := mastodon.ImageSizeLimit(); got != 16777216 {
+		t.Errorf("Expected Mastodon's limit, got %d", got)
+	}
+	if got := pleroma.ImageSizeLimit(); got != 8000000 {
+		t.Errorf("Expected Pleroma's limit, got %d", got)
+	}
+	if got := (&Instance{}).ImageSizeLimit(); got != 0 {
+		t.Errorf("Expected no limit, got %d", got)
+	}
+}
+
+func TestInstanceSupportsLocalOnly(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"4.2.1", false},
+		{"4.2.1+glitch", true},
+		{"4.0.2+hometown-1.1.1", true},
+		{"2.7.2 (compatible; Akkoma 3.10.4)", false},
+	}
+
+	for _, tt := range tests {
+		instance := &Instance{Version: tt.version}
+		if got := instance.SupportsLocalOnly(); got != tt.want {
+			t.Errorf("SupportsLocalOnly() for %q = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestInstanceMaxCharacters(t *testing.T) {
+	tests := []struct {
+		body string
+		want int
+	}{
+		{`{"configuration":{"statuses":{"max_characters":500}}}`, 500},
+		{`{"max_toot_chars":5000}`, 5000},
+		{`{}`, 0},
+	}
+
+	for _, tt := range tests {
+		var instance Instance
+		if err := json.Unmarshal([]byte(tt.body), &instance); err != nil {
+			t.Fatalf("Failed to decode %s: %v", tt.body, err)
+		}
+		if got := instance.MaxCharacters(); got != tt.want {
+			t.Errorf("MaxCharacters() for %s = %d, want %d", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestGetInstance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/instance" {
+			t.Errorf("Expected path /api/v1/instance, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&Instance{URI: "example.com", Version: "4.2.1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_token")
+	instance, err := client.GetInstance()
+	if err != nil {
+		t.Fatalf("Failed to get instance: %v", err)
+	}
+
+	if instance.Version != "4.2.1" {
+		t.Errorf("Expected version 4.2.1, got %q", instance.Version)
+	}
+}
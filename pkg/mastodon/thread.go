@@ -0,0 +1,105 @@
+package mastodon
+
+import "slices"
+
+// UnansweredReplies returns the replies to accountID's statuses in a thread
+// that accountID hasn't replied to, oldest first. root is the status the
+// thread starts from and descendants are everything below it, as returned by
+// GetStatusContext.
+func UnansweredReplies(accountID string, root *Status, descendants []*Status) []*Status {
+	mine := make(map[string]bool)
+	answered := make(map[string]bool)
+
+	if authorID(root) == accountID {
+		mine[root.ID] = true
+	}
+	for _, status := range descendants {
+		if authorID(status) == accountID {
+			mine[status.ID] = true
+			answered[status.InReplyTo] = true
+		}
+	}
+
+	var unanswered []*Status
+	for _, status := range descendants {
+		if authorID(status) != accountID && mine[status.InReplyTo] && !answered[status.ID] {
+			unanswered = append(unanswered, status)
+		}
+	}
+	return unanswered
+}
+
+// ThreadTail returns the ID of the last status in accountID's chain of
+// replies to themself that starts at statusID. Where the chain forks, the
+// newest branch is followed. descendants are everything below statusID, as
+// returned by GetStatusContext, which lists them in thread order.
+func ThreadTail(accountID, statusID string, descendants []*Status) string {
+	tail := statusID
+	for {
+		next := ""
+		for _, status := range descendants {
+			if status.InReplyTo == tail && authorID(status) == accountID {
+				next = status.ID
+			}
+		}
+		if next == "" {
+			return tail
+		}
+		tail = next
+	}
+}
+
+// SelfThread returns accountID's thread from root: root and every status of
+// theirs below it that replies to one of those, leaves first, so each comes
+// before the status it replies to. Replies from others, and anything of
+// accountID's below them, aren't part of it. descendants are everything
+// below root, as returned by GetStatusContext, which lists them in thread
+// order. It returns nil if root isn't accountID's.
+func SelfThread(accountID string, root *Status, descendants []*Status) []*Status {
+	if authorID(root) != accountID {
+		return nil
+	}
+
+	inThread := map[string]bool{root.ID: true}
+	thread := []*Status{root}
+	for _, status := range descendants {
+		if authorID(status) == accountID && inThread[status.InReplyTo] {
+			inThread[status.ID] = true
+			thread = append(thread, status)
+		}
+	}
+
+	// In thread order a reply always follows what it replies to
+	slices.Reverse(thread)
+	return thread
+}
+
+// ReplyAudience returns the accounts a reply to target should mention, as
+// the web client does: target's author, then everyone target mentions, but
+// never accountID, the one replying
+func ReplyAudience(accountID string, target *Status) []string {
+	seen := make(map[string]bool)
+	var accts []string
+	add := func(id, acct string) {
+		if id == accountID || acct == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		accts = append(accts, acct)
+	}
+
+	if target.Account != nil {
+		add(target.Account.ID, target.Account.Acct)
+	}
+	for _, mention := range target.Mentions {
+		add(mention.ID, mention.Acct)
+	}
+	return accts
+}
+
+func authorID(status *Status) string {
+	if status == nil || status.Account == nil {
+		return ""
+	}
+	return status.Account.ID
+}
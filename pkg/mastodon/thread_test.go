@@ -0,0 +1,141 @@
+package mastodon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnansweredReplies(t *testing.T) {
+	me := &Account{ID: "1"}
+	alice := &Account{ID: "2"}
+	bob := &Account{ID: "3"}
+
+	root := &Status{ID: "100", Account: me}
+	descendants := []*Status{
+		// Alice replied and I answered
+		{ID: "101", InReplyTo: "100", Account: alice},
+		{ID: "102", InReplyTo: "101", Account: me},
+		// Alice replied to my answer, and I haven't replied
+		{ID: "103", InReplyTo: "102", Account: alice},
+		// Bob replied to the root, and I haven't replied
+		{ID: "104", InReplyTo: "100", Account: bob},
+		// Bob replied to Alice, not to me
+		{ID: "105", InReplyTo: "101", Account: bob},
+		// My own follow-up in the thread isn't something to answer
+		{ID: "106", InReplyTo: "100", Account: me},
+		// A reply with no account is never mistaken for mine
+		{ID: "107", InReplyTo: "106"},
+	}
+
+	got := UnansweredReplies("1", root, descendants)
+
+	want := []string{"103", "104", "107"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d unanswered replies, got %d: %+v", len(want), len(got), got)
+	}
+	for i, id := range want {
+		if got[i].ID != id {
+			t.Errorf("Expected reply %s at %d, got %s", id, i, got[i].ID)
+		}
+	}
+}
+
+func TestUnansweredRepliesNoReplies(t *testing.T) {
+	root := &Status{ID: "100", Account: &Account{ID: "1"}}
+	if got := UnansweredReplies("1", root, nil); len(got) != 0 {
+		t.Errorf("Expected no unanswered replies, got %+v", got)
+	}
+}
+
+func TestThreadTail(t *testing.T) {
+	me := &Account{ID: "1"}
+	alice := &Account{ID: "2"}
+
+	descendants := []*Status{
+		{ID: "101", InReplyTo: "100", Account: me},
+		// Alice replied partway down; her reply isn't part of my chain
+		{ID: "102", InReplyTo: "101", Account: alice},
+		{ID: "103", InReplyTo: "102", Account: me},
+		// The chain forks; the later branch wins
+		{ID: "104", InReplyTo: "101", Account: me},
+		{ID: "105", InReplyTo: "101", Account: me},
+		{ID: "106", InReplyTo: "105", Account: me},
+	}
+
+	tests := []struct {
+		name     string
+		statusID string
+		want     string
+	}{
+		{"follows the chain", "100", "106"},
+		{"starts partway down", "105", "106"},
+		{"already at the tail", "106", "106"},
+		{"starts from someone else's status", "102", "103"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ThreadTail("1", tt.statusID, descendants); got != tt.want {
+				t.Errorf("ThreadTail(%s) = %s, want %s", tt.statusID, got, tt.want)
+			}
+		})
+	}
+
+	if got := ThreadTail("1", "100", nil); got != "100" {
+		t.Errorf("Expected a status with no replies to be its own tail, got %s", got)
+	}
+}
+
+func TestSelfThread(t *testing.T) {
+	me := &Account{ID: "1"}
+	alice := &Account{ID: "2"}
+
+	root := &Status{ID: "100", Account: me}
+	descendants := []*Status{
+		{ID: "101", InReplyTo: "100", Account: me},
+		{ID: "102", InReplyTo: "101", Account: me},
+		// Alice's reply, and my answer to it, belong to her conversation
+		{ID: "103", InReplyTo: "101", Account: alice},
+		{ID: "104", InReplyTo: "103", Account: me},
+		// A second branch of my own
+		{ID: "105", InReplyTo: "100", Account: me},
+	}
+
+	var got []string
+	for _, status := range SelfThread("1", root, descendants) {
+		got = append(got, status.ID)
+	}
+	if want := []string{"105", "102", "101", "100"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SelfThread = %q, want %q", got, want)
+	}
+
+	if thread := SelfThread("2", root, descendants); thread != nil {
+		t.Errorf("Expected nil for someone else's root, got %d statuses", len(thread))
+	}
+}
+
+func TestReplyAudience(t *testing.T) {
+	target := &Status{
+		ID:      "1",
+		Account: &Account{ID: "alice", Acct: "alice"},
+		Mentions: []*Mention{
+			{ID: "me", Acct: "me"},
+			{ID: "bob", Acct: "bob@example.org"},
+			{ID: "alice", Acct: "alice"},
+			{ID: "carol", Acct: "carol@example.net"},
+		},
+	}
+
+	got := ReplyAudience("me", target)
+	want := []string{"alice", "bob@example.org", "carol@example.net"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Replying to yourself only brings along the people you mentioned
+	target.Account = &Account{ID: "me", Acct: "me"}
+	got = ReplyAudience("me", target)
+	want = []string{"bob@example.org", "alice", "carol@example.net"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
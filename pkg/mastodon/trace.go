@@ -0,0 +1,75 @@
+package mastodon
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// rateLimitHeaders are the response headers Mastodon uses to report how much
+// of the rate limit is left
+var rateLimitHeaders = []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+
+// TraceTo logs every request the client makes to logger, with its status,
+// latency, and rate-limit headers. Secrets in the query string and the
+// access token are redacted. Like ObserveRequests, it should be set up after
+// RecordTo and ReplayFrom.
+func (c *Client) TraceTo(logger *slog.Logger) {
+	next := c.HTTPClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.HTTPClient.Transport = &tracingTransport{next: next, logger: logger}
+}
+
+type tracingTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attrs := []any{
+		slog.String("method", req.Method),
+		slog.String("host", req.URL.Host),
+		slog.String("path", redactURL(req.URL)),
+	}
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		attrs = append(attrs, slog.String("auth", redactAuthorization(auth)))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	attrs = append(attrs, slog.Duration("latency", time.Since(start).Round(time.Millisecond)))
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		t.logger.Error("request failed", attrs...)
+		return resp, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	for _, header := range rateLimitHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			attrs = append(attrs, slog.String(strings.ToLower(strings.TrimPrefix(header, "X-")), value))
+		}
+	}
+
+	level := slog.LevelDebug
+	if resp.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	t.logger.Log(req.Context(), level, "request", attrs...)
+
+	return resp, nil
+}
+
+// redactAuthorization keeps the scheme of an Authorization header, so logs
+// show whether a token was sent, but not the token itself
+func redactAuthorization(value string) string {
+	scheme, token, _ := strings.Cut(value, " ")
+	if token == "" {
+		return scheme
+	}
+	return scheme + " " + redacted
+}
@@ -0,0 +1,70 @@
+package mastodon
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTraceTo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "300")
+		w.Header().Set("X-RateLimit-Remaining", "299")
+		w.Header().Set("X-RateLimit-Reset", "2026-03-01T12:05:00.000Z")
+		if r.URL.Path == "/api/v1/statuses/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"2"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := NewClient(server.URL, "secret_token")
+	client.TraceTo(logger)
+
+	client.GetStatus("2")
+	client.GetStatus("1")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %q", len(lines), buf.String())
+	}
+
+	for _, want := range []string{
+		"level=DEBUG", "method=GET", "path=/api/v1/statuses/2", "status=200", "latency=",
+		`auth="Bearer REDACTED"`, "ratelimit-limit=300", "ratelimit-remaining=299",
+		"ratelimit-reset=2026-03-01T12:05:00.000Z",
+	} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("Expected %q in %q", want, lines[0])
+		}
+	}
+	if !strings.Contains(lines[1], "level=WARN") || !strings.Contains(lines[1], "status=404") {
+		t.Errorf("Expected a failed request to be logged as a warning, got %q", lines[1])
+	}
+	if strings.Contains(buf.String(), "secret_token") {
+		t.Error("Expected the access token to be redacted")
+	}
+}
+
+func TestTraceToLogsTransportErrors(t *testing.T) {
+	var buf bytes.Buffer
+	client := NewClient("http://127.0.0.1:1", "")
+	client.TraceTo(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	if _, err := client.GetStatus("1"); err == nil {
+		t.Fatal("Expected the request to fail")
+	}
+	if !strings.Contains(buf.String(), "level=ERROR") || !strings.Contains(buf.String(), "error=") {
+		t.Errorf("Expected the error to be logged, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "auth=Bearer ") {
+		t.Errorf("Expected an empty token to be shown as such, got %q", buf.String())
+	}
+}
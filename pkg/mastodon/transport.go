@@ -0,0 +1,173 @@
+package mastodon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TransportOptions adjusts how the client connects to the instance
+type TransportOptions struct {
+	// CAFile is a PEM bundle of certificates to trust in addition to the
+	// system's, for instances signed by a private CA
+	CAFile string
+
+	// Insecure skips certificate verification entirely
+	Insecure bool
+
+	// SOCKSProxy is a SOCKS5 proxy, such as Tor's 127.0.0.1:9050, to send
+	// every request through in place of the environment's proxy. The proxy
+	// resolves host names, so .onion addresses work.
+	SOCKSProxy string
+}
+
+// UseTransport sets up the client's connections with opts. Unless there's a
+// SOCKS proxy, proxies are taken from HTTP_PROXY, HTTPS_PROXY, and NO_PROXY,
+// as with the default transport. It replaces the transport, so it must come
+// before RecordTo, ReplayFrom, ObserveRequests, and TraceTo.
+func (c *Client) UseTransport(opts TransportOptions) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if opts.SOCKSProxy != "" {
+		proxyURL, err := ParseSOCKSProxy(opts.SOCKSProxy)
+		if err != nil {
+			return err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	} else if IsOnion(c.BaseURL) {
+		return fmt.Errorf("%s is an onion service, which needs a SOCKS proxy such as Tor. Run 'tusk config set socks_proxy 127.0.0.1:9050'", c.BaseURL)
+	}
+
+	if opts.CAFile != "" || opts.Insecure {
+		tlsConfig := &tls.Config{InsecureSkipVerify: opts.Insecure}
+
+		if opts.CAFile != "" {
+			pool, err := loadCertPool(opts.CAFile)
+			if err != nil {
+				return err
+			}
+			tlsConfig.RootCAs = pool
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	c.HTTPClient.Transport = transport
+	return nil
+}
+
+// ParseSOCKSProxy reads a SOCKS5 proxy address, either host:port or a
+// socks5:// or socks5h:// URL
+func ParseSOCKSProxy(value string) (*url.URL, error) {
+	if !strings.Contains(value, "://") {
+		value = "socks5h://" + value
+	}
+
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SOCKS proxy %q: %w", value, err)
+	}
+	if proxyURL.Scheme != "socks5" && proxyURL.Scheme != "socks5h" {
+		return nil, fmt.Errorf("invalid SOCKS proxy %q: only socks5 is supported", value)
+	}
+	if proxyURL.Hostname() == "" || proxyURL.Port() == "" {
+		return nil, fmt.Errorf("invalid SOCKS proxy %q: expected host:port", value)
+	}
+	return proxyURL, nil
+}
+
+// IsOnion reports whether a domain or URL is a Tor onion service
+func IsOnion(domain string) bool {
+	host := domain
+	if u, err := url.Parse(domain); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return strings.HasSuffix(strings.ToLower(strings.TrimSuffix(host, ".")), ".onion")
+}
+
+// loadCertPool adds the certificates in a PEM file to the system's
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+	}
+	return pool, nil
+}
+
+// ValidateCAFile checks that path holds at least one PEM certificate
+func ValidateCAFile(path string) error {
+	_, err := loadCertPool(path)
+	return err
+}
+
+// UseContext makes ctx cancel the client's requests, including any in
+// flight, so an interrupted command stops promptly. It wraps the transport,
+// so it must come after UseTransport.
+func (c *Client) UseContext(ctx context.Context) {
+	next := c.HTTPClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.HTTPClient.Transport = &contextTransport{next: next, ctx: ctx}
+}
+
+// WithContext returns a copy of the client whose requests ctx cancels, for
+// scoping a few calls to a deadline without changing c. The copy shares c's
+// connections and settings.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	scoped := *c
+	httpClient := *c.HTTPClient
+	scoped.HTTPClient = &httpClient
+	scoped.UseContext(ctx)
+	return &scoped
+}
+
+type contextTransport struct {
+	next http.RoundTripper
+	ctx  context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Keep the request's own context, which carries the client's timeout
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.ctx, cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// The body is still being read after RoundTrip returns
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,112 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Tag is a hashtag, as returned by the trends endpoint
+type Tag struct {
+	Name    string  `json:"name"`
+	URL     string  `json:"url"`
+	History History `json:"history"`
+}
+
+// TrendingLink is a link being shared widely, with a preview of the page
+type TrendingLink struct {
+	URL          string  `json:"url"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	ProviderName string  `json:"provider_name"`
+	History      History `json:"history"`
+}
+
+// TagHistory is a day of a tag's usage. Mastodon sends the counts as strings.
+type TagHistory struct {
+	Day      string `json:"day"`
+	Uses     string `json:"uses"`
+	Accounts string `json:"accounts"`
+}
+
+// History is the last week of a trend's usage, today first
+type History []*TagHistory
+
+// Today returns how many times the trend was used today, and by how many
+// people
+func (h History) Today() (uses, accounts int) {
+	if len(h) == 0 {
+		return 0, 0
+	}
+	uses, _ = strconv.Atoi(h[0].Uses)
+	accounts, _ = strconv.Atoi(h[0].Accounts)
+	return uses, accounts
+}
+
+// Uses returns the daily use counts, oldest first, for drawing over time
+func (h History) Uses() []int {
+	uses := make([]int, len(h))
+	for i, day := range h {
+		uses[len(h)-1-i], _ = strconv.Atoi(day.Uses)
+	}
+	return uses
+}
+
+// GetTrendingTags fetches the hashtags trending on the instance
+func (c *Client) GetTrendingTags(limit int) ([]*Tag, error) {
+	var tags []*Tag
+	if err := c.getTrends("tags", limit, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetTrendingStatuses fetches the statuses trending on the instance
+func (c *Client) GetTrendingStatuses(limit int) ([]*Status, error) {
+	var statuses []*Status
+	if err := c.getTrends("statuses", limit, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// GetTrendingLinks fetches the links trending on the instance
+func (c *Client) GetTrendingLinks(limit int) ([]*TrendingLink, error) {
+	var links []*TrendingLink
+	if err := c.getTrends("links", limit, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// getTrends decodes one of the /api/v1/trends endpoints into v
+func (c *Client) getTrends(kind string, limit int, v any) error {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	endpoint := fmt.Sprintf("%s/api/v1/trends/%s?%s", c.BaseURL, kind, params.Encode())
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorizeIfLoggedIn(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get trends: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError("get trends", resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode trends response: %w", err)
+	}
+
+	return nil
+}
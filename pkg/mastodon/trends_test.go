@@ -0,0 +1,80 @@
+package mastodon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetTrendingTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/trends/tags" {
+			t.Errorf("Expected path /api/v1/trends/tags, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`[{"name":"caturday","url":"https://example.com/tags/caturday","history":[{"day":"1700000000","uses":"120","accounts":"80"}]}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	tags, err := client.GetTrendingTags(10)
+	if err != nil {
+		t.Fatalf("Failed to get trends: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Name != "caturday" || tags[0].History[0].Uses != "120" {
+		t.Errorf("Unexpected tags: %+v", tags)
+	}
+}
+
+func TestGetTrendingStatusesAndLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/trends/statuses":
+			w.Write([]byte(`[{"id":"1","reblogs_count":5,"favourites_count":9}]`))
+		case "/api/v1/trends/links":
+			w.Write([]byte(`[{"url":"https://example.com/story","title":"A story","provider_name":"Example","history":[{"day":"1700000000","uses":"12","accounts":"10"}]}]`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+
+	statuses, err := client.GetTrendingStatuses(5)
+	if err != nil {
+		t.Fatalf("GetTrendingStatuses failed: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].FavouritesCount != 9 {
+		t.Errorf("Unexpected statuses: %+v", statuses)
+	}
+
+	links, err := client.GetTrendingLinks(5)
+	if err != nil {
+		t.Fatalf("GetTrendingLinks failed: %v", err)
+	}
+	if len(links) != 1 || links[0].Title != "A story" || links[0].ProviderName != "Example" {
+		t.Errorf("Unexpected links: %+v", links)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	history := History{
+		{Day: "3", Uses: "30", Accounts: "20"},
+		{Day: "2", Uses: "not a number", Accounts: "1"},
+		{Day: "1", Uses: "10", Accounts: "5"},
+	}
+
+	uses, accounts := history.Today()
+	if uses != 30 || accounts != 20 {
+		t.Errorf("Expected 30 uses by 20 accounts today, got %d by %d", uses, accounts)
+	}
+
+	if got := history.Uses(); !reflect.DeepEqual(got, []int{10, 0, 30}) {
+		t.Errorf("Expected uses oldest first, got %v", got)
+	}
+
+	if uses, accounts := (History{}).Today(); uses != 0 || accounts != 0 {
+		t.Errorf("Expected nothing today for an empty history, got %d by %d", uses, accounts)
+	}
+}
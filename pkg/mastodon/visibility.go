@@ -0,0 +1,72 @@
+package mastodon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Visibility controls who can see a status
+type Visibility string
+
+const (
+	VisibilityPublic   Visibility = "public"
+	VisibilityUnlisted Visibility = "unlisted"
+	VisibilityPrivate  Visibility = "private"
+	VisibilityDirect   Visibility = "direct"
+)
+
+// Visibilities lists every valid visibility, from widest to narrowest audience
+var Visibilities = []Visibility{
+	VisibilityPublic,
+	VisibilityUnlisted,
+	VisibilityPrivate,
+	VisibilityDirect,
+}
+
+var visibilityAliases = map[string]Visibility{
+	"p":         VisibilityPublic,
+	"u":         VisibilityUnlisted,
+	"f":         VisibilityPrivate,
+	"followers": VisibilityPrivate,
+	"d":         VisibilityDirect,
+}
+
+// ParseVisibility converts a visibility name or shorthand alias
+// (p, u, f/followers, d) into a Visibility
+func ParseVisibility(s string) (Visibility, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	for _, v := range Visibilities {
+		if s == string(v) {
+			return v, nil
+		}
+	}
+
+	if v, ok := visibilityAliases[s]; ok {
+		return v, nil
+	}
+
+	return "", fmt.Errorf("invalid visibility %q: must be one of public (p), unlisted (u), private (f, followers), direct (d)", s)
+}
+
+// Narrowest returns whichever of a and b reaches the smaller audience. An
+// empty visibility loses to any other.
+func Narrowest(a, b Visibility) Visibility {
+	if visibilityRank(a) >= visibilityRank(b) {
+		return a
+	}
+	return b
+}
+
+func visibilityRank(v Visibility) int {
+	for i, candidate := range Visibilities {
+		if v == candidate {
+			return i
+		}
+	}
+	return -1
+}
+
+func (v Visibility) String() string {
+	return string(v)
+}
@@ -0,0 +1,59 @@
+package mastodon
+
+import "testing"
+
+func TestParseVisibility(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Visibility
+	}{
+		{"public", VisibilityPublic},
+		{"unlisted", VisibilityUnlisted},
+		{"private", VisibilityPrivate},
+		{"direct", VisibilityDirect},
+		{"p", VisibilityPublic},
+		{"u", VisibilityUnlisted},
+		{"f", VisibilityPrivate},
+		{"followers", VisibilityPrivate},
+		{"d", VisibilityDirect},
+		{"  Unlisted ", VisibilityUnlisted},
+	}
+
+	for _, tt := range tests {
+		v, err := ParseVisibility(tt.input)
+		if err != nil {
+			t.Errorf("ParseVisibility(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if v != tt.expected {
+			t.Errorf("ParseVisibility(%q) = %q, expected %q", tt.input, v, tt.expected)
+		}
+	}
+}
+
+func TestParseVisibilityInvalid(t *testing.T) {
+	for _, input := range []string{"", "everyone", "x"} {
+		if _, err := ParseVisibility(input); err == nil {
+			t.Errorf("ParseVisibility(%q) expected error, got nil", input)
+		}
+	}
+}
+
+func TestNarrowest(t *testing.T) {
+	tests := []struct {
+		a, b     Visibility
+		expected Visibility
+	}{
+		{VisibilityPublic, VisibilityUnlisted, VisibilityUnlisted},
+		{VisibilityDirect, VisibilityPrivate, VisibilityDirect},
+		{VisibilityPrivate, VisibilityPrivate, VisibilityPrivate},
+		{"", VisibilityPublic, VisibilityPublic},
+		{VisibilityUnlisted, "", VisibilityUnlisted},
+	}
+
+	for _, tt := range tests {
+		if got := Narrowest(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Narrowest(%q, %q) = %q, expected %q", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}